@@ -0,0 +1,9 @@
+// Package api holds the OpenAPI description of the /api/v1 JSON API.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 document served at /api/v1/openapi.json.
+//
+//go:embed openapi.json
+var Spec []byte
@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/sessions"
@@ -18,9 +20,14 @@ import (
 	"github.com/DSSD-Madison/gmu/pkg/awskendra"
 	"github.com/DSSD-Madison/gmu/pkg/config"
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
 	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
 	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/heuristic"
+	"github.com/DSSD-Madison/gmu/pkg/kendrasync"
 	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/openai"
+	"github.com/DSSD-Madison/gmu/pkg/pgsearch"
 	"github.com/DSSD-Madison/gmu/pkg/ratelimiter"
 	"github.com/DSSD-Madison/gmu/pkg/services"
 	"github.com/DSSD-Madison/gmu/routes"
@@ -103,20 +110,86 @@ func main() {
 
 	dbClient := db.New(sqlDB)
 
-	// --- AWS Kendra Initialization ---
-	kendraClient, err := awskendra.New(*awsConfig, appLogger)
-	if err != nil {
-		appLogger.Error("Could not initialize kendra client", "error", err)
+	// --- Search Backend Initialization ---
+	var kendraClient awskendra.Client
+	switch appConfig.SearchBackend {
+	case "postgres":
+		kendraClient = pgsearch.New(dbClient, appLogger)
+		appLogger.Info("Postgres search client initialized")
+	default:
+		kendraClient, err = awskendra.New(*awsConfig, appLogger)
+		if err != nil {
+			appLogger.Error("Could not initialize kendra client", "error", err)
+			os.Exit(1)
+		}
+		appLogger.Info("Kendra client initialized")
+	}
+
+	// --- Metadata Extractor Initialization ---
+	extractionSettingsService := services.NewExtractionSettingsService(appLogger, dbClient)
+	var extractionSettings services.ExtractionSettings = extractionSettingsService
+	extractionFilesDir := ""
+	switch appConfig.ExtractionSource {
+	case "database":
+	case "files":
+		extractionSettings, err = services.NewFileExtractionSettings(appConfig.ExtractionDir)
+		if err != nil {
+			appLogger.Error("Could not load extraction settings", "error", err)
+			os.Exit(1)
+		}
+		extractionFilesDir = appConfig.ExtractionDir
+	default:
+		appLogger.Error("Unknown extraction source", "source", appConfig.ExtractionSource)
 		os.Exit(1)
 	}
-	appLogger.Info("Kendra client initialized")
+	appLogger.Info("Extraction settings loaded", "source", appConfig.ExtractionSource)
 
-	// TODO: Add DI and make an interface
-	bedrockClient, err := awskendra.NewBedrockClient(*awsConfig)
+	repairAttempts, err := strconv.Atoi(appConfig.MetadataRepairAttempts)
+	if err != nil || repairAttempts < 0 {
+		appLogger.Error("Invalid METADATA_REPAIR_ATTEMPTS", "value", appConfig.MetadataRepairAttempts)
+		os.Exit(1)
+	}
+	awsConfig.RepairAttempts = repairAttempts
+	modelPrices, err := services.ParseModelPrices(appConfig.ModelPrices)
 	if err != nil {
-		appLogger.Error("Could not initialize bedrock client", "error", err)
+		appLogger.Error("Invalid MODEL_PRICES", "error", err)
+		os.Exit(1)
+	}
+
+	var extractors []services.MetadataExtractor
+	for _, name := range strings.Split(appConfig.MetadataExtractors, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		var client services.MetadataClient
+		switch name {
+		case "":
+			continue
+		case services.ExtractorBedrock:
+			client, err = awskendra.NewBedrockClient(*awsConfig, appLogger)
+		case services.ExtractorOpenAI:
+			client, err = openai.New(openai.Config{
+				BaseURL:        appConfig.OpenAIBaseURL,
+				APIKey:         appConfig.OpenAIAPIKey,
+				Model:          appConfig.OpenAIModel,
+				RepairAttempts: repairAttempts,
+			})
+		case services.ExtractorHeuristic:
+			client = heuristic.New()
+		default:
+			appLogger.Error("Unknown metadata extractor", "extractor", name)
+			os.Exit(1)
+		}
+		if err != nil {
+			appLogger.Error("Could not initialize metadata extractor", "extractor", name, "error", err)
+			os.Exit(1)
+		}
+		extractors = append(extractors, services.NewMetadataExtractor(appLogger, name, client, extractionSettings, modelPrices))
+	}
+	if len(extractors) == 0 {
+		appLogger.Error("METADATA_EXTRACTORS lists no extractors")
 		os.Exit(1)
 	}
+	metadataExtractor := services.NewFallbackExtractor(appLogger, extractors...)
+	appLogger.Info("Metadata extractors initialized", "extractors", metadataExtractor.Name())
 
 	s3Client, err := awskendra.NewS3Client(*awsConfig)
 	if err != nil {
@@ -124,8 +197,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	appLogger.Info("Bedrock client initialized")
-
 	appLogger.Info("Initializing Session Store...")
 	sessionSecretKey := os.Getenv("SESSION_SECRET_KEY")
 	if sessionSecretKey == "" {
@@ -150,9 +221,28 @@ func main() {
 	// --- Service Initialization ---
 	appLogger.Info("Initializing services...")
 
+	uploadMaxFiles, err := strconv.Atoi(appConfig.UploadMaxFiles)
+	if err != nil || uploadMaxFiles <= 0 {
+		appLogger.Error("Invalid UPLOAD_MAX_FILES", "value", appConfig.UploadMaxFiles)
+		os.Exit(1)
+	}
+	uploadMaxMB, err := strconv.Atoi(appConfig.UploadMaxMB)
+	if err != nil || uploadMaxMB <= 0 {
+		appLogger.Error("Invalid UPLOAD_MAX_MB", "value", appConfig.UploadMaxMB)
+		os.Exit(1)
+	}
+	uploadLimits := services.UploadLimits{MaxFiles: uploadMaxFiles, MaxBytes: int64(uploadMaxMB) << 20}
+	indexApprovedOnly, err := strconv.ParseBool(appConfig.IndexApprovedOnly)
+	if err != nil {
+		appLogger.Error("Invalid INDEX_APPROVED_ONLY", "value", appConfig.IndexApprovedOnly)
+		os.Exit(1)
+	}
+
 	// Rate Limiters
 	ipRateLimiter := ratelimiter.NewInMemoryRateLimiter(context.Background(), appLogger, ipMaxAttempts, ipBlockDuration, ipWindow)
 	userRateLimiter := ratelimiter.NewInMemoryRateLimiter(context.Background(), appLogger, userMaxAttempts, userBlockDuration, userWindow)
+	// Failed API keys are limited apart from logins, so scripts cannot lock browsers out
+	apiKeyRateLimiter := ratelimiter.NewInMemoryRateLimiter(context.Background(), appLogger, ipMaxAttempts, ipBlockDuration, ipWindow)
 	appLogger.Debug("Rate Limiters initialized")
 
 	sessionManager, err := services.NewGorillaSessionManager(cookieStore, sessionCookieName, appLogger, dbClient)
@@ -165,11 +255,43 @@ func main() {
 	authenticationService := services.NewLoginService(appLogger, ipRateLimiter, userRateLimiter, userService)
 	searchService := services.NewSearchService(appLogger, kendraClient, dbClient)
 	suggestionService := services.NewSuggestionService(appLogger, kendraClient)
-	bedrockService := services.NewBedrockService(appLogger, *bedrockClient)
 	fileManagerService := services.NewFilemanagerService(appLogger, s3Client)
+	previewService := services.NewPreviewService(appLogger, dbClient, s3Client, services.NewCommandRenderer())
+	duplicateService := services.NewDuplicateService(appLogger, dbClient)
+	apiKeyService := services.NewAPIKeyService(appLogger, dbClient, apiKeyRateLimiter)
+	auditService := services.NewAuditService(appLogger, dbClient)
+	txRunner := repository.NewTxRunner(sqlDB, dbClient)
+	pageIndexService := services.NewPageIndexService(appLogger, repository.NewPageRepository(txRunner))
+	documentRepository := repository.NewDocumentRepository(txRunner, indexApprovedOnly)
+	termRepository := repository.NewTermRepository(txRunner, indexApprovedOnly)
+	revisionService := services.NewRevisionService(appLogger, dbClient, documentRepository)
+	ingestService := services.NewIngestService(appLogger, dbClient, fileManagerService, metadataExtractor, documentRepository, pageIndexService, previewService, auditService)
+	uploadService := services.NewUploadService(appLogger, dbClient, fileManagerService, duplicateService, ingestService, uploadLimits)
+	spendService := services.NewSpendService(appLogger, dbClient)
+	reviewService := services.NewReviewService(appLogger, dbClient)
+	taxonomyService := services.NewTaxonomyService(appLogger, dbClient, termRepository)
+	authorService := services.NewAuthorService(appLogger, dbClient)
+	publisherService := services.NewPublisherService(appLogger, dbClient)
 
 	appLogger.Info("Services initialized")
 
+	// --- Upload Jobs ---
+	if err := ingestService.Resume(context.Background()); err != nil {
+		appLogger.Error("Failed to resume upload jobs", "error", err)
+	}
+
+	// --- Kendra Sync ---
+	if appConfig.KendraSyncInterval != "" {
+		interval, err := time.ParseDuration(appConfig.KendraSyncInterval)
+		if err != nil {
+			appLogger.Error("Invalid KENDRA_SYNC_INTERVAL", "value", appConfig.KendraSyncInterval, "error", err)
+			os.Exit(1)
+		}
+		syncer := kendrasync.NewSyncer(appLogger, dbClient, kendrasync.NewKendraAPI(*awsConfig), s3Client, awsConfig.IndexID, awsConfig.RoleArn)
+		go syncer.Run(context.Background(), interval)
+		appLogger.Info("Kendra sync scheduled", "interval", interval.String())
+	}
+
 	// --- Handler Initialization ---
 	appLogger.Info("Initializing Handlers...")
 
@@ -177,9 +299,20 @@ func main() {
 	searchHandler := handlers.NewSearchHandler(appLogger, searchService, sessionManager)
 	authHandler := handlers.NewAuthenticationHandler(appLogger, sessionManager, authenticationService)
 	suggestionsHandler := handlers.NewSuggestionsHandler(appLogger, suggestionService)
-	uploadHandler := handlers.NewUploadHandler(appLogger, dbClient, bedrockService, fileManagerService, sessionManager)
-	userManagementHandler := handlers.NewUserManagementHandler(appLogger, dbClient, sessionManager)
-	databaseHandler := handlers.NewDatabaseHandler(appLogger, dbClient)
+	uploadHandler := handlers.NewUploadHandler(appLogger, dbClient, uploadService, auditService, documentRepository, reviewService, authorService, sessionManager)
+	userManagementHandler := handlers.NewUserManagementHandler(appLogger, dbClient, auditService, sessionManager)
+	databaseHandler := handlers.NewDatabaseHandler(appLogger, taxonomyService)
+	duplicatesHandler := handlers.NewDuplicatesHandler(appLogger, duplicateService, auditService, sessionManager)
+	apiHandler := handlers.NewAPIHandler(appLogger, searchService, dbClient)
+	apiKeysHandler := handlers.NewAPIKeysHandler(appLogger, apiKeyService, auditService, dbClient, sessionManager)
+	revisionsHandler := handlers.NewRevisionsHandler(appLogger, revisionService, auditService, sessionManager)
+	ingestHandler := handlers.NewIngestHandler(appLogger, ingestService, uploadService, sessionManager)
+	extractionHandler := handlers.NewExtractionHandler(appLogger, extractionSettingsService, auditService, sessionManager, extractionFilesDir)
+	spendHandler := handlers.NewSpendHandler(appLogger, spendService, sessionManager)
+	reviewHandler := handlers.NewReviewHandler(appLogger, reviewService, sessionManager)
+	taxonomyHandler := handlers.NewTaxonomyHandler(appLogger, taxonomyService, auditService, sessionManager)
+	authorHandler := handlers.NewAuthorHandler(appLogger, authorService, taxonomyService, auditService, sessionManager)
+	publisherHandler := handlers.NewPublisherHandler(appLogger, publisherService, sessionManager)
 
 	appLogger.Info("Handlers initialized")
 
@@ -225,18 +358,33 @@ func main() {
 			if path == "/search/suggestions" {
 				return true
 			}
+			// Bearer keys are not sent automatically by browsers, so they need no CSRF token.
+			if services.HasBearerToken(c.Request()) {
+				return true
+			}
 			return false
 		},
 	}))
 
 	// --- Routes Initialization ---
+	routes.RegisterAPIRoutes(e, apiHandler, apiKeyService)
+	routes.RegisterAPIKeyRoutes(e, apiKeysHandler, sessionManager)
 	routes.RegisterAuthenticationRoutes(e, authHandler)
-	routes.RegisterDatabaseRoutes(e, databaseHandler, sessionManager)
+	routes.RegisterAuthorRoutes(e, authorHandler, sessionManager, apiKeyService)
+	routes.RegisterDatabaseRoutes(e, databaseHandler, sessionManager, apiKeyService)
+	routes.RegisterDuplicatesRoutes(e, duplicatesHandler, sessionManager, apiKeyService)
+	routes.RegisterExtractionRoutes(e, extractionHandler, sessionManager, apiKeyService)
 	routes.RegisterHomeRoutes(e, homeHandler)
+	routes.RegisterIngestRoutes(e, ingestHandler, sessionManager, apiKeyService)
+	routes.RegisterPublisherRoutes(e, publisherHandler)
+	routes.RegisterReviewRoutes(e, reviewHandler, sessionManager, apiKeyService)
+	routes.RegisterRevisionRoutes(e, revisionsHandler, sessionManager, apiKeyService)
 	routes.RegisterSearchRoutes(e, searchHandler)
+	routes.RegisterSpendRoutes(e, spendHandler, sessionManager, apiKeyService)
 	routes.RegisterSuggestionsRoutes(e, suggestionsHandler)
-	routes.RegisterUploadRoutes(e, uploadHandler, sessionManager)
-	routes.RegisterUserManagementRoutes(e, userManagementHandler, sessionManager)
+	routes.RegisterTaxonomyRoutes(e, taxonomyHandler, sessionManager, apiKeyService)
+	routes.RegisterUploadRoutes(e, uploadHandler, sessionManager, apiKeyService)
+	routes.RegisterUserManagementRoutes(e, userManagementHandler, sessionManager, apiKeyService)
 	appLogger.Info("Routes initialized")
 
 	e.Static("/images", "web/assets/images")
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+)
+
+// backfill-hashes downloads every document without a content_hash and stores
+// the SHA-256 of its file, so byte-identical uploads and duplicates can be found.
+func main() {
+	dbConfig, err := db_util.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading database config: %v", err)
+	}
+	awsConfig, err := awskendra.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading kendra config: %v", err)
+	}
+
+	appLogger := logger.New(&logger.HandlerOptions{
+		Mode:  "dev",
+		Level: slog.LevelInfo,
+	})
+
+	databaseURL := fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=disable",
+		dbConfig.DBUser, dbConfig.DBPassword, dbConfig.DBHost, dbConfig.DBName,
+	)
+	sqlDB, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		appLogger.Error("Unable to initialize sql.DB", "error", err)
+		os.Exit(1)
+	}
+	defer func(sqlDB *sql.DB) {
+		if err := sqlDB.Close(); err != nil {
+			appLogger.Error("Failed to close sql.DB", "error", err)
+		}
+	}(sqlDB)
+
+	s3Client, err := awskendra.NewS3Client(*awsConfig)
+	if err != nil {
+		appLogger.Error("Failed to create S3 client", "error", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	dbClient := db.New(sqlDB)
+	fileManagerService := services.NewFilemanagerService(appLogger, s3Client)
+
+	docs, err := dbClient.ListDocumentsWithoutContentHash(ctx)
+	if err != nil {
+		appLogger.Error("Failed to list documents without content hash", "error", err)
+		os.Exit(1)
+	}
+	appLogger.Info("Backfilling content hashes", "documents", len(docs))
+
+	failed := 0
+	for _, doc := range docs {
+		bucket, key, ok := db_util.SplitS3URI(doc.S3File)
+		if !ok {
+			appLogger.Warn("Skipping document with invalid S3 path", "docID", doc.ID, "s3File", doc.S3File)
+			failed++
+			continue
+		}
+
+		docBytes, err := fileManagerService.DownloadFile(ctx, key, bucket)
+		if err != nil {
+			appLogger.Error("Failed to download document", "docID", doc.ID, "s3File", doc.S3File, "error", err)
+			failed++
+			continue
+		}
+
+		err = dbClient.SetDocumentContentHash(ctx, db.SetDocumentContentHashParams{
+			ID:          doc.ID,
+			ContentHash: sql.NullString{String: services.ContentHash(docBytes), Valid: true},
+		})
+		if err != nil {
+			appLogger.Error("Failed to save content hash", "docID", doc.ID, "error", err)
+			failed++
+		}
+	}
+
+	appLogger.Info("Backfill complete", "documents", len(docs), "failed", failed)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/language"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// backfill-languages detects the language of every document that has none
+// from its title, abstract and the text of its first pages, falling back to
+// its path. Run backfill-pages first so that scanned documents have text.
+func main() {
+	dbConfig, err := db_util.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading database config: %v", err)
+	}
+
+	appLogger := logger.New(&logger.HandlerOptions{
+		Mode:  "dev",
+		Level: slog.LevelInfo,
+	})
+
+	databaseURL := fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=disable",
+		dbConfig.DBUser, dbConfig.DBPassword, dbConfig.DBHost, dbConfig.DBName,
+	)
+	sqlDB, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		appLogger.Error("Unable to initialize sql.DB", "error", err)
+		os.Exit(1)
+	}
+	defer func(sqlDB *sql.DB) {
+		if err := sqlDB.Close(); err != nil {
+			appLogger.Error("Failed to close sql.DB", "error", err)
+		}
+	}(sqlDB)
+
+	ctx := context.Background()
+	dbClient := db.New(sqlDB)
+
+	docs, err := dbClient.ListDocumentsWithoutLanguage(ctx)
+	if err != nil {
+		appLogger.Error("Failed to list documents without a language", "error", err)
+		os.Exit(1)
+	}
+	appLogger.Info("Backfilling document languages", "documents", len(docs))
+
+	detected, undetected, failed := 0, 0, 0
+	for _, doc := range docs {
+		code := language.ForDocument(doc.Title+"\n"+doc.Abstract+"\n"+doc.Text, doc.S3File)
+		if code == "" {
+			undetected++
+			continue
+		}
+
+		err := dbClient.SetDocumentLanguage(ctx, db.SetDocumentLanguageParams{
+			ID:       doc.ID,
+			Language: sql.NullString{String: code, Valid: true},
+		})
+		if err != nil {
+			appLogger.Error("Failed to set document language", "docID", doc.ID, "language", code, "error", err)
+			failed++
+			continue
+		}
+		detected++
+	}
+
+	appLogger.Info("Backfill complete", "documents", len(docs), "detected", detected, "undetected", undetected, "failed", failed)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/kendrasync"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// kendra-sync runs a single sync pass: documents with to_index set are pushed to
+// Kendra and documents with to_delete set are removed from Kendra, S3 and the database.
+func main() {
+	dbConfig, err := db_util.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading database config: %v", err)
+	}
+	awsConfig, err := awskendra.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading kendra config: %v", err)
+	}
+
+	appLogger := logger.New(&logger.HandlerOptions{
+		Mode:  "dev",
+		Level: slog.LevelInfo,
+	})
+
+	databaseURL := fmt.Sprintf(
+		"postgres://%s:%s@%s/%s?sslmode=disable",
+		dbConfig.DBUser, dbConfig.DBPassword, dbConfig.DBHost, dbConfig.DBName,
+	)
+	sqlDB, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		appLogger.Error("Unable to initialize sql.DB", "error", err)
+		os.Exit(1)
+	}
+	defer func(sqlDB *sql.DB) {
+		if err := sqlDB.Close(); err != nil {
+			appLogger.Error("Failed to close sql.DB", "error", err)
+		}
+	}(sqlDB)
+
+	s3Client, err := awskendra.NewS3Client(*awsConfig)
+	if err != nil {
+		appLogger.Error("Failed to create S3 client", "error", err)
+		os.Exit(1)
+	}
+
+	dbClient := db.New(sqlDB)
+	syncer := kendrasync.NewSyncer(appLogger, dbClient, kendrasync.NewKendraAPI(*awsConfig), s3Client, awsConfig.IndexID, awsConfig.RoleArn)
+
+	report, syncErr := syncer.SyncOnce(context.Background())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := syncer.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("Failed to shut down sync workers", "error", err)
+	}
+
+	if syncErr != nil {
+		appLogger.Error("Kendra sync failed", "error", syncErr)
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		appLogger.Warn("Kendra sync finished with failures", "failed", report.Failed)
+	}
+}
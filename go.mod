@@ -61,6 +61,6 @@ require (
 	golang.org/x/crypto v0.35.0
 	golang.org/x/net v0.36.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
+	golang.org/x/text v0.22.0
 	golang.org/x/time v0.5.0 // indirect
 )
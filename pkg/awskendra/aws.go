@@ -19,6 +19,7 @@ func LoadConfig() (*Config, error) {
 	creds := Provider{aws.Credentials{
 		AccessKeyID:     accessKey,
 		SecretAccessKey: secretKey,
+		SessionToken:    os.Getenv("SESSION_TOKEN"),
 	}}
 
 	return &Config{
@@ -26,7 +27,7 @@ func LoadConfig() (*Config, error) {
 		Region:           os.Getenv("REGION"),
 		IndexID:          os.Getenv("INDEX_ID"),
 		ModelID:          os.Getenv("MODEL_ID"),
-		KeywordsFilePath: os.Getenv("KEYWORDS_FILE_PATH"),
+		RoleArn:          os.Getenv("ROLE_ARN"),
 		BucketName:       "manually-uploaded-bep",
 		RetryMaxAttempts: 10,
 	}, nil
@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
 	"os"
 	"regexp"
 	"strings"
@@ -16,11 +15,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
 	"github.com/ledongthuc/pdf"
 	"github.com/nguyenthenguyen/docx"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
 )
 
 var (
 	digitLineRegex      = regexp.MustCompile(`^\s*\d+\s*$`)
-	jsonRegex           = regexp.MustCompile(`(?s)\{.*}`)
 	yearPattern         = regexp.MustCompile(`^\d{4}$`)
 	yearMonthPattern    = regexp.MustCompile(`^\d{4}-\d{2}$`)
 	yearMonthDayPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
@@ -32,11 +32,9 @@ var (
 
 // BedrockClient provides methods to interact with the AWS Bedrock Runtime service.
 type BedrockClient struct {
-	client     *bedrockruntime.Client
-	config     Config
-	categories []string
-	regions    []string
-	keywords   []string
+	client *bedrockruntime.Client
+	config Config
+	log    logger.Logger
 }
 
 type ClaudeMessage struct {
@@ -72,34 +70,22 @@ type ClaudeResponseBody struct {
 }
 
 type ExtractedMetadata struct {
-	Title        string   `json:"title"`
-	Abstract     string   `json:"abstract"`
-	Category     string   `json:"category"`     // Note: Python uses category_name for the list, this seems like a single category? Adjust if needed.
-	PublishDate  string   `json:"publish_date"` // Keep as string for simplicity, parse later if needed
-	Source       string   `json:"source"`
-	RegionName   []string `json:"region_name"`   // Array of strings
-	KeywordName  []string `json:"keyword_name"`  // Array of strings
-	AuthorName   []string `json:"author_name"`   // Array of strings
-	CategoryName []string `json:"category_name"` // Array of strings
-}
-
-func loadKeywordsFromFile(filepath string) ([]string, error) {
-	content, err := os.ReadFile(filepath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read keyword file: %w", err)
-	}
-	lines := strings.Split(string(content), "\n")
-	var loadedKeywords []string
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" {
-			loadedKeywords = append(loadedKeywords, trimmed)
-		}
-	}
-	return loadedKeywords, nil
+	Title           string   `json:"title"`
+	Abstract        string   `json:"abstract"`
+	EnglishTitle    string   `json:"english_title"`
+	EnglishAbstract string   `json:"english_abstract"`
+	Category        string   `json:"category"`     // Note: Python uses category_name for the list, this seems like a single category? Adjust if needed.
+	PublishDate     string   `json:"publish_date"` // Keep as string for simplicity, parse later if needed
+	Source          string   `json:"source"`
+	RegionName      []string `json:"region_name"`   // Array of strings
+	KeywordName     []string `json:"keyword_name"`  // Array of strings
+	AuthorName      []string `json:"author_name"`   // Array of strings
+	CategoryName    []string `json:"category_name"` // Array of strings
 }
 
-func NewBedrockClient(cfg Config) (*BedrockClient, error) {
+// NewBedrockClient creates a client that extracts metadata with the Bedrock
+// model in cfg.ModelID.
+func NewBedrockClient(cfg Config, log logger.Logger) (*BedrockClient, error) {
 	opts := aws.Config{
 		Region:      cfg.Region,
 		Credentials: cfg.Credentials,
@@ -107,50 +93,13 @@ func NewBedrockClient(cfg Config) (*BedrockClient, error) {
 
 	brClient := bedrockruntime.NewFromConfig(opts)
 
-	categories := []string{
-		"article", "background paper", "blog post", "book", "brief", "case study", "dataset", "educational guide",
-		"evaluation", "fact sheet", "government report", "organizational study", "paper", "policy brief", "policy paper",
-		"project evaluation", "project evaluations", "report", "working paper",
-	}
-
-	regions := []string{
-		"Afghanistan", "Africa", "Albania", "Angola", "Asia", "Bangladesh", "Benin", "Bosnia And Herzegovina",
-		"Burkina Faso", "Burundi", "Cambodia", "Caribean", "Central African Republic Car", "Central America",
-		"Democratic Republic Of Congo Drc", "Democratic Republic Of Congo Drc / Central African Republic Car",
-		"Ecuador", "Egypt", "El Salvador", "Ethiopia", "Europe", "Georgia", "Ghana", "Global", "Guatemala",
-		"Guinea", "Indonesia", "Indo Pacific", "Iraq", "Israel", "Jamaica", "Jerusalem", "Jordan", "Kenya",
-		"Kosovo", "Kyrgyzstan", "Latin America", "Lebanon", "Liberia", "Macedonia", "Madagascar", "Mali",
-		"Middle East", "Morocco", "Myanmar", "Nepal", "Nigeria", "North America", "Oceana", "Oceania",
-		"Pakistan", "Papua New Guinea", "Peru", "Philippines", "Russia", "Rwanda", "Senegal", "Somalia",
-		"South Africa", "South America", "South Sudan", "Sri Lanka", "Sudan", "Tajikistan", "Tanzania",
-		"Timor Leste", "Uganda", "Ukraine", "West Bank", "Yemen", "Zambia", "Zimbabwe",
-	}
-
-	// Load keywords from file
-	keywords, err := loadKeywordsFromFile(cfg.KeywordsFilePath)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to load keywords from file: %w", err)
-	}
-
 	return &BedrockClient{
-		client:     brClient,
-		config:     cfg,
-		categories: categories,
-		regions:    regions,
-		keywords:   keywords,
+		client: brClient,
+		config: cfg,
+		log:    log.With("package", "awskendra"),
 	}, nil
 }
 
-// estimateCost approximates Bedrock API call cost.
-func estimateCost(inputTokens int, outputTokens int) float64 {
-	inputCost := float64(inputTokens) / 1000.0 * 0.00025   // Haiku input cost
-	outputCost := float64(outputTokens) / 1000.0 * 0.00125 // Haiku output cost
-	totalCost := inputCost + outputCost
-	// Round to 6 decimal places
-	return math.Round(totalCost*1e6) / 1e6
-}
-
 // cleanText removes lines that are just numbers or too short.
 func cleanText(text string) string {
 	lines := strings.Split(text, "\n")
@@ -170,46 +119,73 @@ func cleanText(text string) string {
 
 // extractTextFromPdf extracts text from the first N pages of a PDF.
 func extractTextFromPdf(pdfBytes []byte, maxPages int) (string, error) {
+	pages, err := extractPagesFromPdf(pdfBytes, maxPages)
+	if err != nil {
+		return "", err
+	}
+
+	var textBuilder strings.Builder
+	for _, content := range pages {
+		if content == "" {
+			continue
+		}
+		textBuilder.WriteString(content)
+		textBuilder.WriteString("\n") // Add newline between pages like Python code
+	}
+
+	return textBuilder.String(), nil
+}
+
+// extractPagesFromPdf extracts the text of each page of a PDF, up to maxPages (all pages if maxPages <= 0).
+// The returned slice is indexed by page number - 1; unreadable pages are left empty.
+func extractPagesFromPdf(pdfBytes []byte, maxPages int) ([]string, error) {
 	reader := bytes.NewReader(pdfBytes)
 	pdfReader, err := pdf.NewReader(reader, int64(len(pdfBytes)))
 	if err != nil {
-		return "", fmt.Errorf("failed to create PDF reader: %w", err)
+		return nil, fmt.Errorf("failed to create PDF reader: %w", err)
 	}
 
 	numPages := pdfReader.NumPage()
 	if numPages == 0 {
-		return "", fmt.Errorf("PDF has no pages")
+		return nil, fmt.Errorf("PDF has no pages")
 	}
 
-	pagesToRead := maxPages
-	if numPages < maxPages {
-		pagesToRead = numPages
+	pagesToRead := numPages
+	if maxPages > 0 && maxPages < numPages {
+		pagesToRead = maxPages
 	}
 
-	var textBuilder strings.Builder
+	pages := make([]string, pagesToRead)
 	for i := 1; i <= pagesToRead; i++ { // pdf library pages are 1-indexed
 		page := pdfReader.Page(i)
 		if page.V.IsNull() {
-			fmt.Printf("⚠️ Warning: Skipping potentially invalid page %d", i)
 			continue
 		}
 		content, err := page.GetPlainText(nil)
 		if err != nil {
-			// Log error but try to continue with other pages
-			fmt.Printf("⚠️ Warning: Failed to get text from page %d: %v", i, err)
+			// Leave the page empty and continue with the others
 			continue
 		}
-		textBuilder.WriteString(content)
-		textBuilder.WriteString("\n") // Add newline between pages like Python code
+		pages[i-1] = content
 	}
 
-	return textBuilder.String(), nil
+	return pages, nil
 }
 
 func ExtractTextFromDocxBytes(data []byte) (string, error) {
+	pages, err := extractPagesFromDocxBytes(data, maxPagesToParse)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(pages, "\f"), nil
+}
+
+// extractPagesFromDocxBytes splits a DOCX on its page breaks, up to maxPages (all pages if maxPages <= 0).
+func extractPagesFromDocxBytes(data []byte, maxPages int) ([]string, error) {
 	tmp, err := os.CreateTemp("", "docx-*.docx")
 	if err != nil {
-		return "", fmt.Errorf("creating temp file: %w", err)
+		return nil, fmt.Errorf("creating temp file: %w", err)
 	}
 	defer func() {
 		_ = os.Remove(tmp.Name())
@@ -218,18 +194,18 @@ func ExtractTextFromDocxBytes(data []byte) (string, error) {
 	if _, err := tmp.Write(data); err != nil {
 		cerr := tmp.Close()
 		if cerr != nil {
-			return "", fmt.Errorf("writing temp docx: %w (also failed to close: %v)", err, cerr)
+			return nil, fmt.Errorf("writing temp docx: %w (also failed to close: %v)", err, cerr)
 		}
-		return "", fmt.Errorf("writing temp docx: %w", err)
+		return nil, fmt.Errorf("writing temp docx: %w", err)
 	}
 	
 	if err := tmp.Close(); err != nil {
-		return "", fmt.Errorf("closing temp docx: %w", err)
+		return nil, fmt.Errorf("closing temp docx: %w", err)
 	}
 
 	doc, err := docx.ReadDocxFile(tmp.Name())
 	if err != nil {
-		return "", fmt.Errorf("reading temp docx: %w", err)
+		return nil, fmt.Errorf("reading temp docx: %w", err)
 	}
 	defer func() {
 		_ = doc.Close()
@@ -239,21 +215,34 @@ func ExtractTextFromDocxBytes(data []byte) (string, error) {
 	content := doc.Editable().GetContent()
 
 	parts := strings.Split(content, "\f")
-	if len(parts) > maxPagesToParse {
-		parts = parts[:maxPagesToParse]
+	if maxPages > 0 && len(parts) > maxPages {
+		parts = parts[:maxPages]
 	}
 
-	text := strings.Join(parts, "\f")
+	return parts, nil
+}
+
+// ExtractPages returns the text of every page of a PDF or DOCX document, indexed by page number - 1.
+func ExtractPages(docBytes []byte) ([]string, error) {
+	f, err := DetectFormat(docBytes)
+	if err != nil {
+		return nil, err
+	}
 
-	return text, nil
+	if f == "pdf" {
+		return extractPagesFromPdf(docBytes, 0)
+	}
+	return extractPagesFromDocxBytes(docBytes, 0)
 }
 
-// callClaudeHaiku sends the prompt to Bedrock and gets the response.
-func (c BedrockClient) callClaudeHaiku(prompt string) (string, int, int, error) {
+// callClaudeHaiku sends the conversation to Bedrock and gets the response.
+func (c BedrockClient) callClaudeHaiku(ctx context.Context, messages []ChatMessage) (Reply, error) {
+	claudeMessages := make([]ClaudeMessage, len(messages))
+	for i, m := range messages {
+		claudeMessages[i] = ClaudeMessage{Role: m.Role, Content: m.Content}
+	}
 	requestBody := ClaudeRequestBody{
-		Messages: []ClaudeMessage{
-			{Role: "user", Content: prompt},
-		},
+		Messages:         claudeMessages,
 		MaxTokens:        maxTokens,
 		Temperature:      temperature,
 		TopP:             topP,
@@ -262,12 +251,10 @@ func (c BedrockClient) callClaudeHaiku(prompt string) (string, int, int, error)
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", 0, 0, fmt.Errorf("failed to marshal request body: %w", err)
+		return Reply{}, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	// inputTokens := estimateTokens(prompt) // Estimate before sending if needed
-
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second) // Add a timeout
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second) // Add a timeout
 	defer cancel()
 
 	resp, err := c.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
@@ -277,30 +264,26 @@ func (c BedrockClient) callClaudeHaiku(prompt string) (string, int, int, error)
 		Accept:      aws.String("application/json"),
 	})
 	if err != nil {
-		return "", 0, 0, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+		return Reply{}, fmt.Errorf("failed to invoke Bedrock model: %w", err)
 	}
 
 	var responseBody ClaudeResponseBody
-	err = json.Unmarshal(resp.Body, &responseBody)
-	if err != nil {
-		// Sometimes the response might not be perfect JSON, try raw output
-		fmt.Printf("⚠️ Failed to unmarshal Bedrock JSON response: %v. Raw response: %s", err, string(resp.Body))
-		// Attempt to return the raw body if unmarshalling fails but content might exist
-		if len(resp.Body) > 0 {
-			return string(resp.Body), 0, 0, fmt.Errorf("failed to unmarshal response, but got raw body")
-		}
-		return "", 0, 0, fmt.Errorf("failed to unmarshal Bedrock response body: %w", err)
+	if err := json.Unmarshal(resp.Body, &responseBody); err != nil {
+		c.log.ErrorContext(ctx, "Failed to unmarshal Bedrock response", "body", string(resp.Body), "error", err)
+		return Reply{}, fmt.Errorf("failed to unmarshal Bedrock response body: %w", err)
 	}
 
+	reply := Reply{
+		InputTokens:  responseBody.Usage.InputTokens,
+		OutputTokens: responseBody.Usage.OutputTokens,
+	}
 	if len(responseBody.Content) == 0 || responseBody.Content[0].Type != "text" {
-		return "", responseBody.Usage.InputTokens, responseBody.Usage.OutputTokens, fmt.Errorf("unexpected response format from Bedrock: %s", string(resp.Body))
+		return reply, fmt.Errorf("unexpected response format from Bedrock: %s", string(resp.Body))
 	}
+	reply.Text = strings.TrimSpace(responseBody.Content[0].Text)
 
-	outputText := strings.TrimSpace(responseBody.Content[0].Text)
-	inputTokens := responseBody.Usage.InputTokens
-	outputTokens := responseBody.Usage.OutputTokens
-
-	return outputText, inputTokens, outputTokens, nil
+	c.log.DebugContext(ctx, "Claude response received", "model", c.config.ModelID, "inputTokens", reply.InputTokens, "outputTokens", reply.OutputTokens)
+	return reply, nil
 }
 
 // DetectFormat inspects the leading bytes to determine if the data is a PDF or DOCX.
@@ -326,35 +309,6 @@ func DetectFormat(data []byte) (string, error) {
 	return "", fmt.Errorf("unrecognized file signature")
 }
 
-// extractFirstJson tries to find and parse the first JSON object in a string.
-func extractFirstJson(text string) (*ExtractedMetadata, error) {
-	match := jsonRegex.FindString(text)
-	if match == "" {
-		return nil, fmt.Errorf("no JSON object found in the text")
-	}
-
-	var metadata ExtractedMetadata
-	decoder := json.NewDecoder(strings.NewReader(match))
-	decoder.DisallowUnknownFields() // Optional: Be stricter about fields
-
-	err := decoder.Decode(&metadata)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON object: %w. Text was: %s", err, match)
-	}
-
-	metadata.PublishDate, err = NormalizeDate(metadata.PublishDate)
-	if err != nil {
-		return &metadata, fmt.Errorf("failed to decode date string: %w. Date was: %s", err, metadata.PublishDate)
-	}
-
-	metadata.RegionName = ClipList(metadata.RegionName, 10)
-	metadata.CategoryName = ClipList(metadata.CategoryName, 10)
-	metadata.AuthorName = ClipList(metadata.AuthorName, 10)
-	metadata.KeywordName = ClipList(metadata.KeywordName, 10)
-
-	return &metadata, nil
-}
-
 func NormalizeDate(input string) (string, error) {
 	input = strings.TrimSpace(input)
 	switch {
@@ -393,55 +347,13 @@ func ClipList(items []string, maxItems int) []string {
 	return out
 }
 
-// buildPrompt constructs the prompt for Claude.
-func (c BedrockClient) buildPrompt(text string) string {
-	// Use fmt.Sprintf for easy formatting, similar to f-strings
-	return fmt.Sprintf(`
-You are an assistant extracting structured metadata from an academic policy document.
-
-Prefer to select from the following known lists if relevant:
-
-CATEGORIES:
-%s
-
-REGIONS:
-%s
-
-KEYWORDS:
-%s
-
-Normalize all values by removing dashes and replacing them with spaces. For example, "conflict-resolution" becomes "conflict resolution".
-All string fields must be enclosed in double quotes.
-Double quotes inside any string **must** be escaped using a backslash: use \", never ” or “. Do not escape any characters that are not quotes.
-Do not include any preamble, explanation, commentary, or non-JSON output — just return the JSON.
-Only generate regions that are widely known and well-represented in global datasets and literature.
-Focus on fully recognized countries or broad, commonly referenced geographic areas (e.g., Central America, Southeast Asia).
-Avoid small, obscure, or low-data regions (e.g., Kurdistan, Upper Nile, Northern Ireland), as these are less likely to be relevant or supported by sufficient context.
-
-
-Return only a valid JSON object with the following fields:
-- "title" (string, required)
-- "abstract" (string)
-- "category" (string, max 100 characters): e.g., article, research paper, etc.
-- "publish_date" (date)
-- "source" (string, max 255 characters): use "bucket" as a placeholder
-- "region_name" (array of unique strings, required, max 10)
-- "keyword_name" (array of unique strings, required, max 10)
-- "author_name" (array of unique strings, required, max 10)
-- "category_name" (array of unique strings, required, max 10)
-
-Do not explain. Do not say "Here is the JSON". Do not use Markdown. Just return the JSON object.
-TEXT:
-%s
-`, strings.Join(c.categories, ", "), strings.Join(c.regions, ", "), strings.Join(c.keywords, ", "), text)
-}
-
-// ProcessDocAndExtractMetadata fetches PDF from S3, extracts text, calls LLM, and parses metadata.
-func (c BedrockClient) ProcessDocAndExtractMetadata(ctx context.Context, docBytes []byte) (*ExtractedMetadata, error) {
+// ExtractDocumentText returns the cleaned text of the first pages of a PDF or
+// DOCX document, which is what metadata is extracted from.
+func ExtractDocumentText(docBytes []byte) (string, error) {
 	f, err := DetectFormat(docBytes)
 
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	var rawText string
@@ -453,28 +365,29 @@ func (c BedrockClient) ProcessDocAndExtractMetadata(ctx context.Context, docByte
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract text from PDF: %w", err)
+		return "", fmt.Errorf("failed to extract text from PDF: %w", err)
 	}
 	if rawText == "" {
-		return nil, fmt.Errorf("no text could be extracted from the first %d pages", maxPagesToParse)
+		return "", fmt.Errorf("no text could be extracted from the first %d pages", maxPagesToParse)
 	}
 
-	cleanedText := cleanText(rawText)
-	prompt := c.buildPrompt(cleanedText)
+	return cleanText(rawText), nil
+}
 
-	claudeResponseText, actualInputTokens, actualOutputTokens, err := c.callClaudeHaiku(prompt)
+// ProcessDocAndExtractMetadata extracts the document's text, calls the LLM, and parses metadata.
+func (c BedrockClient) ProcessDocAndExtractMetadata(ctx context.Context, docBytes []byte, prompt Prompt, vocab Vocabulary) (*Extraction, error) {
+	cleanedText, err := ExtractDocumentText(docBytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Claude: %w", err)
+		return nil, err
 	}
-	fmt.Printf("☁️ Claude response received.")
-	fmt.Printf("📊 Actual Tokens -> Input: %d, Output: %d", actualInputTokens, actualOutputTokens)
-	fmt.Printf("💸 Actual cost for this doc: $%.6f", estimateCost(actualInputTokens, actualOutputTokens))
-
-	metadata, err := extractFirstJson(claudeResponseText)
+	rendered, err := prompt.Render(vocab, cleanedText)
 	if err != nil {
-		fmt.Printf("Raw Claude response on JSON parse failure:\n%s", claudeResponseText)
-		return metadata, fmt.Errorf("error extracting JSON from Claude response: %w", err)
+		return nil, err
 	}
 
-	return metadata, nil
+	extraction := &Extraction{Model: c.config.ModelID, PromptVersion: prompt.Version}
+	if err := ExtractWithRepair(ctx, c.callClaudeHaiku, rendered, c.config.RepairAttempts, extraction); err != nil {
+		return nil, fmt.Errorf("error extracting metadata from Claude response: %w", err)
+	}
+	return extraction, nil
 }
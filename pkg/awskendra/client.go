@@ -23,5 +23,9 @@ type Config struct {
 	ModelID          string
 	BucketName       string
 	RetryMaxAttempts int
-	KeywordsFilePath string
+	// RoleArn is the IAM role Kendra assumes to read documents from S3 when indexing.
+	RoleArn string
+	// RepairAttempts is how many times the metadata model is asked to fix a
+	// reply that does not match MetadataSchema.
+	RepairAttempts int
 }
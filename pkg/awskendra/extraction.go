@@ -0,0 +1,142 @@
+package awskendra
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Vocabulary is the known categories, regions and keywords that metadata
+// extraction prefers over inventing new names.
+type Vocabulary struct {
+	Categories []string
+	Regions    []string
+	Keywords   []string
+}
+
+// Extraction is the metadata extracted from one document, what produced it
+// and what it cost. Model and PromptVersion are empty for extractors that use
+// neither. Repairs is how many times the model was asked to fix its reply, and
+// Problems is what was still wrong after the last repair. The tokens count the
+// first reply and every repair. Cost is in US dollars, and nil when the model
+// has no configured price.
+type Extraction struct {
+	Metadata      ExtractedMetadata
+	Extractor     string
+	Model         string
+	PromptVersion string
+	Repairs       int
+	Problems      []string
+	InputTokens   int
+	OutputTokens  int
+	Latency       time.Duration
+	Cost          *float64
+}
+
+// Prompt is a versioned template for the metadata extraction prompt. The
+// template is rendered with text/template and can use {{.Categories}},
+// {{.Regions}} and {{.Keywords}}, each a comma-separated list, and {{.Text}},
+// the document's text.
+type Prompt struct {
+	Version  string
+	Template string
+}
+
+type promptData struct {
+	Categories string
+	Regions    string
+	Keywords   string
+	Text       string
+}
+
+// Files in an extraction directory.
+const (
+	promptFile     = "prompt.tmpl"
+	categoriesFile = "categories.txt"
+	regionsFile    = "regions.txt"
+	keywordsFile   = "keywords.txt"
+)
+
+// Render fills in the prompt for one document.
+func (p Prompt) Render(vocab Vocabulary, text string) (string, error) {
+	tmpl, err := template.New(p.Version).Parse(p.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template %s: %w", p.Version, err)
+	}
+
+	var b bytes.Buffer
+	err = tmpl.Execute(&b, promptData{
+		Categories: strings.Join(vocab.Categories, ", "),
+		Regions:    strings.Join(vocab.Regions, ", "),
+		Keywords:   strings.Join(vocab.Keywords, ", "),
+		Text:       text,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template %s: %w", p.Version, err)
+	}
+	return b.String(), nil
+}
+
+// Validate checks that the template renders and includes the document's text.
+func (p Prompt) Validate() error {
+	if !strings.Contains(p.Template, "{{.Text}}") {
+		return errors.New("the prompt must include {{.Text}}")
+	}
+	_, err := p.Render(Vocabulary{}, "")
+	return err
+}
+
+// LoadExtractionDir reads a versioned extraction directory, such as
+// extraction/v1, holding prompt.tmpl and the categories, regions and keywords
+// listed one per line. The prompt's version is "file:" and the directory's name.
+func LoadExtractionDir(dir string) (Prompt, Vocabulary, error) {
+	data, err := os.ReadFile(filepath.Join(dir, promptFile))
+	if err != nil {
+		return Prompt{}, Vocabulary{}, fmt.Errorf("failed to read prompt: %w", err)
+	}
+	prompt := Prompt{
+		Version:  "file:" + filepath.Base(filepath.Clean(dir)),
+		Template: string(data),
+	}
+	if err := prompt.Validate(); err != nil {
+		return Prompt{}, Vocabulary{}, err
+	}
+
+	var vocab Vocabulary
+	lists := []struct {
+		file  string
+		names *[]string
+	}{
+		{categoriesFile, &vocab.Categories},
+		{regionsFile, &vocab.Regions},
+		{keywordsFile, &vocab.Keywords},
+	}
+	for _, list := range lists {
+		*list.names, err = loadListFromFile(filepath.Join(dir, list.file))
+		if err != nil {
+			return Prompt{}, Vocabulary{}, err
+		}
+	}
+	return prompt, vocab, nil
+}
+
+func loadListFromFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vocabulary file: %w", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	var loaded []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" {
+			loaded = append(loaded, trimmed)
+		}
+	}
+	return loaded, nil
+}
@@ -108,7 +108,7 @@ func queryOutputToResults(out kendra.QueryOutput) KendraResults {
 		"Keyword":    "Keywords",
 		"Region":     "Regions",
 		"Category":   "Categories",
-		"Source":     "Source",
+		"Source":     "Publisher",
 		"_file_type": "File Type",
 	}
 
@@ -148,7 +148,7 @@ func (c *kendraClientImpl) MakeQuery(ctx context.Context, query string, filters
 
 			var subFilter types.AttributeFilter
 
-			if key == "_file_type" || k == "Source" {
+			if key == "_file_type" || k == "Source" || k == "Language" {
 				subFilter.OrAllFilters = make([]types.AttributeFilter, len(filterCategory))
 				for i, strVal := range filterCategory {
 					val := strVal
@@ -195,20 +195,7 @@ func (c *kendraClientImpl) MakeQuery(ctx context.Context, query string, filters
 	c.log.DebugContext(ctx, "Kendra query executed successfully", "result_count", queryResult.Results.Count)
 
 	results := queryResult.Results
-	calculatedPages := (results.Count + 9) / 10
-	totalPages := calculatedPages
-	if totalPages > 10 {
-		totalPages = 10
-	}
-
-	results.PageStatus = PageStatus{
-		CurrentPage: pageNum,
-		PrevPage:    pageNum - 1,
-		NextPage:    pageNum + 1,
-		HasPrev:     pageNum > 1,
-		HasNext:     pageNum < totalPages,
-		TotalPages:  totalPages,
-	}
+	results.PageStatus = NewPageStatus(pageNum, results.Count)
 
 	results.Query = query
 	// results.UrlData.Query = results.Query
@@ -8,6 +8,15 @@ type Job[P, R any] struct {
 	ctx        context.Context
 }
 
+// NewJob creates a job bound to ctx, for queues used outside this package.
+func NewJob[P, R any](ctx context.Context, payload P, resultChan chan<- R) Job[P, R] {
+	return Job[P, R]{
+		Payload:    payload,
+		ResultChan: resultChan,
+		ctx:        ctx,
+	}
+}
+
 type Queue[P, R any] interface {
 	Enqueue(job Job[P, R]) bool
 	Shutdown(ctx context.Context) error
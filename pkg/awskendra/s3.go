@@ -3,8 +3,11 @@ package awskendra
 import (
 	"bytes"
 	"context"
+	"io"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 // S3Client provides methods to interact with the AWS S3 service.
@@ -42,6 +45,19 @@ func (s *S3Client) Upload(ctx context.Context, key string, body []byte, contentT
 	return err
 }
 
+// UploadPublic writes a publicly readable object to the given bucket. It is used for
+// preview images, which are linked to directly from search results.
+func (s *S3Client) UploadPublic(ctx context.Context, bucket string, key string, body []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &bucket,
+		Key:         &key,
+		Body:        bytes.NewReader(body),
+		ContentType: &contentType,
+		ACL:         types.ObjectCannedACLPublicRead,
+	})
+	return err
+}
+
 func (s *S3Client) Delete(ctx context.Context, bucket string, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: &bucket,
@@ -49,3 +65,17 @@ func (s *S3Client) Delete(ctx context.Context, bucket string, key string) error
 	})
 	return err
 }
+
+func (s *S3Client) Download(ctx context.Context, bucket string, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = out.Body.Close()
+	}()
+	return io.ReadAll(out.Body)
+}
@@ -1,8 +1,15 @@
 package awskendra
 
 type Excerpt struct {
-	Text    string
-	PageNum int
+	Text       string
+	PageNum    int
+	Highlights []Highlight
+}
+
+// Highlight marks a matched term in an excerpt as byte offsets into Excerpt.Text.
+type Highlight struct {
+	Start int
+	End   int
 }
 
 type KendraResult struct {
@@ -11,13 +18,27 @@ type KendraResult struct {
 	Link        string
 	Image       string
 	Authors     []string
+	AuthorIDs   []string // parallel to Authors when known, for linking to author pages
 	Regions     []string
 	Keywords    []string
 	PublishDate string
 	Categories  []string
 	Abstract    string
 	Source      string
+	PublisherID string // set with Source when known, for linking to the publisher page
 	UUID        string
+	// Language is the name of the language the document is written in, when
+	// known, and EnglishTitle and EnglishAbstract translate it if it is not English.
+	Language        string
+	EnglishTitle    string
+	EnglishAbstract string
+	Translations    []Translation
+}
+
+// Translation links a result to a translation of the same document.
+type Translation struct {
+	Language string
+	Link     string
 }
 
 type PageStatus struct {
@@ -29,6 +50,29 @@ type PageStatus struct {
 	TotalPages  int
 }
 
+// ResultsPerPage is the number of results returned for each page of a query.
+const ResultsPerPage = 10
+
+// MaxPages caps the number of pages reachable through pagination, matching Kendra's limit.
+const MaxPages = 10
+
+// NewPageStatus builds the pagination state for the given page of a result set of size count.
+func NewPageStatus(pageNum int, count int) PageStatus {
+	totalPages := (count + ResultsPerPage - 1) / ResultsPerPage
+	if totalPages > MaxPages {
+		totalPages = MaxPages
+	}
+
+	return PageStatus{
+		CurrentPage: pageNum,
+		PrevPage:    pageNum - 1,
+		NextPage:    pageNum + 1,
+		HasPrev:     pageNum > 1,
+		HasNext:     pageNum < totalPages,
+		TotalPages:  totalPages,
+	}
+}
+
 type KendraResults struct {
 	IsStoringUrl bool
 	Results      map[string]KendraResult
@@ -48,6 +92,8 @@ type FilterOption struct {
 	Label    string
 	Selected bool
 	Count    int32
+	Parent   string // the option this one is nested under, for hierarchical facets
+	Depth    int    // 0 for a top-level option
 }
 
 type FilterCategory struct {
@@ -0,0 +1,387 @@
+package awskendra
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MetadataSchema is the JSON schema a model's reply must match. It is sent
+// back to the model with the validation errors when a reply needs repair.
+//
+//go:embed metadata.schema.json
+var MetadataSchema string
+
+// metadataSchema is MetadataSchema decoded. validateValue supports the
+// keywords it uses: type, required, additionalProperties, properties, items,
+// minLength, maxLength, maxItems and format "date".
+var metadataSchema = mustParseSchema(MetadataSchema)
+
+type schema struct {
+	Type                 string             `json:"type"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Properties           map[string]*schema `json:"properties"`
+	Items                *schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MaxItems             *int               `json:"maxItems"`
+	Format               string             `json:"format"`
+}
+
+func mustParseSchema(raw string) *schema {
+	var s schema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		panic(fmt.Sprintf("invalid metadata schema: %v", err))
+	}
+	return &s
+}
+
+// FieldError is one way a reply does not match the schema. Field is empty
+// when the reply as a whole is wrong.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists everything wrong with a reply.
+type ValidationError []FieldError
+
+func (v ValidationError) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.String()
+	}
+	return "invalid metadata: " + strings.Join(msgs, "; ")
+}
+
+// ValidateMetadata finds the JSON object in a model's reply and checks it
+// against MetadataSchema. Fields that do not match are left empty and
+// reported, so the valid fields can still be used. Lists are deduplicated,
+// lists that are too long are cut to the maximum, and publish_date is
+// normalized with NormalizeDate. Null and empty values count as missing.
+func ValidateMetadata(reply string) (ExtractedMetadata, []FieldError) {
+	var metadata ExtractedMetadata
+	object, ok := findJSONObject(reply)
+	if !ok {
+		return metadata, []FieldError{{Message: "the reply does not contain a JSON object"}}
+	}
+
+	var problems []FieldError
+	valid := make(map[string]any, len(object))
+	for field, raw := range object {
+		property, known := metadataSchema.Properties[field]
+		if !known {
+			if metadataSchema.AdditionalProperties != nil && !*metadataSchema.AdditionalProperties {
+				problems = append(problems, FieldError{field, "is not a known field"})
+			}
+			continue
+		}
+		value, fieldProblems := validateValue(field, property, raw)
+		problems = append(problems, fieldProblems...)
+		if value != nil {
+			valid[field] = value
+		}
+	}
+	for _, field := range metadataSchema.Required {
+		if _, ok := valid[field]; !ok && !hasProblem(problems, field) {
+			problems = append(problems, FieldError{field, "is required"})
+		}
+	}
+
+	// Every value has been checked against the field's type, so this cannot fail
+	data, _ := json.Marshal(valid)
+	_ = json.Unmarshal(data, &metadata)
+	// Order by field so that errors and repair prompts do not depend on map order
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Field < problems[j].Field })
+	return metadata, problems
+}
+
+// findJSONObject decodes the first complete JSON object in text, skipping any
+// text before it or after it.
+func findJSONObject(text string) (map[string]json.RawMessage, bool) {
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		var object map[string]json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(text[start:])).Decode(&object); err == nil {
+			return object, true
+		}
+		next := strings.IndexByte(text[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return nil, false
+}
+
+// validateValue returns the value of a string or string array field, or nil
+// when it is missing or invalid.
+func validateValue(field string, s *schema, raw json.RawMessage) (any, []FieldError) {
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	switch s.Type {
+	case "string":
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, []FieldError{{field, "must be a string"}}
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, nil
+		}
+		if problem := checkString(s, value); problem != "" {
+			return nil, []FieldError{{field, problem}}
+		}
+		if s.Format == "date" {
+			normalized, err := NormalizeDate(value)
+			if err != nil {
+				return nil, []FieldError{{field, fmt.Sprintf("must be a date as YYYY-MM-DD, YYYY-MM or YYYY, not %q", value)}}
+			}
+			value = normalized
+		}
+		return value, nil
+
+	case "array":
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, []FieldError{{field, "must be an array of strings"}}
+		}
+		var problems []FieldError
+		values := make([]string, 0, len(items))
+		for i, item := range items {
+			value, itemProblems := validateValue(fmt.Sprintf("%s[%d]", field, i), s.Items, item)
+			problems = append(problems, itemProblems...)
+			if value != nil {
+				values = append(values, value.(string))
+			}
+		}
+		values = ClipList(values, len(values))
+		if s.MaxItems != nil && len(values) > *s.MaxItems {
+			problems = append(problems, FieldError{field, fmt.Sprintf("must have at most %d items, not %d", *s.MaxItems, len(values))})
+			values = values[:*s.MaxItems]
+		}
+		if len(values) == 0 {
+			return nil, problems
+		}
+		return values, problems
+
+	default:
+		return nil, []FieldError{{field, fmt.Sprintf("has unsupported schema type %q", s.Type)}}
+	}
+}
+
+func checkString(s *schema, value string) string {
+	n := utf8.RuneCountInString(value)
+	if s.MinLength != nil && n < *s.MinLength {
+		return fmt.Sprintf("must have at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		return fmt.Sprintf("must have at most %d characters, not %d", *s.MaxLength, n)
+	}
+	return ""
+}
+
+func hasProblem(problems []FieldError, field string) bool {
+	for _, p := range problems {
+		if p.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ChatMessage is one turn of a conversation with a model.
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// Reply is a model's answer and the tokens the call used.
+type Reply struct {
+	Text         string
+	InputTokens  int
+	OutputTokens int
+}
+
+// Completer sends a conversation to a model and returns its reply.
+type Completer func(ctx context.Context, messages []ChatMessage) (Reply, error)
+
+// ExtractionError is an extraction that failed after the model had replied.
+// Extraction holds what the model was, how many tokens it used and how many
+// repairs were tried, so the cost can still be recorded.
+type ExtractionError struct {
+	Extraction *Extraction
+	Err        error
+}
+
+func (e *ExtractionError) Error() string { return e.Err.Error() }
+
+func (e *ExtractionError) Unwrap() error { return e.Err }
+
+// ExtractWithRepair sends prompt to the model and validates its reply, filling
+// in extraction's metadata and usage. While there are problems, it asks the
+// model to fix them, up to repairs times. A field is settled once a reply
+// gives it a valid value, and later replies can neither break it nor be asked
+// about it again. It fails only if no reply had a valid title, or if the first
+// call to the model fails; once the model has replied, the error is an
+// *ExtractionError.
+func ExtractWithRepair(ctx context.Context, complete Completer, prompt string, repairs int, extraction *Extraction) error {
+	messages := []ChatMessage{{Role: "user", Content: prompt}}
+	settled := map[string]bool{}
+	outstanding := map[string][]FieldError{}
+
+	for attempt := 0; ; attempt++ {
+		reply, err := complete(ctx, messages)
+		extraction.InputTokens += reply.InputTokens
+		extraction.OutputTokens += reply.OutputTokens
+		if err != nil {
+			if attempt == 0 {
+				return err
+			}
+			// Keep what earlier replies gave us
+			break
+		}
+		extraction.Repairs = attempt
+
+		metadata, problems := ValidateMetadata(reply.Text)
+		extraction.Metadata = mergeMetadata(extraction.Metadata, metadata)
+
+		// Problems with the whole reply or with unknown fields only concern the latest reply
+		for field := range outstanding {
+			if metadataSchema.Properties[field] == nil {
+				delete(outstanding, field)
+			}
+		}
+		byField := map[string][]FieldError{}
+		for _, p := range problems {
+			field, _, _ := strings.Cut(p.Field, "[")
+			byField[field] = append(byField[field], p)
+		}
+		for field := range metadataSchema.Properties {
+			if !isEmptyField(metadata, field) && len(byField[field]) == 0 {
+				settled[field] = true
+				delete(outstanding, field)
+			}
+		}
+		for field, fieldProblems := range byField {
+			if !settled[field] {
+				outstanding[field] = fieldProblems
+			}
+		}
+
+		if len(outstanding) == 0 || attempt == repairs {
+			break
+		}
+		messages = append(messages,
+			ChatMessage{Role: "assistant", Content: reply.Text},
+			ChatMessage{Role: "user", Content: RepairPrompt(flattenProblems(outstanding))},
+		)
+	}
+
+	remaining := flattenProblems(outstanding)
+	for _, p := range remaining {
+		extraction.Problems = append(extraction.Problems, p.String())
+	}
+	if extraction.Metadata.Title == "" {
+		return &ExtractionError{
+			Extraction: extraction,
+			Err:        fmt.Errorf("no valid metadata after %d repairs: %w", extraction.Repairs, ValidationError(remaining)),
+		}
+	}
+	return nil
+}
+
+func flattenProblems(byField map[string][]FieldError) []FieldError {
+	var problems []FieldError
+	for _, fieldProblems := range byField {
+		problems = append(problems, fieldProblems...)
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Field < problems[j].Field })
+	return problems
+}
+
+// RepairPrompt asks the model to correct its previous reply.
+func RepairPrompt(problems []FieldError) string {
+	var b strings.Builder
+	b.WriteString("Your reply does not match the required JSON schema:\n")
+	for _, p := range problems {
+		b.WriteString("- ")
+		b.WriteString(p.String())
+		b.WriteString("\n")
+	}
+	b.WriteString("\nReply with only the corrected JSON object, keeping the values that were valid. The schema is:\n")
+	b.WriteString(MetadataSchema)
+	return b.String()
+}
+
+// mergeMetadata returns kept with the non-empty fields of newer.
+func mergeMetadata(kept, newer ExtractedMetadata) ExtractedMetadata {
+	pick := func(a, b string) string {
+		if b != "" {
+			return b
+		}
+		return a
+	}
+	pickList := func(a, b []string) []string {
+		if len(b) > 0 {
+			return b
+		}
+		return a
+	}
+	return ExtractedMetadata{
+		Title:           pick(kept.Title, newer.Title),
+		Abstract:        pick(kept.Abstract, newer.Abstract),
+		EnglishTitle:    pick(kept.EnglishTitle, newer.EnglishTitle),
+		EnglishAbstract: pick(kept.EnglishAbstract, newer.EnglishAbstract),
+		Category:        pick(kept.Category, newer.Category),
+		PublishDate:     pick(kept.PublishDate, newer.PublishDate),
+		Source:          pick(kept.Source, newer.Source),
+		RegionName:      pickList(kept.RegionName, newer.RegionName),
+		KeywordName:     pickList(kept.KeywordName, newer.KeywordName),
+		AuthorName:      pickList(kept.AuthorName, newer.AuthorName),
+		CategoryName:    pickList(kept.CategoryName, newer.CategoryName),
+	}
+}
+
+// isEmptyField reports whether a field of the schema has no value.
+func isEmptyField(m ExtractedMetadata, field string) bool {
+	switch field {
+	case "title":
+		return m.Title == ""
+	case "abstract":
+		return m.Abstract == ""
+	case "english_title":
+		return m.EnglishTitle == ""
+	case "english_abstract":
+		return m.EnglishAbstract == ""
+	case "category":
+		return m.Category == ""
+	case "publish_date":
+		return m.PublishDate == ""
+	case "source":
+		return m.Source == ""
+	case "region_name":
+		return len(m.RegionName) == 0
+	case "keyword_name":
+		return len(m.KeywordName) == 0
+	case "author_name":
+		return len(m.AuthorName) == 0
+	case "category_name":
+		return len(m.CategoryName) == 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,188 @@
+package awskendra
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	tests := []struct {
+		name         string
+		reply        string
+		want         ExtractedMetadata
+		wantProblems []string
+	}{
+		{
+			name:  "valid reply with text around it",
+			reply: "Here is the JSON:\n{\"title\": \"Report {draft}\", \"publish_date\": \"2020-05\", \"region_name\": [\"Kenya\", \"kenya\", \"Chad\"]}\nThanks {}",
+			want:  ExtractedMetadata{Title: "Report {draft}", PublishDate: "2020-05-01", RegionName: []string{"Kenya", "Chad"}},
+		},
+		{
+			name:  "null and empty values are missing",
+			reply: `{"title": "Report", "abstract": null, "publish_date": "", "author_name": []}`,
+			want:  ExtractedMetadata{Title: "Report"},
+		},
+		{
+			name:  "invalid fields are dropped and the rest kept",
+			reply: `{"title": "Report", "publish_date": "last spring", "category": "` + strings.Repeat("x", 101) + `", "source": 7, "keyword_name": ["peace", 3, ""], "summary": "extra"}`,
+			want:  ExtractedMetadata{Title: "Report", KeywordName: []string{"peace"}},
+			wantProblems: []string{
+				"category: must have at most 100 characters, not 101",
+				"keyword_name[1]: must be a string",
+				`publish_date: must be a date as YYYY-MM-DD, YYYY-MM or YYYY, not "last spring"`,
+				"source: must be a string",
+				"summary: is not a known field",
+			},
+		},
+		{
+			name:         "long lists are cut",
+			reply:        `{"title": "Report", "author_name": ["a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"]}`,
+			want:         ExtractedMetadata{Title: "Report", AuthorName: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+			wantProblems: []string{"author_name: must have at most 10 items, not 11"},
+		},
+		{
+			name:         "missing title",
+			reply:        `{"abstract": "About", "region_name": "Kenya"}`,
+			want:         ExtractedMetadata{Abstract: "About"},
+			wantProblems: []string{"region_name: must be an array of strings", "title: is required"},
+		},
+		{
+			name:         "no JSON",
+			reply:        "I could not read the document.",
+			wantProblems: []string{"the reply does not contain a JSON object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problems := ValidateMetadata(tt.reply)
+			assert.Equal(t, tt.want, got)
+
+			var messages []string
+			for _, p := range problems {
+				messages = append(messages, p.String())
+			}
+			assert.Equal(t, tt.wantProblems, messages)
+		})
+	}
+}
+
+// scriptedModel replies with each of replies in turn and records what it was sent.
+type scriptedModel struct {
+	replies []string
+	errs    []error
+	sent    [][]ChatMessage
+}
+
+// Every reply uses 100 input and 10 output tokens.
+func (m *scriptedModel) complete(_ context.Context, messages []ChatMessage) (Reply, error) {
+	i := len(m.sent)
+	m.sent = append(m.sent, messages)
+	if i < len(m.errs) && m.errs[i] != nil {
+		return Reply{}, m.errs[i]
+	}
+	return Reply{Text: m.replies[i], InputTokens: 100, OutputTokens: 10}, nil
+}
+
+func TestExtractWithRepair(t *testing.T) {
+	t.Run("valid reply needs no repair", func(t *testing.T) {
+		model := &scriptedModel{replies: []string{`{"title": "Report"}`}}
+
+		extraction := &Extraction{}
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 2, extraction)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Report", extraction.Metadata.Title)
+		assert.Zero(t, extraction.Repairs)
+		assert.Empty(t, extraction.Problems)
+		assert.Equal(t, 100, extraction.InputTokens)
+		assert.Equal(t, 10, extraction.OutputTokens)
+		assert.Len(t, model.sent, 1)
+	})
+
+	t.Run("repair keeps fields from earlier replies", func(t *testing.T) {
+		model := &scriptedModel{replies: []string{
+			`{"title": "Report", "publish_date": "soon", "region_name": ["Kenya"]}`,
+			`{"publish_date": "2021"}`,
+		}}
+
+		extraction := &Extraction{}
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 2, extraction)
+
+		require.NoError(t, err)
+		assert.Equal(t, ExtractedMetadata{Title: "Report", PublishDate: "2021-01-01", RegionName: []string{"Kenya"}}, extraction.Metadata)
+		assert.Equal(t, 1, extraction.Repairs)
+		assert.Empty(t, extraction.Problems, "the title is missing from the repair but was found before")
+		assert.Equal(t, 200, extraction.InputTokens)
+		assert.Equal(t, 20, extraction.OutputTokens)
+
+		require.Len(t, model.sent, 2)
+		repair := model.sent[1]
+		require.Len(t, repair, 3)
+		assert.Equal(t, ChatMessage{Role: "user", Content: "prompt"}, repair[0])
+		assert.Equal(t, "assistant", repair[1].Role)
+		assert.Contains(t, repair[2].Content, `publish_date: must be a date as YYYY-MM-DD, YYYY-MM or YYYY, not "soon"`)
+		assert.Contains(t, repair[2].Content, MetadataSchema)
+	})
+
+	t.Run("gives up after the repairs and keeps valid fields", func(t *testing.T) {
+		model := &scriptedModel{replies: []string{
+			`{"title": "Report", "source": 1}`,
+			`{"title": "Report", "source": 2}`,
+			`{"title": "Report", "source": 3}`,
+		}}
+
+		extraction := &Extraction{}
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 1, extraction)
+
+		require.NoError(t, err)
+		assert.Equal(t, ExtractedMetadata{Title: "Report"}, extraction.Metadata)
+		assert.Equal(t, 1, extraction.Repairs)
+		assert.Equal(t, []string{"source: must be a string"}, extraction.Problems)
+		assert.Len(t, model.sent, 2)
+	})
+
+	t.Run("fails without a title", func(t *testing.T) {
+		model := &scriptedModel{replies: []string{"no", `{"abstract": "About"}`}}
+
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 1, &Extraction{Model: "haiku"})
+
+		var invalid ValidationError
+		require.ErrorAs(t, err, &invalid)
+		assert.EqualError(t, err, "no valid metadata after 1 repairs: invalid metadata: title: is required")
+
+		var failed *ExtractionError
+		require.ErrorAs(t, err, &failed)
+		assert.Equal(t, "haiku", failed.Extraction.Model)
+		assert.Equal(t, 200, failed.Extraction.InputTokens, "the tokens of both replies are counted")
+	})
+
+	t.Run("a failed repair call keeps the first reply", func(t *testing.T) {
+		model := &scriptedModel{
+			replies: []string{`{"title": "Report", "source": 1}`},
+			errs:    []error{nil, errors.New("throttled")},
+		}
+
+		extraction := &Extraction{}
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 2, extraction)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Report", extraction.Metadata.Title)
+		assert.Equal(t, []string{"source: must be a string"}, extraction.Problems)
+	})
+
+	t.Run("a failed first call fails", func(t *testing.T) {
+		model := &scriptedModel{errs: []error{errors.New("throttled")}}
+
+		err := ExtractWithRepair(context.Background(), model.complete, "prompt", 2, &Extraction{})
+
+		assert.EqualError(t, err, "throttled")
+		var failed *ExtractionError
+		assert.False(t, errors.As(err, &failed), "nothing was used")
+	})
+}
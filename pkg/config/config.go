@@ -9,6 +9,37 @@ import (
 type Config struct {
 	Mode string
 	LogLevel string
+	// SearchBackend selects the search implementation: "kendra" (default) or "postgres".
+	SearchBackend string
+	// KendraSyncInterval is how often the background Kendra sync runs (e.g. "15m").
+	// Leave empty to disable it and run cmd/kendra-sync on a schedule instead.
+	KendraSyncInterval string
+	// UploadMaxFiles and UploadMaxMB cap one upload, counting the files inside
+	// ZIP archives.
+	UploadMaxFiles string
+	UploadMaxMB string
+	// MetadataExtractors lists the metadata extractors to try in order, separated
+	// by commas: "bedrock" (default), "openai" and "heuristic".
+	MetadataExtractors string
+	// OpenAIBaseURL, OpenAIAPIKey and OpenAIModel configure the "openai" extractor
+	// for any OpenAI-compatible server, e.g. "http://localhost:11434/v1".
+	OpenAIBaseURL string
+	OpenAIAPIKey string
+	OpenAIModel string
+	// MetadataRepairAttempts is how many times a model is asked to fix a reply
+	// that does not match the metadata schema.
+	MetadataRepairAttempts string
+	// ModelPrices prices each model's tokens for the spend report, as
+	// model=input/output in US dollars per million tokens, separated by semicolons.
+	ModelPrices string
+	// ExtractionSource is where the extraction prompt and vocabularies come from:
+	// "database" (default), edited on the extraction settings page, or "files",
+	// read from the versioned directory ExtractionDir.
+	ExtractionSource string
+	ExtractionDir string
+	// IndexApprovedOnly, when "true", only queues documents for indexing once a
+	// reviewer has approved their metadata.
+	IndexApprovedOnly string
 }
 
 func LoadConfig() (*Config, error) {
@@ -19,6 +50,19 @@ func LoadConfig() (*Config, error) {
 	return &Config{
 		Mode: lookupEnv("MODE", "dev"),
 		LogLevel: lookupEnv("LOG_LEVEL", "info"),
+		SearchBackend: lookupEnv("SEARCH_BACKEND", "kendra"),
+		KendraSyncInterval: lookupEnv("KENDRA_SYNC_INTERVAL", ""),
+		UploadMaxFiles: lookupEnv("UPLOAD_MAX_FILES", "200"),
+		UploadMaxMB: lookupEnv("UPLOAD_MAX_MB", "500"),
+		MetadataExtractors: lookupEnv("METADATA_EXTRACTORS", "bedrock"),
+		OpenAIBaseURL: lookupEnv("OPENAI_BASE_URL", ""),
+		OpenAIAPIKey: lookupEnv("OPENAI_API_KEY", ""),
+		OpenAIModel: lookupEnv("OPENAI_MODEL", ""),
+		MetadataRepairAttempts: lookupEnv("METADATA_REPAIR_ATTEMPTS", "2"),
+		ModelPrices: lookupEnv("MODEL_PRICES", "anthropic.claude-3-haiku-20240307-v1:0=0.25/1.25"),
+		ExtractionSource: lookupEnv("EXTRACTION_SOURCE", "database"),
+		ExtractionDir: lookupEnv("EXTRACTION_DIR", "extraction/v1"),
+		IndexApprovedOnly: lookupEnv("INDEX_APPROVED_ONLY", "false"),
 	}, nil
 }
 
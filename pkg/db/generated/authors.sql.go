@@ -13,33 +13,40 @@ import (
 )
 
 const findAuthorByName = `-- name: FindAuthorByName :one
-SELECT id, name FROM authors WHERE LOWER(name) = LOWER($1) LIMIT 1
+SELECT id, name, approved, orcid, affiliation FROM authors WHERE LOWER(name) = LOWER($1) LIMIT 1
 `
 
 func (q *Queries) FindAuthorByName(ctx context.Context, lower string) (Author, error) {
 	row := q.db.QueryRowContext(ctx, findAuthorByName, lower)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Approved,
+		&i.Orcid,
+		&i.Affiliation,
+	)
 	return i, err
 }
 
 const insertAuthor = `-- name: InsertAuthor :exec
-INSERT INTO authors (id, name) VALUES ($1, $2)
+INSERT INTO authors (id, name, approved) VALUES ($1, $2, $3)
 `
 
 type InsertAuthorParams struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
 }
 
 func (q *Queries) InsertAuthor(ctx context.Context, arg InsertAuthorParams) error {
-	_, err := q.db.ExecContext(ctx, insertAuthor, arg.ID, arg.Name)
+	_, err := q.db.ExecContext(ctx, insertAuthor, arg.ID, arg.Name, arg.Approved)
 	return err
 }
 
 const listAllAuthors = `-- name: ListAllAuthors :many
 
-SELECT id, name FROM authors ORDER BY name
+SELECT id, name, approved, orcid, affiliation FROM authors ORDER BY name
 `
 
 // Optional: limit the number of results (good for autocomplete)
@@ -52,7 +59,13 @@ func (q *Queries) ListAllAuthors(ctx context.Context) ([]Author, error) {
 	var items []Author
 	for rows.Next() {
 		var i Author
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Approved,
+			&i.Orcid,
+			&i.Affiliation,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
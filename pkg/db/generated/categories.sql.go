@@ -13,32 +13,33 @@ import (
 )
 
 const findCategoryByName = `-- name: FindCategoryByName :one
-SELECT id, name FROM categories WHERE LOWER(name) = LOWER($1) LIMIT 1
+SELECT id, name, approved FROM categories WHERE LOWER(name) = LOWER($1) LIMIT 1
 `
 
 func (q *Queries) FindCategoryByName(ctx context.Context, lower string) (Category, error) {
 	row := q.db.QueryRowContext(ctx, findCategoryByName, lower)
 	var i Category
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Approved)
 	return i, err
 }
 
 const insertCategory = `-- name: InsertCategory :exec
-INSERT INTO categories (id, name) VALUES ($1, $2)
+INSERT INTO categories (id, name, approved) VALUES ($1, $2, $3)
 `
 
 type InsertCategoryParams struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
 }
 
 func (q *Queries) InsertCategory(ctx context.Context, arg InsertCategoryParams) error {
-	_, err := q.db.ExecContext(ctx, insertCategory, arg.ID, arg.Name)
+	_, err := q.db.ExecContext(ctx, insertCategory, arg.ID, arg.Name, arg.Approved)
 	return err
 }
 
 const listAllCategories = `-- name: ListAllCategories :many
-SELECT id, name FROM categories ORDER BY name
+SELECT id, name, approved FROM categories ORDER BY name
 `
 
 func (q *Queries) ListAllCategories(ctx context.Context) ([]Category, error) {
@@ -50,7 +51,7 @@ func (q *Queries) ListAllCategories(ctx context.Context) ([]Category, error) {
 	var items []Category
 	for rows.Next() {
 		var i Category
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Approved); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
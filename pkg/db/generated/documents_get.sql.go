@@ -16,7 +16,8 @@ import (
 const findDocumentByID = `-- name: FindDocumentByID :one
 
 SELECT
-    d.id, d.file_name, d.title, d.abstract, d.publish_date, d.source, d.to_index, d.s3_file, d.s3_file_preview, d.pdf_link, d.created_at, d.deleted_at, d.to_delete, d.to_generate_preview,
+    d.id, d.file_name, d.title, d.abstract, d.publish_date, d.to_index, d.s3_file, d.s3_file_preview, d.pdf_link, d.created_at, d.deleted_at, d.to_delete, d.to_generate_preview, d.content_hash, d.review_state, d.approved_by, d.approved_by_name, d.approved_at, d.publisher_id, d.language, d.english_title, d.english_abstract, d.translation_group,
+    p.name AS source,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT a.name), NULL)::text[] AS author_names,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT r.name), NULL)::text[] AS region_names,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT k.name), NULL)::text[] AS keyword_names,
@@ -30,8 +31,9 @@ LEFT JOIN doc_keywords dk ON d.id = dk.doc_id
 LEFT JOIN keywords k ON dk.keyword_id = k.id
 LEFT JOIN doc_categories dc ON d.id = dc.doc_id
 LEFT JOIN categories c ON dc.category_id = c.id
+LEFT JOIN publishers p ON p.id = d.publisher_id
 WHERE d.id = $1
-GROUP BY d.id
+GROUP BY d.id, p.id
 `
 
 type FindDocumentByIDRow struct {
@@ -40,7 +42,6 @@ type FindDocumentByIDRow struct {
 	Title             string
 	Abstract          sql.NullString
 	PublishDate       sql.NullTime
-	Source            sql.NullString
 	ToIndex           sql.NullBool
 	S3File            string
 	S3FilePreview     sql.NullString
@@ -49,6 +50,17 @@ type FindDocumentByIDRow struct {
 	DeletedAt         sql.NullTime
 	ToDelete          bool
 	ToGeneratePreview sql.NullBool
+	ContentHash       sql.NullString
+	ReviewState       string
+	ApprovedBy        uuid.NullUUID
+	ApprovedByName    sql.NullString
+	ApprovedAt        sql.NullTime
+	PublisherID       uuid.NullUUID
+	Language          sql.NullString
+	EnglishTitle      sql.NullString
+	EnglishAbstract   sql.NullString
+	TranslationGroup  uuid.NullUUID
+	Source            sql.NullString
 	AuthorNames       []string
 	RegionNames       []string
 	KeywordNames      []string
@@ -65,7 +77,6 @@ func (q *Queries) FindDocumentByID(ctx context.Context, id uuid.UUID) (FindDocum
 		&i.Title,
 		&i.Abstract,
 		&i.PublishDate,
-		&i.Source,
 		&i.ToIndex,
 		&i.S3File,
 		&i.S3FilePreview,
@@ -74,6 +85,17 @@ func (q *Queries) FindDocumentByID(ctx context.Context, id uuid.UUID) (FindDocum
 		&i.DeletedAt,
 		&i.ToDelete,
 		&i.ToGeneratePreview,
+		&i.ContentHash,
+		&i.ReviewState,
+		&i.ApprovedBy,
+		&i.ApprovedByName,
+		&i.ApprovedAt,
+		&i.PublisherID,
+		&i.Language,
+		&i.EnglishTitle,
+		&i.EnglishAbstract,
+		&i.TranslationGroup,
+		&i.Source,
 		pq.Array(&i.AuthorNames),
 		pq.Array(&i.RegionNames),
 		pq.Array(&i.KeywordNames),
@@ -83,7 +105,7 @@ func (q *Queries) FindDocumentByID(ctx context.Context, id uuid.UUID) (FindDocum
 }
 
 const findDocumentByS3Path = `-- name: FindDocumentByS3Path :one
-SELECT id, file_name, title, abstract, publish_date, source, to_index, s3_file, s3_file_preview, pdf_link, created_at, deleted_at, to_delete, to_generate_preview
+SELECT id, file_name, title, abstract, publish_date, to_index, s3_file, s3_file_preview, pdf_link, created_at, deleted_at, to_delete, to_generate_preview, content_hash, review_state, approved_by, approved_by_name, approved_at, publisher_id, language, english_title, english_abstract, translation_group
 FROM documents
 WHERE s3_file = $1
 `
@@ -97,7 +119,6 @@ func (q *Queries) FindDocumentByS3Path(ctx context.Context, s3File string) (Docu
 		&i.Title,
 		&i.Abstract,
 		&i.PublishDate,
-		&i.Source,
 		&i.ToIndex,
 		&i.S3File,
 		&i.S3FilePreview,
@@ -106,13 +127,24 @@ func (q *Queries) FindDocumentByS3Path(ctx context.Context, s3File string) (Docu
 		&i.DeletedAt,
 		&i.ToDelete,
 		&i.ToGeneratePreview,
+		&i.ContentHash,
+		&i.ReviewState,
+		&i.ApprovedBy,
+		&i.ApprovedByName,
+		&i.ApprovedAt,
+		&i.PublisherID,
+		&i.Language,
+		&i.EnglishTitle,
+		&i.EnglishAbstract,
+		&i.TranslationGroup,
 	)
 	return i, err
 }
 
 const getDocumentsByURIs = `-- name: GetDocumentsByURIs :many
 SELECT
-    d.id, d.file_name, d.title, d.abstract, d.publish_date, d.source, d.to_index, d.s3_file, d.s3_file_preview, d.pdf_link, d.created_at, d.deleted_at, d.to_delete, d.to_generate_preview,  -- Select all columns from the documents table
+    d.id, d.file_name, d.title, d.abstract, d.publish_date, d.to_index, d.s3_file, d.s3_file_preview, d.pdf_link, d.created_at, d.deleted_at, d.to_delete, d.to_generate_preview, d.content_hash, d.review_state, d.approved_by, d.approved_by_name, d.approved_at, d.publisher_id, d.language, d.english_title, d.english_abstract, d.translation_group,  -- Select all columns from the documents table
+    p.name AS source,
     -- Aggregate author names into a text array
     COALESCE(ARRAY_AGG(DISTINCT a.name) FILTER (WHERE a.id IS NOT NULL), '{}'::text[]) AS author_names,
     -- Aggregate region names into a text array
@@ -120,7 +152,23 @@ SELECT
     -- Aggregate keyword names into a text array
     COALESCE(ARRAY_AGG(DISTINCT k.name) FILTER (WHERE k.id IS NOT NULL), '{}'::text[]) AS keyword_names,
     -- Aggregate category names into a text array
-    COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE c.id IS NOT NULL), '{}'::text[]) AS category_names
+    COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE c.id IS NOT NULL), '{}'::text[]) AS category_names,
+    -- Author IDs in the same order as author_names, which are unique and sorted
+    ARRAY(
+        SELECT ao.id::text FROM doc_authors dao JOIN authors ao ON ao.id = dao.author_id
+        WHERE dao.doc_id = d.id ORDER BY ao.name
+    )::text[] AS author_ids,
+    -- Languages and files of the document's translations, in the same order
+    ARRAY(
+        SELECT COALESCE(t.language, '') FROM documents t
+        WHERE t.translation_group = d.translation_group AND t.id <> d.id AND t.to_delete = false
+        ORDER BY t.language, t.id
+    )::text[] AS translation_languages,
+    ARRAY(
+        SELECT t.s3_file FROM documents t
+        WHERE t.translation_group = d.translation_group AND t.id <> d.id AND t.to_delete = false
+        ORDER BY t.language, t.id
+    )::text[] AS translation_files
 FROM
     documents d
         LEFT JOIN
@@ -139,33 +187,48 @@ FROM
     doc_categories dc ON d.id = dc.doc_id
         LEFT JOIN
     categories c ON dc.category_id = c.id
+        LEFT JOIN
+    publishers p ON p.id = d.publisher_id
 WHERE
     d.s3_file = ANY($1::text[]) -- Filter documents by the provided list of s3_file paths
 GROUP BY
-    d.id -- Group by document ID to aggregate related names for each document
+    d.id, p.id -- Group by document ID to aggregate related names for each document
 ORDER BY
     d.id
 `
 
 type GetDocumentsByURIsRow struct {
-	ID                uuid.UUID
-	FileName          string
-	Title             string
-	Abstract          sql.NullString
-	PublishDate       sql.NullTime
-	Source            sql.NullString
-	ToIndex           sql.NullBool
-	S3File            string
-	S3FilePreview     sql.NullString
-	PdfLink           sql.NullString
-	CreatedAt         sql.NullTime
-	DeletedAt         sql.NullTime
-	ToDelete          bool
-	ToGeneratePreview sql.NullBool
-	AuthorNames       interface{}
-	RegionNames       interface{}
-	KeywordNames      interface{}
-	CategoryNames     interface{}
+	ID                   uuid.UUID
+	FileName             string
+	Title                string
+	Abstract             sql.NullString
+	PublishDate          sql.NullTime
+	ToIndex              sql.NullBool
+	S3File               string
+	S3FilePreview        sql.NullString
+	PdfLink              sql.NullString
+	CreatedAt            sql.NullTime
+	DeletedAt            sql.NullTime
+	ToDelete             bool
+	ToGeneratePreview    sql.NullBool
+	ContentHash          sql.NullString
+	ReviewState          string
+	ApprovedBy           uuid.NullUUID
+	ApprovedByName       sql.NullString
+	ApprovedAt           sql.NullTime
+	PublisherID          uuid.NullUUID
+	Language             sql.NullString
+	EnglishTitle         sql.NullString
+	EnglishAbstract      sql.NullString
+	TranslationGroup     uuid.NullUUID
+	Source               sql.NullString
+	AuthorNames          interface{}
+	RegionNames          interface{}
+	KeywordNames         interface{}
+	CategoryNames        interface{}
+	AuthorIds            []string
+	TranslationLanguages []string
+	TranslationFiles     []string
 }
 
 func (q *Queries) GetDocumentsByURIs(ctx context.Context, dollar_1 []string) ([]GetDocumentsByURIsRow, error) {
@@ -183,7 +246,6 @@ func (q *Queries) GetDocumentsByURIs(ctx context.Context, dollar_1 []string) ([]
 			&i.Title,
 			&i.Abstract,
 			&i.PublishDate,
-			&i.Source,
 			&i.ToIndex,
 			&i.S3File,
 			&i.S3FilePreview,
@@ -192,10 +254,24 @@ func (q *Queries) GetDocumentsByURIs(ctx context.Context, dollar_1 []string) ([]
 			&i.DeletedAt,
 			&i.ToDelete,
 			&i.ToGeneratePreview,
+			&i.ContentHash,
+			&i.ReviewState,
+			&i.ApprovedBy,
+			&i.ApprovedByName,
+			&i.ApprovedAt,
+			&i.PublisherID,
+			&i.Language,
+			&i.EnglishTitle,
+			&i.EnglishAbstract,
+			&i.TranslationGroup,
+			&i.Source,
 			&i.AuthorNames,
 			&i.RegionNames,
 			&i.KeywordNames,
 			&i.CategoryNames,
+			pq.Array(&i.AuthorIds),
+			pq.Array(&i.TranslationLanguages),
+			pq.Array(&i.TranslationFiles),
 		); err != nil {
 			return nil, err
 		}
@@ -211,7 +287,7 @@ func (q *Queries) GetDocumentsByURIs(ctx context.Context, dollar_1 []string) ([]
 }
 
 const searchDocumentsSorted = `-- name: SearchDocumentsSorted :many
-SELECT id, file_name, title, abstract, publish_date, source, to_index, s3_file, s3_file_preview, pdf_link, created_at, deleted_at, to_delete, to_generate_preview
+SELECT id, file_name, title, abstract, publish_date, to_index, s3_file, s3_file_preview, pdf_link, created_at, deleted_at, to_delete, to_generate_preview, content_hash, review_state, approved_by, approved_by_name, approved_at, publisher_id, language, english_title, english_abstract, translation_group
 FROM documents
 WHERE title     ILIKE '%' || $1 || '%'
    OR file_name ILIKE '%' || $1 || '%'
@@ -258,7 +334,6 @@ func (q *Queries) SearchDocumentsSorted(ctx context.Context, arg SearchDocuments
 			&i.Title,
 			&i.Abstract,
 			&i.PublishDate,
-			&i.Source,
 			&i.ToIndex,
 			&i.S3File,
 			&i.S3FilePreview,
@@ -267,6 +342,16 @@ func (q *Queries) SearchDocumentsSorted(ctx context.Context, arg SearchDocuments
 			&i.DeletedAt,
 			&i.ToDelete,
 			&i.ToGeneratePreview,
+			&i.ContentHash,
+			&i.ReviewState,
+			&i.ApprovedBy,
+			&i.ApprovedByName,
+			&i.ApprovedAt,
+			&i.PublisherID,
+			&i.Language,
+			&i.EnglishTitle,
+			&i.EnglishAbstract,
+			&i.TranslationGroup,
 		); err != nil {
 			return nil, err
 		}
@@ -15,6 +15,7 @@ import (
 const insertDocAuthor = `-- name: InsertDocAuthor :exec
 INSERT INTO doc_authors (id, doc_id, author_id)
 VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, author_id) DO NOTHING
 `
 
 type InsertDocAuthorParams struct {
@@ -31,6 +32,7 @@ func (q *Queries) InsertDocAuthor(ctx context.Context, arg InsertDocAuthorParams
 const insertDocCategory = `-- name: InsertDocCategory :exec
 INSERT INTO doc_categories (id, doc_id, category_id)
 VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, category_id) DO NOTHING
 `
 
 type InsertDocCategoryParams struct {
@@ -47,6 +49,7 @@ func (q *Queries) InsertDocCategory(ctx context.Context, arg InsertDocCategoryPa
 const insertDocKeyword = `-- name: InsertDocKeyword :exec
 INSERT INTO doc_keywords (id, doc_id, keyword_id)
 VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, keyword_id) DO NOTHING
 `
 
 type InsertDocKeywordParams struct {
@@ -63,6 +66,7 @@ func (q *Queries) InsertDocKeyword(ctx context.Context, arg InsertDocKeywordPara
 const insertDocRegion = `-- name: InsertDocRegion :exec
 INSERT INTO doc_regions (id, doc_id, region_id)
 VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, region_id) DO NOTHING
 `
 
 type InsertDocRegionParams struct {
@@ -85,17 +89,31 @@ INSERT INTO documents (
   abstract,
   publish_date,
   created_at,
-  to_delete
-) VALUES ($1, $2, $3, $4, $5, $6,NOW(), false)
+  to_delete,
+  content_hash,
+  publisher_id,
+  language,
+  english_title,
+  english_abstract,
+  review_state,
+  to_index
+) VALUES ($1, $2, $3, $4, $5, $6,NOW(), false, $7, $8, $9, $10, $11, $12, $13)
 `
 
 type InsertUploadedDocumentParams struct {
-	ID          uuid.UUID
-	S3File      string
-	FileName    string
-	Title       string
-	Abstract    sql.NullString
-	PublishDate sql.NullTime
+	ID              uuid.UUID
+	S3File          string
+	FileName        string
+	Title           string
+	Abstract        sql.NullString
+	PublishDate     sql.NullTime
+	ContentHash     sql.NullString
+	PublisherID     uuid.NullUUID
+	Language        sql.NullString
+	EnglishTitle    sql.NullString
+	EnglishAbstract sql.NullString
+	ReviewState     string
+	ToIndex         sql.NullBool
 }
 
 func (q *Queries) InsertUploadedDocument(ctx context.Context, arg InsertUploadedDocumentParams) error {
@@ -106,6 +124,13 @@ func (q *Queries) InsertUploadedDocument(ctx context.Context, arg InsertUploaded
 		arg.Title,
 		arg.Abstract,
 		arg.PublishDate,
+		arg.ContentHash,
+		arg.PublisherID,
+		arg.Language,
+		arg.EnglishTitle,
+		arg.EnglishAbstract,
+		arg.ReviewState,
+		arg.ToIndex,
 	)
 	return err
 }
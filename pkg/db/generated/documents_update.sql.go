@@ -35,18 +35,24 @@ SET
   title = $2,
   abstract = $3,
   publish_date = $4,
-  source = $5,
-  to_index = $6
+  publisher_id = $5,
+  language = $6,
+  english_title = $7,
+  english_abstract = $8,
+  to_index = $9
 WHERE id = $1
 `
 
 type UpdateDocumentMetadataParams struct {
-	ID          uuid.UUID
-	Title       string
-	Abstract    sql.NullString
-	PublishDate sql.NullTime
-	Source      sql.NullString
-	ToIndex     sql.NullBool
+	ID              uuid.UUID
+	Title           string
+	Abstract        sql.NullString
+	PublishDate     sql.NullTime
+	PublisherID     uuid.NullUUID
+	Language        sql.NullString
+	EnglishTitle    sql.NullString
+	EnglishAbstract sql.NullString
+	ToIndex         sql.NullBool
 }
 
 func (q *Queries) UpdateDocumentMetadata(ctx context.Context, arg UpdateDocumentMetadataParams) error {
@@ -55,7 +61,10 @@ func (q *Queries) UpdateDocumentMetadata(ctx context.Context, arg UpdateDocument
 		arg.Title,
 		arg.Abstract,
 		arg.PublishDate,
-		arg.Source,
+		arg.PublisherID,
+		arg.Language,
+		arg.EnglishTitle,
+		arg.EnglishAbstract,
 		arg.ToIndex,
 	)
 	return err
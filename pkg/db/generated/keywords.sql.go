@@ -13,32 +13,33 @@ import (
 )
 
 const findKeywordByName = `-- name: FindKeywordByName :one
-SELECT id, name FROM keywords WHERE LOWER(name) = LOWER($1) LIMIT 1
+SELECT id, name, approved FROM keywords WHERE LOWER(name) = LOWER($1) LIMIT 1
 `
 
 func (q *Queries) FindKeywordByName(ctx context.Context, lower string) (Keyword, error) {
 	row := q.db.QueryRowContext(ctx, findKeywordByName, lower)
 	var i Keyword
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Approved)
 	return i, err
 }
 
 const insertKeyword = `-- name: InsertKeyword :exec
-INSERT INTO keywords (id, name) VALUES ($1, $2)
+INSERT INTO keywords (id, name, approved) VALUES ($1, $2, $3)
 `
 
 type InsertKeywordParams struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
 }
 
 func (q *Queries) InsertKeyword(ctx context.Context, arg InsertKeywordParams) error {
-	_, err := q.db.ExecContext(ctx, insertKeyword, arg.ID, arg.Name)
+	_, err := q.db.ExecContext(ctx, insertKeyword, arg.ID, arg.Name, arg.Approved)
 	return err
 }
 
 const listAllKeywords = `-- name: ListAllKeywords :many
-SELECT id, name FROM keywords ORDER BY name
+SELECT id, name, approved FROM keywords ORDER BY name
 `
 
 func (q *Queries) ListAllKeywords(ctx context.Context) ([]Keyword, error) {
@@ -50,7 +51,7 @@ func (q *Queries) ListAllKeywords(ctx context.Context) ([]Keyword, error) {
 	var items []Keyword
 	for rows.Next() {
 		var i Keyword
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Approved); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
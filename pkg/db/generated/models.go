@@ -6,19 +6,62 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	RateLimit  int32
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
+type AuditEvent struct {
+	ID        uuid.UUID
+	ActorID   uuid.NullUUID
+	ActorName string
+	Action    string
+	DocID     uuid.NullUUID
+	Target    sql.NullString
+	Diff      json.RawMessage
+	CreatedAt time.Time
+}
+
 type Author struct {
-	ID   uuid.UUID
-	Name string
+	ID          uuid.UUID
+	Name        string
+	Approved    bool
+	Orcid       sql.NullString
+	Affiliation sql.NullString
+}
+
+type AuthorAlias struct {
+	AliasKey  string
+	Alias     string
+	AuthorID  uuid.UUID
+	CreatedAt time.Time
 }
 
 type Category struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
+}
+
+type CategoryAlias struct {
+	AliasKey   string
+	Alias      string
+	CategoryID uuid.UUID
+	CreatedAt  time.Time
 }
 
 type DocAuthor struct {
@@ -51,7 +94,6 @@ type Document struct {
 	Title             string
 	Abstract          sql.NullString
 	PublishDate       sql.NullTime
-	Source            sql.NullString
 	ToIndex           sql.NullBool
 	S3File            string
 	S3FilePreview     sql.NullString
@@ -60,6 +102,77 @@ type Document struct {
 	DeletedAt         sql.NullTime
 	ToDelete          bool
 	ToGeneratePreview sql.NullBool
+	ContentHash       sql.NullString
+	ReviewState       string
+	ApprovedBy        uuid.NullUUID
+	ApprovedByName    sql.NullString
+	ApprovedAt        sql.NullTime
+	PublisherID       uuid.NullUUID
+	Language          sql.NullString
+	EnglishTitle      sql.NullString
+	EnglishAbstract   sql.NullString
+	TranslationGroup  uuid.NullUUID
+}
+
+type DocumentFieldSource struct {
+	DocID     uuid.UUID
+	Field     string
+	Source    string
+	UpdatedAt time.Time
+}
+
+type DocumentPage struct {
+	ID           uuid.UUID
+	DocID        uuid.UUID
+	PageNumber   int32
+	Content      string
+	SearchVector interface{}
+}
+
+type DocumentRevision struct {
+	ID              uuid.UUID
+	DocID           uuid.UUID
+	Revision        int32
+	Title           string
+	Abstract        sql.NullString
+	PublishDate     sql.NullTime
+	Source          sql.NullString
+	Authors         []string
+	Keywords        []string
+	Regions         []string
+	Categories      []string
+	RestoredFrom    sql.NullInt32
+	CreatedBy       uuid.NullUUID
+	CreatedByName   string
+	CreatedAt       time.Time
+	Language        sql.NullString
+	EnglishTitle    sql.NullString
+	EnglishAbstract sql.NullString
+}
+
+type DocumentSearch struct {
+	DocID        uuid.UUID
+	SearchVector interface{}
+}
+
+type DocumentSyncStatus struct {
+	DocID         uuid.UUID
+	Operation     string
+	Status        string
+	Error         sql.NullString
+	Attempts      int32
+	LastAttemptAt time.Time
+	LastSuccessAt sql.NullTime
+}
+
+type ExtractionPrompt struct {
+	Version       int32
+	Template      string
+	Note          string
+	RestoredFrom  sql.NullInt32
+	CreatedBy     uuid.NullUUID
+	CreatedByName string
+	CreatedAt     time.Time
 }
 
 type FlywaySchemaHistory struct {
@@ -75,20 +188,115 @@ type FlywaySchemaHistory struct {
 	Success       bool
 }
 
+type IngestBatchFile struct {
+	ID            uuid.UUID
+	BatchID       uuid.UUID
+	Position      int32
+	FileName      string
+	Outcome       string
+	Message       sql.NullString
+	JobID         uuid.NullUUID
+	DocID         uuid.NullUUID
+	CreatedBy     uuid.NullUUID
+	CreatedByName string
+	CreatedAt     time.Time
+}
+
+type IngestJob struct {
+	ID               uuid.UUID
+	DocID            uuid.UUID
+	FileName         string
+	S3File           string
+	ContentType      string
+	ContentHash      string
+	FileData         []byte
+	Stage            string
+	Status           string
+	Pages            []string
+	Metadata         json.RawMessage
+	Error            sql.NullString
+	Attempts         int32
+	CreatedBy        uuid.NullUUID
+	CreatedByName    string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	ProvidedMetadata json.RawMessage
+}
+
 type Keyword struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
+}
+
+type KeywordAlias struct {
+	AliasKey  string
+	Alias     string
+	KeywordID uuid.UUID
+	CreatedAt time.Time
+}
+
+type MetadataExtraction struct {
+	ID            uuid.UUID
+	JobID         uuid.NullUUID
+	DocID         uuid.UUID
+	Extractor     string
+	Model         string
+	PromptVersion string
+	CreatedAt     time.Time
+	InputTokens   int32
+	OutputTokens  int32
+	Cost          sql.NullFloat64
+	LatencyMs     int32
+	CreatedBy     uuid.NullUUID
+	CreatedByName string
+	Error         sql.NullString
+}
+
+type Publisher struct {
+	ID       uuid.UUID
+	Name     string
+	Approved bool
+}
+
+type PublisherAlias struct {
+	AliasKey    string
+	Alias       string
+	PublisherID uuid.UUID
+	CreatedAt   time.Time
 }
 
 type Region struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
+	ParentID uuid.NullUUID
+	IsoCode  sql.NullString
+}
+
+type RegionAlias struct {
+	AliasKey  string
+	Alias     string
+	RegionID  uuid.UUID
+	CreatedAt time.Time
 }
 
 type User struct {
 	Username     string
 	PasswordHash string
-	IsMaster     bool
 	CreatedAt    time.Time
 	ID           uuid.UUID
+	Role         string
+}
+
+type VocabularyCategory struct {
+	CategoryID uuid.UUID
+}
+
+type VocabularyKeyword struct {
+	KeywordID uuid.UUID
+}
+
+type VocabularyRegion struct {
+	RegionID uuid.UUID
 }
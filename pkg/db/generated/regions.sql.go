@@ -13,32 +13,39 @@ import (
 )
 
 const findRegionByName = `-- name: FindRegionByName :one
-SELECT id, name FROM regions WHERE LOWER(name) = LOWER($1) LIMIT 1
+SELECT id, name, approved, parent_id, iso_code FROM regions WHERE LOWER(name) = LOWER($1) LIMIT 1
 `
 
 func (q *Queries) FindRegionByName(ctx context.Context, lower string) (Region, error) {
 	row := q.db.QueryRowContext(ctx, findRegionByName, lower)
 	var i Region
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Approved,
+		&i.ParentID,
+		&i.IsoCode,
+	)
 	return i, err
 }
 
 const insertRegion = `-- name: InsertRegion :exec
-INSERT INTO regions (id, name) VALUES ($1, $2)
+INSERT INTO regions (id, name, approved) VALUES ($1, $2, $3)
 `
 
 type InsertRegionParams struct {
-	ID   uuid.UUID
-	Name string
+	ID       uuid.UUID
+	Name     string
+	Approved bool
 }
 
 func (q *Queries) InsertRegion(ctx context.Context, arg InsertRegionParams) error {
-	_, err := q.db.ExecContext(ctx, insertRegion, arg.ID, arg.Name)
+	_, err := q.db.ExecContext(ctx, insertRegion, arg.ID, arg.Name, arg.Approved)
 	return err
 }
 
 const listAllRegions = `-- name: ListAllRegions :many
-SELECT id, name FROM regions ORDER BY name
+SELECT id, name, approved, parent_id, iso_code FROM regions ORDER BY name
 `
 
 func (q *Queries) ListAllRegions(ctx context.Context) ([]Region, error) {
@@ -50,7 +57,13 @@ func (q *Queries) ListAllRegions(ctx context.Context) ([]Region, error) {
 	var items []Region
 	for rows.Next() {
 		var i Region
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Approved,
+			&i.ParentID,
+			&i.IsoCode,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -12,19 +12,30 @@ import (
 	"github.com/google/uuid"
 )
 
+const countUsersWithRole = `-- name: CountUsersWithRole :one
+SELECT count(*) FROM users WHERE role = $1
+`
+
+func (q *Queries) CountUsersWithRole(ctx context.Context, role string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsersWithRole, role)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :exec
-INSERT INTO users (username, password_hash, is_master)
+INSERT INTO users (username, password_hash, role)
 VALUES ($1, $2, $3)
 `
 
 type CreateUserParams struct {
 	Username     string
 	PasswordHash string
-	IsMaster     bool
+	Role         string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
-	_, err := q.db.ExecContext(ctx, createUser, arg.Username, arg.PasswordHash, arg.IsMaster)
+	_, err := q.db.ExecContext(ctx, createUser, arg.Username, arg.PasswordHash, arg.Role)
 	return err
 }
 
@@ -38,7 +49,7 @@ func (q *Queries) DeleteUserByUsername(ctx context.Context, username string) err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, password_hash, is_master, created_at
+SELECT id, username, password_hash, role, created_at
 FROM users
 WHERE id = $1
 `
@@ -47,7 +58,7 @@ type GetUserByIDRow struct {
 	ID           uuid.UUID
 	Username     string
 	PasswordHash string
-	IsMaster     bool
+	Role         string
 	CreatedAt    time.Time
 }
 
@@ -58,14 +69,14 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow
 		&i.ID,
 		&i.Username,
 		&i.PasswordHash,
-		&i.IsMaster,
+		&i.Role,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT username, password_hash, is_master, created_at, id FROM users WHERE username = $1
+SELECT username, password_hash, created_at, id, role FROM users WHERE username = $1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -74,20 +85,20 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 	err := row.Scan(
 		&i.Username,
 		&i.PasswordHash,
-		&i.IsMaster,
 		&i.CreatedAt,
 		&i.ID,
+		&i.Role,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT username, is_master FROM users ORDER BY username
+SELECT username, role FROM users ORDER BY username
 `
 
 type ListUsersRow struct {
 	Username string
-	IsMaster bool
+	Role     string
 }
 
 func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
@@ -99,7 +110,7 @@ func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
 	var items []ListUsersRow
 	for rows.Next() {
 		var i ListUsersRow
-		if err := rows.Scan(&i.Username, &i.IsMaster); err != nil {
+		if err := rows.Scan(&i.Username, &i.Role); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -112,3 +123,17 @@ func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
 	}
 	return items, nil
 }
+
+const updateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users SET role = $2 WHERE username = $1
+`
+
+type UpdateUserRoleParams struct {
+	Username string
+	Role     string
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserRole, arg.Username, arg.Role)
+	return err
+}
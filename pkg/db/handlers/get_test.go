@@ -78,7 +78,7 @@ func (suite *HandlersTestSuite) SetupTest() {
 			Title:           "Document 1",
 			Abstract:        sql.NullString{String: "Abstract 1", Valid: true},
 			PublishDate:     suite.now,
-			Source:          sql.NullString{String: "Source 1", Valid: true},
+			PublisherID:     uuid.NullUUID{UUID: uuid.New(), Valid: true},
 			ToIndex: 		 sql.NullBool{Bool: false, Valid: true},
 			S3File:          "doc1.pdf",
 			S3FilePreview:   sql.NullString{String: "preview1.pdf", Valid: true},
@@ -92,7 +92,7 @@ func (suite *HandlersTestSuite) SetupTest() {
 			Title:           "Document 2",
 			Abstract:        sql.NullString{String: "Abstract 2", Valid: true},
 			PublishDate:     suite.now,
-			Source:          sql.NullString{String: "Source 2", Valid: true},
+			PublisherID:     uuid.NullUUID{UUID: uuid.New(), Valid: true},
 			ToIndex: 		 sql.NullBool{Bool: true, Valid: true},
 			S3File:          "doc2.pdf",
 			S3FilePreview:   sql.NullString{String: "preview2.pdf", Valid: true},
@@ -0,0 +1,16 @@
+-- Outcome of the last Kendra sync attempt for each document.
+-- operation is 'index' or 'delete'; status is 'indexed', 'deleted', 'skipped' or 'failed'.
+
+-- 1. Create the sync status table
+CREATE TABLE IF NOT EXISTS document_sync_status (
+    doc_id uuid PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    operation varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,
+    error text,
+    attempts integer DEFAULT 1 NOT NULL,
+    last_attempt_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_success_at timestamp without time zone
+);
+
+-- 2. Create index for finding failures
+CREATE INDEX IF NOT EXISTS idx_document_sync_status_status ON document_sync_status(status);
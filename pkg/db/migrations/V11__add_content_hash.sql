@@ -0,0 +1,8 @@
+-- SHA-256 of each document's file, used to catch byte-identical uploads
+-- and to group duplicates for review. Existing rows are filled in by cmd/backfill-hashes.
+
+-- 1. Add the column
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS content_hash varchar(64);
+
+-- 2. Create index (not unique: existing duplicates are resolved through the review page)
+CREATE INDEX IF NOT EXISTS idx_documents_content_hash ON documents(content_hash);
@@ -0,0 +1,20 @@
+-- API keys for scripts and integrations. Only the SHA-256 of each key is stored;
+-- prefix is kept in clear so admins can tell keys apart.
+-- rate_limit is the number of requests allowed per minute.
+
+-- 1. Create the API key table
+CREATE TABLE IF NOT EXISTS api_keys (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    prefix varchar(16) NOT NULL,
+    key_hash varchar(64) NOT NULL UNIQUE,
+    scopes text[] DEFAULT '{}' NOT NULL,
+    rate_limit integer DEFAULT 60 NOT NULL,
+    expires_at timestamp without time zone,
+    last_used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+-- 2. Create index for listing a user's keys
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
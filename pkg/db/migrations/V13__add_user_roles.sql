@@ -0,0 +1,13 @@
+-- Replace the is_master flag with a role. Permissions for each role are defined
+-- in pkg/services/roles.go. Existing masters become admins; everyone else keeps
+-- the access they had as editors.
+
+-- 1. Add the role column
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(20) DEFAULT 'editor' NOT NULL
+    CONSTRAINT users_role_check CHECK (role IN ('viewer', 'editor', 'reviewer', 'admin'));
+
+-- 2. Carry over masters
+UPDATE users SET role = 'admin' WHERE is_master;
+
+-- 3. Drop the old flag
+ALTER TABLE users DROP COLUMN IF EXISTS is_master;
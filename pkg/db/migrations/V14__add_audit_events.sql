@@ -0,0 +1,20 @@
+-- Who changed what. diff holds {"field": {"before": ..., "after": ...}} for the
+-- fields an action changed. actor_name is kept so history still reads correctly
+-- after the user is deleted, and doc_id has no foreign key so history outlives
+-- the document.
+
+-- 1. Create the audit table
+CREATE TABLE IF NOT EXISTS audit_events (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    actor_id uuid REFERENCES users(id) ON DELETE SET NULL,
+    actor_name text NOT NULL,
+    action varchar(50) NOT NULL,
+    doc_id uuid,
+    target text,
+    diff jsonb DEFAULT '{}' NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+-- 2. Create indexes for per-document history and recent activity
+CREATE INDEX IF NOT EXISTS idx_audit_events_doc_id ON audit_events(doc_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at DESC);
@@ -0,0 +1,51 @@
+-- Every metadata save stores the full editable state of the document as a new
+-- revision, so an earlier version can be compared against or restored. Rows are
+-- never updated; restoring an old revision adds a new one with restored_from set.
+
+-- 1. Create the revisions table
+CREATE TABLE IF NOT EXISTS document_revisions (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    doc_id uuid NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    revision integer NOT NULL,
+    title text NOT NULL,
+    abstract text,
+    publish_date date,
+    source character varying(255),
+    authors text[] DEFAULT '{}' NOT NULL,
+    keywords text[] DEFAULT '{}' NOT NULL,
+    regions text[] DEFAULT '{}' NOT NULL,
+    categories text[] DEFAULT '{}' NOT NULL,
+    restored_from integer,
+    created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT document_revisions_doc_id_revision_key UNIQUE (doc_id, revision)
+);
+
+-- 2. Reject updates so revisions stay as they were saved
+CREATE OR REPLACE FUNCTION document_revisions_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    RAISE EXCEPTION 'document revisions cannot be modified';
+END;
+$$;
+
+CREATE TRIGGER document_revisions_no_update BEFORE UPDATE ON document_revisions
+    FOR EACH ROW EXECUTE FUNCTION document_revisions_immutable();
+
+-- 3. Record the current state of existing documents as their first revision
+INSERT INTO document_revisions (doc_id, revision, title, abstract, publish_date, source,
+                                authors, keywords, regions, categories, created_by_name)
+SELECT d.id, 1, d.title, d.abstract, d.publish_date, d.source,
+       ARRAY(SELECT a.name FROM doc_authors da JOIN authors a ON a.id = da.author_id
+             WHERE da.doc_id = d.id ORDER BY a.name),
+       ARRAY(SELECT k.name FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+             WHERE dk.doc_id = d.id ORDER BY k.name),
+       ARRAY(SELECT r.name FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+             WHERE dr.doc_id = d.id ORDER BY r.name),
+       ARRAY(SELECT c.name FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+             WHERE dc.doc_id = d.id ORDER BY c.name),
+       'system'
+FROM documents d
+ON CONFLICT (doc_id, revision) DO NOTHING;
@@ -0,0 +1,29 @@
+-- Uploads are accepted into ingest_jobs and processed in the background. stage is
+-- the last stage that completed, so a failed job resumes from the next one when it
+-- is retried. The uploaded file is kept in file_data until the document is saved.
+
+-- 1. Create the ingest jobs table
+CREATE TABLE IF NOT EXISTS ingest_jobs (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    doc_id uuid NOT NULL,
+    file_name character varying(255) NOT NULL,
+    s3_file character varying(1024) NOT NULL,
+    content_type character varying(255) NOT NULL,
+    content_hash character varying(64) NOT NULL,
+    file_data bytea,
+    stage character varying(32) DEFAULT 'received' NOT NULL,
+    status character varying(16) DEFAULT 'pending' NOT NULL,
+    pages text[],
+    metadata jsonb DEFAULT '{}' NOT NULL,
+    error text,
+    attempts integer DEFAULT 0 NOT NULL,
+    created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT ingest_jobs_stage_check CHECK (stage IN ('received', 'stored', 'text_extracted', 'metadata_extracted', 'saved')),
+    CONSTRAINT ingest_jobs_status_check CHECK (status IN ('pending', 'running', 'failed', 'done'))
+);
+
+-- 2. Index unfinished jobs, which are resumed on startup
+CREATE INDEX IF NOT EXISTS idx_ingest_jobs_status ON ingest_jobs (status) WHERE status <> 'done';
@@ -0,0 +1,23 @@
+-- A multi-file or ZIP upload is a batch. Every file in it gets a row here with
+-- the outcome of accepting it: queued as an ingest job, skipped as a duplicate,
+-- or failed (for example an unsupported file inside an archive).
+
+-- 1. Create the batch files table
+CREATE TABLE IF NOT EXISTS ingest_batch_files (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    batch_id uuid NOT NULL,
+    position integer NOT NULL,
+    file_name character varying(1024) NOT NULL,
+    outcome character varying(16) NOT NULL,
+    message text,
+    job_id uuid REFERENCES ingest_jobs(id) ON DELETE SET NULL,
+    doc_id uuid REFERENCES documents(id) ON DELETE SET NULL,
+    created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT ingest_batch_files_batch_id_position_key UNIQUE (batch_id, position),
+    CONSTRAINT ingest_batch_files_outcome_check CHECK (outcome IN ('queued', 'duplicate', 'failed'))
+);
+
+-- 2. Find unfinished jobs by content hash, to catch duplicates still in flight
+CREATE INDEX IF NOT EXISTS idx_ingest_jobs_content_hash ON ingest_jobs (content_hash) WHERE status <> 'done';
@@ -0,0 +1,5 @@
+-- A manifest import supplies some or all of a document's metadata. It is kept
+-- with the job so that Bedrock only fills in the fields the manifest left blank.
+
+-- 1. Add the metadata supplied with the upload
+ALTER TABLE ingest_jobs ADD COLUMN IF NOT EXISTS provided_metadata jsonb DEFAULT '{}'::jsonb NOT NULL;
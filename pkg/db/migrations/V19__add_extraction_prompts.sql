@@ -0,0 +1,1026 @@
+-- Metadata extraction reads its prompt and vocabularies from the database, so
+-- they can be changed without a redeploy. Prompts are versioned: the newest
+-- version is used, and rows are never updated. Restoring an old version adds a
+-- new one with restored_from set. Each extraction records the prompt version and
+-- model that produced it.
+
+-- 1. Create the prompt versions table
+CREATE TABLE IF NOT EXISTS extraction_prompts (
+    version integer PRIMARY KEY,
+    template text NOT NULL,
+    note text DEFAULT '' NOT NULL,
+    restored_from integer,
+    created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+-- 2. Store the prompt that was built into the Bedrock client as version 1
+INSERT INTO extraction_prompts (version, template, note, created_by_name)
+VALUES (1, $prompt$You are an assistant extracting structured metadata from an academic policy document.
+
+Prefer to select from the following known lists if relevant:
+
+CATEGORIES:
+{{.Categories}}
+
+REGIONS:
+{{.Regions}}
+
+KEYWORDS:
+{{.Keywords}}
+
+Normalize all values by removing dashes and replacing them with spaces. For example, "conflict-resolution" becomes "conflict resolution".
+All string fields must be enclosed in double quotes.
+Double quotes inside any string **must** be escaped using a backslash: use \", never ” or “. Do not escape any characters that are not quotes.
+Do not include any preamble, explanation, commentary, or non-JSON output — just return the JSON.
+Only generate regions that are widely known and well-represented in global datasets and literature.
+Focus on fully recognized countries or broad, commonly referenced geographic areas (e.g., Central America, Southeast Asia).
+Avoid small, obscure, or low-data regions (e.g., Kurdistan, Upper Nile, Northern Ireland), as these are less likely to be relevant or supported by sufficient context.
+
+
+Return only a valid JSON object with the following fields:
+- "title" (string, required)
+- "abstract" (string)
+- "category" (string, max 100 characters): e.g., article, research paper, etc.
+- "publish_date" (date)
+- "source" (string, max 255 characters): use "bucket" as a placeholder
+- "region_name" (array of unique strings, required, max 10)
+- "keyword_name" (array of unique strings, required, max 10)
+- "author_name" (array of unique strings, required, max 10)
+- "category_name" (array of unique strings, required, max 10)
+
+Do not explain. Do not say "Here is the JSON". Do not use Markdown. Just return the JSON object.
+TEXT:
+{{.Text}}
+$prompt$, 'Initial prompt', 'system')
+ON CONFLICT (version) DO NOTHING;
+
+-- 3. Create the vocabulary tables, which mark the categories, regions and
+--    keywords that are listed in the prompt
+CREATE TABLE IF NOT EXISTS vocabulary_categories (
+    category_id uuid PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS vocabulary_regions (
+    region_id uuid PRIMARY KEY REFERENCES regions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS vocabulary_keywords (
+    keyword_id uuid PRIMARY KEY REFERENCES keywords(id) ON DELETE CASCADE
+);
+
+-- 4. Seed the vocabularies with the lists that were built in or read from
+--    KEYWORDS_FILE_PATH, creating the terms that do not exist yet
+CREATE TEMPORARY TABLE seed_categories (name text NOT NULL);
+INSERT INTO seed_categories (name) VALUES
+    ('article'),
+    ('background paper'),
+    ('blog post'),
+    ('book'),
+    ('brief'),
+    ('case study'),
+    ('dataset'),
+    ('educational guide'),
+    ('evaluation'),
+    ('fact sheet'),
+    ('government report'),
+    ('organizational study'),
+    ('paper'),
+    ('policy brief'),
+    ('policy paper'),
+    ('project evaluation'),
+    ('project evaluations'),
+    ('report'),
+    ('working paper');
+
+CREATE TEMPORARY TABLE seed_regions (name text NOT NULL);
+INSERT INTO seed_regions (name) VALUES
+    ('Afghanistan'),
+    ('Africa'),
+    ('Albania'),
+    ('Angola'),
+    ('Asia'),
+    ('Bangladesh'),
+    ('Benin'),
+    ('Bosnia And Herzegovina'),
+    ('Burkina Faso'),
+    ('Burundi'),
+    ('Cambodia'),
+    ('Caribean'),
+    ('Central African Republic Car'),
+    ('Central America'),
+    ('Democratic Republic Of Congo Drc'),
+    ('Democratic Republic Of Congo Drc / Central African Republic Car'),
+    ('Ecuador'),
+    ('Egypt'),
+    ('El Salvador'),
+    ('Ethiopia'),
+    ('Europe'),
+    ('Georgia'),
+    ('Ghana'),
+    ('Global'),
+    ('Guatemala'),
+    ('Guinea'),
+    ('Indonesia'),
+    ('Indo Pacific'),
+    ('Iraq'),
+    ('Israel'),
+    ('Jamaica'),
+    ('Jerusalem'),
+    ('Jordan'),
+    ('Kenya'),
+    ('Kosovo'),
+    ('Kyrgyzstan'),
+    ('Latin America'),
+    ('Lebanon'),
+    ('Liberia'),
+    ('Macedonia'),
+    ('Madagascar'),
+    ('Mali'),
+    ('Middle East'),
+    ('Morocco'),
+    ('Myanmar'),
+    ('Nepal'),
+    ('Nigeria'),
+    ('North America'),
+    ('Oceana'),
+    ('Oceania'),
+    ('Pakistan'),
+    ('Papua New Guinea'),
+    ('Peru'),
+    ('Philippines'),
+    ('Russia'),
+    ('Rwanda'),
+    ('Senegal'),
+    ('Somalia'),
+    ('South Africa'),
+    ('South America'),
+    ('South Sudan'),
+    ('Sri Lanka'),
+    ('Sudan'),
+    ('Tajikistan'),
+    ('Tanzania'),
+    ('Timor Leste'),
+    ('Uganda'),
+    ('Ukraine'),
+    ('West Bank'),
+    ('Yemen'),
+    ('Zambia'),
+    ('Zimbabwe');
+
+CREATE TEMPORARY TABLE seed_keywords (name text NOT NULL);
+INSERT INTO seed_keywords (name) VALUES
+    ('abuse'),
+    ('accessibility'),
+    ('access to justice'),
+    ('access to water'),
+    ('accountability'),
+    ('acholi'),
+    ('active citizenship'),
+    ('adr'),
+    ('adversarial approaches'),
+    ('adversarial groups'),
+    ('advocacy'),
+    ('agreements'),
+    ('aid'),
+    ('alliances'),
+    ('alternative dispute resolution'),
+    ('amnesty'),
+    ('and evaluation dm&e'),
+    ('anti corruption'),
+    ('anti government elements'),
+    ('apprenticeships'),
+    ('arbitration'),
+    ('arcss agreement on the resolution of the conflict in the republic of south sudan'),
+    ('armed conflict'),
+    ('armed non state actors'),
+    ('armed violence'),
+    ('art'),
+    ('at risk youth'),
+    ('attitudes'),
+    ('awareness'),
+    ('baseline'),
+    ('baseline indicators'),
+    ('behavior change'),
+    ('benchmark'),
+    ('borderland brokers'),
+    ('bureau of conflict and stabilization'),
+    ('business'),
+    ('business and peace'),
+    ('business development'),
+    ('business environment'),
+    ('c2p'),
+    ('capacity building'),
+    ('care facilities'),
+    ('case studies'),
+    ('cash transfers'),
+    ('castes'),
+    ('cbcpm'),
+    ('cbos'),
+    ('ceasefire'),
+    ('ceasefire data'),
+    ('central sahel'),
+    ('child marriage'),
+    ('children'),
+    ('citizen action'),
+    ('citizen engagement'),
+    ('citizen participation'),
+    ('citizens'),
+    ('civic education'),
+    ('civic engagement'),
+    ('civic integration'),
+    ('civic leadership trainings'),
+    ('civic responsibility'),
+    ('civilians'),
+    ('civilian threats'),
+    ('civil leaders'),
+    ('civil military relations'),
+    ('civil society'),
+    ('civil society engagement'),
+    ('civil society organizations'),
+    ('civil war'),
+    ('climate'),
+    ('climate and conflict'),
+    ('climate and peacebuiliding'),
+    ('climate change'),
+    ('climate related risks'),
+    ('clinics'),
+    ('cm'),
+    ('coalition building'),
+    ('coexistence'),
+    ('cognitive mapping'),
+    ('cohabitation'),
+    ('collaboration'),
+    ('combatants'),
+    ('combatants to peacemakers'),
+    ('committees'),
+    ('commmunity participation'),
+    ('communal conflict'),
+    ('communal violence'),
+    ('communication'),
+    ('communications  media strategies'),
+    ('communications  public relations'),
+    ('community'),
+    ('community based child protection mechanisms'),
+    ('community based organizations'),
+    ('community development'),
+    ('community dispute'),
+    ('community engagement'),
+    ('community knowledge'),
+    ('community leaders'),
+    ('community level'),
+    ('community mobilization'),
+    ('complaint systems'),
+    ('conceptual framework'),
+    ('concerts'),
+    ('conflict'),
+    ('conflict affected groups'),
+    ('conflict analysis'),
+    ('conflict context'),
+    ('conflict data'),
+    ('conflict dynamics'),
+    ('conflict escalation'),
+    ('conflict evolution'),
+    ('conflict experiences'),
+    ('conflict identification'),
+    ('conflict management'),
+    ('conflict management and resolution'),
+    ('conflict mapping'),
+    ('conflict mediation'),
+    ('conflict mitigation'),
+    ('conflict prevention'),
+    ('conflict prevention and early warning'),
+    ('conflict related deaths'),
+    ('conflict resolution'),
+    ('conflict response'),
+    ('conflict scans'),
+    ('conflict sensitivity'),
+    ('conflict stakeholders'),
+    ('conflict transformation'),
+    ('constituency dialogues'),
+    ('cooperation'),
+    ('coordination'),
+    ('corruption'),
+    ('corruption and conflict'),
+    ('cost of conflict'),
+    ('countering violent extremism'),
+    ('country level'),
+    ('county level'),
+    ('court delays'),
+    ('covid 19'),
+    ('crime'),
+    ('crime prevention'),
+    ('criminal activity'),
+    ('crisis'),
+    ('csos'),
+    ('cultural events'),
+    ('cultural violence'),
+    ('customary peace agreements'),
+    ('customs officials'),
+    ('cve'),
+    ('cyclical conflict'),
+    ('data'),
+    ('data collection tools'),
+    ('ddr'),
+    ('debate clubs'),
+    ('debates'),
+    ('decentralization'),
+    ('decision makers'),
+    ('decision making'),
+    ('decision making forums'),
+    ('demobilization'),
+    ('democracy'),
+    ('democracy and governance'),
+    ('design'),
+    ('desk review'),
+    ('development'),
+    ('diagnosis'),
+    ('dialogue'),
+    ('dialogue facilitation'),
+    ('digital security'),
+    ('diplomacy'),
+    ('diplomacy  track 1'),
+    ('diplomacy  track 2'),
+    ('direct conflict deaths'),
+    ('disarmament'),
+    ('discrimination'),
+    ('discussion groups'),
+    ('disease'),
+    ('disputants'),
+    ('dispute management'),
+    ('dispute resolution'),
+    ('district level'),
+    ('diversity'),
+    ('do no harm'),
+    ('donors'),
+    ('drama'),
+    ('drought resilience'),
+    ('drug trafficking'),
+    ('early recovery'),
+    ('early response'),
+    ('early warning'),
+    ('early warning and early response'),
+    ('early warning systems'),
+    ('ebola'),
+    ('economic activities'),
+    ('economic activity'),
+    ('economic development'),
+    ('economic foundations'),
+    ('economic fragility'),
+    ('economic inequities'),
+    ('economic recovery'),
+    ('economics  and conflict'),
+    ('economics and conflict'),
+    ('economy'),
+    ('education'),
+    ('effectiveness'),
+    ('elected leaders'),
+    ('election'),
+    ('elections'),
+    ('election violence'),
+    ('electoral violence'),
+    ('empathy'),
+    ('empirical evidence'),
+    ('employees'),
+    ('employment'),
+    ('employment generation'),
+    ('entrepreneurship'),
+    ('equity'),
+    ('ethnic groups'),
+    ('ethnic minorities'),
+    ('ethnic tension'),
+    ('eudemonia'),
+    ('european engagement'),
+    ('evaluation'),
+    ('evidence based practice'),
+    ('ewer'),
+    ('ex combatants'),
+    ('extremism'),
+    ('facilitation'),
+    ('failed states'),
+    ('farmers'),
+    ('field research'),
+    ('field test'),
+    ('field work'),
+    ('financial sustainability'),
+    ('financing peace'),
+    ('fiscal decentralization'),
+    ('focus groups'),
+    ('food insecurity'),
+    ('forums'),
+    ('fragibility'),
+    ('fragility'),
+    ('fragility and investment'),
+    ('frameworks'),
+    ('funding peacebuilding'),
+    ('gang violence'),
+    ('gbv'),
+    ('gender'),
+    ('gender based violence'),
+    ('gender discrimination'),
+    ('gender equality'),
+    ('gender mainstreaming'),
+    ('girls'),
+    ('good governance'),
+    ('governance'),
+    ('governance and regulation'),
+    ('governance  constitutions'),
+    ('governance  democracy'),
+    ('governance  power sharing'),
+    ('governance power sharing'),
+    ('governance  reform'),
+    ('governance reform'),
+    ('governance  reforms'),
+    ('governance strategies'),
+    ('governance  transition'),
+    ('governance  transition. mediation'),
+    ('governance  transitition'),
+    ('government'),
+    ('grassroots'),
+    ('guidance'),
+    ('hate speech'),
+    ('healing'),
+    ('health'),
+    ('holy sites'),
+    ('horizontal inequalities'),
+    ('households'),
+    ('household surveys'),
+    ('human capacity building'),
+    ('humanitarian engagement'),
+    ('humanitarian response'),
+    ('human rights'),
+    ('human rights abuse'),
+    ('human rights standards'),
+    ('human rights  transitional justice'),
+    ('human rights violations'),
+    ('human security'),
+    ('humiliation'),
+    ('hunger and violence'),
+    ('identity and conflict'),
+    ('idps'),
+    ('implementation'),
+    ('inclusion'),
+    ('inclusive'),
+    ('inclusive peace'),
+    ('inclusive peacebuilding'),
+    ('inclusive peacebuilidng'),
+    ('inclusive peace processes'),
+    ('income'),
+    ('income generating activities'),
+    ('income levels'),
+    ('indicator resource'),
+    ('indicators'),
+    ('indirect conflict deaths'),
+    ('information campaigns'),
+    ('information flow'),
+    ('information technology'),
+    ('infrastructure'),
+    ('instability'),
+    ('institutional capacity building'),
+    ('institutional fragility'),
+    ('institutional reform'),
+    ('institutions'),
+    ('integration'),
+    ('inter communal conflict'),
+    ('intercommunal conflict'),
+    ('intercommunal violence'),
+    ('inter community'),
+    ('inter ethnic'),
+    ('inter ethnic integration'),
+    ('inter ethnic peace committees'),
+    ('interfaith collaboration'),
+    ('interfaith cooperation'),
+    ('inter group dialogue'),
+    ('inter group interactions'),
+    ('inter group perceptions'),
+    ('internally displaced people'),
+    ('internally displaced persons/refugees'),
+    ('internally generated funds'),
+    ('international community'),
+    ('international courts'),
+    ('international laws'),
+    ('international level'),
+    ('interpersonal violence'),
+    ('intervention'),
+    ('interviews'),
+    ('intra communal conflict'),
+    ('intra community'),
+    ('invest'),
+    ('islam'),
+    ('it'),
+    ('joint economic activity'),
+    ('judges'),
+    ('judiciary system'),
+    ('justice'),
+    ('justice sector'),
+    ('kabupaten level'),
+    ('knowledge building'),
+    ('kota level'),
+    ('lack of contact and peacebuilding'),
+    ('land'),
+    ('land allocation'),
+    ('land disputes'),
+    ('land law'),
+    ('land reform'),
+    ('land tenure'),
+    ('law enforcement'),
+    ('leaders'),
+    ('leadership'),
+    ('leadership trainings'),
+    ('legal aid'),
+    ('legal clinic'),
+    ('legal frameworks'),
+    ('legal literacy'),
+    ('legal reform'),
+    ('legal rights'),
+    ('legal system'),
+    ('legislators'),
+    ('legitimate politics'),
+    ('lga'),
+    ('light weapons'),
+    ('literature review'),
+    ('livelihood access'),
+    ('livelihoods'),
+    ('local'),
+    ('local action'),
+    ('local actors'),
+    ('local authorities'),
+    ('local community activism'),
+    ('local conflict'),
+    ('local council'),
+    ('local courts'),
+    ('local governance'),
+    ('local government'),
+    ('local government associations'),
+    ('local institutions'),
+    ('localization'),
+    ('local judges'),
+    ('local leaders'),
+    ('locally led'),
+    ('locally led peacemaking initiatives'),
+    ('locally led peacemaking  interreligious'),
+    ('locally led peacemaking  women led'),
+    ('local mechanisms'),
+    ('local networks'),
+    ('local peacebuilding'),
+    ('local peacebuiliding'),
+    ('local peace initiative'),
+    ('local peacemaking'),
+    ('local peace systems'),
+    ('lord''s resistance army'),
+    ('lra'),
+    ('madi'),
+    ('management'),
+    ('maputo protocol'),
+    ('marches'),
+    ('marginalized groups'),
+    ('measurement'),
+    ('media'),
+    ('media surveys'),
+    ('mediation'),
+    ('mediation committee'),
+    ('medium enterprise sectors'),
+    ('members of the national assembly'),
+    ('men'),
+    ('mentorship'),
+    ('messaging'),
+    ('meta analysis'),
+    ('micro enterprise sectors'),
+    ('micro level'),
+    ('migration and conflict'),
+    ('military'),
+    ('military spending'),
+    ('mining'),
+    ('mitigation'),
+    ('mnas'),
+    ('mobilization'),
+    ('modernization'),
+    ('monitoring'),
+    ('monitoring and evaluation'),
+    ('monitoring and evaluation dm&e'),
+    ('monitoring and reporting'),
+    ('monitoring and verification'),
+    ('monitoring/verifiaction  third party'),
+    ('monitoring/verification'),
+    ('monitoring/verification  local'),
+    ('monitoring/verification  regional organization'),
+    ('monitoring/verification  regional organizationm'),
+    ('monitoring/verification  third party'),
+    ('monitoring/verification  united nations'),
+    ('morbidity'),
+    ('mortality'),
+    ('muftiate'),
+    ('multi track diplomacy'),
+    ('municipal level'),
+    ('muslim'),
+    ('mutual interest'),
+    ('mutual trust'),
+    ('national development'),
+    ('national human rights commission'),
+    ('national identity'),
+    ('national integration'),
+    ('national land policy'),
+    ('national laws'),
+    ('national level'),
+    ('national reconciliation'),
+    ('natural resources'),
+    ('natural resources and conflict'),
+    ('needs'),
+    ('negotiation'),
+    ('negotiations'),
+    ('networks'),
+    ('network strengthening'),
+    ('news program'),
+    ('ngos'),
+    ('non governmental organizations'),
+    ('nonstate'),
+    ('non state actors'),
+    ('non state institutions'),
+    ('non violence'),
+    ('northern triangle'),
+    ('nsas'),
+    ('nuclear posture'),
+    ('operations management'),
+    ('opinions'),
+    ('opportunity grants'),
+    ('organized crime'),
+    ('organized violence'),
+    ('outreach activities'),
+    ('pad'),
+    ('parliament'),
+    ('parliamentarians'),
+    ('participation'),
+    ('participatory'),
+    ('participatory decision making'),
+    ('partnerships'),
+    ('pastoralists'),
+    ('peace'),
+    ('peace activities'),
+    ('peace agreement'),
+    ('peace agreements'),
+    ('peace architecture'),
+    ('peace architecture dialogues'),
+    ('peacebuilders'),
+    ('peacebuilding'),
+    ('peacebuilding and peacemaking'),
+    ('peacebuilding and state goals'),
+    ('peace committees'),
+    ('peace councils'),
+    ('peace dividends'),
+    ('peace events'),
+    ('peace festivals'),
+    ('peacekeeping'),
+    ('peacemakers'),
+    ('peacemaking'),
+    ('peace messaging'),
+    ('peace process'),
+    ('peace processes'),
+    ('peace processes  implementation'),
+    ('peace processes  inclusion'),
+    ('peace processes  strategies'),
+    ('peace process strategies'),
+    ('peace strategy'),
+    ('peace sustainability'),
+    ('perceptions'),
+    ('perceptions of corruption'),
+    ('perceptions of threat'),
+    ('performance management'),
+    ('persons with disabilities'),
+    ('pesantrens'),
+    ('physical security'),
+    ('pluralism'),
+    ('police'),
+    ('police engagement'),
+    ('policy'),
+    ('policy advocacy'),
+    ('policymakers'),
+    ('political engagement'),
+    ('political groups'),
+    ('political inequities'),
+    ('political institutions'),
+    ('political knowledge'),
+    ('political leaders'),
+    ('political manipulation'),
+    ('political mission'),
+    ('political participation'),
+    ('political parties'),
+    ('political reconciliation'),
+    ('political transition'),
+    ('political violence'),
+    ('positive masculinity'),
+    ('post conflict'),
+    ('post conflict peacebuilding'),
+    ('post conflict policy'),
+    ('post conflict society'),
+    ('post war'),
+    ('poverty'),
+    ('power'),
+    ('presidential amnesty'),
+    ('prevention'),
+    ('preventive diplomacy'),
+    ('priorities'),
+    ('private enterprises'),
+    ('private sector and peacebuilding'),
+    ('problem solving workshop'),
+    ('processes'),
+    ('program evaluation'),
+    ('program learning'),
+    ('programs'),
+    ('project evaluation'),
+    ('property rights'),
+    ('protection'),
+    ('protective factors'),
+    ('protracted conflict'),
+    ('provincial level'),
+    ('psgs'),
+    ('psychosocial services'),
+    ('public'),
+    ('public health'),
+    ('public opinion'),
+    ('public opinions'),
+    ('public perceptions'),
+    ('public policy'),
+    ('public safety'),
+    ('public sector'),
+    ('public works project'),
+    ('quantitative evidence'),
+    ('questionnaire'),
+    ('radio'),
+    ('rapid conflict pulse surveys'),
+    ('ratification  constitution'),
+    ('ratification  peace agreement'),
+    ('reccomendations'),
+    ('receiving communities'),
+    ('reconciliation'),
+    ('reconcilitation'),
+    ('reconstruction'),
+    ('recovery'),
+    ('recreational activities'),
+    ('referenda  independence'),
+    ('reflection'),
+    ('reform'),
+    ('refugees'),
+    ('regional groups'),
+    ('regional level'),
+    ('reintegration'),
+    ('reintegration ddr'),
+    ('relationship building'),
+    ('relative depravation'),
+    ('relief'),
+    ('religion'),
+    ('religion and conflict'),
+    ('religious education'),
+    ('religious extremism'),
+    ('religious freedom'),
+    ('religious groups'),
+    ('religious leaders'),
+    ('religious tolerance'),
+    ('religious traditions'),
+    ('religious violence'),
+    ('reparation'),
+    ('reparations'),
+    ('reporting'),
+    ('research'),
+    ('researchers'),
+    ('residents'),
+    ('resilience'),
+    ('resistance'),
+    ('resist violence'),
+    ('resources'),
+    ('respect'),
+    ('response'),
+    ('responsibilities'),
+    ('responsive democracy'),
+    ('returnee communities'),
+    ('returnees'),
+    ('return zones'),
+    ('rights'),
+    ('risk'),
+    ('roles'),
+    ('root causes'),
+    ('rule of law'),
+    ('rural'),
+    ('safety'),
+    ('sanctions'),
+    ('schools'),
+    ('sdg'),
+    ('sdg 16'),
+    ('seasonal migration'),
+    ('secondary schools'),
+    ('security'),
+    ('security forces'),
+    ('security reform'),
+    ('security sector'),
+    ('seeds of peace'),
+    ('sensitivity'),
+    ('services'),
+    ('sexual violence'),
+    ('sgbv'),
+    ('shame'),
+    ('shocks'),
+    ('short message service'),
+    ('skills development'),
+    ('small arms'),
+    ('small enterprise sectors'),
+    ('sms'),
+    ('social activities'),
+    ('social capital'),
+    ('social coexistence'),
+    ('social cohesion'),
+    ('social conflict'),
+    ('social engagement'),
+    ('social harmony'),
+    ('social inequities'),
+    ('social integration'),
+    ('social norms'),
+    ('social peace'),
+    ('social reconstruction'),
+    ('social services'),
+    ('social stability'),
+    ('social transformation'),
+    ('social violence'),
+    ('societal acceptance'),
+    ('societal stability'),
+    ('society'),
+    ('socio economic costs'),
+    ('socio economic development'),
+    ('socio economic groups'),
+    ('solutions'),
+    ('sports'),
+    ('stability'),
+    ('stabilization'),
+    ('stakeholder meetings'),
+    ('stakeholders'),
+    ('state building'),
+    ('statebuilding'),
+    ('state failure'),
+    ('state governance'),
+    ('state government'),
+    ('state institutions'),
+    ('state level'),
+    ('statistical analysis'),
+    ('stories'),
+    ('strengths'),
+    ('stresses'),
+    ('structural violence'),
+    ('students'),
+    ('students'' participation'),
+    ('study'),
+    ('subnational messaging'),
+    ('summer camp'),
+    ('support services'),
+    ('survey'),
+    ('survey module'),
+    ('surveys'),
+    ('survivors'),
+    ('susceptibility'),
+    ('sustainability'),
+    ('sustainable development'),
+    ('sustainable development goals'),
+    ('sustainable environment'),
+    ('sustainable income'),
+    ('sustained peace'),
+    ('task force'),
+    ('teachers'),
+    ('technical assistance'),
+    ('technical trainings'),
+    ('technology'),
+    ('television'),
+    ('terrorism'),
+    ('theater'),
+    ('theatre'),
+    ('theories of change'),
+    ('theory of change'),
+    ('third party'),
+    ('toc'),
+    ('tolerance'),
+    ('tools'),
+    ('town halls'),
+    ('track ii'),
+    ('traders'),
+    ('trading'),
+    ('traditional leaders'),
+    ('traditional peace agreements'),
+    ('training'),
+    ('trainings'),
+    ('transition'),
+    ('transitional justice'),
+    ('transition processes'),
+    ('transitions'),
+    ('transnational organized crime'),
+    ('transnational societies'),
+    ('transparency'),
+    ('trasnsitional justice'),
+    ('trauma'),
+    ('trauma state'),
+    ('treatment facilities'),
+    ('trends'),
+    ('tribes'),
+    ('trust'),
+    ('trust building'),
+    ('tv'),
+    ('tvet'),
+    ('un'),
+    ('united nations'),
+    ('united nations mission'),
+    ('unmarried girls'),
+    ('urban'),
+    ('u report'),
+    ('vaw'),
+    ('victims'),
+    ('victims of conflict'),
+    ('violations'),
+    ('violence'),
+    ('violence against women'),
+    ('violence mitigation'),
+    ('violence prevention'),
+    ('violence reduction'),
+    ('violence resistance'),
+    ('violent conflict'),
+    ('violent extremism'),
+    ('vocation'),
+    ('vocational trainings'),
+    ('vocs'),
+    ('voter registration'),
+    ('voters'),
+    ('vulnerability'),
+    ('vulnerable'),
+    ('vulnerable groups'),
+    ('war'),
+    ('warning'),
+    ('war peace transitions'),
+    ('war prevention'),
+    ('war torn societies'),
+    ('water quality'),
+    ('weaknesses'),
+    ('well being'),
+    ('white paper'),
+    ('women'),
+    ('women leaders'),
+    ('women political leaders'),
+    ('women''s empowerment'),
+    ('women''s land rights'),
+    ('women''s participation'),
+    ('women''s rights'),
+    ('women''s rights organization'),
+    ('workshops'),
+    ('wps'),
+    ('young leaders'),
+    ('young men'),
+    ('youth'),
+    ('youth employment'),
+    ('youth engagement'),
+    ('youth leadership'),
+    ('youth led organizations'),
+    ('youth mobilization'),
+    ('youth perceptions'),
+    ('youth to youth research');
+
+INSERT INTO categories (name)
+SELECT s.name FROM seed_categories s
+WHERE NOT EXISTS (SELECT 1 FROM categories c WHERE LOWER(c.name) = LOWER(s.name));
+
+INSERT INTO regions (name)
+SELECT s.name FROM seed_regions s
+WHERE NOT EXISTS (SELECT 1 FROM regions r WHERE LOWER(r.name) = LOWER(s.name));
+
+INSERT INTO keywords (name)
+SELECT s.name FROM seed_keywords s
+WHERE NOT EXISTS (SELECT 1 FROM keywords k WHERE LOWER(k.name) = LOWER(s.name));
+
+INSERT INTO vocabulary_categories (category_id)
+SELECT DISTINCT ON (LOWER(c.name)) c.id
+FROM categories c JOIN seed_categories s ON LOWER(c.name) = LOWER(s.name)
+ORDER BY LOWER(c.name), c.id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO vocabulary_regions (region_id)
+SELECT DISTINCT ON (LOWER(r.name)) r.id
+FROM regions r JOIN seed_regions s ON LOWER(r.name) = LOWER(s.name)
+ORDER BY LOWER(r.name), r.id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO vocabulary_keywords (keyword_id)
+SELECT DISTINCT ON (LOWER(k.name)) k.id
+FROM keywords k JOIN seed_keywords s ON LOWER(k.name) = LOWER(s.name)
+ORDER BY LOWER(k.name), k.id
+ON CONFLICT DO NOTHING;
+
+DROP TABLE seed_categories;
+DROP TABLE seed_regions;
+DROP TABLE seed_keywords;
+
+-- 5. Create the extractions table. doc_id has no foreign key because metadata
+--    is extracted before the document is saved.
+CREATE TABLE IF NOT EXISTS metadata_extractions (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    job_id uuid REFERENCES ingest_jobs(id) ON DELETE SET NULL,
+    doc_id uuid NOT NULL,
+    extractor character varying(32) NOT NULL,
+    model character varying(255) DEFAULT '' NOT NULL,
+    prompt_version character varying(64) DEFAULT '' NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_metadata_extractions_job_id ON metadata_extractions (job_id);
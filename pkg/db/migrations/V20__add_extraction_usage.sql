@@ -0,0 +1,22 @@
+-- Each extraction records the tokens it used, what they cost and how long it
+-- took, so that spend can be reported by day and by user. Extractions that
+-- used the model but failed are recorded too, with the error, since they were
+-- paid for. cost is NULL when the model has no configured price.
+
+-- 1. Add usage, cost and latency
+ALTER TABLE metadata_extractions
+    ADD COLUMN IF NOT EXISTS input_tokens integer DEFAULT 0 NOT NULL,
+    ADD COLUMN IF NOT EXISTS output_tokens integer DEFAULT 0 NOT NULL,
+    ADD COLUMN IF NOT EXISTS cost double precision,
+    ADD COLUMN IF NOT EXISTS latency_ms integer DEFAULT 0 NOT NULL;
+
+-- 2. Add who uploaded the document, kept by name in case the user is deleted
+ALTER TABLE metadata_extractions
+    ADD COLUMN IF NOT EXISTS created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS created_by_name character varying(255) DEFAULT '' NOT NULL;
+
+-- 3. Add the error of a failed extraction
+ALTER TABLE metadata_extractions ADD COLUMN IF NOT EXISTS error text;
+
+-- 4. Index the reports' date range
+CREATE INDEX IF NOT EXISTS idx_metadata_extractions_created_at ON metadata_extractions (created_at);
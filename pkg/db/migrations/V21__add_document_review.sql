@@ -0,0 +1,25 @@
+-- Documents uploaded since the metadata extractors arrived carry fields written
+-- by a model. Each document now has a review state, and each field records
+-- whether its value came from the model (llm) or a person (human). Documents
+-- that already exist were curated by hand, so they start approved.
+
+-- 1. Add the review state and who approved the document, kept by name in case
+--    the user is deleted
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS review_state varchar(20) DEFAULT 'approved' NOT NULL
+        CONSTRAINT documents_review_state_check CHECK (review_state IN ('ai_extracted', 'in_review', 'approved')),
+    ADD COLUMN IF NOT EXISTS approved_by uuid REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS approved_by_name character varying(255),
+    ADD COLUMN IF NOT EXISTS approved_at timestamp without time zone;
+
+-- 2. Index the reviewer queue
+CREATE INDEX IF NOT EXISTS idx_documents_review_state ON documents (created_at) WHERE review_state <> 'approved';
+
+-- 3. Create the per-field provenance table
+CREATE TABLE IF NOT EXISTS document_field_sources (
+    doc_id uuid NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    field varchar(32) NOT NULL,
+    source varchar(10) NOT NULL CONSTRAINT document_field_sources_source_check CHECK (source IN ('llm', 'human')),
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (doc_id, field)
+);
@@ -0,0 +1,47 @@
+-- New author, keyword, region and category names used to be created for any
+-- unseen spelling. Names are now matched to existing terms through an alias
+-- table per taxonomy, and terms that match nothing are created unapproved until
+-- an admin approves them or maps them to an existing term. Existing terms stay
+-- approved.
+
+-- 1. Flag terms awaiting approval
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS approved boolean DEFAULT true NOT NULL;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS approved boolean DEFAULT true NOT NULL;
+ALTER TABLE keywords ADD COLUMN IF NOT EXISTS approved boolean DEFAULT true NOT NULL;
+ALTER TABLE regions ADD COLUMN IF NOT EXISTS approved boolean DEFAULT true NOT NULL;
+
+-- 2. Create the alias tables, keyed by the alias folded to lower case without
+--    diacritics or punctuation
+CREATE TABLE IF NOT EXISTS author_aliases (
+    alias_key character varying(255) PRIMARY KEY,
+    alias character varying(255) NOT NULL,
+    author_id uuid NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS category_aliases (
+    alias_key character varying(255) PRIMARY KEY,
+    alias character varying(255) NOT NULL,
+    category_id uuid NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS keyword_aliases (
+    alias_key character varying(255) PRIMARY KEY,
+    alias character varying(255) NOT NULL,
+    keyword_id uuid NOT NULL REFERENCES keywords(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS region_aliases (
+    alias_key character varying(255) PRIMARY KEY,
+    alias character varying(255) NOT NULL,
+    region_id uuid NOT NULL REFERENCES regions(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- 3. Index the aliases by term
+CREATE INDEX IF NOT EXISTS idx_author_aliases_author_id ON author_aliases (author_id);
+CREATE INDEX IF NOT EXISTS idx_category_aliases_category_id ON category_aliases (category_id);
+CREATE INDEX IF NOT EXISTS idx_keyword_aliases_keyword_id ON keyword_aliases (keyword_id);
+CREATE INDEX IF NOT EXISTS idx_region_aliases_region_id ON region_aliases (region_id);
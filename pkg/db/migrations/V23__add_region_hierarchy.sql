@@ -0,0 +1,297 @@
+-- Regions were a flat list mixing countries, continents and blocs, so filtering
+-- on "Africa" missed documents tagged only "Kenya". Regions now have a parent,
+-- and countries an ISO 3166-1 alpha-2 code. The continents and every ISO 3166
+-- country are added, each country under its continent; existing regions with
+-- the same name, ignoring case, take the code and parent instead of being
+-- duplicated. Blocs such as "Middle East" stay at the top until an admin gives
+-- them children.
+
+-- 1. Add the hierarchy and the country codes
+ALTER TABLE regions ADD COLUMN IF NOT EXISTS parent_id uuid REFERENCES regions(id) ON DELETE SET NULL;
+ALTER TABLE regions ADD COLUMN IF NOT EXISTS iso_code character varying(2);
+ALTER TABLE regions ADD CONSTRAINT regions_iso_code_key UNIQUE (iso_code);
+ALTER TABLE regions ADD CONSTRAINT regions_parent_check CHECK (parent_id <> id);
+CREATE INDEX IF NOT EXISTS idx_regions_parent_id ON regions (parent_id);
+
+-- 2. Stage the countries with their continents
+CREATE TEMPORARY TABLE region_seed (
+    iso_code character varying(2) PRIMARY KEY,
+    name character varying(255) NOT NULL,
+    continent character varying(255)
+) ON COMMIT DROP;
+
+INSERT INTO region_seed (iso_code, name, continent) VALUES
+    ('AD', 'Andorra', 'Europe'),
+    ('AE', 'United Arab Emirates', 'Asia'),
+    ('AF', 'Afghanistan', 'Asia'),
+    ('AG', 'Antigua and Barbuda', 'North America'),
+    ('AI', 'Anguilla', 'North America'),
+    ('AL', 'Albania', 'Europe'),
+    ('AM', 'Armenia', 'Asia'),
+    ('AO', 'Angola', 'Africa'),
+    ('AQ', 'Antarctica', NULL),
+    ('AR', 'Argentina', 'South America'),
+    ('AS', 'American Samoa', 'Oceania'),
+    ('AT', 'Austria', 'Europe'),
+    ('AU', 'Australia', 'Oceania'),
+    ('AW', 'Aruba', 'North America'),
+    ('AX', 'Åland Islands', 'Europe'),
+    ('AZ', 'Azerbaijan', 'Asia'),
+    ('BA', 'Bosnia and Herzegovina', 'Europe'),
+    ('BB', 'Barbados', 'North America'),
+    ('BD', 'Bangladesh', 'Asia'),
+    ('BE', 'Belgium', 'Europe'),
+    ('BF', 'Burkina Faso', 'Africa'),
+    ('BG', 'Bulgaria', 'Europe'),
+    ('BH', 'Bahrain', 'Asia'),
+    ('BI', 'Burundi', 'Africa'),
+    ('BJ', 'Benin', 'Africa'),
+    ('BL', 'Saint Barthélemy', 'North America'),
+    ('BM', 'Bermuda', 'North America'),
+    ('BN', 'Brunei', 'Asia'),
+    ('BO', 'Bolivia', 'South America'),
+    ('BQ', 'Caribbean Netherlands', 'North America'),
+    ('BR', 'Brazil', 'South America'),
+    ('BS', 'Bahamas', 'North America'),
+    ('BT', 'Bhutan', 'Asia'),
+    ('BV', 'Bouvet Island', 'Antarctica'),
+    ('BW', 'Botswana', 'Africa'),
+    ('BY', 'Belarus', 'Europe'),
+    ('BZ', 'Belize', 'North America'),
+    ('CA', 'Canada', 'North America'),
+    ('CC', 'Cocos (Keeling) Islands', 'Asia'),
+    ('CD', 'Democratic Republic of the Congo', 'Africa'),
+    ('CF', 'Central African Republic', 'Africa'),
+    ('CG', 'Republic of the Congo', 'Africa'),
+    ('CH', 'Switzerland', 'Europe'),
+    ('CI', 'Côte d''Ivoire', 'Africa'),
+    ('CK', 'Cook Islands', 'Oceania'),
+    ('CL', 'Chile', 'South America'),
+    ('CM', 'Cameroon', 'Africa'),
+    ('CN', 'China', 'Asia'),
+    ('CO', 'Colombia', 'South America'),
+    ('CR', 'Costa Rica', 'North America'),
+    ('CU', 'Cuba', 'North America'),
+    ('CV', 'Cabo Verde', 'Africa'),
+    ('CW', 'Curaçao', 'North America'),
+    ('CX', 'Christmas Island', 'Asia'),
+    ('CY', 'Cyprus', 'Asia'),
+    ('CZ', 'Czechia', 'Europe'),
+    ('DE', 'Germany', 'Europe'),
+    ('DJ', 'Djibouti', 'Africa'),
+    ('DK', 'Denmark', 'Europe'),
+    ('DM', 'Dominica', 'North America'),
+    ('DO', 'Dominican Republic', 'North America'),
+    ('DZ', 'Algeria', 'Africa'),
+    ('EC', 'Ecuador', 'South America'),
+    ('EE', 'Estonia', 'Europe'),
+    ('EG', 'Egypt', 'Africa'),
+    ('EH', 'Western Sahara', 'Africa'),
+    ('ER', 'Eritrea', 'Africa'),
+    ('ES', 'Spain', 'Europe'),
+    ('ET', 'Ethiopia', 'Africa'),
+    ('FI', 'Finland', 'Europe'),
+    ('FJ', 'Fiji', 'Oceania'),
+    ('FK', 'Falkland Islands', 'South America'),
+    ('FM', 'Micronesia', 'Oceania'),
+    ('FO', 'Faroe Islands', 'Europe'),
+    ('FR', 'France', 'Europe'),
+    ('GA', 'Gabon', 'Africa'),
+    ('GB', 'United Kingdom', 'Europe'),
+    ('GD', 'Grenada', 'North America'),
+    ('GE', 'Georgia', 'Asia'),
+    ('GF', 'French Guiana', 'South America'),
+    ('GG', 'Guernsey', 'Europe'),
+    ('GH', 'Ghana', 'Africa'),
+    ('GI', 'Gibraltar', 'Europe'),
+    ('GL', 'Greenland', 'North America'),
+    ('GM', 'Gambia', 'Africa'),
+    ('GN', 'Guinea', 'Africa'),
+    ('GP', 'Guadeloupe', 'North America'),
+    ('GQ', 'Equatorial Guinea', 'Africa'),
+    ('GR', 'Greece', 'Europe'),
+    ('GS', 'South Georgia and the South Sandwich Islands', 'Antarctica'),
+    ('GT', 'Guatemala', 'North America'),
+    ('GU', 'Guam', 'Oceania'),
+    ('GW', 'Guinea-Bissau', 'Africa'),
+    ('GY', 'Guyana', 'South America'),
+    ('HK', 'Hong Kong', 'Asia'),
+    ('HM', 'Heard Island and McDonald Islands', 'Antarctica'),
+    ('HN', 'Honduras', 'North America'),
+    ('HR', 'Croatia', 'Europe'),
+    ('HT', 'Haiti', 'North America'),
+    ('HU', 'Hungary', 'Europe'),
+    ('ID', 'Indonesia', 'Asia'),
+    ('IE', 'Ireland', 'Europe'),
+    ('IL', 'Israel', 'Asia'),
+    ('IM', 'Isle of Man', 'Europe'),
+    ('IN', 'India', 'Asia'),
+    ('IO', 'British Indian Ocean Territory', 'Asia'),
+    ('IQ', 'Iraq', 'Asia'),
+    ('IR', 'Iran', 'Asia'),
+    ('IS', 'Iceland', 'Europe'),
+    ('IT', 'Italy', 'Europe'),
+    ('JE', 'Jersey', 'Europe'),
+    ('JM', 'Jamaica', 'North America'),
+    ('JO', 'Jordan', 'Asia'),
+    ('JP', 'Japan', 'Asia'),
+    ('KE', 'Kenya', 'Africa'),
+    ('KG', 'Kyrgyzstan', 'Asia'),
+    ('KH', 'Cambodia', 'Asia'),
+    ('KI', 'Kiribati', 'Oceania'),
+    ('KM', 'Comoros', 'Africa'),
+    ('KN', 'Saint Kitts and Nevis', 'North America'),
+    ('KP', 'North Korea', 'Asia'),
+    ('KR', 'South Korea', 'Asia'),
+    ('KW', 'Kuwait', 'Asia'),
+    ('KY', 'Cayman Islands', 'North America'),
+    ('KZ', 'Kazakhstan', 'Asia'),
+    ('LA', 'Laos', 'Asia'),
+    ('LB', 'Lebanon', 'Asia'),
+    ('LC', 'Saint Lucia', 'North America'),
+    ('LI', 'Liechtenstein', 'Europe'),
+    ('LK', 'Sri Lanka', 'Asia'),
+    ('LR', 'Liberia', 'Africa'),
+    ('LS', 'Lesotho', 'Africa'),
+    ('LT', 'Lithuania', 'Europe'),
+    ('LU', 'Luxembourg', 'Europe'),
+    ('LV', 'Latvia', 'Europe'),
+    ('LY', 'Libya', 'Africa'),
+    ('MA', 'Morocco', 'Africa'),
+    ('MC', 'Monaco', 'Europe'),
+    ('MD', 'Moldova', 'Europe'),
+    ('ME', 'Montenegro', 'Europe'),
+    ('MF', 'Saint Martin', 'North America'),
+    ('MG', 'Madagascar', 'Africa'),
+    ('MH', 'Marshall Islands', 'Oceania'),
+    ('MK', 'North Macedonia', 'Europe'),
+    ('ML', 'Mali', 'Africa'),
+    ('MM', 'Myanmar', 'Asia'),
+    ('MN', 'Mongolia', 'Asia'),
+    ('MO', 'Macao', 'Asia'),
+    ('MP', 'Northern Mariana Islands', 'Oceania'),
+    ('MQ', 'Martinique', 'North America'),
+    ('MR', 'Mauritania', 'Africa'),
+    ('MS', 'Montserrat', 'North America'),
+    ('MT', 'Malta', 'Europe'),
+    ('MU', 'Mauritius', 'Africa'),
+    ('MV', 'Maldives', 'Asia'),
+    ('MW', 'Malawi', 'Africa'),
+    ('MX', 'Mexico', 'North America'),
+    ('MY', 'Malaysia', 'Asia'),
+    ('MZ', 'Mozambique', 'Africa'),
+    ('NA', 'Namibia', 'Africa'),
+    ('NC', 'New Caledonia', 'Oceania'),
+    ('NE', 'Niger', 'Africa'),
+    ('NF', 'Norfolk Island', 'Oceania'),
+    ('NG', 'Nigeria', 'Africa'),
+    ('NI', 'Nicaragua', 'North America'),
+    ('NL', 'Netherlands', 'Europe'),
+    ('NO', 'Norway', 'Europe'),
+    ('NP', 'Nepal', 'Asia'),
+    ('NR', 'Nauru', 'Oceania'),
+    ('NU', 'Niue', 'Oceania'),
+    ('NZ', 'New Zealand', 'Oceania'),
+    ('OM', 'Oman', 'Asia'),
+    ('PA', 'Panama', 'North America'),
+    ('PE', 'Peru', 'South America'),
+    ('PF', 'French Polynesia', 'Oceania'),
+    ('PG', 'Papua New Guinea', 'Oceania'),
+    ('PH', 'Philippines', 'Asia'),
+    ('PK', 'Pakistan', 'Asia'),
+    ('PL', 'Poland', 'Europe'),
+    ('PM', 'Saint Pierre and Miquelon', 'North America'),
+    ('PN', 'Pitcairn Islands', 'Oceania'),
+    ('PR', 'Puerto Rico', 'North America'),
+    ('PS', 'Palestine', 'Asia'),
+    ('PT', 'Portugal', 'Europe'),
+    ('PW', 'Palau', 'Oceania'),
+    ('PY', 'Paraguay', 'South America'),
+    ('QA', 'Qatar', 'Asia'),
+    ('RE', 'Réunion', 'Africa'),
+    ('RO', 'Romania', 'Europe'),
+    ('RS', 'Serbia', 'Europe'),
+    ('RU', 'Russia', 'Europe'),
+    ('RW', 'Rwanda', 'Africa'),
+    ('SA', 'Saudi Arabia', 'Asia'),
+    ('SB', 'Solomon Islands', 'Oceania'),
+    ('SC', 'Seychelles', 'Africa'),
+    ('SD', 'Sudan', 'Africa'),
+    ('SE', 'Sweden', 'Europe'),
+    ('SG', 'Singapore', 'Asia'),
+    ('SH', 'Saint Helena, Ascension and Tristan da Cunha', 'Africa'),
+    ('SI', 'Slovenia', 'Europe'),
+    ('SJ', 'Svalbard and Jan Mayen', 'Europe'),
+    ('SK', 'Slovakia', 'Europe'),
+    ('SL', 'Sierra Leone', 'Africa'),
+    ('SM', 'San Marino', 'Europe'),
+    ('SN', 'Senegal', 'Africa'),
+    ('SO', 'Somalia', 'Africa'),
+    ('SR', 'Suriname', 'South America'),
+    ('SS', 'South Sudan', 'Africa'),
+    ('ST', 'São Tomé and Príncipe', 'Africa'),
+    ('SV', 'El Salvador', 'North America'),
+    ('SX', 'Sint Maarten', 'North America'),
+    ('SY', 'Syria', 'Asia'),
+    ('SZ', 'Eswatini', 'Africa'),
+    ('TC', 'Turks and Caicos Islands', 'North America'),
+    ('TD', 'Chad', 'Africa'),
+    ('TF', 'French Southern Territories', 'Antarctica'),
+    ('TG', 'Togo', 'Africa'),
+    ('TH', 'Thailand', 'Asia'),
+    ('TJ', 'Tajikistan', 'Asia'),
+    ('TK', 'Tokelau', 'Oceania'),
+    ('TL', 'Timor-Leste', 'Asia'),
+    ('TM', 'Turkmenistan', 'Asia'),
+    ('TN', 'Tunisia', 'Africa'),
+    ('TO', 'Tonga', 'Oceania'),
+    ('TR', 'Türkiye', 'Asia'),
+    ('TT', 'Trinidad and Tobago', 'North America'),
+    ('TV', 'Tuvalu', 'Oceania'),
+    ('TW', 'Taiwan', 'Asia'),
+    ('TZ', 'Tanzania', 'Africa'),
+    ('UA', 'Ukraine', 'Europe'),
+    ('UG', 'Uganda', 'Africa'),
+    ('UM', 'United States Minor Outlying Islands', 'Oceania'),
+    ('US', 'United States', 'North America'),
+    ('UY', 'Uruguay', 'South America'),
+    ('UZ', 'Uzbekistan', 'Asia'),
+    ('VA', 'Vatican City', 'Europe'),
+    ('VC', 'Saint Vincent and the Grenadines', 'North America'),
+    ('VE', 'Venezuela', 'South America'),
+    ('VG', 'British Virgin Islands', 'North America'),
+    ('VI', 'U.S. Virgin Islands', 'North America'),
+    ('VN', 'Vietnam', 'Asia'),
+    ('VU', 'Vanuatu', 'Oceania'),
+    ('WF', 'Wallis and Futuna', 'Oceania'),
+    ('WS', 'Samoa', 'Oceania'),
+    ('YE', 'Yemen', 'Asia'),
+    ('YT', 'Mayotte', 'Africa'),
+    ('ZA', 'South Africa', 'Africa'),
+    ('ZM', 'Zambia', 'Africa'),
+    ('ZW', 'Zimbabwe', 'Africa');
+
+-- 3. Add the continents that do not exist yet
+INSERT INTO regions (id, name)
+SELECT gen_random_uuid(), c.name
+FROM (SELECT DISTINCT continent AS name FROM region_seed WHERE continent IS NOT NULL) c
+WHERE NOT EXISTS (SELECT 1 FROM regions r WHERE LOWER(r.name) = LOWER(c.name));
+
+-- 4. Give existing countries their code and, unless an admin already chose
+--    one, their continent
+UPDATE regions r
+SET iso_code = s.iso_code,
+    parent_id = COALESCE(r.parent_id, (
+        SELECT p.id FROM regions p WHERE LOWER(p.name) = LOWER(s.continent) ORDER BY p.name LIMIT 1
+    ))
+FROM region_seed s
+WHERE r.id = (SELECT x.id FROM regions x WHERE LOWER(x.name) = LOWER(s.name) ORDER BY x.name LIMIT 1)
+  AND r.iso_code IS NULL;
+
+-- 5. Add the remaining countries
+INSERT INTO regions (id, name, iso_code, parent_id)
+SELECT gen_random_uuid(), s.name, s.iso_code, (
+    SELECT p.id FROM regions p WHERE LOWER(p.name) = LOWER(s.continent) ORDER BY p.name LIMIT 1
+)
+FROM region_seed s
+WHERE NOT EXISTS (SELECT 1 FROM regions r WHERE LOWER(r.name) = LOWER(s.name));
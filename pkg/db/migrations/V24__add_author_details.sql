@@ -0,0 +1,10 @@
+-- Authors were only a name, so two spellings of one person could not be told
+-- apart and a person's work could not be listed. Authors now have an optional
+-- ORCID iD, stored in its hyphenated form (0000-0002-1825-0097), and an
+-- affiliation. An ORCID iD belongs to one author at most.
+
+-- 1. Add the ORCID iD and the affiliation
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS orcid character varying(19);
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS affiliation character varying(255);
+ALTER TABLE authors ADD CONSTRAINT authors_orcid_key UNIQUE (orcid);
+ALTER TABLE authors ADD CONSTRAINT authors_orcid_check CHECK (orcid ~ '^[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{3}[0-9X]$');
@@ -0,0 +1,64 @@
+-- A document's source was free text, so one publisher was spelled several
+-- ways and the extraction prompt filled it with the placeholder "bucket".
+-- Publishers are now a taxonomy like authors and keywords, with aliases and
+-- approval, and each document refers to at most one of them. Existing sources
+-- become publishers, one per spelling that differs only in case or spacing,
+-- named after its most common spelling. Placeholders are dropped.
+
+-- 1. Create the publishers and their aliases
+CREATE TABLE IF NOT EXISTS publishers (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    name character varying(255) NOT NULL UNIQUE,
+    approved boolean DEFAULT true NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS publisher_aliases (
+    alias_key character varying(255) PRIMARY KEY,
+    alias character varying(255) NOT NULL,
+    publisher_id uuid NOT NULL REFERENCES publishers(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_publisher_aliases_publisher_id ON publisher_aliases (publisher_id);
+
+-- 2. Refer to the publisher from each document
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS publisher_id uuid REFERENCES publishers(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_documents_publisher_id ON documents (publisher_id);
+
+-- 3. Turn the existing sources into publishers
+WITH sources AS (
+    SELECT id, regexp_replace(btrim(source), '\s+', ' ', 'g') AS name
+    FROM documents
+    WHERE source IS NOT NULL
+),
+spellings AS (
+    SELECT LOWER(name) AS key, name, COUNT(*) AS uses
+    FROM sources
+    WHERE name <> '' AND LOWER(name) NOT IN ('bucket', 'source', 'unknown', 'n/a', 'none')
+    GROUP BY LOWER(name), name
+)
+INSERT INTO publishers (name)
+SELECT DISTINCT ON (key) name
+FROM spellings
+ORDER BY key, uses DESC, name
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE documents d
+SET publisher_id = p.id
+FROM publishers p
+WHERE LOWER(p.name) = LOWER(regexp_replace(btrim(d.source), '\s+', ' ', 'g'));
+
+-- 4. Drop the free-text source
+ALTER TABLE documents DROP COLUMN IF EXISTS source;
+
+-- 5. Ask the model for the publisher rather than a placeholder, in a new
+--    version of the newest prompt if it still has the placeholder
+INSERT INTO extraction_prompts (version, template, note, created_by_name)
+SELECT version + 1,
+       replace(template, '"source" (string, max 255 characters): use "bucket" as a placeholder',
+               '"source" (string, max 255 characters): the organisation that published the document, such as a university, think tank, NGO or UN agency; omit it if the document does not say'),
+       'Ask for the publisher instead of a placeholder source',
+       'system'
+FROM extraction_prompts
+WHERE version = (SELECT MAX(version) FROM extraction_prompts)
+  AND template LIKE '%use "bucket" as a placeholder%';
@@ -0,0 +1,86 @@
+-- Documents were assumed to be in English. Each now records the language it is
+-- written in, detected from its text at upload, and non-English documents keep
+-- an English translation of their title and abstract so that English searches
+-- find them. Translations of the same document share a translation group.
+-- Existing documents whose path names a language, such as "French/", get it
+-- here; the rest are filled in by cmd/backfill-languages.
+
+-- 1. Record each document's language, English title and abstract, and group
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS language character varying(8);
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS english_title text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS english_abstract text;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS translation_group uuid;
+
+CREATE INDEX IF NOT EXISTS idx_documents_language ON documents (language);
+CREATE INDEX IF NOT EXISTS idx_documents_translation_group ON documents (translation_group) WHERE translation_group IS NOT NULL;
+
+ALTER TABLE document_revisions ADD COLUMN IF NOT EXISTS language character varying(8);
+ALTER TABLE document_revisions ADD COLUMN IF NOT EXISTS english_title text;
+ALTER TABLE document_revisions ADD COLUMN IF NOT EXISTS english_abstract text;
+
+-- 2. Take the language from paths that name one, as the duplicate review does
+UPDATE documents
+SET language = CASE
+        WHEN LOWER(s3_file) LIKE '%french%' THEN 'fr'
+        WHEN LOWER(s3_file) LIKE '%spanish%' THEN 'es'
+    END
+WHERE language IS NULL
+  AND (LOWER(s3_file) LIKE '%french%') <> (LOWER(s3_file) LIKE '%spanish%');
+
+-- 3. Search the English title and abstract as well as the original ones
+CREATE OR REPLACE FUNCTION refresh_document_search(p_doc_id uuid) RETURNS void
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO document_search (doc_id, search_vector)
+    SELECT
+        d.id,
+        setweight(to_tsvector('english', COALESCE(d.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(d.english_title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(a.name, ' ')
+            FROM doc_authors da JOIN authors a ON a.id = da.author_id
+            WHERE da.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(k.name, ' ')
+            FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+            WHERE dk.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(d.abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(d.english_abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(r.name, ' ')
+            FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+            WHERE dr.doc_id = d.id), '')), 'D') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(c.name, ' ')
+            FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+            WHERE dc.doc_id = d.id), '')), 'D')
+    FROM documents d
+    WHERE d.id = p_doc_id
+    ON CONFLICT (doc_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS documents_search_refresh ON documents;
+CREATE TRIGGER documents_search_refresh
+    AFTER INSERT OR UPDATE OF title, abstract, english_title, english_abstract ON documents
+    FOR EACH ROW EXECUTE FUNCTION document_search_doc_trigger();
+
+-- 4. Ask the model to keep the original title and abstract and to translate
+--    them, in a new version of the newest prompt if it still has the old fields
+INSERT INTO extraction_prompts (version, template, note, created_by_name)
+SELECT version + 1,
+       replace(replace(template,
+               'sufficient context.' || chr(10),
+               'sufficient context.' || chr(10) ||
+               'Write the category, regions, keywords and categories in English, whatever language the document is written in.' || chr(10)),
+               '- "title" (string, required)' || chr(10) || '- "abstract" (string)' || chr(10),
+               '- "title" (string, required): in the language the document is written in' || chr(10) ||
+               '- "abstract" (string): in the language the document is written in' || chr(10) ||
+               '- "english_title" (string, max 1000 characters): the title translated into English; omit it if the document is in English' || chr(10) ||
+               '- "english_abstract" (string, max 5000 characters): the abstract translated into English; omit it if the document is in English' || chr(10)),
+       'Keep non-English titles and abstracts and add English translations',
+       'system'
+FROM extraction_prompts
+WHERE version = (SELECT MAX(version) FROM extraction_prompts)
+  AND template LIKE '%- "title" (string, required)' || chr(10) || '- "abstract" (string)' || chr(10) || '%';
@@ -0,0 +1,120 @@
+-- Full-text search index used by the postgres search backend (SEARCH_BACKEND=postgres).
+-- Each document gets one tsvector built from its title, abstract and the names of
+-- its authors, keywords, regions and categories. Triggers keep it up to date.
+
+-- 1. Create the search table
+CREATE TABLE IF NOT EXISTS document_search (
+    doc_id uuid PRIMARY KEY REFERENCES documents(id) ON DELETE CASCADE,
+    search_vector tsvector NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_search_vector
+    ON document_search USING gin (search_vector);
+
+-- 2. Rebuild the search vector of a single document
+CREATE OR REPLACE FUNCTION refresh_document_search(p_doc_id uuid) RETURNS void
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO document_search (doc_id, search_vector)
+    SELECT
+        d.id,
+        setweight(to_tsvector('english', COALESCE(d.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(a.name, ' ')
+            FROM doc_authors da JOIN authors a ON a.id = da.author_id
+            WHERE da.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(k.name, ' ')
+            FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+            WHERE dk.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(d.abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(r.name, ' ')
+            FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+            WHERE dr.doc_id = d.id), '')), 'D') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(c.name, ' ')
+            FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+            WHERE dc.doc_id = d.id), '')), 'D')
+    FROM documents d
+    WHERE d.id = p_doc_id
+    ON CONFLICT (doc_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+END;
+$$;
+
+-- 3. Triggers on documents and the join tables
+CREATE OR REPLACE FUNCTION document_search_doc_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    PERFORM refresh_document_search(NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION document_search_join_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.doc_id IS NOT NULL THEN
+        PERFORM refresh_document_search(OLD.doc_id);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.doc_id IS NOT NULL THEN
+        PERFORM refresh_document_search(NEW.doc_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+-- TG_ARGV[0] is the join table and TG_ARGV[1] its foreign key column, e.g. ('doc_authors', 'author_id')
+CREATE OR REPLACE FUNCTION document_search_term_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    EXECUTE format(
+        'SELECT refresh_document_search(doc_id) FROM %I WHERE %I = $1 AND doc_id IS NOT NULL',
+        TG_ARGV[0], TG_ARGV[1]
+    ) USING NEW.id;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER documents_search_refresh
+    AFTER INSERT OR UPDATE OF title, abstract ON documents
+    FOR EACH ROW EXECUTE FUNCTION document_search_doc_trigger();
+
+CREATE TRIGGER doc_authors_search_refresh
+    AFTER INSERT OR UPDATE OR DELETE ON doc_authors
+    FOR EACH ROW EXECUTE FUNCTION document_search_join_trigger();
+
+CREATE TRIGGER doc_keywords_search_refresh
+    AFTER INSERT OR UPDATE OR DELETE ON doc_keywords
+    FOR EACH ROW EXECUTE FUNCTION document_search_join_trigger();
+
+CREATE TRIGGER doc_regions_search_refresh
+    AFTER INSERT OR UPDATE OR DELETE ON doc_regions
+    FOR EACH ROW EXECUTE FUNCTION document_search_join_trigger();
+
+CREATE TRIGGER doc_categories_search_refresh
+    AFTER INSERT OR UPDATE OR DELETE ON doc_categories
+    FOR EACH ROW EXECUTE FUNCTION document_search_join_trigger();
+
+CREATE TRIGGER authors_search_refresh
+    AFTER UPDATE OF name ON authors
+    FOR EACH ROW EXECUTE FUNCTION document_search_term_trigger('doc_authors', 'author_id');
+
+CREATE TRIGGER keywords_search_refresh
+    AFTER UPDATE OF name ON keywords
+    FOR EACH ROW EXECUTE FUNCTION document_search_term_trigger('doc_keywords', 'keyword_id');
+
+CREATE TRIGGER regions_search_refresh
+    AFTER UPDATE OF name ON regions
+    FOR EACH ROW EXECUTE FUNCTION document_search_term_trigger('doc_regions', 'region_id');
+
+CREATE TRIGGER categories_search_refresh
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW EXECUTE FUNCTION document_search_term_trigger('doc_categories', 'category_id');
+
+-- 4. Backfill existing documents
+SELECT refresh_document_search(id) FROM documents;
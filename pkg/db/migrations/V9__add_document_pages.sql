@@ -0,0 +1,16 @@
+-- Per-page text of each document, used to build excerpts with page numbers
+-- and highlighted terms without relying on Kendra.
+
+-- 1. Create the pages table
+CREATE TABLE IF NOT EXISTS document_pages (
+    id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+    doc_id uuid NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    page_number integer NOT NULL,
+    content text NOT NULL,
+    search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', content)) STORED,
+    UNIQUE (doc_id, page_number)
+);
+
+-- 2. Create indexes
+CREATE INDEX IF NOT EXISTS idx_document_pages_doc_id ON document_pages(doc_id);
+CREATE INDEX IF NOT EXISTS idx_document_pages_search_vector ON document_pages USING gin (search_vector);
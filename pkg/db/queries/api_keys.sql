@@ -0,0 +1,25 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, rate_limit, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+
+-- name: GetAPIKeyByHash :one
+SELECT k.id, k.user_id, k.scopes, k.rate_limit, k.expires_at, u.username, u.role
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = $1;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: ListAPIKeys :many
+SELECT k.id, k.name, k.prefix, k.scopes, k.rate_limit, k.expires_at, k.last_used_at, k.created_at, u.username
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+ORDER BY k.created_at DESC;
+
+-- name: DeleteAPIKey :exec
+DELETE FROM api_keys WHERE id = $1;
@@ -0,0 +1,9 @@
+-- name: InsertAuditEvent :exec
+INSERT INTO audit_events (actor_id, actor_name, action, doc_id, target, diff)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListDocumentAuditEvents :many
+SELECT id, actor_name, action, diff, created_at
+FROM audit_events
+WHERE doc_id = $1
+ORDER BY created_at DESC, id;
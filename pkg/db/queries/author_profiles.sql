@@ -0,0 +1,20 @@
+-- name: FindAuthorByORCID :one
+SELECT * FROM authors WHERE orcid = sqlc.arg(orcid)::text;
+
+-- name: UpdateAuthorDetails :execrows
+UPDATE authors
+SET orcid = sqlc.narg(orcid)::text,
+    affiliation = sqlc.narg(affiliation)::text
+WHERE id = sqlc.arg(id)::uuid;
+
+-- name: ListAuthorAliasNames :many
+SELECT alias FROM author_aliases WHERE author_id = $1 ORDER BY alias;
+
+-- name: ListAuthorPublications :many
+SELECT d.id, d.title, d.publish_date, d.publisher_id, p.name AS source, d.s3_file
+FROM documents d
+JOIN doc_authors da ON da.doc_id = d.id
+LEFT JOIN publishers p ON p.id = d.publisher_id
+WHERE da.author_id = $1
+  AND d.to_delete = false
+ORDER BY d.publish_date DESC NULLS LAST, d.title;
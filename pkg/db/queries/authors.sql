@@ -11,10 +11,10 @@ ORDER BY
     LIMIT 10; -- Optional: limit the number of results (good for autocomplete)
 
 -- name: ListAllAuthors :many
-SELECT id, name FROM authors ORDER BY name;
+SELECT id, name, approved, orcid, affiliation FROM authors ORDER BY name;
 
 -- name: FindAuthorByName :one
 SELECT * FROM authors WHERE LOWER(name) = LOWER($1) LIMIT 1;
 
 -- name: InsertAuthor :exec
-INSERT INTO authors (id, name) VALUES ($1, $2);
\ No newline at end of file
+INSERT INTO authors (id, name, approved) VALUES ($1, $2, $3);
\ No newline at end of file
@@ -11,10 +11,10 @@ ORDER BY
     LIMIT 10;
 
 -- name: ListAllCategories :many
-SELECT id, name FROM categories ORDER BY name;
+SELECT id, name, approved FROM categories ORDER BY name;
 
 -- name: FindCategoryByName :one
 SELECT * FROM categories WHERE LOWER(name) = LOWER($1) LIMIT 1;
 
 -- name: InsertCategory :exec
-INSERT INTO categories (id, name) VALUES ($1, $2);
\ No newline at end of file
+INSERT INTO categories (id, name, approved) VALUES ($1, $2, $3);
\ No newline at end of file
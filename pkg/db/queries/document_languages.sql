@@ -0,0 +1,61 @@
+-- name: ListDocumentTranslations :many
+SELECT t.id, t.title, t.language, t.s3_file
+FROM documents d
+JOIN documents t ON t.translation_group = d.translation_group AND t.id <> d.id
+WHERE d.id = $1
+  AND t.to_delete = false
+ORDER BY t.language, t.title;
+
+-- name: SetTranslationGroup :exec
+-- Documents already grouped with one of doc_ids join the group with it, so
+-- linking two documents merges their groups of translations.
+UPDATE documents
+SET translation_group = sqlc.arg(translation_group)::uuid
+WHERE id = ANY(sqlc.arg(doc_ids)::uuid[])
+   OR translation_group IN (
+        SELECT g.translation_group FROM documents g
+        WHERE g.id = ANY(sqlc.arg(doc_ids)::uuid[]) AND g.translation_group IS NOT NULL);
+
+-- name: ClearTranslationGroup :exec
+UPDATE documents
+SET translation_group = NULL
+WHERE translation_group = sqlc.arg(translation_group)::uuid
+  AND id <> ALL(sqlc.arg(keep_ids)::uuid[]);
+
+-- name: DissolveLoneTranslationGroup :exec
+-- A group left with a single document has no translations to link to.
+UPDATE documents
+SET translation_group = NULL
+WHERE translation_group = $1
+  AND (SELECT COUNT(*) FROM documents g WHERE g.translation_group = $1) < 2;
+
+-- name: SuggestTranslations :many
+SELECT id, title, language
+FROM documents
+WHERE to_delete = false
+  AND id <> sqlc.arg(doc_id)::uuid
+  AND (title ILIKE '%' || sqlc.arg(query)::text || '%' OR file_name ILIKE '%' || sqlc.arg(query)::text || '%')
+ORDER BY title
+LIMIT 10;
+
+-- name: ListDocumentsWithoutLanguage :many
+SELECT
+    d.id,
+    d.s3_file,
+    d.title,
+    COALESCE(d.abstract, '')::text AS abstract,
+    COALESCE((
+        SELECT string_agg(p.content, E'\n' ORDER BY p.page_number)
+        FROM document_pages p
+        WHERE p.doc_id = d.id AND p.page_number <= 5), '')::text AS text
+FROM documents d
+WHERE d.to_delete = false
+  AND d.language IS NULL
+ORDER BY d.created_at;
+
+-- name: SetDocumentLanguage :exec
+-- The document is reindexed so that the search index learns its language.
+UPDATE documents
+SET language = $2,
+    to_index = true
+WHERE id = $1;
@@ -0,0 +1,35 @@
+-- name: DeleteDocumentPagesByDocID :exec
+DELETE FROM document_pages WHERE doc_id = $1;
+
+-- name: InsertDocumentPage :exec
+INSERT INTO document_pages (doc_id, page_number, content)
+VALUES ($1, $2, $3);
+
+-- name: ListDocumentsWithoutPages :many
+SELECT d.id, d.s3_file
+FROM documents d
+WHERE d.to_delete = false
+  AND NOT EXISTS (SELECT 1 FROM document_pages p WHERE p.doc_id = d.id)
+ORDER BY d.created_at;
+
+-- name: SearchPageExcerpts :many
+SELECT
+    ranked.doc_id,
+    ranked.page_number,
+    ts_headline('english', ranked.content, websearch_to_tsquery('english', sqlc.arg(query)::text),
+        'StartSel="⟦", StopSel="⟧", MaxFragments=1, MaxWords=35, MinWords=15')::text AS excerpt
+FROM (
+    SELECT
+        p.doc_id,
+        p.page_number,
+        p.content,
+        ROW_NUMBER() OVER (
+            PARTITION BY p.doc_id
+            ORDER BY ts_rank_cd(p.search_vector, websearch_to_tsquery('english', sqlc.arg(query)::text)) DESC, p.page_number
+        ) AS rn
+    FROM document_pages p
+    WHERE p.doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+      AND p.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text)
+) ranked
+WHERE ranked.rn <= sqlc.arg(per_doc)::int
+ORDER BY ranked.doc_id, ranked.rn;
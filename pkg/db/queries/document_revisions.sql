@@ -0,0 +1,24 @@
+-- name: InsertDocumentRevision :one
+INSERT INTO document_revisions (doc_id, revision, title, abstract, publish_date, source,
+                                language, english_title, english_abstract,
+                                authors, keywords, regions, categories,
+                                restored_from, created_by, created_by_name)
+SELECT sqlc.arg(doc_id)::uuid, COALESCE(MAX(revision), 0) + 1,
+       sqlc.arg(title)::text, sqlc.narg(abstract)::text, sqlc.narg(publish_date)::date, sqlc.narg(source)::text,
+       sqlc.narg(language)::text, sqlc.narg(english_title)::text, sqlc.narg(english_abstract)::text,
+       sqlc.arg(authors)::text[], sqlc.arg(keywords)::text[], sqlc.arg(regions)::text[], sqlc.arg(categories)::text[],
+       sqlc.narg(restored_from)::int, sqlc.narg(created_by)::uuid, sqlc.arg(created_by_name)::text
+FROM document_revisions
+WHERE doc_id = sqlc.arg(doc_id)::uuid
+RETURNING revision;
+
+-- name: ListDocumentRevisions :many
+SELECT revision, restored_from, created_by_name, created_at
+FROM document_revisions
+WHERE doc_id = $1
+ORDER BY revision DESC;
+
+-- name: GetDocumentRevision :one
+SELECT id, doc_id, revision, title, abstract, publish_date, source, authors, keywords, regions, categories, restored_from, created_by, created_by_name, created_at, language, english_title, english_abstract
+FROM document_revisions
+WHERE doc_id = $1 AND revision = $2;
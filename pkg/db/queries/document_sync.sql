@@ -0,0 +1,97 @@
+-- name: ListDocumentsToIndex :many
+SELECT
+    d.id,
+    d.title,
+    d.s3_file,
+    d.language,
+    p.name AS source,
+    ARRAY_REMOVE(ARRAY_AGG(DISTINCT a.name), NULL)::text[] AS author_names,
+    ARRAY_REMOVE(ARRAY_AGG(DISTINCT r.name), NULL)::text[] AS region_names,
+    ARRAY_REMOVE(ARRAY_AGG(DISTINCT k.name), NULL)::text[] AS keyword_names,
+    ARRAY_REMOVE(ARRAY_AGG(DISTINCT c.name), NULL)::text[] AS category_names
+FROM documents d
+LEFT JOIN doc_authors da ON d.id = da.doc_id
+LEFT JOIN authors a ON da.author_id = a.id
+LEFT JOIN doc_regions dr ON d.id = dr.doc_id
+LEFT JOIN regions r ON dr.region_id = r.id
+LEFT JOIN doc_keywords dk ON d.id = dk.doc_id
+LEFT JOIN keywords k ON dk.keyword_id = k.id
+LEFT JOIN doc_categories dc ON d.id = dc.doc_id
+LEFT JOIN categories c ON dc.category_id = c.id
+LEFT JOIN publishers p ON p.id = d.publisher_id
+WHERE d.to_index = true
+  AND d.to_delete = false
+GROUP BY d.id, p.id
+ORDER BY d.created_at;
+
+-- name: ListDocumentsToDelete :many
+SELECT id, s3_file, s3_file_preview
+FROM documents
+WHERE to_delete = true
+ORDER BY created_at;
+
+-- name: MarkDocumentIndexed :exec
+UPDATE documents
+SET to_index = false
+WHERE id = $1;
+
+-- name: MarkDocumentSkipped :exec
+-- Skipped documents stay out of the queue until an edit sets to_index again.
+UPDATE documents
+SET to_index = false
+WHERE id = $1;
+
+-- name: DeleteOrphanedTerms :one
+-- Terms that no document references any more are removed, except those an
+-- admin curated: aliased terms, the controlled vocabulary, regions in the
+-- hierarchy or with an ISO code, and authors with an ORCID iD or affiliation.
+WITH deleted_authors AS (
+    DELETE FROM authors a
+    WHERE NOT EXISTS (SELECT 1 FROM doc_authors da WHERE da.author_id = a.id)
+      AND NOT EXISTS (SELECT 1 FROM author_aliases aa WHERE aa.author_id = a.id)
+      AND a.orcid IS NULL
+      AND a.affiliation IS NULL
+    RETURNING a.id
+), deleted_keywords AS (
+    DELETE FROM keywords k
+    WHERE NOT EXISTS (SELECT 1 FROM doc_keywords dk WHERE dk.keyword_id = k.id)
+      AND NOT EXISTS (SELECT 1 FROM keyword_aliases ka WHERE ka.keyword_id = k.id)
+      AND NOT EXISTS (SELECT 1 FROM vocabulary_keywords vk WHERE vk.keyword_id = k.id)
+    RETURNING k.id
+), deleted_regions AS (
+    DELETE FROM regions r
+    WHERE NOT EXISTS (SELECT 1 FROM doc_regions dr WHERE dr.region_id = r.id)
+      AND NOT EXISTS (SELECT 1 FROM region_aliases ra WHERE ra.region_id = r.id)
+      AND NOT EXISTS (SELECT 1 FROM vocabulary_regions vr WHERE vr.region_id = r.id)
+      AND NOT EXISTS (SELECT 1 FROM regions child WHERE child.parent_id = r.id)
+      AND r.parent_id IS NULL
+      AND r.iso_code IS NULL
+    RETURNING r.id
+), deleted_categories AS (
+    DELETE FROM categories c
+    WHERE NOT EXISTS (SELECT 1 FROM doc_categories dc WHERE dc.category_id = c.id)
+      AND NOT EXISTS (SELECT 1 FROM category_aliases ca WHERE ca.category_id = c.id)
+      AND NOT EXISTS (SELECT 1 FROM vocabulary_categories vc WHERE vc.category_id = c.id)
+    RETURNING c.id
+)
+SELECT (
+    (SELECT count(*) FROM deleted_authors)
+    + (SELECT count(*) FROM deleted_keywords)
+    + (SELECT count(*) FROM deleted_regions)
+    + (SELECT count(*) FROM deleted_categories)
+)::bigint AS deleted;
+
+-- name: UpsertDocumentSyncStatus :exec
+INSERT INTO document_sync_status (doc_id, operation, status, error, last_success_at)
+VALUES (
+    $1, $2, $3, $4,
+    CASE WHEN $3 IN ('indexed', 'deleted') THEN now() END
+)
+ON CONFLICT (doc_id) DO UPDATE SET
+    operation = EXCLUDED.operation,
+    status = EXCLUDED.status,
+    error = EXCLUDED.error,
+    attempts = document_sync_status.attempts + 1,
+    last_attempt_at = now(),
+    last_success_at = COALESCE(EXCLUDED.last_success_at, document_sync_status.last_success_at);
+
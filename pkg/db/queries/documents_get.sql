@@ -1,6 +1,7 @@
 -- name: GetDocumentsByURIs :many
 SELECT
     d.*,  -- Select all columns from the documents table
+    p.name AS source,
     -- Aggregate author names into a text array
     COALESCE(ARRAY_AGG(DISTINCT a.name) FILTER (WHERE a.id IS NOT NULL), '{}'::text[]) AS author_names,
     -- Aggregate region names into a text array
@@ -8,7 +9,23 @@ SELECT
     -- Aggregate keyword names into a text array
     COALESCE(ARRAY_AGG(DISTINCT k.name) FILTER (WHERE k.id IS NOT NULL), '{}'::text[]) AS keyword_names,
     -- Aggregate category names into a text array
-    COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE c.id IS NOT NULL), '{}'::text[]) AS category_names
+    COALESCE(ARRAY_AGG(DISTINCT c.name) FILTER (WHERE c.id IS NOT NULL), '{}'::text[]) AS category_names,
+    -- Author IDs in the same order as author_names, which are unique and sorted
+    ARRAY(
+        SELECT ao.id::text FROM doc_authors dao JOIN authors ao ON ao.id = dao.author_id
+        WHERE dao.doc_id = d.id ORDER BY ao.name
+    )::text[] AS author_ids,
+    -- Languages and files of the document's translations, in the same order
+    ARRAY(
+        SELECT COALESCE(t.language, '') FROM documents t
+        WHERE t.translation_group = d.translation_group AND t.id <> d.id AND t.to_delete = false
+        ORDER BY t.language, t.id
+    )::text[] AS translation_languages,
+    ARRAY(
+        SELECT t.s3_file FROM documents t
+        WHERE t.translation_group = d.translation_group AND t.id <> d.id AND t.to_delete = false
+        ORDER BY t.language, t.id
+    )::text[] AS translation_files
 FROM
     documents d
         LEFT JOIN
@@ -27,16 +44,19 @@ FROM
     doc_categories dc ON d.id = dc.doc_id
         LEFT JOIN
     categories c ON dc.category_id = c.id
+        LEFT JOIN
+    publishers p ON p.id = d.publisher_id
 WHERE
     d.s3_file = ANY($1::text[]) -- Filter documents by the provided list of s3_file paths
 GROUP BY
-    d.id -- Group by document ID to aggregate related names for each document
+    d.id, p.id -- Group by document ID to aggregate related names for each document
 ORDER BY
     d.id; -- Optional: Add an order clause if needed
 
 -- name: FindDocumentByID :one
 SELECT
     d.*,
+    p.name AS source,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT a.name), NULL)::text[] AS author_names,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT r.name), NULL)::text[] AS region_names,
     ARRAY_REMOVE(ARRAY_AGG(DISTINCT k.name), NULL)::text[] AS keyword_names,
@@ -50,8 +70,9 @@ LEFT JOIN doc_keywords dk ON d.id = dk.doc_id
 LEFT JOIN keywords k ON dk.keyword_id = k.id
 LEFT JOIN doc_categories dc ON d.id = dc.doc_id
 LEFT JOIN categories c ON dc.category_id = c.id
+LEFT JOIN publishers p ON p.id = d.publisher_id
 WHERE d.id = $1
-GROUP BY d.id;
+GROUP BY d.id, p.id;
 
 -- name: FindDocumentByS3Path :one
 SELECT *
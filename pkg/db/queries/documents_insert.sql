@@ -7,21 +7,32 @@ INSERT INTO documents (
   abstract,
   publish_date,
   created_at,
-  to_delete
-) VALUES ($1, $2, $3, $4, $5, $6,NOW(), false);
+  to_delete,
+  content_hash,
+  publisher_id,
+  language,
+  english_title,
+  english_abstract,
+  review_state,
+  to_index
+) VALUES ($1, $2, $3, $4, $5, $6,NOW(), false, $7, $8, $9, $10, $11, $12, $13);
 
 -- name: InsertDocAuthor :exec
 INSERT INTO doc_authors (id, doc_id, author_id)
-VALUES ($1, $2, $3);
+VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, author_id) DO NOTHING;
 
 -- name: InsertDocKeyword :exec
 INSERT INTO doc_keywords (id, doc_id, keyword_id)
-VALUES ($1, $2, $3);
+VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, keyword_id) DO NOTHING;
 
 -- name: InsertDocCategory :exec
 INSERT INTO doc_categories (id, doc_id, category_id)
-VALUES ($1, $2, $3);
+VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, category_id) DO NOTHING;
 
 -- name: InsertDocRegion :exec
 INSERT INTO doc_regions (id, doc_id, region_id)
-VALUES ($1, $2, $3);
+VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, region_id) DO NOTHING;
@@ -0,0 +1,41 @@
+-- name: SetDocumentFieldSource :exec
+INSERT INTO document_field_sources (doc_id, field, source)
+VALUES ($1, $2, $3)
+ON CONFLICT (doc_id, field) DO UPDATE
+SET source = EXCLUDED.source,
+    updated_at = NOW();
+
+-- name: ListDocumentFieldSources :many
+SELECT field, source
+FROM document_field_sources
+WHERE doc_id = $1
+ORDER BY field;
+
+-- name: SetDocumentReviewState :exec
+UPDATE documents
+SET review_state = $2
+WHERE id = $1;
+
+-- name: ApproveDocument :exec
+UPDATE documents
+SET review_state = 'approved',
+    approved_by = $2,
+    approved_by_name = $3,
+    approved_at = NOW()
+WHERE id = $1;
+
+-- name: ListDocumentsForReview :many
+SELECT d.id,
+       d.title,
+       d.file_name,
+       d.review_state,
+       d.created_at,
+       COALESCE(ARRAY_AGG(s.field ORDER BY s.field) FILTER (WHERE s.source = 'llm'), '{}')::text[] AS llm_fields,
+       COUNT(*) OVER ()                                                                      AS total
+FROM documents d
+LEFT JOIN document_field_sources s ON s.doc_id = d.id
+WHERE d.review_state <> 'approved'
+  AND NOT d.to_delete
+GROUP BY d.id
+ORDER BY d.created_at, d.id
+LIMIT sqlc.arg(max_documents);
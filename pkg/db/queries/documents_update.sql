@@ -4,8 +4,11 @@ SET
   title = $2,
   abstract = $3,
   publish_date = $4,
-  source = $5,
-  to_index = $6
+  publisher_id = $5,
+  language = $6,
+  english_title = $7,
+  english_abstract = $8,
+  to_index = $9
 WHERE id = $1;
 
 -- name: UpdateDocumentDeletionStatus :exec
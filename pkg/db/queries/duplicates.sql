@@ -0,0 +1,72 @@
+-- name: FindDocumentByContentHash :one
+SELECT id, file_name, title
+FROM documents
+WHERE content_hash = $1
+  AND to_delete = false
+ORDER BY created_at
+LIMIT 1;
+
+-- name: SetDocumentContentHash :exec
+UPDATE documents
+SET content_hash = $2
+WHERE id = $1;
+
+-- name: ListDocumentsWithoutContentHash :many
+SELECT id, s3_file
+FROM documents
+WHERE content_hash IS NULL
+ORDER BY created_at;
+
+-- name: ListDuplicateCandidates :many
+-- Documents sharing a content hash or a normalized title with at least one other
+-- document that is not already marked for deletion.
+WITH candidates AS (
+    SELECT
+        id,
+        title,
+        file_name,
+        s3_file,
+        content_hash,
+        created_at,
+        BTRIM(REGEXP_REPLACE(LOWER(title), '[^[:alnum:]]+', ' ', 'g')) AS normalized_title
+    FROM documents
+    WHERE to_delete = false
+)
+SELECT
+    c.id,
+    c.title,
+    c.file_name,
+    c.s3_file,
+    c.content_hash,
+    c.created_at,
+    c.normalized_title::text AS normalized_title
+FROM candidates c
+WHERE (
+    c.content_hash IS NOT NULL
+    AND EXISTS (
+        SELECT 1 FROM candidates o
+        WHERE o.id <> c.id AND o.content_hash = c.content_hash
+    )
+) OR (
+    c.normalized_title NOT IN ('', 'untitled')
+    AND EXISTS (
+        SELECT 1 FROM candidates o
+        WHERE o.id <> c.id AND o.normalized_title = c.normalized_title
+    )
+)
+ORDER BY c.normalized_title, c.created_at;
+
+-- name: ListLeadingPageText :many
+-- The text of the first pages of each document, used to compare near-duplicates.
+SELECT
+    doc_id,
+    STRING_AGG(content, ' ' ORDER BY page_number)::text AS content
+FROM document_pages
+WHERE doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+  AND page_number <= sqlc.arg(max_page)
+GROUP BY doc_id;
+
+-- name: MarkDocumentsToDelete :exec
+UPDATE documents
+SET to_delete = true
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
@@ -0,0 +1,22 @@
+-- name: InsertExtractionPrompt :one
+INSERT INTO extraction_prompts (version, template, note, restored_from, created_by, created_by_name)
+SELECT COALESCE(MAX(version), 0) + 1, sqlc.arg(template)::text, sqlc.arg(note)::text,
+       sqlc.narg(restored_from)::int, sqlc.narg(created_by)::uuid, sqlc.arg(created_by_name)::text
+FROM extraction_prompts
+RETURNING version;
+
+-- name: GetLatestExtractionPrompt :one
+SELECT version, template, note, restored_from, created_by, created_by_name, created_at
+FROM extraction_prompts
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetExtractionPrompt :one
+SELECT version, template, note, restored_from, created_by, created_by_name, created_at
+FROM extraction_prompts
+WHERE version = $1;
+
+-- name: ListExtractionPrompts :many
+SELECT version, note, restored_from, created_by_name, created_at
+FROM extraction_prompts
+ORDER BY version DESC;
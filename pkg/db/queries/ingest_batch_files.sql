@@ -0,0 +1,11 @@
+-- name: InsertIngestBatchFile :exec
+INSERT INTO ingest_batch_files (batch_id, position, file_name, outcome, message, job_id, doc_id, created_by, created_by_name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListIngestBatchFiles :many
+SELECT f.position, f.file_name, f.outcome, f.message, f.job_id, f.doc_id, f.created_by_name, f.created_at,
+       j.status AS job_status, j.doc_id AS job_doc_id, j.error AS job_error
+FROM ingest_batch_files f
+LEFT JOIN ingest_jobs j ON j.id = f.job_id
+WHERE f.batch_id = $1
+ORDER BY f.position;
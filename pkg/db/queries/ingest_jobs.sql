@@ -0,0 +1,88 @@
+-- name: CreateIngestJob :exec
+INSERT INTO ingest_jobs (id, doc_id, file_name, s3_file, content_type, content_hash, file_data, stage, provided_metadata, created_by, created_by_name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: GetIngestJob :one
+SELECT j.id, j.doc_id, j.file_name, j.s3_file, j.content_type, j.content_hash, j.stage, j.status, j.pages, j.metadata,
+       j.provided_metadata, j.error, j.attempts, j.created_by, j.created_by_name, j.created_at, j.updated_at,
+       e.extractor, e.model, e.prompt_version, e.input_tokens, e.output_tokens, e.cost
+FROM ingest_jobs j
+LEFT JOIN LATERAL (
+    SELECT extractor, model, prompt_version, input_tokens, output_tokens, cost
+    FROM metadata_extractions
+    WHERE job_id = j.id AND error IS NULL
+    ORDER BY created_at DESC
+    LIMIT 1
+) e ON true
+WHERE j.id = $1;
+
+-- name: GetIngestJobFile :one
+SELECT file_data
+FROM ingest_jobs
+WHERE id = $1;
+
+-- name: ListRecentIngestJobs :many
+SELECT id, doc_id, file_name, stage, status, error, attempts, created_by_name, created_at, updated_at
+FROM ingest_jobs
+ORDER BY created_at DESC
+LIMIT $1;
+
+-- name: ListUnfinishedIngestJobs :many
+SELECT id
+FROM ingest_jobs
+WHERE status IN ('pending', 'running')
+ORDER BY created_at;
+
+-- name: FindUnfinishedIngestJobByS3File :one
+SELECT id
+FROM ingest_jobs
+WHERE s3_file = $1 AND status <> 'done'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ResetRunningIngestJobs :exec
+UPDATE ingest_jobs
+SET status = 'pending', updated_at = now()
+WHERE status = 'running';
+
+-- name: StartIngestJob :execrows
+UPDATE ingest_jobs
+SET status = 'running', error = NULL, attempts = attempts + 1, updated_at = now()
+WHERE id = $1 AND status = 'pending';
+
+-- name: SetIngestJobStored :exec
+UPDATE ingest_jobs
+SET stage = 'stored', updated_at = now()
+WHERE id = $1;
+
+-- name: SetIngestJobPages :exec
+UPDATE ingest_jobs
+SET stage = 'text_extracted', pages = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: SetIngestJobMetadata :exec
+UPDATE ingest_jobs
+SET stage = 'metadata_extracted', metadata = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: CompleteIngestJob :exec
+UPDATE ingest_jobs
+SET stage = 'saved', status = 'done', file_data = NULL, pages = NULL, updated_at = now()
+WHERE id = $1;
+
+-- name: FailIngestJob :exec
+UPDATE ingest_jobs
+SET status = 'failed', error = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: RetryIngestJob :execrows
+UPDATE ingest_jobs
+SET status = 'pending', error = NULL, updated_at = now()
+WHERE id = $1 AND status = 'failed';
+
+-- name: FindUnfinishedIngestJobByContentHash :one
+SELECT id
+FROM ingest_jobs
+WHERE content_hash = $1 AND status <> 'done'
+ORDER BY created_at DESC
+LIMIT 1;
@@ -11,10 +11,10 @@ ORDER BY
     LIMIT 10;
 
 -- name: ListAllKeywords :many
-SELECT id, name FROM keywords ORDER BY name;
+SELECT id, name, approved FROM keywords ORDER BY name;
 
 -- name: FindKeywordByName :one
 SELECT * FROM keywords WHERE LOWER(name) = LOWER($1) LIMIT 1;
 
 -- name: InsertKeyword :exec
-INSERT INTO keywords (id, name) VALUES ($1, $2);    
\ No newline at end of file
+INSERT INTO keywords (id, name, approved) VALUES ($1, $2, $3);    
\ No newline at end of file
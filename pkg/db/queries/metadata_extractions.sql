@@ -0,0 +1,45 @@
+-- name: InsertMetadataExtraction :exec
+INSERT INTO metadata_extractions (job_id, doc_id, extractor, model, prompt_version, input_tokens, output_tokens, cost,
+                                  latency_ms, created_by, created_by_name, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+
+-- name: SpendByDay :many
+SELECT created_at::date                                 AS day,
+       COUNT(*)                                         AS extractions,
+       COUNT(*) FILTER (WHERE error IS NOT NULL)        AS failed,
+       COALESCE(SUM(input_tokens), 0)::bigint           AS input_tokens,
+       COALESCE(SUM(output_tokens), 0)::bigint          AS output_tokens,
+       COALESCE(SUM(cost), 0)::double precision         AS cost,
+       COUNT(*) FILTER (WHERE cost IS NULL)             AS unpriced
+FROM metadata_extractions
+WHERE created_at >= sqlc.arg(since)::timestamp
+GROUP BY day
+ORDER BY day DESC;
+
+-- name: SpendByUser :many
+SELECT created_by_name,
+       COUNT(*)                                         AS extractions,
+       COUNT(*) FILTER (WHERE error IS NOT NULL)        AS failed,
+       COALESCE(SUM(input_tokens), 0)::bigint           AS input_tokens,
+       COALESCE(SUM(output_tokens), 0)::bigint          AS output_tokens,
+       COALESCE(SUM(cost), 0)::double precision         AS cost,
+       COUNT(*) FILTER (WHERE cost IS NULL)             AS unpriced
+FROM metadata_extractions
+WHERE created_at >= sqlc.arg(since)::timestamp
+GROUP BY created_by_name
+ORDER BY cost DESC, created_by_name;
+
+-- name: SpendByModel :many
+SELECT extractor,
+       model,
+       COUNT(*)                                         AS extractions,
+       COUNT(*) FILTER (WHERE error IS NOT NULL)        AS failed,
+       COALESCE(SUM(input_tokens), 0)::bigint           AS input_tokens,
+       COALESCE(SUM(output_tokens), 0)::bigint          AS output_tokens,
+       COALESCE(SUM(cost), 0)::double precision         AS cost,
+       COUNT(*) FILTER (WHERE cost IS NULL)             AS unpriced,
+       COALESCE(AVG(latency_ms), 0)::integer            AS avg_latency_ms
+FROM metadata_extractions
+WHERE created_at >= sqlc.arg(since)::timestamp
+GROUP BY extractor, model
+ORDER BY cost DESC, extractor, model;
@@ -0,0 +1,12 @@
+-- name: ListDocumentsToGeneratePreview :many
+SELECT id, s3_file
+FROM documents
+WHERE to_generate_preview = true
+  AND to_delete = false
+  AND (LOWER(s3_file) LIKE '%.pdf' OR LOWER(s3_file) LIKE '%.docx')
+ORDER BY created_at;
+
+-- name: SetDocumentPreview :exec
+UPDATE documents
+SET s3_file_preview = $2, to_generate_preview = false
+WHERE id = $1;
@@ -0,0 +1,23 @@
+-- name: FindPublisherByName :one
+SELECT * FROM publishers WHERE LOWER(name) = LOWER($1) LIMIT 1;
+
+-- name: InsertPublisher :exec
+INSERT INTO publishers (id, name, approved) VALUES ($1, $2, $3);
+
+-- name: ListPublisherAliasNames :many
+SELECT alias FROM publisher_aliases WHERE publisher_id = $1 ORDER BY alias;
+
+-- name: ListPublisherPublications :many
+SELECT
+    d.id,
+    d.title,
+    d.publish_date,
+    d.s3_file,
+    ARRAY(
+        SELECT a.name FROM doc_authors da JOIN authors a ON a.id = da.author_id
+        WHERE da.doc_id = d.id ORDER BY a.name
+    )::text[] AS author_names
+FROM documents d
+WHERE d.publisher_id = $1
+  AND d.to_delete = false
+ORDER BY d.publish_date DESC NULLS LAST, d.title;
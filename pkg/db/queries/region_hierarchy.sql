@@ -0,0 +1,11 @@
+-- name: ListRegionHierarchy :many
+SELECT id, name, parent_id, iso_code FROM regions ORDER BY name;
+
+-- name: SetRegionParent :execrows
+UPDATE regions SET parent_id = sqlc.narg(parent_id)::uuid WHERE id = sqlc.arg(id)::uuid;
+
+-- name: MoveRegionChildren :exec
+UPDATE regions
+SET parent_id = sqlc.arg(to_id)::uuid
+WHERE parent_id = sqlc.arg(from_id)::uuid
+  AND id <> sqlc.arg(to_id)::uuid;
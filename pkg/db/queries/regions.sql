@@ -11,10 +11,10 @@ ORDER BY
     LIMIT 10;
 
 -- name: ListAllRegions :many
-SELECT id, name FROM regions ORDER BY name;
+SELECT id, name, approved, parent_id, iso_code FROM regions ORDER BY name;
 
 -- name: FindRegionByName :one
 SELECT * FROM regions WHERE LOWER(name) = LOWER($1) LIMIT 1;
 
 -- name: InsertRegion :exec
-INSERT INTO regions (id, name) VALUES ($1, $2); 
\ No newline at end of file
+INSERT INTO regions (id, name, approved) VALUES ($1, $2, $3); 
\ No newline at end of file
@@ -0,0 +1,102 @@
+-- name: SearchDocumentsFullText :many
+SELECT
+    d.id,
+    d.title,
+    d.s3_file,
+    ts_headline('english', COALESCE(d.abstract, ''), websearch_to_tsquery('english', sqlc.arg(query)::text),
+        'StartSel="⟦", StopSel="⟧", MaxFragments=1, MaxWords=35, MinWords=15')::text AS excerpt,
+    ts_rank_cd(s.search_vector, websearch_to_tsquery('english', sqlc.arg(query)::text))::real AS rank,
+    COUNT(*) OVER () AS total_count
+FROM documents d
+JOIN document_search s ON s.doc_id = d.id
+LEFT JOIN publishers p ON p.id = d.publisher_id
+WHERE d.to_delete = false
+  AND (sqlc.arg(query)::text = '' OR s.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text))
+  AND (cardinality(sqlc.arg(authors)::text[]) = 0 OR EXISTS (
+        SELECT 1 FROM doc_authors da JOIN authors a ON a.id = da.author_id
+        WHERE da.doc_id = d.id AND LOWER(a.name) = ANY(sqlc.arg(authors)::text[])))
+  AND (cardinality(sqlc.arg(keywords)::text[]) = 0 OR EXISTS (
+        SELECT 1 FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+        WHERE dk.doc_id = d.id AND LOWER(k.name) = ANY(sqlc.arg(keywords)::text[])))
+  AND (cardinality(sqlc.arg(regions)::text[]) = 0 OR EXISTS (
+        SELECT 1 FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+        WHERE dr.doc_id = d.id AND LOWER(r.name) = ANY(sqlc.arg(regions)::text[])))
+  AND (cardinality(sqlc.arg(categories)::text[]) = 0 OR EXISTS (
+        SELECT 1 FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+        WHERE dc.doc_id = d.id AND LOWER(c.name) = ANY(sqlc.arg(categories)::text[])))
+  AND (cardinality(sqlc.arg(sources)::text[]) = 0 OR LOWER(p.name) = ANY(sqlc.arg(sources)::text[]))
+  AND (cardinality(sqlc.arg(file_types)::text[]) = 0 OR LOWER(substring(d.s3_file from '\.([^.]+)$')) = ANY(sqlc.arg(file_types)::text[]))
+  AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR d.language = ANY(sqlc.arg(languages)::text[]))
+ORDER BY rank DESC, d.title
+LIMIT sqlc.arg(page_size)::int
+OFFSET sqlc.arg(page_offset)::int;
+
+-- name: CountSearchFacets :many
+WITH matched AS (
+    SELECT d.id, p.name AS source, d.s3_file, d.language
+    FROM documents d
+    JOIN document_search s ON s.doc_id = d.id
+    LEFT JOIN publishers p ON p.id = d.publisher_id
+    WHERE d.to_delete = false
+      AND (sqlc.arg(query)::text = '' OR s.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text))
+      AND (cardinality(sqlc.arg(authors)::text[]) = 0 OR EXISTS (
+            SELECT 1 FROM doc_authors da JOIN authors a ON a.id = da.author_id
+            WHERE da.doc_id = d.id AND LOWER(a.name) = ANY(sqlc.arg(authors)::text[])))
+      AND (cardinality(sqlc.arg(keywords)::text[]) = 0 OR EXISTS (
+            SELECT 1 FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+            WHERE dk.doc_id = d.id AND LOWER(k.name) = ANY(sqlc.arg(keywords)::text[])))
+      AND (cardinality(sqlc.arg(regions)::text[]) = 0 OR EXISTS (
+            SELECT 1 FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+            WHERE dr.doc_id = d.id AND LOWER(r.name) = ANY(sqlc.arg(regions)::text[])))
+      AND (cardinality(sqlc.arg(categories)::text[]) = 0 OR EXISTS (
+            SELECT 1 FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+            WHERE dc.doc_id = d.id AND LOWER(c.name) = ANY(sqlc.arg(categories)::text[])))
+      AND (cardinality(sqlc.arg(sources)::text[]) = 0 OR LOWER(p.name) = ANY(sqlc.arg(sources)::text[]))
+      AND (cardinality(sqlc.arg(file_types)::text[]) = 0 OR LOWER(substring(d.s3_file from '\.([^.]+)$')) = ANY(sqlc.arg(file_types)::text[]))
+      AND (cardinality(sqlc.arg(languages)::text[]) = 0 OR d.language = ANY(sqlc.arg(languages)::text[]))
+)
+SELECT 'Author'::text AS facet, a.name::text AS label, COUNT(*)::int AS doc_count
+FROM matched m JOIN doc_authors da ON da.doc_id = m.id JOIN authors a ON a.id = da.author_id
+GROUP BY a.name
+UNION ALL
+SELECT 'Keyword'::text, k.name::text, COUNT(*)::int
+FROM matched m JOIN doc_keywords dk ON dk.doc_id = m.id JOIN keywords k ON k.id = dk.keyword_id
+GROUP BY k.name
+UNION ALL
+SELECT 'Region'::text, r.name::text, COUNT(*)::int
+FROM matched m JOIN doc_regions dr ON dr.doc_id = m.id JOIN regions r ON r.id = dr.region_id
+GROUP BY r.name
+UNION ALL
+SELECT 'Category'::text, c.name::text, COUNT(*)::int
+FROM matched m JOIN doc_categories dc ON dc.doc_id = m.id JOIN categories c ON c.id = dc.category_id
+GROUP BY c.name
+UNION ALL
+SELECT 'Source'::text, m.source::text, COUNT(*)::int
+FROM matched m
+WHERE m.source IS NOT NULL AND m.source <> ''
+GROUP BY m.source
+UNION ALL
+SELECT 'Language'::text, m.language::text, COUNT(*)::int
+FROM matched m
+WHERE m.language IS NOT NULL
+GROUP BY m.language
+UNION ALL
+SELECT '_file_type'::text, COALESCE(UPPER(substring(m.s3_file from '\.([^.]+)$')), 'UNKNOWN')::text, COUNT(*)::int
+FROM matched m
+GROUP BY 2
+ORDER BY facet, doc_count DESC, label;
+
+-- name: SuggestSearchTerms :many
+SELECT suggestion::text
+FROM (
+    SELECT k.name AS suggestion
+    FROM keywords k
+    WHERE k.name ILIKE sqlc.arg(prefix)::text || '%' ESCAPE '\'
+    UNION
+    SELECT d.title
+    FROM documents d
+    WHERE d.to_delete = false
+      AND d.title ILIKE '%' || sqlc.arg(prefix)::text || '%' ESCAPE '\'
+) s
+ORDER BY length(suggestion), suggestion
+LIMIT 5;
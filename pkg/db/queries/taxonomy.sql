@@ -0,0 +1,413 @@
+-- Aliases, approval and merging of authors, keywords, publishers, regions and
+-- categories. A document has at most one publisher, in documents.publisher_id,
+-- so the publisher queries that move documents update that column.
+-- ListXTerms returns each term under its own name, with an empty alias, and
+-- once more for each of its aliases.
+
+-- name: ListAuthorTerms :many
+SELECT t.id, t.name, ''::text AS alias FROM authors t
+UNION ALL
+SELECT t.id, t.name, a.alias::text AS alias
+FROM author_aliases a
+JOIN authors t ON t.id = a.author_id;
+
+-- name: FindAuthorByID :one
+SELECT * FROM authors WHERE id = $1;
+
+-- name: InsertAuthorAlias :exec
+INSERT INTO author_aliases (alias_key, alias, author_id) VALUES ($1, $2, $3)
+ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, author_id = EXCLUDED.author_id;
+
+-- name: ApproveAuthor :execrows
+UPDATE authors SET approved = true WHERE id = $1;
+
+-- name: MoveDocAuthors :exec
+UPDATE doc_authors d
+SET author_id = sqlc.arg(to_id)::uuid
+WHERE d.author_id = sqlc.arg(from_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM doc_authors e WHERE e.doc_id = d.doc_id AND e.author_id = sqlc.arg(to_id)::uuid);
+
+-- name: MoveAuthorAliases :exec
+UPDATE author_aliases SET author_id = sqlc.arg(to_id)::uuid WHERE author_id = sqlc.arg(from_id)::uuid;
+
+-- name: MoveDocAuthorsForDocuments :exec
+UPDATE doc_authors d
+SET author_id = sqlc.arg(to_id)::uuid
+WHERE d.author_id = sqlc.arg(from_id)::uuid
+  AND d.doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+  AND NOT EXISTS (SELECT 1 FROM doc_authors e WHERE e.doc_id = d.doc_id AND e.author_id = sqlc.arg(to_id)::uuid);
+
+-- name: DeleteDocAuthorsForDocuments :exec
+DELETE FROM doc_authors
+WHERE author_id = sqlc.arg(author_id)::uuid
+  AND doc_id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: RenameAuthor :exec
+UPDATE authors SET name = $2 WHERE id = $1;
+
+-- name: DeleteUnusedAuthor :execrows
+DELETE FROM authors t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM doc_authors d WHERE d.author_id = t.id);
+
+-- name: ListAuthorsForAdmin :many
+SELECT
+    t.id,
+    t.name,
+    t.approved,
+    (SELECT COUNT(*) FROM doc_authors d WHERE d.author_id = t.id) AS documents,
+    COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM author_aliases a WHERE a.author_id = t.id), '{}')::text[] AS aliases,
+    COUNT(*) OVER () AS total
+FROM authors t
+WHERE sqlc.arg(query)::text = '' OR t.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY t.name
+LIMIT sqlc.arg(max_terms)::int;
+
+-- name: ListAuthorDocuments :many
+SELECT d.id, d.title
+FROM doc_authors x
+JOIN documents d ON d.id = x.doc_id
+WHERE x.author_id = sqlc.arg(author_id)::uuid
+ORDER BY d.title;
+
+-- name: DeleteAuthor :execrows
+DELETE FROM authors WHERE id = $1;
+
+-- name: FlagAuthorDocumentsForIndex :exec
+UPDATE documents
+SET to_index = true
+WHERE id IN (SELECT doc_id FROM doc_authors WHERE author_id = sqlc.arg(author_id)::uuid)
+  AND (NOT sqlc.arg(approved_only)::boolean OR review_state = 'approved');
+
+-- name: ListCategoryTerms :many
+SELECT t.id, t.name, ''::text AS alias FROM categories t
+UNION ALL
+SELECT t.id, t.name, a.alias::text AS alias
+FROM category_aliases a
+JOIN categories t ON t.id = a.category_id;
+
+-- name: FindCategoryByID :one
+SELECT * FROM categories WHERE id = $1;
+
+-- name: InsertCategoryAlias :exec
+INSERT INTO category_aliases (alias_key, alias, category_id) VALUES ($1, $2, $3)
+ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, category_id = EXCLUDED.category_id;
+
+-- name: ApproveCategory :execrows
+UPDATE categories SET approved = true WHERE id = $1;
+
+-- name: MoveDocCategories :exec
+UPDATE doc_categories d
+SET category_id = sqlc.arg(to_id)::uuid
+WHERE d.category_id = sqlc.arg(from_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM doc_categories e WHERE e.doc_id = d.doc_id AND e.category_id = sqlc.arg(to_id)::uuid);
+
+-- name: MoveCategoryAliases :exec
+UPDATE category_aliases SET category_id = sqlc.arg(to_id)::uuid WHERE category_id = sqlc.arg(from_id)::uuid;
+
+-- name: MoveDocCategoriesForDocuments :exec
+UPDATE doc_categories d
+SET category_id = sqlc.arg(to_id)::uuid
+WHERE d.category_id = sqlc.arg(from_id)::uuid
+  AND d.doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+  AND NOT EXISTS (SELECT 1 FROM doc_categories e WHERE e.doc_id = d.doc_id AND e.category_id = sqlc.arg(to_id)::uuid);
+
+-- name: DeleteDocCategoriesForDocuments :exec
+DELETE FROM doc_categories
+WHERE category_id = sqlc.arg(category_id)::uuid
+  AND doc_id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: MoveVocabularyCategory :exec
+INSERT INTO vocabulary_categories (category_id)
+SELECT sqlc.arg(to_id)::uuid
+WHERE EXISTS (SELECT 1 FROM vocabulary_categories WHERE category_id = sqlc.arg(from_id)::uuid)
+ON CONFLICT DO NOTHING;
+
+-- name: RenameCategory :exec
+UPDATE categories SET name = $2 WHERE id = $1;
+
+-- name: DeleteUnusedCategory :execrows
+DELETE FROM categories t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM doc_categories d WHERE d.category_id = t.id);
+
+-- name: ListCategoriesForAdmin :many
+SELECT
+    t.id,
+    t.name,
+    t.approved,
+    (SELECT COUNT(*) FROM doc_categories d WHERE d.category_id = t.id) AS documents,
+    COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM category_aliases a WHERE a.category_id = t.id), '{}')::text[] AS aliases,
+    COUNT(*) OVER () AS total
+FROM categories t
+WHERE sqlc.arg(query)::text = '' OR t.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY t.name
+LIMIT sqlc.arg(max_terms)::int;
+
+-- name: ListCategoryDocuments :many
+SELECT d.id, d.title
+FROM doc_categories x
+JOIN documents d ON d.id = x.doc_id
+WHERE x.category_id = sqlc.arg(category_id)::uuid
+ORDER BY d.title;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories WHERE id = $1;
+
+-- name: FlagCategoryDocumentsForIndex :exec
+UPDATE documents
+SET to_index = true
+WHERE id IN (SELECT doc_id FROM doc_categories WHERE category_id = sqlc.arg(category_id)::uuid)
+  AND (NOT sqlc.arg(approved_only)::boolean OR review_state = 'approved');
+
+-- name: ListKeywordTerms :many
+SELECT t.id, t.name, ''::text AS alias FROM keywords t
+UNION ALL
+SELECT t.id, t.name, a.alias::text AS alias
+FROM keyword_aliases a
+JOIN keywords t ON t.id = a.keyword_id;
+
+-- name: FindKeywordByID :one
+SELECT * FROM keywords WHERE id = $1;
+
+-- name: InsertKeywordAlias :exec
+INSERT INTO keyword_aliases (alias_key, alias, keyword_id) VALUES ($1, $2, $3)
+ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, keyword_id = EXCLUDED.keyword_id;
+
+-- name: ApproveKeyword :execrows
+UPDATE keywords SET approved = true WHERE id = $1;
+
+-- name: MoveDocKeywords :exec
+UPDATE doc_keywords d
+SET keyword_id = sqlc.arg(to_id)::uuid
+WHERE d.keyword_id = sqlc.arg(from_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM doc_keywords e WHERE e.doc_id = d.doc_id AND e.keyword_id = sqlc.arg(to_id)::uuid);
+
+-- name: MoveKeywordAliases :exec
+UPDATE keyword_aliases SET keyword_id = sqlc.arg(to_id)::uuid WHERE keyword_id = sqlc.arg(from_id)::uuid;
+
+-- name: MoveDocKeywordsForDocuments :exec
+UPDATE doc_keywords d
+SET keyword_id = sqlc.arg(to_id)::uuid
+WHERE d.keyword_id = sqlc.arg(from_id)::uuid
+  AND d.doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+  AND NOT EXISTS (SELECT 1 FROM doc_keywords e WHERE e.doc_id = d.doc_id AND e.keyword_id = sqlc.arg(to_id)::uuid);
+
+-- name: DeleteDocKeywordsForDocuments :exec
+DELETE FROM doc_keywords
+WHERE keyword_id = sqlc.arg(keyword_id)::uuid
+  AND doc_id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: MoveVocabularyKeyword :exec
+INSERT INTO vocabulary_keywords (keyword_id)
+SELECT sqlc.arg(to_id)::uuid
+WHERE EXISTS (SELECT 1 FROM vocabulary_keywords WHERE keyword_id = sqlc.arg(from_id)::uuid)
+ON CONFLICT DO NOTHING;
+
+-- name: RenameKeyword :exec
+UPDATE keywords SET name = $2 WHERE id = $1;
+
+-- name: DeleteUnusedKeyword :execrows
+DELETE FROM keywords t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM doc_keywords d WHERE d.keyword_id = t.id);
+
+-- name: ListKeywordsForAdmin :many
+SELECT
+    t.id,
+    t.name,
+    t.approved,
+    (SELECT COUNT(*) FROM doc_keywords d WHERE d.keyword_id = t.id) AS documents,
+    COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM keyword_aliases a WHERE a.keyword_id = t.id), '{}')::text[] AS aliases,
+    COUNT(*) OVER () AS total
+FROM keywords t
+WHERE sqlc.arg(query)::text = '' OR t.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY t.name
+LIMIT sqlc.arg(max_terms)::int;
+
+-- name: ListKeywordDocuments :many
+SELECT d.id, d.title
+FROM doc_keywords x
+JOIN documents d ON d.id = x.doc_id
+WHERE x.keyword_id = sqlc.arg(keyword_id)::uuid
+ORDER BY d.title;
+
+-- name: DeleteKeyword :execrows
+DELETE FROM keywords WHERE id = $1;
+
+-- name: FlagKeywordDocumentsForIndex :exec
+UPDATE documents
+SET to_index = true
+WHERE id IN (SELECT doc_id FROM doc_keywords WHERE keyword_id = sqlc.arg(keyword_id)::uuid)
+  AND (NOT sqlc.arg(approved_only)::boolean OR review_state = 'approved');
+
+-- name: ListPublisherTerms :many
+SELECT t.id, t.name, ''::text AS alias FROM publishers t
+UNION ALL
+SELECT t.id, t.name, a.alias::text AS alias
+FROM publisher_aliases a
+JOIN publishers t ON t.id = a.publisher_id;
+
+-- name: FindPublisherByID :one
+SELECT * FROM publishers WHERE id = $1;
+
+-- name: InsertPublisherAlias :exec
+INSERT INTO publisher_aliases (alias_key, alias, publisher_id) VALUES ($1, $2, $3)
+ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, publisher_id = EXCLUDED.publisher_id;
+
+-- name: ApprovePublisher :execrows
+UPDATE publishers SET approved = true WHERE id = $1;
+
+-- name: MoveDocPublishers :exec
+UPDATE documents
+SET publisher_id = sqlc.arg(to_id)::uuid
+WHERE publisher_id = sqlc.arg(from_id)::uuid;
+
+-- name: MovePublisherAliases :exec
+UPDATE publisher_aliases SET publisher_id = sqlc.arg(to_id)::uuid WHERE publisher_id = sqlc.arg(from_id)::uuid;
+
+-- name: MoveDocPublishersForDocuments :exec
+UPDATE documents
+SET publisher_id = sqlc.arg(to_id)::uuid
+WHERE publisher_id = sqlc.arg(from_id)::uuid
+  AND id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: DeleteDocPublishersForDocuments :exec
+UPDATE documents
+SET publisher_id = NULL
+WHERE publisher_id = sqlc.arg(publisher_id)::uuid
+  AND id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: RenamePublisher :exec
+UPDATE publishers SET name = $2 WHERE id = $1;
+
+-- name: DeleteUnusedPublisher :execrows
+DELETE FROM publishers t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM documents d WHERE d.publisher_id = t.id);
+
+-- name: ListPublishersForAdmin :many
+SELECT
+    t.id,
+    t.name,
+    t.approved,
+    (SELECT COUNT(*) FROM documents d WHERE d.publisher_id = t.id) AS documents,
+    COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM publisher_aliases a WHERE a.publisher_id = t.id), '{}')::text[] AS aliases,
+    COUNT(*) OVER () AS total
+FROM publishers t
+WHERE sqlc.arg(query)::text = '' OR t.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY t.name
+LIMIT sqlc.arg(max_terms)::int;
+
+-- name: ListPublisherDocuments :many
+SELECT d.id, d.title
+FROM documents d
+WHERE d.publisher_id = sqlc.arg(publisher_id)::uuid
+ORDER BY d.title;
+
+-- name: DeletePublisher :execrows
+DELETE FROM publishers WHERE id = $1;
+
+-- name: FlagPublisherDocumentsForIndex :exec
+UPDATE documents
+SET to_index = true
+WHERE publisher_id = sqlc.arg(publisher_id)::uuid
+  AND (NOT sqlc.arg(approved_only)::boolean OR review_state = 'approved');
+
+-- name: ListRegionTerms :many
+SELECT t.id, t.name, ''::text AS alias FROM regions t
+UNION ALL
+SELECT t.id, t.name, a.alias::text AS alias
+FROM region_aliases a
+JOIN regions t ON t.id = a.region_id;
+
+-- name: FindRegionByID :one
+SELECT * FROM regions WHERE id = $1;
+
+-- name: InsertRegionAlias :exec
+INSERT INTO region_aliases (alias_key, alias, region_id) VALUES ($1, $2, $3)
+ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, region_id = EXCLUDED.region_id;
+
+-- name: ApproveRegion :execrows
+UPDATE regions SET approved = true WHERE id = $1;
+
+-- name: MoveDocRegions :exec
+UPDATE doc_regions d
+SET region_id = sqlc.arg(to_id)::uuid
+WHERE d.region_id = sqlc.arg(from_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM doc_regions e WHERE e.doc_id = d.doc_id AND e.region_id = sqlc.arg(to_id)::uuid);
+
+-- name: MoveRegionAliases :exec
+UPDATE region_aliases SET region_id = sqlc.arg(to_id)::uuid WHERE region_id = sqlc.arg(from_id)::uuid;
+
+-- name: MoveDocRegionsForDocuments :exec
+UPDATE doc_regions d
+SET region_id = sqlc.arg(to_id)::uuid
+WHERE d.region_id = sqlc.arg(from_id)::uuid
+  AND d.doc_id = ANY(sqlc.arg(doc_ids)::uuid[])
+  AND NOT EXISTS (SELECT 1 FROM doc_regions e WHERE e.doc_id = d.doc_id AND e.region_id = sqlc.arg(to_id)::uuid);
+
+-- name: DeleteDocRegionsForDocuments :exec
+DELETE FROM doc_regions
+WHERE region_id = sqlc.arg(region_id)::uuid
+  AND doc_id = ANY(sqlc.arg(doc_ids)::uuid[]);
+
+-- name: MoveVocabularyRegion :exec
+INSERT INTO vocabulary_regions (region_id)
+SELECT sqlc.arg(to_id)::uuid
+WHERE EXISTS (SELECT 1 FROM vocabulary_regions WHERE region_id = sqlc.arg(from_id)::uuid)
+ON CONFLICT DO NOTHING;
+
+-- name: RenameRegion :exec
+UPDATE regions SET name = $2 WHERE id = $1;
+
+-- name: DeleteUnusedRegion :execrows
+DELETE FROM regions t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM doc_regions d WHERE d.region_id = t.id);
+
+-- name: ListRegionsForAdmin :many
+SELECT
+    t.id,
+    t.name,
+    t.approved,
+    (SELECT COUNT(*) FROM doc_regions d WHERE d.region_id = t.id) AS documents,
+    COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM region_aliases a WHERE a.region_id = t.id), '{}')::text[] AS aliases,
+    COUNT(*) OVER () AS total
+FROM regions t
+WHERE sqlc.arg(query)::text = '' OR t.name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY t.name
+LIMIT sqlc.arg(max_terms)::int;
+
+-- name: ListRegionDocuments :many
+SELECT d.id, d.title
+FROM doc_regions x
+JOIN documents d ON d.id = x.doc_id
+WHERE x.region_id = sqlc.arg(region_id)::uuid
+ORDER BY d.title;
+
+-- name: DeleteRegion :execrows
+DELETE FROM regions WHERE id = $1;
+
+-- name: FlagRegionDocumentsForIndex :exec
+UPDATE documents
+SET to_index = true
+WHERE id IN (SELECT doc_id FROM doc_regions WHERE region_id = sqlc.arg(region_id)::uuid)
+  AND (NOT sqlc.arg(approved_only)::boolean OR review_state = 'approved');
+
+-- name: ListPendingTerms :many
+SELECT 'authors'::text AS taxonomy, t.id, t.name, (SELECT COUNT(*) FROM doc_authors d WHERE d.author_id = t.id) AS documents
+FROM authors t WHERE NOT t.approved
+UNION ALL
+SELECT 'categories'::text, t.id, t.name, (SELECT COUNT(*) FROM doc_categories d WHERE d.category_id = t.id)
+FROM categories t WHERE NOT t.approved
+UNION ALL
+SELECT 'keywords'::text, t.id, t.name, (SELECT COUNT(*) FROM doc_keywords d WHERE d.keyword_id = t.id)
+FROM keywords t WHERE NOT t.approved
+UNION ALL
+SELECT 'publishers'::text, t.id, t.name, (SELECT COUNT(*) FROM documents d WHERE d.publisher_id = t.id)
+FROM publishers t WHERE NOT t.approved
+UNION ALL
+SELECT 'regions'::text, t.id, t.name, (SELECT COUNT(*) FROM doc_regions d WHERE d.region_id = t.id)
+FROM regions t WHERE NOT t.approved
+ORDER BY taxonomy, name;
@@ -2,23 +2,22 @@
 SELECT * FROM users WHERE username = $1;
 
 -- name: CreateUser :exec
-INSERT INTO users (username, password_hash, is_master)
+INSERT INTO users (username, password_hash, role)
 VALUES ($1, $2, $3);
 
 -- name: ListUsers :many
-SELECT username, is_master FROM users ORDER BY username;
+SELECT username, role FROM users ORDER BY username;
 
 -- name: DeleteUserByUsername :exec
 DELETE FROM users WHERE username = $1;
 
 -- name: GetUserByID :one
-SELECT id, username, password_hash, is_master, created_at
+SELECT id, username, password_hash, role, created_at
 FROM users
 WHERE id = $1;
 
+-- name: UpdateUserRole :exec
+UPDATE users SET role = $2 WHERE username = $1;
 
-
-
-
-
-
+-- name: CountUsersWithRole :one
+SELECT count(*) FROM users WHERE role = $1;
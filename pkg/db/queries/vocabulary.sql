@@ -0,0 +1,38 @@
+-- name: ListVocabularyCategories :many
+SELECT c.name
+FROM vocabulary_categories v
+JOIN categories c ON c.id = v.category_id
+ORDER BY c.name;
+
+-- name: ListVocabularyRegions :many
+SELECT r.name
+FROM vocabulary_regions v
+JOIN regions r ON r.id = v.region_id
+ORDER BY r.name;
+
+-- name: ListVocabularyKeywords :many
+SELECT k.name
+FROM vocabulary_keywords v
+JOIN keywords k ON k.id = v.keyword_id
+ORDER BY k.name;
+
+-- name: AddVocabularyCategory :exec
+INSERT INTO vocabulary_categories (category_id) VALUES ($1)
+ON CONFLICT DO NOTHING;
+
+-- name: AddVocabularyRegion :exec
+INSERT INTO vocabulary_regions (region_id) VALUES ($1)
+ON CONFLICT DO NOTHING;
+
+-- name: AddVocabularyKeyword :exec
+INSERT INTO vocabulary_keywords (keyword_id) VALUES ($1)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveVocabularyCategory :execrows
+DELETE FROM vocabulary_categories WHERE category_id = $1;
+
+-- name: RemoveVocabularyRegion :execrows
+DELETE FROM vocabulary_regions WHERE region_id = $1;
+
+-- name: RemoveVocabularyKeyword :execrows
+DELETE FROM vocabulary_keywords WHERE keyword_id = $1;
@@ -0,0 +1,655 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+)
+
+const unknownEditor = "unknown"
+
+// Review states of a document. Documents with fields written by a model start
+// as ReviewAIExtracted, move to ReviewInReview when someone edits them, and to
+// ReviewApproved when a reviewer approves them. Editing an approved document
+// without approving it again moves it back to ReviewInReview.
+const (
+	ReviewAIExtracted = "ai_extracted"
+	ReviewInReview    = "in_review"
+	ReviewApproved    = "approved"
+)
+
+// Where a metadata field's value came from, as stored in document_field_sources.
+const (
+	SourceLLM   = "llm"
+	SourceHuman = "human"
+)
+
+// Querier is the subset of db.Queries used by DocumentRepository and
+// TermRepository.
+type Querier interface {
+	util.TermQuerier
+
+	FindDocumentByID(ctx context.Context, id uuid.UUID) (db.FindDocumentByIDRow, error)
+	InsertUploadedDocument(ctx context.Context, arg db.InsertUploadedDocumentParams) error
+	UpdateDocumentMetadata(ctx context.Context, arg db.UpdateDocumentMetadataParams) error
+
+	DeleteDocAuthorsByDocID(ctx context.Context, docID uuid.NullUUID) error
+	DeleteDocKeywordsByDocID(ctx context.Context, docID uuid.NullUUID) error
+	DeleteDocCategoriesByDocID(ctx context.Context, docID uuid.NullUUID) error
+	DeleteDocRegionsByDocID(ctx context.Context, docID uuid.NullUUID) error
+
+	InsertDocAuthor(ctx context.Context, arg db.InsertDocAuthorParams) error
+	InsertDocKeyword(ctx context.Context, arg db.InsertDocKeywordParams) error
+	InsertDocCategory(ctx context.Context, arg db.InsertDocCategoryParams) error
+	InsertDocRegion(ctx context.Context, arg db.InsertDocRegionParams) error
+
+	InsertDocumentRevision(ctx context.Context, arg db.InsertDocumentRevisionParams) (int32, error)
+
+	SetTranslationGroup(ctx context.Context, arg db.SetTranslationGroupParams) error
+	ClearTranslationGroup(ctx context.Context, arg db.ClearTranslationGroupParams) error
+	DissolveLoneTranslationGroup(ctx context.Context, translationGroup uuid.NullUUID) error
+
+	DeleteDocumentPagesByDocID(ctx context.Context, docID uuid.UUID) error
+	InsertDocumentPage(ctx context.Context, arg db.InsertDocumentPageParams) error
+
+	SetDocumentFieldSource(ctx context.Context, arg db.SetDocumentFieldSourceParams) error
+	SetDocumentReviewState(ctx context.Context, arg db.SetDocumentReviewStateParams) error
+	ApproveDocument(ctx context.Context, arg db.ApproveDocumentParams) error
+
+	FindAuthorByID(ctx context.Context, id uuid.UUID) (db.Author, error)
+	InsertAuthorAlias(ctx context.Context, arg db.InsertAuthorAliasParams) error
+	ApproveAuthor(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveDocAuthors(ctx context.Context, arg db.MoveDocAuthorsParams) error
+	MoveAuthorAliases(ctx context.Context, arg db.MoveAuthorAliasesParams) error
+	DeleteAuthor(ctx context.Context, id uuid.UUID) (int64, error)
+	FlagAuthorDocumentsForIndex(ctx context.Context, arg db.FlagAuthorDocumentsForIndexParams) error
+	MoveDocAuthorsForDocuments(ctx context.Context, arg db.MoveDocAuthorsForDocumentsParams) error
+	DeleteDocAuthorsForDocuments(ctx context.Context, arg db.DeleteDocAuthorsForDocumentsParams) error
+	RenameAuthor(ctx context.Context, arg db.RenameAuthorParams) error
+	DeleteUnusedAuthor(ctx context.Context, id uuid.UUID) (int64, error)
+
+	FindKeywordByID(ctx context.Context, id uuid.UUID) (db.Keyword, error)
+	InsertKeywordAlias(ctx context.Context, arg db.InsertKeywordAliasParams) error
+	ApproveKeyword(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveDocKeywords(ctx context.Context, arg db.MoveDocKeywordsParams) error
+	MoveKeywordAliases(ctx context.Context, arg db.MoveKeywordAliasesParams) error
+	DeleteKeyword(ctx context.Context, id uuid.UUID) (int64, error)
+	FlagKeywordDocumentsForIndex(ctx context.Context, arg db.FlagKeywordDocumentsForIndexParams) error
+	MoveDocKeywordsForDocuments(ctx context.Context, arg db.MoveDocKeywordsForDocumentsParams) error
+	DeleteDocKeywordsForDocuments(ctx context.Context, arg db.DeleteDocKeywordsForDocumentsParams) error
+	RenameKeyword(ctx context.Context, arg db.RenameKeywordParams) error
+	DeleteUnusedKeyword(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveVocabularyKeyword(ctx context.Context, arg db.MoveVocabularyKeywordParams) error
+
+	FindPublisherByID(ctx context.Context, id uuid.UUID) (db.Publisher, error)
+	InsertPublisherAlias(ctx context.Context, arg db.InsertPublisherAliasParams) error
+	ApprovePublisher(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveDocPublishers(ctx context.Context, arg db.MoveDocPublishersParams) error
+	MovePublisherAliases(ctx context.Context, arg db.MovePublisherAliasesParams) error
+	DeletePublisher(ctx context.Context, id uuid.UUID) (int64, error)
+	FlagPublisherDocumentsForIndex(ctx context.Context, arg db.FlagPublisherDocumentsForIndexParams) error
+	MoveDocPublishersForDocuments(ctx context.Context, arg db.MoveDocPublishersForDocumentsParams) error
+	DeleteDocPublishersForDocuments(ctx context.Context, arg db.DeleteDocPublishersForDocumentsParams) error
+	RenamePublisher(ctx context.Context, arg db.RenamePublisherParams) error
+	DeleteUnusedPublisher(ctx context.Context, id uuid.UUID) (int64, error)
+
+	FindCategoryByID(ctx context.Context, id uuid.UUID) (db.Category, error)
+	InsertCategoryAlias(ctx context.Context, arg db.InsertCategoryAliasParams) error
+	ApproveCategory(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveDocCategories(ctx context.Context, arg db.MoveDocCategoriesParams) error
+	MoveCategoryAliases(ctx context.Context, arg db.MoveCategoryAliasesParams) error
+	DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error)
+	FlagCategoryDocumentsForIndex(ctx context.Context, arg db.FlagCategoryDocumentsForIndexParams) error
+	MoveDocCategoriesForDocuments(ctx context.Context, arg db.MoveDocCategoriesForDocumentsParams) error
+	DeleteDocCategoriesForDocuments(ctx context.Context, arg db.DeleteDocCategoriesForDocumentsParams) error
+	RenameCategory(ctx context.Context, arg db.RenameCategoryParams) error
+	DeleteUnusedCategory(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveVocabularyCategory(ctx context.Context, arg db.MoveVocabularyCategoryParams) error
+
+	FindRegionByID(ctx context.Context, id uuid.UUID) (db.Region, error)
+	InsertRegionAlias(ctx context.Context, arg db.InsertRegionAliasParams) error
+	ApproveRegion(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveDocRegions(ctx context.Context, arg db.MoveDocRegionsParams) error
+	MoveRegionAliases(ctx context.Context, arg db.MoveRegionAliasesParams) error
+	DeleteRegion(ctx context.Context, id uuid.UUID) (int64, error)
+	FlagRegionDocumentsForIndex(ctx context.Context, arg db.FlagRegionDocumentsForIndexParams) error
+	MoveDocRegionsForDocuments(ctx context.Context, arg db.MoveDocRegionsForDocumentsParams) error
+	DeleteDocRegionsForDocuments(ctx context.Context, arg db.DeleteDocRegionsForDocumentsParams) error
+	RenameRegion(ctx context.Context, arg db.RenameRegionParams) error
+	DeleteUnusedRegion(ctx context.Context, id uuid.UUID) (int64, error)
+	MoveVocabularyRegion(ctx context.Context, arg db.MoveVocabularyRegionParams) error
+	MoveRegionChildren(ctx context.Context, arg db.MoveRegionChildrenParams) error
+	UpdateAuthorDetails(ctx context.Context, arg db.UpdateAuthorDetailsParams) (int64, error)
+}
+
+// TxRunner runs fn in a transaction. The transaction is committed if fn
+// returns nil and rolled back otherwise.
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(q Querier) error) error
+}
+
+type sqlTxRunner struct {
+	sqlDB   *sql.DB
+	queries *db.Queries
+}
+
+// NewTxRunner runs transactions on sqlDB with queries.WithTx.
+func NewTxRunner(sqlDB *sql.DB, queries *db.Queries) TxRunner {
+	return &sqlTxRunner{sqlDB: sqlDB, queries: queries}
+}
+
+func (r *sqlTxRunner) WithTx(ctx context.Context, fn func(q Querier) error) error {
+	tx, err := r.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(r.queries.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DocumentRepository writes a document together with its authors, keywords,
+// categories, regions and publisher and a metadata revision, so that a failure part way
+// through leaves nothing behind.
+type DocumentRepository struct {
+	tx                TxRunner
+	indexApprovedOnly bool
+}
+
+// NewDocumentRepository creates the repository. With indexApprovedOnly, only
+// approved documents are queued for indexing; the rest wait for a reviewer.
+func NewDocumentRepository(tx TxRunner, indexApprovedOnly bool) *DocumentRepository {
+	return &DocumentRepository{tx: tx, indexApprovedOnly: indexApprovedOnly}
+}
+
+// Terms lists a document's associations as the metadata form posts them: the
+// UUID of an existing term, or NewTermPrefix followed by a name to find or create.
+// A document has one publisher at most; an empty Publisher means none.
+type Terms struct {
+	Authors    []string
+	Keywords   []string
+	Categories []string
+	Regions    []string
+	Publisher  string
+}
+
+// ByName turns term names into values that Terms resolves by name.
+func ByName(names []string) []string {
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = util.NewTermPrefix + name
+	}
+	return values
+}
+
+// PublisherByName is ByName for Terms.Publisher, keeping a blank name blank.
+func PublisherByName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return ""
+	}
+	return util.NewTermPrefix + name
+}
+
+// NewDocument is an uploaded document and its extracted metadata. Sources maps
+// the audit log's field names, such as "title" and "authors", to SourceLLM or
+// SourceHuman. The document starts as ReviewAIExtracted if any field came from
+// a model, and ReviewInReview otherwise. Language is an ISO 639-1 code, and
+// EnglishTitle and EnglishAbstract translate a document in another language.
+type NewDocument struct {
+	ID              uuid.UUID
+	S3File          string
+	FileName        string
+	Title           string
+	Abstract        sql.NullString
+	PublishDate     sql.NullTime
+	ContentHash     sql.NullString
+	Language        sql.NullString
+	EnglishTitle    sql.NullString
+	EnglishAbstract sql.NullString
+	Terms           Terms
+	Sources         map[string]string
+}
+
+// MetadataUpdate replaces a document's editable metadata. Approve marks the
+// document approved by the editor in RevisionInfo. Translations, unless nil,
+// replaces the documents linked as translations as SetTranslations does.
+// ORCIDs sets the ORCID iDs of the document's authors.
+type MetadataUpdate struct {
+	DocID           uuid.UUID
+	Title           string
+	Abstract        sql.NullString
+	PublishDate     sql.NullTime
+	Language        sql.NullString
+	EnglishTitle    sql.NullString
+	EnglishAbstract sql.NullString
+	Terms           Terms
+	Translations    []uuid.UUID
+	ORCIDs          []AuthorORCID
+	Approve         bool
+}
+
+// AuthorORCID is the ORCID iD of an author, in its hyphenated form. A blank
+// ORCID removes the author's iD.
+type AuthorORCID struct {
+	AuthorID uuid.UUID
+	ORCID    string
+}
+
+// RevisionInfo says who made a change, for the revision it creates.
+// RestoredFrom is the revision being restored, or 0.
+type RevisionInfo struct {
+	CreatedBy     uuid.UUID
+	CreatedByName string
+	RestoredFrom  int32
+}
+
+// CreateDocument inserts an uploaded document with its terms, field sources
+// and first revision.
+func (r *DocumentRepository) CreateDocument(ctx context.Context, doc NewDocument, info RevisionInfo) error {
+	state := ReviewInReview
+	if slices.Contains(slices.Collect(maps.Values(doc.Sources)), SourceLLM) {
+		state = ReviewAIExtracted
+	}
+
+	return r.tx.WithTx(ctx, func(q Querier) error {
+		publisher, err := resolvePublisher(ctx, q, doc.Terms.Publisher)
+		if err != nil {
+			return err
+		}
+		if err := q.InsertUploadedDocument(ctx, db.InsertUploadedDocumentParams{
+			ID:              doc.ID,
+			S3File:          doc.S3File,
+			FileName:        doc.FileName,
+			Title:           doc.Title,
+			Abstract:        doc.Abstract,
+			PublishDate:     doc.PublishDate,
+			ContentHash:     doc.ContentHash,
+			PublisherID:     publisher,
+			Language:        doc.Language,
+			EnglishTitle:    doc.EnglishTitle,
+			EnglishAbstract: doc.EnglishAbstract,
+			ReviewState:     state,
+			ToIndex:         r.toIndex(state),
+		}); err != nil {
+			return fmt.Errorf("failed to insert document: %w", err)
+		}
+
+		if err := addTerms(ctx, q, doc.ID, doc.Terms); err != nil {
+			return err
+		}
+
+		for _, field := range slices.Sorted(maps.Keys(doc.Sources)) {
+			if err := setFieldSource(ctx, q, doc.ID, field, doc.Sources[field]); err != nil {
+				return err
+			}
+		}
+
+		saved, err := q.FindDocumentByID(ctx, doc.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read document for revision: %w", err)
+		}
+		_, err = recordRevision(ctx, q, saved, info)
+		return err
+	})
+}
+
+// SaveMetadata updates the document, replaces its terms and records the result
+// as a new revision, which it returns. Fields whose values change are marked as
+// written by a person. The document moves to ReviewInReview, or to
+// ReviewApproved when the update approves it. The
+// document is queued for re-indexing unless only approved documents are indexed
+// and it is not approved.
+func (r *DocumentRepository) SaveMetadata(ctx context.Context, update MetadataUpdate, info RevisionInfo) (int32, error) {
+	var revision int32
+	err := r.tx.WithTx(ctx, func(q Querier) error {
+		before, err := q.FindDocumentByID(ctx, update.DocID)
+		if err != nil {
+			return fmt.Errorf("failed to read document: %w", err)
+		}
+		state := nextReviewState(update.Approve)
+		publisher, err := resolvePublisher(ctx, q, update.Terms.Publisher)
+		if err != nil {
+			return err
+		}
+
+		if err := q.UpdateDocumentMetadata(ctx, db.UpdateDocumentMetadataParams{
+			ID:              update.DocID,
+			Title:           update.Title,
+			Abstract:        update.Abstract,
+			PublishDate:     update.PublishDate,
+			PublisherID:     publisher,
+			Language:        update.Language,
+			EnglishTitle:    update.EnglishTitle,
+			EnglishAbstract: update.EnglishAbstract,
+			ToIndex:         r.toIndex(state),
+		}); err != nil {
+			return fmt.Errorf("failed to update document: %w", err)
+		}
+
+		if err := clearTerms(ctx, q, update.DocID); err != nil {
+			return err
+		}
+		if err := addTerms(ctx, q, update.DocID, update.Terms); err != nil {
+			return err
+		}
+		if update.Translations != nil {
+			if err := setTranslations(ctx, q, update.DocID, update.Translations); err != nil {
+				return err
+			}
+		}
+		for _, author := range update.ORCIDs {
+			if err := setAuthorORCID(ctx, q, author); err != nil {
+				return err
+			}
+		}
+
+		after, err := q.FindDocumentByID(ctx, update.DocID)
+		if err != nil {
+			return fmt.Errorf("failed to read document for revision: %w", err)
+		}
+		for _, field := range changedFields(before, after) {
+			if err := setFieldSource(ctx, q, update.DocID, field, SourceHuman); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case update.Approve:
+			if err := q.ApproveDocument(ctx, db.ApproveDocumentParams{
+				ID:             update.DocID,
+				ApprovedBy:     uuid.NullUUID{UUID: info.CreatedBy, Valid: info.CreatedBy != uuid.Nil},
+				ApprovedByName: sql.NullString{String: editorName(info), Valid: true},
+			}); err != nil {
+				return fmt.Errorf("failed to approve document: %w", err)
+			}
+		case state != before.ReviewState:
+			if err := q.SetDocumentReviewState(ctx, db.SetDocumentReviewStateParams{ID: update.DocID, ReviewState: state}); err != nil {
+				return fmt.Errorf("failed to update review state: %w", err)
+			}
+		}
+
+		revision, err = recordRevision(ctx, q, after, info)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return revision, nil
+}
+
+// SetTranslations makes translations the documents that docID is linked to as
+// translations of the same document. Documents already linked to one of them
+// join too, and documents that were linked to docID but are not listed leave.
+// An empty list unlinks docID from its translations.
+func (r *DocumentRepository) SetTranslations(ctx context.Context, docID uuid.UUID, translations []uuid.UUID) error {
+	return r.tx.WithTx(ctx, func(q Querier) error {
+		return setTranslations(ctx, q, docID, translations)
+	})
+}
+
+func setTranslations(ctx context.Context, q Querier, docID uuid.UUID, translations []uuid.UUID) error {
+	doc, err := q.FindDocumentByID(ctx, docID)
+	if err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	current := doc.TranslationGroup
+
+	// Never nil, since a null array would keep every document in the group
+	members := []uuid.UUID{}
+	for _, id := range translations {
+		if id != docID && !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+	if len(members) > 0 {
+		members = append(members, docID)
+	}
+
+	if current.Valid {
+		if err := q.ClearTranslationGroup(ctx, db.ClearTranslationGroupParams{
+			TranslationGroup: current.UUID,
+			KeepIds:          members,
+		}); err != nil {
+			return fmt.Errorf("failed to unlink translations: %w", err)
+		}
+	}
+	if len(members) > 0 {
+		group := current.UUID
+		if !current.Valid {
+			group = uuid.New()
+		}
+		if err := q.SetTranslationGroup(ctx, db.SetTranslationGroupParams{
+			TranslationGroup: group,
+			DocIds:           members,
+		}); err != nil {
+			return fmt.Errorf("failed to link translations: %w", err)
+		}
+	}
+	if current.Valid {
+		if err := q.DissolveLoneTranslationGroup(ctx, current); err != nil {
+			return fmt.Errorf("failed to unlink the last translation: %w", err)
+		}
+	}
+	return nil
+}
+
+// setAuthorORCID sets an author's ORCID iD and keeps its affiliation.
+func setAuthorORCID(ctx context.Context, q Querier, author AuthorORCID) error {
+	row, err := q.FindAuthorByID(ctx, author.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to read author: %w", err)
+	}
+	if _, err := q.UpdateAuthorDetails(ctx, db.UpdateAuthorDetailsParams{
+		ID:          author.AuthorID,
+		Orcid:       sql.NullString{String: author.ORCID, Valid: author.ORCID != ""},
+		Affiliation: row.Affiliation,
+	}); err != nil {
+		return fmt.Errorf("failed to set ORCID iD of %s: %w", row.Name, err)
+	}
+	return nil
+}
+
+// toIndex is the to_index flag for a document in the given review state.
+func (r *DocumentRepository) toIndex(state string) sql.NullBool {
+	return sql.NullBool{Bool: !r.indexApprovedOnly || state == ReviewApproved, Valid: true}
+}
+
+// nextReviewState is the state of a document after a metadata save. An
+// approved document that is edited needs approving again, so that its new
+// metadata is not indexed unreviewed.
+func nextReviewState(approve bool) string {
+	if approve {
+		return ReviewApproved
+	}
+	return ReviewInReview
+}
+
+func setFieldSource(ctx context.Context, q Querier, docID uuid.UUID, field, source string) error {
+	if err := q.SetDocumentFieldSource(ctx, db.SetDocumentFieldSourceParams{DocID: docID, Field: field, Source: source}); err != nil {
+		return fmt.Errorf("failed to record source of %s: %w", field, err)
+	}
+	return nil
+}
+
+// changedFields names the metadata fields that differ between two reads of a
+// document, using the audit log's field names.
+func changedFields(before, after db.FindDocumentByIDRow) []string {
+	fields := []struct {
+		name    string
+		changed bool
+	}{
+		{"title", before.Title != after.Title},
+		{"abstract", before.Abstract.String != after.Abstract.String},
+		{"publish_date", before.PublishDate.Valid != after.PublishDate.Valid || !before.PublishDate.Time.Equal(after.PublishDate.Time)},
+		{"source", before.Source.String != after.Source.String},
+		{"language", before.Language.String != after.Language.String},
+		{"english_title", before.EnglishTitle.String != after.EnglishTitle.String},
+		{"english_abstract", before.EnglishAbstract.String != after.EnglishAbstract.String},
+		{"authors", !slices.Equal(revisionNames(before.AuthorNames), revisionNames(after.AuthorNames))},
+		{"keywords", !slices.Equal(revisionNames(before.KeywordNames), revisionNames(after.KeywordNames))},
+		{"regions", !slices.Equal(revisionNames(before.RegionNames), revisionNames(after.RegionNames))},
+		{"categories", !slices.Equal(revisionNames(before.CategoryNames), revisionNames(after.CategoryNames))},
+	}
+	var changed []string
+	for _, field := range fields {
+		if field.changed {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
+
+func clearTerms(ctx context.Context, q Querier, docID uuid.UUID) error {
+	documentID := uuid.NullUUID{UUID: docID, Valid: true}
+
+	if err := q.DeleteDocAuthorsByDocID(ctx, documentID); err != nil {
+		return fmt.Errorf("failed to clear authors: %w", err)
+	}
+	if err := q.DeleteDocKeywordsByDocID(ctx, documentID); err != nil {
+		return fmt.Errorf("failed to clear keywords: %w", err)
+	}
+	if err := q.DeleteDocCategoriesByDocID(ctx, documentID); err != nil {
+		return fmt.Errorf("failed to clear categories: %w", err)
+	}
+	if err := q.DeleteDocRegionsByDocID(ctx, documentID); err != nil {
+		return fmt.Errorf("failed to clear regions: %w", err)
+	}
+	return nil
+}
+
+func addTerms(ctx context.Context, q Querier, docID uuid.UUID, terms Terms) error {
+	documentID := uuid.NullUUID{UUID: docID, Valid: true}
+
+	authors, err := util.ResolveIDs(ctx, q, terms.Authors, util.GetOrCreateAuthor)
+	if err != nil {
+		return fmt.Errorf("failed to resolve authors: %w", err)
+	}
+	keywords, err := util.ResolveIDs(ctx, q, terms.Keywords, util.GetOrCreateKeyword)
+	if err != nil {
+		return fmt.Errorf("failed to resolve keywords: %w", err)
+	}
+	categories, err := util.ResolveIDs(ctx, q, terms.Categories, util.GetOrCreateCategory)
+	if err != nil {
+		return fmt.Errorf("failed to resolve categories: %w", err)
+	}
+	regions, err := util.ResolveIDs(ctx, q, terms.Regions, util.GetOrCreateRegion)
+	if err != nil {
+		return fmt.Errorf("failed to resolve regions: %w", err)
+	}
+
+	for _, authorID := range authors {
+		if err := q.InsertDocAuthor(ctx, db.InsertDocAuthorParams{
+			ID:       uuid.New(),
+			DocID:    documentID,
+			AuthorID: uuid.NullUUID{UUID: authorID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to insert into doc_authors: %w", err)
+		}
+	}
+
+	for _, keywordID := range keywords {
+		if err := q.InsertDocKeyword(ctx, db.InsertDocKeywordParams{
+			ID:        uuid.New(),
+			DocID:     documentID,
+			KeywordID: uuid.NullUUID{UUID: keywordID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to insert into doc_keywords: %w", err)
+		}
+	}
+
+	for _, categoryID := range categories {
+		if err := q.InsertDocCategory(ctx, db.InsertDocCategoryParams{
+			ID:         uuid.New(),
+			DocID:      documentID,
+			CategoryID: uuid.NullUUID{UUID: categoryID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to insert into doc_categories: %w", err)
+		}
+	}
+
+	for _, regionID := range regions {
+		if err := q.InsertDocRegion(ctx, db.InsertDocRegionParams{
+			ID:       uuid.New(),
+			DocID:    documentID,
+			RegionID: uuid.NullUUID{UUID: regionID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed to insert into doc_regions: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolvePublisher resolves a document's publisher as addTerms resolves its
+// other terms. The ID is null when the document has none.
+func resolvePublisher(ctx context.Context, q Querier, value string) (uuid.NullUUID, error) {
+	ids, err := util.ResolveIDs(ctx, q, []string{value}, util.GetOrCreatePublisher)
+	if err != nil {
+		return uuid.NullUUID{}, fmt.Errorf("failed to resolve publisher: %w", err)
+	}
+	if len(ids) == 0 {
+		return uuid.NullUUID{}, nil
+	}
+	return uuid.NullUUID{UUID: ids[0], Valid: true}, nil
+}
+
+// recordRevision copies the document as just read from q into a new revision.
+func recordRevision(ctx context.Context, q Querier, doc db.FindDocumentByIDRow, info RevisionInfo) (int32, error) {
+	revision, err := q.InsertDocumentRevision(ctx, db.InsertDocumentRevisionParams{
+		DocID:           doc.ID,
+		Title:           doc.Title,
+		Abstract:        doc.Abstract,
+		PublishDate:     doc.PublishDate,
+		Source:          doc.Source,
+		Language:        doc.Language,
+		EnglishTitle:    doc.EnglishTitle,
+		EnglishAbstract: doc.EnglishAbstract,
+		Authors:         revisionNames(doc.AuthorNames),
+		Keywords:        revisionNames(doc.KeywordNames),
+		Regions:         revisionNames(doc.RegionNames),
+		Categories:      revisionNames(doc.CategoryNames),
+		RestoredFrom:    sql.NullInt32{Int32: info.RestoredFrom, Valid: info.RestoredFrom != 0},
+		CreatedBy:       uuid.NullUUID{UUID: info.CreatedBy, Valid: info.CreatedBy != uuid.Nil},
+		CreatedByName:   editorName(info),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert revision: %w", err)
+	}
+	return revision, nil
+}
+
+// editorName is who made a change, or unknownEditor.
+func editorName(info RevisionInfo) string {
+	if info.CreatedByName == "" {
+		return unknownEditor
+	}
+	return info.CreatedByName
+}
+
+// revisionNames returns the names sorted, and never nil since the revision
+// columns are NOT NULL.
+func revisionNames(names []string) []string {
+	sorted := slices.Clone(names)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,1372 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+var errInjected = errors.New("injected failure")
+
+const (
+	kindAuthor    = "author"
+	kindKeyword   = "keyword"
+	kindCategory  = "category"
+	kindRegion    = "region"
+	kindPublisher = "publisher"
+)
+
+// fakeState is everything fakeQuerier stores, kept separate so a rollback can
+// put back a copy.
+type fakeState struct {
+	documents  map[uuid.UUID]db.FindDocumentByIDRow
+	terms      map[string]map[uuid.UUID]string      // kind -> term ID -> name
+	links      map[string]map[uuid.UUID][]uuid.UUID // kind -> doc ID -> term IDs; at most one publisher
+	revisions  map[uuid.UUID][]db.InsertDocumentRevisionParams
+	sources    map[uuid.UUID]map[string]string // doc ID -> field -> source
+	pending    map[uuid.UUID]bool              // unapproved term IDs
+	aliases    map[string]map[string]fakeAlias // kind -> alias key -> alias
+	vocabulary map[uuid.UUID]bool              // term IDs offered to the model
+	parents    map[uuid.UUID]uuid.UUID         // region ID -> parent region ID
+	details    map[uuid.UUID]db.UpdateAuthorDetailsParams
+	pages      map[uuid.UUID]map[int32]string // document ID -> page number -> text
+}
+
+type fakeAlias struct {
+	name   string
+	termID uuid.UUID
+}
+
+func newFakeState() fakeState {
+	state := fakeState{
+		documents:  make(map[uuid.UUID]db.FindDocumentByIDRow),
+		terms:      make(map[string]map[uuid.UUID]string),
+		links:      make(map[string]map[uuid.UUID][]uuid.UUID),
+		revisions:  make(map[uuid.UUID][]db.InsertDocumentRevisionParams),
+		sources:    make(map[uuid.UUID]map[string]string),
+		pending:    make(map[uuid.UUID]bool),
+		aliases:    make(map[string]map[string]fakeAlias),
+		vocabulary: make(map[uuid.UUID]bool),
+		parents:    make(map[uuid.UUID]uuid.UUID),
+		details:    make(map[uuid.UUID]db.UpdateAuthorDetailsParams),
+		pages:      make(map[uuid.UUID]map[int32]string),
+	}
+	for _, kind := range []string{kindAuthor, kindKeyword, kindCategory, kindRegion, kindPublisher} {
+		state.terms[kind] = make(map[uuid.UUID]string)
+		state.links[kind] = make(map[uuid.UUID][]uuid.UUID)
+		state.aliases[kind] = make(map[string]fakeAlias)
+	}
+	return state
+}
+
+func (s fakeState) clone() fakeState {
+	out := fakeState{
+		documents:  maps.Clone(s.documents),
+		terms:      make(map[string]map[uuid.UUID]string),
+		links:      make(map[string]map[uuid.UUID][]uuid.UUID),
+		revisions:  make(map[uuid.UUID][]db.InsertDocumentRevisionParams),
+		sources:    make(map[uuid.UUID]map[string]string),
+		pending:    maps.Clone(s.pending),
+		aliases:    make(map[string]map[string]fakeAlias),
+		vocabulary: maps.Clone(s.vocabulary),
+		parents:    maps.Clone(s.parents),
+		details:    maps.Clone(s.details),
+		pages:      make(map[uuid.UUID]map[int32]string),
+	}
+	for kind, terms := range s.terms {
+		out.terms[kind] = maps.Clone(terms)
+	}
+	for kind, aliases := range s.aliases {
+		out.aliases[kind] = maps.Clone(aliases)
+	}
+	for kind, links := range s.links {
+		out.links[kind] = make(map[uuid.UUID][]uuid.UUID)
+		for docID, ids := range links {
+			out.links[kind][docID] = slices.Clone(ids)
+		}
+	}
+	for docID, revs := range s.revisions {
+		out.revisions[docID] = slices.Clone(revs)
+	}
+	for docID, sources := range s.sources {
+		out.sources[docID] = maps.Clone(sources)
+	}
+	for docID, pages := range s.pages {
+		out.pages[docID] = maps.Clone(pages)
+	}
+	return out
+}
+
+// fakeQuerier keeps documents in memory and fails the method named by failOn.
+type fakeQuerier struct {
+	fakeState
+	failOn string
+	calls  []string
+}
+
+func (f *fakeQuerier) call(method string) error {
+	f.calls = append(f.calls, method)
+	if method == f.failOn {
+		return errInjected
+	}
+	return nil
+}
+
+func (f *fakeQuerier) findTerm(method, kind, name string) (uuid.UUID, error) {
+	if err := f.call(method); err != nil {
+		return uuid.Nil, err
+	}
+	for id, n := range f.terms[kind] {
+		if strings.EqualFold(n, name) {
+			return id, nil
+		}
+	}
+	return uuid.Nil, sql.ErrNoRows
+}
+
+func (f *fakeQuerier) insertTerm(method, kind string, id uuid.UUID, name string, approved bool) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	f.terms[kind][id] = name
+	if !approved {
+		f.pending[id] = true
+	}
+	return nil
+}
+
+func (f *fakeQuerier) listTerms(method, kind string) ([]taxonomy.Term, error) {
+	if err := f.call(method); err != nil {
+		return nil, err
+	}
+	var terms []taxonomy.Term
+	for id, name := range f.terms[kind] {
+		terms = append(terms, taxonomy.Term{ID: id, Name: name})
+	}
+	for _, alias := range f.aliases[kind] {
+		terms = append(terms, taxonomy.Term{ID: alias.termID, Name: f.terms[kind][alias.termID], Alias: alias.name})
+	}
+	return terms, nil
+}
+
+func (f *fakeQuerier) findTermByID(method, kind string, id uuid.UUID) (string, error) {
+	if err := f.call(method); err != nil {
+		return "", err
+	}
+	name, ok := f.terms[kind][id]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return name, nil
+}
+
+func (f *fakeQuerier) addAlias(method, kind, key, alias string, id uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	f.aliases[kind][key] = fakeAlias{name: alias, termID: id}
+	return nil
+}
+
+func (f *fakeQuerier) approveTerm(method, kind string, id uuid.UUID) (int64, error) {
+	if err := f.call(method); err != nil {
+		return 0, err
+	}
+	if _, ok := f.terms[kind][id]; !ok {
+		return 0, nil
+	}
+	delete(f.pending, id)
+	return 1, nil
+}
+
+// moveDocs relinks documents from one term to another, leaving the old link
+// where the document already has the new term, as the query does.
+func (f *fakeQuerier) moveDocs(method, kind string, from, to uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	for docID, ids := range f.links[kind] {
+		if slices.Contains(ids, to) {
+			continue
+		}
+		for i, id := range ids {
+			if id == from {
+				ids[i] = to
+			}
+		}
+		f.links[kind][docID] = ids
+	}
+	return nil
+}
+
+func (f *fakeQuerier) moveAliases(method, kind string, from, to uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	for key, alias := range f.aliases[kind] {
+		if alias.termID == from {
+			f.aliases[kind][key] = fakeAlias{name: alias.name, termID: to}
+		}
+	}
+	return nil
+}
+
+// deleteTerm removes a term with its links and aliases, as the foreign keys cascade.
+func (f *fakeQuerier) deleteTerm(method, kind string, id uuid.UUID) (int64, error) {
+	if err := f.call(method); err != nil {
+		return 0, err
+	}
+	return f.dropTerm(kind, id), nil
+}
+
+func (f *fakeQuerier) dropTerm(kind string, id uuid.UUID) int64 {
+	if _, ok := f.terms[kind][id]; !ok {
+		return 0
+	}
+	delete(f.terms[kind], id)
+	delete(f.pending, id)
+	delete(f.vocabulary, id)
+	for docID, ids := range f.links[kind] {
+		f.links[kind][docID] = slices.DeleteFunc(ids, func(termID uuid.UUID) bool { return termID == id })
+	}
+	maps.DeleteFunc(f.aliases[kind], func(_ string, alias fakeAlias) bool { return alias.termID == id })
+	return 1
+}
+
+// moveSomeDocs is moveDocs for the given documents only.
+func (f *fakeQuerier) moveSomeDocs(method, kind string, from, to uuid.UUID, docIDs []uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	for _, docID := range docIDs {
+		ids := f.links[kind][docID]
+		if slices.Contains(ids, to) {
+			continue
+		}
+		for i, id := range ids {
+			if id == from {
+				ids[i] = to
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeQuerier) unlinkDocs(method, kind string, id uuid.UUID, docIDs []uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	for _, docID := range docIDs {
+		f.links[kind][docID] = slices.DeleteFunc(f.links[kind][docID], func(termID uuid.UUID) bool { return termID == id })
+	}
+	return nil
+}
+
+func (f *fakeQuerier) renameTerm(method, kind string, id uuid.UUID, name string) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	f.terms[kind][id] = name
+	return nil
+}
+
+// deleteUnusedTerm deletes a term only when no document links to it.
+func (f *fakeQuerier) deleteUnusedTerm(method, kind string, id uuid.UUID) (int64, error) {
+	if err := f.call(method); err != nil {
+		return 0, err
+	}
+	for _, ids := range f.links[kind] {
+		if slices.Contains(ids, id) {
+			return 0, nil
+		}
+	}
+	return f.dropTerm(kind, id), nil
+}
+
+func (f *fakeQuerier) moveVocabulary(method string, from, to uuid.UUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	if f.vocabulary[from] {
+		f.vocabulary[to] = true
+	}
+	return nil
+}
+
+func (f *fakeQuerier) flagDocuments(method, kind string, id uuid.UUID, approvedOnly bool) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	for docID, ids := range f.links[kind] {
+		doc := f.documents[docID]
+		if slices.Contains(ids, id) && (!approvedOnly || doc.ReviewState == ReviewApproved) {
+			doc.ToIndex = sql.NullBool{Bool: true, Valid: true}
+			f.documents[docID] = doc
+		}
+	}
+	return nil
+}
+
+// link adds a term to a document, ignoring a term it already has as the
+// inserts' ON CONFLICT clauses do.
+func (f *fakeQuerier) link(method, kind string, docID uuid.NullUUID, termID uuid.NullUUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	if !slices.Contains(f.links[kind][docID.UUID], termID.UUID) {
+		f.links[kind][docID.UUID] = append(f.links[kind][docID.UUID], termID.UUID)
+	}
+	return nil
+}
+
+func (f *fakeQuerier) unlink(method, kind string, docID uuid.NullUUID) error {
+	if err := f.call(method); err != nil {
+		return err
+	}
+	delete(f.links[kind], docID.UUID)
+	return nil
+}
+
+func (f *fakeQuerier) names(kind string, docID uuid.UUID) []string {
+	var names []string
+	for _, id := range f.links[kind][docID] {
+		names = append(names, f.terms[kind][id])
+	}
+	return names
+}
+
+func (f *fakeQuerier) FindAuthorByName(ctx context.Context, lower string) (db.Author, error) {
+	id, err := f.findTerm("FindAuthorByName", kindAuthor, lower)
+	return db.Author{ID: id, Name: lower}, err
+}
+
+func (f *fakeQuerier) InsertAuthor(ctx context.Context, arg db.InsertAuthorParams) error {
+	return f.insertTerm("InsertAuthor", kindAuthor, arg.ID, arg.Name, arg.Approved)
+}
+
+func (f *fakeQuerier) ListAuthorTerms(ctx context.Context) ([]db.ListAuthorTermsRow, error) {
+	terms, err := f.listTerms("ListAuthorTerms", kindAuthor)
+	rows := make([]db.ListAuthorTermsRow, len(terms))
+	for i, term := range terms {
+		rows[i] = db.ListAuthorTermsRow(term)
+	}
+	return rows, err
+}
+
+func (f *fakeQuerier) FindAuthorByID(ctx context.Context, id uuid.UUID) (db.Author, error) {
+	name, err := f.findTermByID("FindAuthorByID", kindAuthor, id)
+	details := f.details[id]
+	return db.Author{ID: id, Name: name, Approved: !f.pending[id], Orcid: details.Orcid, Affiliation: details.Affiliation}, err
+}
+
+// UpdateAuthorDetails enforces authors_orcid_key.
+func (f *fakeQuerier) UpdateAuthorDetails(ctx context.Context, arg db.UpdateAuthorDetailsParams) (int64, error) {
+	if err := f.call("UpdateAuthorDetails"); err != nil {
+		return 0, err
+	}
+	for id, details := range f.details {
+		if id != arg.ID && arg.Orcid.Valid && details.Orcid == arg.Orcid {
+			return 0, errors.New("duplicate key value violates unique constraint \"authors_orcid_key\"")
+		}
+	}
+	if _, ok := f.terms[kindAuthor][arg.ID]; !ok {
+		return 0, nil
+	}
+	f.details[arg.ID] = arg
+	return 1, nil
+}
+
+func (f *fakeQuerier) DeleteDocumentPagesByDocID(ctx context.Context, docID uuid.UUID) error {
+	if err := f.call("DeleteDocumentPagesByDocID"); err != nil {
+		return err
+	}
+	delete(f.pages, docID)
+	return nil
+}
+
+func (f *fakeQuerier) InsertDocumentPage(ctx context.Context, arg db.InsertDocumentPageParams) error {
+	if err := f.call("InsertDocumentPage"); err != nil {
+		return err
+	}
+	if f.pages[arg.DocID] == nil {
+		f.pages[arg.DocID] = make(map[int32]string)
+	}
+	f.pages[arg.DocID][arg.PageNumber] = arg.Content
+	return nil
+}
+
+func (f *fakeQuerier) InsertAuthorAlias(ctx context.Context, arg db.InsertAuthorAliasParams) error {
+	return f.addAlias("InsertAuthorAlias", kindAuthor, arg.AliasKey, arg.Alias, arg.AuthorID)
+}
+
+func (f *fakeQuerier) ApproveAuthor(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.approveTerm("ApproveAuthor", kindAuthor, id)
+}
+
+func (f *fakeQuerier) MoveDocAuthors(ctx context.Context, arg db.MoveDocAuthorsParams) error {
+	return f.moveDocs("MoveDocAuthors", kindAuthor, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MoveAuthorAliases(ctx context.Context, arg db.MoveAuthorAliasesParams) error {
+	return f.moveAliases("MoveAuthorAliases", kindAuthor, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) DeleteAuthor(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteTerm("DeleteAuthor", kindAuthor, id)
+}
+
+func (f *fakeQuerier) FlagAuthorDocumentsForIndex(ctx context.Context, arg db.FlagAuthorDocumentsForIndexParams) error {
+	return f.flagDocuments("FlagAuthorDocumentsForIndex", kindAuthor, arg.AuthorID, arg.ApprovedOnly)
+}
+
+func (f *fakeQuerier) MoveDocAuthorsForDocuments(ctx context.Context, arg db.MoveDocAuthorsForDocumentsParams) error {
+	return f.moveSomeDocs("MoveDocAuthorsForDocuments", kindAuthor, arg.FromID, arg.ToID, arg.DocIds)
+}
+
+func (f *fakeQuerier) DeleteDocAuthorsForDocuments(ctx context.Context, arg db.DeleteDocAuthorsForDocumentsParams) error {
+	return f.unlinkDocs("DeleteDocAuthorsForDocuments", kindAuthor, arg.AuthorID, arg.DocIds)
+}
+
+func (f *fakeQuerier) RenameAuthor(ctx context.Context, arg db.RenameAuthorParams) error {
+	return f.renameTerm("RenameAuthor", kindAuthor, arg.ID, arg.Name)
+}
+
+func (f *fakeQuerier) DeleteUnusedAuthor(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteUnusedTerm("DeleteUnusedAuthor", kindAuthor, id)
+}
+
+func (f *fakeQuerier) FindKeywordByName(ctx context.Context, lower string) (db.Keyword, error) {
+	id, err := f.findTerm("FindKeywordByName", kindKeyword, lower)
+	return db.Keyword{ID: id, Name: lower}, err
+}
+
+func (f *fakeQuerier) InsertKeyword(ctx context.Context, arg db.InsertKeywordParams) error {
+	return f.insertTerm("InsertKeyword", kindKeyword, arg.ID, arg.Name, arg.Approved)
+}
+
+func (f *fakeQuerier) ListKeywordTerms(ctx context.Context) ([]db.ListKeywordTermsRow, error) {
+	terms, err := f.listTerms("ListKeywordTerms", kindKeyword)
+	rows := make([]db.ListKeywordTermsRow, len(terms))
+	for i, term := range terms {
+		rows[i] = db.ListKeywordTermsRow(term)
+	}
+	return rows, err
+}
+
+func (f *fakeQuerier) FindKeywordByID(ctx context.Context, id uuid.UUID) (db.Keyword, error) {
+	name, err := f.findTermByID("FindKeywordByID", kindKeyword, id)
+	return db.Keyword{ID: id, Name: name, Approved: !f.pending[id]}, err
+}
+
+func (f *fakeQuerier) InsertKeywordAlias(ctx context.Context, arg db.InsertKeywordAliasParams) error {
+	return f.addAlias("InsertKeywordAlias", kindKeyword, arg.AliasKey, arg.Alias, arg.KeywordID)
+}
+
+func (f *fakeQuerier) ApproveKeyword(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.approveTerm("ApproveKeyword", kindKeyword, id)
+}
+
+func (f *fakeQuerier) MoveDocKeywords(ctx context.Context, arg db.MoveDocKeywordsParams) error {
+	return f.moveDocs("MoveDocKeywords", kindKeyword, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MoveKeywordAliases(ctx context.Context, arg db.MoveKeywordAliasesParams) error {
+	return f.moveAliases("MoveKeywordAliases", kindKeyword, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) DeleteKeyword(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteTerm("DeleteKeyword", kindKeyword, id)
+}
+
+func (f *fakeQuerier) FlagKeywordDocumentsForIndex(ctx context.Context, arg db.FlagKeywordDocumentsForIndexParams) error {
+	return f.flagDocuments("FlagKeywordDocumentsForIndex", kindKeyword, arg.KeywordID, arg.ApprovedOnly)
+}
+
+func (f *fakeQuerier) MoveDocKeywordsForDocuments(ctx context.Context, arg db.MoveDocKeywordsForDocumentsParams) error {
+	return f.moveSomeDocs("MoveDocKeywordsForDocuments", kindKeyword, arg.FromID, arg.ToID, arg.DocIds)
+}
+
+func (f *fakeQuerier) DeleteDocKeywordsForDocuments(ctx context.Context, arg db.DeleteDocKeywordsForDocumentsParams) error {
+	return f.unlinkDocs("DeleteDocKeywordsForDocuments", kindKeyword, arg.KeywordID, arg.DocIds)
+}
+
+func (f *fakeQuerier) RenameKeyword(ctx context.Context, arg db.RenameKeywordParams) error {
+	return f.renameTerm("RenameKeyword", kindKeyword, arg.ID, arg.Name)
+}
+
+func (f *fakeQuerier) DeleteUnusedKeyword(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteUnusedTerm("DeleteUnusedKeyword", kindKeyword, id)
+}
+
+func (f *fakeQuerier) MoveVocabularyKeyword(ctx context.Context, arg db.MoveVocabularyKeywordParams) error {
+	return f.moveVocabulary("MoveVocabularyKeyword", arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) FindPublisherByName(ctx context.Context, lower string) (db.Publisher, error) {
+	id, err := f.findTerm("FindPublisherByName", kindPublisher, lower)
+	return db.Publisher{ID: id, Name: lower}, err
+}
+
+func (f *fakeQuerier) InsertPublisher(ctx context.Context, arg db.InsertPublisherParams) error {
+	return f.insertTerm("InsertPublisher", kindPublisher, arg.ID, arg.Name, arg.Approved)
+}
+
+func (f *fakeQuerier) ListPublisherTerms(ctx context.Context) ([]db.ListPublisherTermsRow, error) {
+	terms, err := f.listTerms("ListPublisherTerms", kindPublisher)
+	rows := make([]db.ListPublisherTermsRow, len(terms))
+	for i, term := range terms {
+		rows[i] = db.ListPublisherTermsRow(term)
+	}
+	return rows, err
+}
+
+func (f *fakeQuerier) FindPublisherByID(ctx context.Context, id uuid.UUID) (db.Publisher, error) {
+	name, err := f.findTermByID("FindPublisherByID", kindPublisher, id)
+	return db.Publisher{ID: id, Name: name, Approved: !f.pending[id]}, err
+}
+
+func (f *fakeQuerier) InsertPublisherAlias(ctx context.Context, arg db.InsertPublisherAliasParams) error {
+	return f.addAlias("InsertPublisherAlias", kindPublisher, arg.AliasKey, arg.Alias, arg.PublisherID)
+}
+
+func (f *fakeQuerier) ApprovePublisher(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.approveTerm("ApprovePublisher", kindPublisher, id)
+}
+
+func (f *fakeQuerier) MoveDocPublishers(ctx context.Context, arg db.MoveDocPublishersParams) error {
+	return f.moveDocs("MoveDocPublishers", kindPublisher, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MovePublisherAliases(ctx context.Context, arg db.MovePublisherAliasesParams) error {
+	return f.moveAliases("MovePublisherAliases", kindPublisher, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) DeletePublisher(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteTerm("DeletePublisher", kindPublisher, id)
+}
+
+func (f *fakeQuerier) FlagPublisherDocumentsForIndex(ctx context.Context, arg db.FlagPublisherDocumentsForIndexParams) error {
+	return f.flagDocuments("FlagPublisherDocumentsForIndex", kindPublisher, arg.PublisherID, arg.ApprovedOnly)
+}
+
+func (f *fakeQuerier) MoveDocPublishersForDocuments(ctx context.Context, arg db.MoveDocPublishersForDocumentsParams) error {
+	return f.moveSomeDocs("MoveDocPublishersForDocuments", kindPublisher, arg.FromID, arg.ToID, arg.DocIds)
+}
+
+func (f *fakeQuerier) DeleteDocPublishersForDocuments(ctx context.Context, arg db.DeleteDocPublishersForDocumentsParams) error {
+	return f.unlinkDocs("DeleteDocPublishersForDocuments", kindPublisher, arg.PublisherID, arg.DocIds)
+}
+
+func (f *fakeQuerier) RenamePublisher(ctx context.Context, arg db.RenamePublisherParams) error {
+	return f.renameTerm("RenamePublisher", kindPublisher, arg.ID, arg.Name)
+}
+
+func (f *fakeQuerier) DeleteUnusedPublisher(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteUnusedTerm("DeleteUnusedPublisher", kindPublisher, id)
+}
+
+func (f *fakeQuerier) FindRegionByName(ctx context.Context, lower string) (db.Region, error) {
+	id, err := f.findTerm("FindRegionByName", kindRegion, lower)
+	return db.Region{ID: id, Name: lower}, err
+}
+
+func (f *fakeQuerier) InsertRegion(ctx context.Context, arg db.InsertRegionParams) error {
+	return f.insertTerm("InsertRegion", kindRegion, arg.ID, arg.Name, arg.Approved)
+}
+
+func (f *fakeQuerier) ListRegionTerms(ctx context.Context) ([]db.ListRegionTermsRow, error) {
+	terms, err := f.listTerms("ListRegionTerms", kindRegion)
+	rows := make([]db.ListRegionTermsRow, len(terms))
+	for i, term := range terms {
+		rows[i] = db.ListRegionTermsRow(term)
+	}
+	return rows, err
+}
+
+func (f *fakeQuerier) FindRegionByID(ctx context.Context, id uuid.UUID) (db.Region, error) {
+	name, err := f.findTermByID("FindRegionByID", kindRegion, id)
+	return db.Region{ID: id, Name: name, Approved: !f.pending[id]}, err
+}
+
+func (f *fakeQuerier) InsertRegionAlias(ctx context.Context, arg db.InsertRegionAliasParams) error {
+	return f.addAlias("InsertRegionAlias", kindRegion, arg.AliasKey, arg.Alias, arg.RegionID)
+}
+
+func (f *fakeQuerier) ApproveRegion(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.approveTerm("ApproveRegion", kindRegion, id)
+}
+
+func (f *fakeQuerier) MoveDocRegions(ctx context.Context, arg db.MoveDocRegionsParams) error {
+	return f.moveDocs("MoveDocRegions", kindRegion, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MoveRegionAliases(ctx context.Context, arg db.MoveRegionAliasesParams) error {
+	return f.moveAliases("MoveRegionAliases", kindRegion, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) DeleteRegion(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteTerm("DeleteRegion", kindRegion, id)
+}
+
+func (f *fakeQuerier) FlagRegionDocumentsForIndex(ctx context.Context, arg db.FlagRegionDocumentsForIndexParams) error {
+	return f.flagDocuments("FlagRegionDocumentsForIndex", kindRegion, arg.RegionID, arg.ApprovedOnly)
+}
+
+func (f *fakeQuerier) MoveDocRegionsForDocuments(ctx context.Context, arg db.MoveDocRegionsForDocumentsParams) error {
+	return f.moveSomeDocs("MoveDocRegionsForDocuments", kindRegion, arg.FromID, arg.ToID, arg.DocIds)
+}
+
+func (f *fakeQuerier) DeleteDocRegionsForDocuments(ctx context.Context, arg db.DeleteDocRegionsForDocumentsParams) error {
+	return f.unlinkDocs("DeleteDocRegionsForDocuments", kindRegion, arg.RegionID, arg.DocIds)
+}
+
+func (f *fakeQuerier) RenameRegion(ctx context.Context, arg db.RenameRegionParams) error {
+	return f.renameTerm("RenameRegion", kindRegion, arg.ID, arg.Name)
+}
+
+func (f *fakeQuerier) DeleteUnusedRegion(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteUnusedTerm("DeleteUnusedRegion", kindRegion, id)
+}
+
+func (f *fakeQuerier) MoveVocabularyRegion(ctx context.Context, arg db.MoveVocabularyRegionParams) error {
+	return f.moveVocabulary("MoveVocabularyRegion", arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MoveRegionChildren(ctx context.Context, arg db.MoveRegionChildrenParams) error {
+	if err := f.call("MoveRegionChildren"); err != nil {
+		return err
+	}
+	for id, parent := range f.parents {
+		if parent == arg.FromID && id != arg.ToID {
+			f.parents[id] = arg.ToID
+		}
+	}
+	return nil
+}
+
+func (f *fakeQuerier) FindCategoryByName(ctx context.Context, lower string) (db.Category, error) {
+	id, err := f.findTerm("FindCategoryByName", kindCategory, lower)
+	return db.Category{ID: id, Name: lower}, err
+}
+
+func (f *fakeQuerier) InsertCategory(ctx context.Context, arg db.InsertCategoryParams) error {
+	return f.insertTerm("InsertCategory", kindCategory, arg.ID, arg.Name, arg.Approved)
+}
+
+func (f *fakeQuerier) ListCategoryTerms(ctx context.Context) ([]db.ListCategoryTermsRow, error) {
+	terms, err := f.listTerms("ListCategoryTerms", kindCategory)
+	rows := make([]db.ListCategoryTermsRow, len(terms))
+	for i, term := range terms {
+		rows[i] = db.ListCategoryTermsRow(term)
+	}
+	return rows, err
+}
+
+func (f *fakeQuerier) FindCategoryByID(ctx context.Context, id uuid.UUID) (db.Category, error) {
+	name, err := f.findTermByID("FindCategoryByID", kindCategory, id)
+	return db.Category{ID: id, Name: name, Approved: !f.pending[id]}, err
+}
+
+func (f *fakeQuerier) InsertCategoryAlias(ctx context.Context, arg db.InsertCategoryAliasParams) error {
+	return f.addAlias("InsertCategoryAlias", kindCategory, arg.AliasKey, arg.Alias, arg.CategoryID)
+}
+
+func (f *fakeQuerier) ApproveCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.approveTerm("ApproveCategory", kindCategory, id)
+}
+
+func (f *fakeQuerier) MoveDocCategories(ctx context.Context, arg db.MoveDocCategoriesParams) error {
+	return f.moveDocs("MoveDocCategories", kindCategory, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) MoveCategoryAliases(ctx context.Context, arg db.MoveCategoryAliasesParams) error {
+	return f.moveAliases("MoveCategoryAliases", kindCategory, arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) DeleteCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteTerm("DeleteCategory", kindCategory, id)
+}
+
+func (f *fakeQuerier) FlagCategoryDocumentsForIndex(ctx context.Context, arg db.FlagCategoryDocumentsForIndexParams) error {
+	return f.flagDocuments("FlagCategoryDocumentsForIndex", kindCategory, arg.CategoryID, arg.ApprovedOnly)
+}
+
+func (f *fakeQuerier) MoveDocCategoriesForDocuments(ctx context.Context, arg db.MoveDocCategoriesForDocumentsParams) error {
+	return f.moveSomeDocs("MoveDocCategoriesForDocuments", kindCategory, arg.FromID, arg.ToID, arg.DocIds)
+}
+
+func (f *fakeQuerier) DeleteDocCategoriesForDocuments(ctx context.Context, arg db.DeleteDocCategoriesForDocumentsParams) error {
+	return f.unlinkDocs("DeleteDocCategoriesForDocuments", kindCategory, arg.CategoryID, arg.DocIds)
+}
+
+func (f *fakeQuerier) RenameCategory(ctx context.Context, arg db.RenameCategoryParams) error {
+	return f.renameTerm("RenameCategory", kindCategory, arg.ID, arg.Name)
+}
+
+func (f *fakeQuerier) DeleteUnusedCategory(ctx context.Context, id uuid.UUID) (int64, error) {
+	return f.deleteUnusedTerm("DeleteUnusedCategory", kindCategory, id)
+}
+
+func (f *fakeQuerier) MoveVocabularyCategory(ctx context.Context, arg db.MoveVocabularyCategoryParams) error {
+	return f.moveVocabulary("MoveVocabularyCategory", arg.FromID, arg.ToID)
+}
+
+func (f *fakeQuerier) FindDocumentByID(ctx context.Context, id uuid.UUID) (db.FindDocumentByIDRow, error) {
+	if err := f.call("FindDocumentByID"); err != nil {
+		return db.FindDocumentByIDRow{}, err
+	}
+	doc, ok := f.documents[id]
+	if !ok {
+		return doc, sql.ErrNoRows
+	}
+	doc.AuthorNames = f.names(kindAuthor, id)
+	doc.KeywordNames = f.names(kindKeyword, id)
+	doc.CategoryNames = f.names(kindCategory, id)
+	doc.RegionNames = f.names(kindRegion, id)
+	doc.PublisherID, doc.Source = uuid.NullUUID{}, sql.NullString{}
+	if ids := f.links[kindPublisher][id]; len(ids) > 0 {
+		doc.PublisherID = uuid.NullUUID{UUID: ids[0], Valid: true}
+		doc.Source = sql.NullString{String: f.terms[kindPublisher][ids[0]], Valid: true}
+	}
+	return doc, nil
+}
+
+func (f *fakeQuerier) InsertUploadedDocument(ctx context.Context, arg db.InsertUploadedDocumentParams) error {
+	if err := f.call("InsertUploadedDocument"); err != nil {
+		return err
+	}
+	f.documents[arg.ID] = db.FindDocumentByIDRow{ID: arg.ID, FileName: arg.FileName, Title: arg.Title, Abstract: arg.Abstract, PublishDate: arg.PublishDate, Language: arg.Language, EnglishTitle: arg.EnglishTitle, EnglishAbstract: arg.EnglishAbstract, ReviewState: arg.ReviewState, ToIndex: arg.ToIndex}
+	f.setPublisher(arg.ID, arg.PublisherID)
+	return nil
+}
+
+func (f *fakeQuerier) UpdateDocumentMetadata(ctx context.Context, arg db.UpdateDocumentMetadataParams) error {
+	if err := f.call("UpdateDocumentMetadata"); err != nil {
+		return err
+	}
+	doc := f.documents[arg.ID]
+	doc.Title, doc.Abstract, doc.PublishDate, doc.ToIndex = arg.Title, arg.Abstract, arg.PublishDate, arg.ToIndex
+	doc.Language, doc.EnglishTitle, doc.EnglishAbstract = arg.Language, arg.EnglishTitle, arg.EnglishAbstract
+	f.documents[arg.ID] = doc
+	f.setPublisher(arg.ID, arg.PublisherID)
+	return nil
+}
+
+// setPublisher stores a document's publisher_id as its only publisher link.
+func (f *fakeQuerier) setPublisher(docID uuid.UUID, publisherID uuid.NullUUID) {
+	delete(f.links[kindPublisher], docID)
+	if publisherID.Valid {
+		f.links[kindPublisher][docID] = []uuid.UUID{publisherID.UUID}
+	}
+}
+
+func (f *fakeQuerier) DeleteDocAuthorsByDocID(ctx context.Context, docID uuid.NullUUID) error {
+	return f.unlink("DeleteDocAuthorsByDocID", kindAuthor, docID)
+}
+
+func (f *fakeQuerier) DeleteDocKeywordsByDocID(ctx context.Context, docID uuid.NullUUID) error {
+	return f.unlink("DeleteDocKeywordsByDocID", kindKeyword, docID)
+}
+
+func (f *fakeQuerier) DeleteDocCategoriesByDocID(ctx context.Context, docID uuid.NullUUID) error {
+	return f.unlink("DeleteDocCategoriesByDocID", kindCategory, docID)
+}
+
+func (f *fakeQuerier) DeleteDocRegionsByDocID(ctx context.Context, docID uuid.NullUUID) error {
+	return f.unlink("DeleteDocRegionsByDocID", kindRegion, docID)
+}
+
+func (f *fakeQuerier) InsertDocAuthor(ctx context.Context, arg db.InsertDocAuthorParams) error {
+	return f.link("InsertDocAuthor", kindAuthor, arg.DocID, arg.AuthorID)
+}
+
+func (f *fakeQuerier) InsertDocKeyword(ctx context.Context, arg db.InsertDocKeywordParams) error {
+	return f.link("InsertDocKeyword", kindKeyword, arg.DocID, arg.KeywordID)
+}
+
+func (f *fakeQuerier) InsertDocCategory(ctx context.Context, arg db.InsertDocCategoryParams) error {
+	return f.link("InsertDocCategory", kindCategory, arg.DocID, arg.CategoryID)
+}
+
+func (f *fakeQuerier) InsertDocRegion(ctx context.Context, arg db.InsertDocRegionParams) error {
+	return f.link("InsertDocRegion", kindRegion, arg.DocID, arg.RegionID)
+}
+
+func (f *fakeQuerier) InsertDocumentRevision(ctx context.Context, arg db.InsertDocumentRevisionParams) (int32, error) {
+	if err := f.call("InsertDocumentRevision"); err != nil {
+		return 0, err
+	}
+	f.revisions[arg.DocID] = append(f.revisions[arg.DocID], arg)
+	return int32(len(f.revisions[arg.DocID])), nil
+}
+
+func (f *fakeQuerier) SetDocumentFieldSource(ctx context.Context, arg db.SetDocumentFieldSourceParams) error {
+	if err := f.call("SetDocumentFieldSource"); err != nil {
+		return err
+	}
+	if f.sources[arg.DocID] == nil {
+		f.sources[arg.DocID] = make(map[string]string)
+	}
+	f.sources[arg.DocID][arg.Field] = arg.Source
+	return nil
+}
+
+func (f *fakeQuerier) SetDocumentReviewState(ctx context.Context, arg db.SetDocumentReviewStateParams) error {
+	if err := f.call("SetDocumentReviewState"); err != nil {
+		return err
+	}
+	doc := f.documents[arg.ID]
+	doc.ReviewState = arg.ReviewState
+	f.documents[arg.ID] = doc
+	return nil
+}
+
+func (f *fakeQuerier) ApproveDocument(ctx context.Context, arg db.ApproveDocumentParams) error {
+	if err := f.call("ApproveDocument"); err != nil {
+		return err
+	}
+	doc := f.documents[arg.ID]
+	doc.ReviewState, doc.ApprovedBy, doc.ApprovedByName = ReviewApproved, arg.ApprovedBy, arg.ApprovedByName
+	f.documents[arg.ID] = doc
+	return nil
+}
+
+func (f *fakeQuerier) SetTranslationGroup(ctx context.Context, arg db.SetTranslationGroupParams) error {
+	if err := f.call("SetTranslationGroup"); err != nil {
+		return err
+	}
+	var merged []uuid.UUID
+	for _, id := range arg.DocIds {
+		if group := f.documents[id].TranslationGroup; group.Valid {
+			merged = append(merged, group.UUID)
+		}
+	}
+	for id, doc := range f.documents {
+		if slices.Contains(arg.DocIds, id) || (doc.TranslationGroup.Valid && slices.Contains(merged, doc.TranslationGroup.UUID)) {
+			doc.TranslationGroup = uuid.NullUUID{UUID: arg.TranslationGroup, Valid: true}
+			f.documents[id] = doc
+		}
+	}
+	return nil
+}
+
+func (f *fakeQuerier) ClearTranslationGroup(ctx context.Context, arg db.ClearTranslationGroupParams) error {
+	if err := f.call("ClearTranslationGroup"); err != nil {
+		return err
+	}
+	for id, doc := range f.documents {
+		if doc.TranslationGroup.UUID == arg.TranslationGroup && doc.TranslationGroup.Valid && !slices.Contains(arg.KeepIds, id) {
+			doc.TranslationGroup = uuid.NullUUID{}
+			f.documents[id] = doc
+		}
+	}
+	return nil
+}
+
+func (f *fakeQuerier) DissolveLoneTranslationGroup(ctx context.Context, translationGroup uuid.NullUUID) error {
+	if err := f.call("DissolveLoneTranslationGroup"); err != nil {
+		return err
+	}
+	members := f.translationGroup(translationGroup)
+	if len(members) == 1 {
+		doc := f.documents[members[0]]
+		doc.TranslationGroup = uuid.NullUUID{}
+		f.documents[members[0]] = doc
+	}
+	return nil
+}
+
+// translationGroup lists the IDs of the documents in a translation group.
+func (f *fakeQuerier) translationGroup(group uuid.NullUUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for id, doc := range f.documents {
+		if group.Valid && doc.TranslationGroup == group {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// fakeTxRunner emulates a transaction by restoring a copy of the state when fn fails.
+type fakeTxRunner struct {
+	q         *fakeQuerier
+	commits   int
+	rollbacks int
+}
+
+func (r *fakeTxRunner) WithTx(ctx context.Context, fn func(q Querier) error) error {
+	saved := r.q.fakeState.clone()
+	if err := fn(r.q); err != nil {
+		r.q.fakeState = saved
+		r.rollbacks++
+		return err
+	}
+	r.commits++
+	return nil
+}
+
+type DocumentRepositoryTestSuite struct {
+	suite.Suite
+	q    *fakeQuerier
+	tx   *fakeTxRunner
+	repo *DocumentRepository
+}
+
+func (suite *DocumentRepositoryTestSuite) SetupTest() {
+	suite.q = &fakeQuerier{fakeState: newFakeState()}
+	suite.tx = &fakeTxRunner{q: suite.q}
+	suite.repo = NewDocumentRepository(suite.tx, false)
+}
+
+func (suite *DocumentRepositoryTestSuite) newDocument() NewDocument {
+	return NewDocument{
+		ID:       uuid.New(),
+		S3File:   "s3://bucket/report.pdf",
+		FileName: "report.pdf",
+		Title:    "Report",
+		Terms: Terms{
+			Authors:    ByName([]string{"Zed", "Amy"}),
+			Keywords:   ByName([]string{"peace"}),
+			Categories: ByName([]string{"Report"}),
+			Regions:    ByName([]string{"Kenya"}),
+		},
+		Sources: map[string]string{"title": SourceHuman, "authors": SourceLLM},
+	}
+}
+
+// seed stores a document with one author, committed outside any failure.
+func (suite *DocumentRepositoryTestSuite) seed() uuid.UUID {
+	doc := suite.newDocument()
+	doc.Terms = Terms{Authors: ByName([]string{"Old"})}
+	doc.Sources = map[string]string{"title": SourceLLM, "abstract": SourceLLM, "authors": SourceLLM}
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{CreatedByName: "admin"}))
+	suite.q.calls = nil
+	return doc.ID
+}
+
+func (suite *DocumentRepositoryTestSuite) update(docID uuid.UUID) MetadataUpdate {
+	return MetadataUpdate{
+		DocID: docID,
+		Title: "New title",
+		Terms: Terms{
+			Authors:    ByName([]string{"New"}),
+			Keywords:   ByName([]string{"peace"}),
+			Categories: ByName([]string{"Report"}),
+			Regions:    ByName([]string{"Kenya"}),
+		},
+	}
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocument() {
+	doc := suite.newDocument()
+
+	err := suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{CreatedBy: uuid.New(), CreatedByName: "editor"})
+
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.tx.commits)
+	suite.ElementsMatch([]string{"Zed", "Amy"}, suite.q.names(kindAuthor, doc.ID))
+	suite.Equal([]string{"Kenya"}, suite.q.names(kindRegion, doc.ID))
+	suite.Require().Len(suite.q.revisions[doc.ID], 1)
+	rev := suite.q.revisions[doc.ID][0]
+	suite.Equal([]string{"Amy", "Zed"}, rev.Authors)
+	suite.Equal("editor", rev.CreatedByName)
+	suite.False(rev.RestoredFrom.Valid)
+	suite.Equal(ReviewAIExtracted, suite.q.documents[doc.ID].ReviewState)
+	suite.True(suite.q.documents[doc.ID].ToIndex.Bool)
+	suite.Equal(map[string]string{"title": SourceHuman, "authors": SourceLLM}, suite.q.sources[doc.ID])
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentNormalizesTerms() {
+	oceania, congo := uuid.New(), uuid.New()
+	suite.q.terms[kindRegion][oceania] = "Oceania"
+	suite.q.terms[kindRegion][congo] = "Democratic Republic of the Congo"
+	suite.q.aliases[kindRegion]["drc"] = fakeAlias{name: "DRC", termID: congo}
+	doc := suite.newDocument()
+	doc.Terms.Regions = ByName([]string{"OCEANIA", "D.R.C.", "Oceana"})
+
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{}))
+
+	regions := suite.q.links[kindRegion][doc.ID]
+	suite.Require().Len(regions, 3)
+	suite.Equal([]uuid.UUID{oceania, congo}, regions[:2])
+	suite.Equal("Oceana", suite.q.terms[kindRegion][regions[2]], "a close spelling is not taken for the term")
+	suite.True(suite.q.pending[regions[2]], "unknown names await approval")
+	suite.False(suite.q.pending[oceania])
+	suite.Len(suite.q.terms[kindRegion], 3)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentLinksEachTermOnce() {
+	congo := uuid.New()
+	suite.q.terms[kindRegion][congo] = "Democratic Republic of the Congo"
+	suite.q.aliases[kindRegion]["drc"] = fakeAlias{name: "DRC", termID: congo}
+	doc := suite.newDocument()
+	doc.Terms.Regions = append(ByName([]string{"D.R.C.", "democratic republic of the congo"}), congo.String())
+	doc.Terms.Authors = ByName([]string{"Amy", "AMY"})
+
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{}))
+
+	suite.Equal([]uuid.UUID{congo}, suite.q.links[kindRegion][doc.ID])
+	suite.Equal([]string{"Amy"}, suite.q.names(kindAuthor, doc.ID))
+	suite.Equal(1, strings.Count(strings.Join(suite.q.calls, " "), "InsertDocRegion"), "each term is inserted once")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentKeepsNearMissNames() {
+	mark, joan := uuid.New(), uuid.New()
+	suite.q.terms[kindAuthor][mark] = "Mark Jones"
+	suite.q.terms[kindAuthor][joan] = "Joan Smith"
+	meditation := uuid.New()
+	suite.q.terms[kindKeyword][meditation] = "meditation"
+	doc := suite.newDocument()
+	doc.Terms.Authors = ByName([]string{"Mary Jones", "John Smith", "mark jones"})
+	doc.Terms.Keywords = ByName([]string{"mediation"})
+
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{}))
+
+	suite.Equal([]string{"Mary Jones", "John Smith", "Mark Jones"}, suite.q.names(kindAuthor, doc.ID))
+	suite.Equal([]string{"mediation"}, suite.q.names(kindKeyword, doc.ID))
+	for _, id := range suite.q.links[kindAuthor][doc.ID][:2] {
+		suite.True(suite.q.pending[id], "a new author awaits approval")
+	}
+	suite.Len(suite.q.terms[kindAuthor], 4)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentResolvesPublisher() {
+	undp := uuid.New()
+	suite.q.terms[kindPublisher][undp] = "United Nations Development Programme"
+	suite.q.aliases[kindPublisher]["undp"] = fakeAlias{name: "UNDP", termID: undp}
+	doc := suite.newDocument()
+	doc.Terms.Publisher = PublisherByName("U.N.D.P.")
+
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{}))
+
+	suite.Equal([]uuid.UUID{undp}, suite.q.links[kindPublisher][doc.ID])
+	suite.Equal("United Nations Development Programme", suite.q.revisions[doc.ID][0].Source.String)
+	suite.Len(suite.q.terms[kindPublisher], 1)
+
+	update := suite.update(doc.ID)
+	_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{})
+	suite.Require().NoError(err)
+
+	suite.Empty(suite.q.links[kindPublisher][doc.ID], "an empty publisher clears it")
+	suite.False(suite.q.revisions[doc.ID][1].Source.Valid)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentWithoutModelFields() {
+	doc := suite.newDocument()
+	doc.Sources = map[string]string{"title": SourceHuman}
+	suite.repo = NewDocumentRepository(suite.tx, true)
+
+	suite.Require().NoError(suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{}))
+
+	suite.Equal(ReviewInReview, suite.q.documents[doc.ID].ReviewState)
+	suite.False(suite.q.documents[doc.ID].ToIndex.Bool, "only approved documents are indexed")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestCreateDocumentRollsBackOnFailure() {
+	steps := []string{
+		"InsertUploadedDocument",
+		"FindAuthorByName", "ListAuthorTerms", "InsertAuthor",
+		"FindKeywordByName", "ListKeywordTerms", "InsertKeyword",
+		"FindCategoryByName", "ListCategoryTerms", "InsertCategory",
+		"FindRegionByName", "ListRegionTerms", "InsertRegion",
+		"InsertDocAuthor", "InsertDocKeyword", "InsertDocCategory", "InsertDocRegion",
+		"SetDocumentFieldSource", "FindDocumentByID", "InsertDocumentRevision",
+	}
+	for _, step := range steps {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			suite.q.failOn = step
+			doc := suite.newDocument()
+
+			err := suite.repo.CreateDocument(context.Background(), doc, RevisionInfo{})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Contains(suite.q.calls, step, "step was never reached")
+			suite.Equal(step, suite.q.calls[len(suite.q.calls)-1], "steps ran after the failure")
+			suite.Equal(0, suite.tx.commits)
+			suite.Equal(1, suite.tx.rollbacks)
+			suite.Empty(suite.q.documents)
+			suite.Empty(suite.q.terms[kindAuthor])
+			suite.Empty(suite.q.revisions)
+			suite.Empty(suite.q.sources)
+		})
+	}
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadata() {
+	docID := suite.seed()
+
+	revision, err := suite.repo.SaveMetadata(context.Background(), suite.update(docID), RevisionInfo{CreatedByName: "editor", RestoredFrom: 1})
+
+	suite.Require().NoError(err)
+	suite.EqualValues(2, revision)
+	suite.Equal("New title", suite.q.documents[docID].Title)
+	suite.True(suite.q.documents[docID].ToIndex.Bool)
+	suite.Equal([]string{"New"}, suite.q.names(kindAuthor, docID))
+	rev := suite.q.revisions[docID][1]
+	suite.Equal("New title", rev.Title)
+	suite.EqualValues(1, rev.RestoredFrom.Int32)
+	suite.Equal(ReviewInReview, suite.q.documents[docID].ReviewState)
+	suite.Equal(map[string]string{
+		"title":      SourceHuman,
+		"abstract":   SourceLLM,
+		"authors":    SourceHuman,
+		"keywords":   SourceHuman,
+		"categories": SourceHuman,
+		"regions":    SourceHuman,
+	}, suite.q.sources[docID], "changed fields were written by a person")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataLanguage() {
+	docID := suite.seed()
+	update := suite.update(docID)
+	update.Title = "Rapport annuel"
+	update.Language = sql.NullString{String: "fr", Valid: true}
+	update.EnglishTitle = sql.NullString{String: "Annual report", Valid: true}
+
+	_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{CreatedByName: "editor"})
+
+	suite.Require().NoError(err)
+	doc := suite.q.documents[docID]
+	suite.Equal("fr", doc.Language.String)
+	suite.Equal("Annual report", doc.EnglishTitle.String)
+	rev := suite.q.revisions[docID][1]
+	suite.Equal("fr", rev.Language.String)
+	suite.Equal("Annual report", rev.EnglishTitle.String)
+	suite.False(rev.EnglishAbstract.Valid)
+	suite.Equal(SourceHuman, suite.q.sources[docID]["language"])
+	suite.Equal(SourceHuman, suite.q.sources[docID]["english_title"])
+	suite.NotContains(suite.q.sources[docID], "english_abstract", "unchanged fields keep their source")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSetTranslations() {
+	ctx := context.Background()
+	english, french, spanish, other := suite.seed(), suite.seed(), suite.seed(), suite.seed()
+	group := func(id uuid.UUID) uuid.NullUUID { return suite.q.documents[id].TranslationGroup }
+
+	suite.Require().NoError(suite.repo.SetTranslations(ctx, english, []uuid.UUID{french}))
+	suite.True(group(english).Valid)
+	suite.Equal(group(english), group(french))
+	suite.False(group(spanish).Valid)
+
+	// Linking a document that has translations of its own merges the groups
+	suite.Require().NoError(suite.repo.SetTranslations(ctx, spanish, []uuid.UUID{other}))
+	suite.Require().NoError(suite.repo.SetTranslations(ctx, english, []uuid.UUID{french, spanish}))
+	suite.ElementsMatch([]uuid.UUID{english, french, spanish, other}, suite.q.translationGroup(group(english)))
+
+	// Documents left off the list leave the group
+	suite.Require().NoError(suite.repo.SetTranslations(ctx, english, []uuid.UUID{french, english}))
+	suite.ElementsMatch([]uuid.UUID{english, french}, suite.q.translationGroup(group(english)))
+	suite.False(group(spanish).Valid)
+	suite.False(group(other).Valid)
+
+	// Unlinking the last translation dissolves the group
+	suite.Require().NoError(suite.repo.SetTranslations(ctx, french, nil))
+	suite.False(group(english).Valid)
+	suite.False(group(french).Valid)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSetTranslationsRollsBackOnFailure() {
+	for _, step := range []string{"FindDocumentByID", "ClearTranslationGroup", "SetTranslationGroup", "DissolveLoneTranslationGroup"} {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			english, french, spanish := suite.seed(), suite.seed(), suite.seed()
+			suite.Require().NoError(suite.repo.SetTranslations(context.Background(), english, []uuid.UUID{french}))
+			before := suite.q.documents[english].TranslationGroup
+			suite.q.failOn = step
+
+			err := suite.repo.SetTranslations(context.Background(), english, []uuid.UUID{spanish})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Equal(1, suite.tx.rollbacks)
+			suite.ElementsMatch([]uuid.UUID{english, french}, suite.q.translationGroup(before))
+		})
+	}
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataTranslations() {
+	english, french := suite.seed(), suite.seed()
+	update := suite.update(english)
+	update.Translations = []uuid.UUID{french}
+
+	_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{CreatedByName: "editor"})
+
+	suite.Require().NoError(err)
+	group := suite.q.documents[english].TranslationGroup
+	suite.ElementsMatch([]uuid.UUID{english, french}, suite.q.translationGroup(group))
+
+	update.Translations = nil
+	_, err = suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{CreatedByName: "editor"})
+	suite.Require().NoError(err)
+	suite.Equal(group, suite.q.documents[english].TranslationGroup, "nil leaves translations as they are")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataTranslationsRollBackWithMetadata() {
+	for _, step := range []string{"SetTranslationGroup", "InsertDocumentRevision"} {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			english, french := suite.seed(), suite.seed()
+			update := suite.update(english)
+			update.Translations = []uuid.UUID{french}
+			suite.q.failOn = step
+
+			_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Equal(1, suite.tx.rollbacks)
+			suite.Equal("Report", suite.q.documents[english].Title)
+			suite.False(suite.q.documents[english].TranslationGroup.Valid)
+			suite.False(suite.q.documents[french].TranslationGroup.Valid)
+		})
+	}
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataORCIDs() {
+	docID := suite.seed()
+	author, other := suite.q.links[kindAuthor][docID][0], uuid.New()
+	suite.q.terms[kindAuthor][other] = "Josiah Carberry"
+	taken := sql.NullString{String: "0000-0002-1825-0097", Valid: true}
+	suite.q.details[other] = db.UpdateAuthorDetailsParams{ID: other, Orcid: taken}
+	suite.q.details[author] = db.UpdateAuthorDetailsParams{ID: author, Affiliation: sql.NullString{String: "UN", Valid: true}}
+	update := suite.update(docID)
+	update.Terms.Authors = []string{author.String()}
+	update.ORCIDs = []AuthorORCID{{AuthorID: author, ORCID: "0000-0002-1694-233X"}}
+
+	_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{CreatedByName: "editor"})
+
+	suite.Require().NoError(err)
+	saved, err := suite.q.FindAuthorByID(context.Background(), author)
+	suite.Require().NoError(err)
+	suite.Equal("0000-0002-1694-233X", saved.Orcid.String)
+	suite.Equal("UN", saved.Affiliation.String, "setting the iD keeps the affiliation")
+
+	// An iD another author took since the form was checked fails the whole save
+	update.Title = "Conflicting title"
+	update.ORCIDs = []AuthorORCID{{AuthorID: author, ORCID: taken.String}}
+	_, err = suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{CreatedByName: "editor"})
+
+	suite.Error(err)
+	suite.Equal(1, suite.tx.rollbacks)
+	suite.Equal("New title", suite.q.documents[docID].Title)
+	suite.Len(suite.q.revisions[docID], 2)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataReview() {
+	suite.repo = NewDocumentRepository(suite.tx, true)
+	docID := suite.seed()
+	ctx := context.Background()
+	update := suite.update(docID)
+
+	_, err := suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedByName: "editor"})
+	suite.Require().NoError(err)
+	suite.Equal(ReviewInReview, suite.q.documents[docID].ReviewState)
+	suite.False(suite.q.documents[docID].ToIndex.Bool, "not indexed until approved")
+
+	reviewer := uuid.New()
+	update.Approve = true
+	_, err = suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedBy: reviewer, CreatedByName: "reviewer"})
+	suite.Require().NoError(err)
+	doc := suite.q.documents[docID]
+	suite.Equal(ReviewApproved, doc.ReviewState)
+	suite.Equal(uuid.NullUUID{UUID: reviewer, Valid: true}, doc.ApprovedBy)
+	suite.Equal("reviewer", doc.ApprovedByName.String)
+	suite.True(doc.ToIndex.Bool)
+	suite.Equal(SourceLLM, suite.q.sources[docID]["abstract"], "unchanged fields keep their source")
+
+	// Editing an approved document sends it back for review
+	update.Approve = false
+	update.Title = "Edited again"
+	_, err = suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedByName: "editor"})
+	suite.Require().NoError(err)
+	suite.Equal(ReviewInReview, suite.q.documents[docID].ReviewState)
+	suite.False(suite.q.documents[docID].ToIndex.Bool, "edits are not indexed until approved again")
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataReviewsEditedApprovedDocument() {
+	docID := suite.seed()
+	ctx := context.Background()
+	update := suite.update(docID)
+	update.Approve = true
+	_, err := suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedByName: "reviewer"})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ReviewApproved, suite.q.documents[docID].ReviewState)
+
+	update.Approve = false
+	update.Title = "Edited by an editor"
+	_, err = suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedByName: "editor"})
+
+	suite.Require().NoError(err)
+	suite.Equal(ReviewInReview, suite.q.documents[docID].ReviewState)
+	suite.True(suite.q.documents[docID].ToIndex.Bool, "without INDEX_APPROVED_ONLY every edit is indexed")
+
+	update.Approve = true
+	_, err = suite.repo.SaveMetadata(ctx, update, RevisionInfo{CreatedByName: "reviewer"})
+	suite.Require().NoError(err)
+	suite.Equal(ReviewApproved, suite.q.documents[docID].ReviewState)
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataRollsBackOnFailure() {
+	steps := []string{
+		"FindDocumentByID", "UpdateDocumentMetadata",
+		"DeleteDocAuthorsByDocID", "DeleteDocKeywordsByDocID", "DeleteDocCategoriesByDocID", "DeleteDocRegionsByDocID",
+		"FindAuthorByName", "ListAuthorTerms", "InsertAuthor",
+		"FindKeywordByName", "ListKeywordTerms", "InsertKeyword",
+		"FindCategoryByName", "ListCategoryTerms", "InsertCategory",
+		"FindRegionByName", "ListRegionTerms", "InsertRegion",
+		"InsertDocAuthor", "InsertDocKeyword", "InsertDocCategory", "InsertDocRegion",
+		"SetDocumentFieldSource", "SetDocumentReviewState", "InsertDocumentRevision",
+	}
+	for _, step := range steps {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			docID := suite.seed()
+			suite.q.failOn = step
+
+			_, err := suite.repo.SaveMetadata(context.Background(), suite.update(docID), RevisionInfo{})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Equal(step, suite.q.calls[len(suite.q.calls)-1], "steps ran after the failure")
+			suite.Equal(1, suite.tx.rollbacks)
+			suite.Equal("Report", suite.q.documents[docID].Title)
+			suite.Equal([]string{"Old"}, suite.q.names(kindAuthor, docID), "document lost its authors")
+			suite.Len(suite.q.revisions[docID], 1)
+			suite.Equal(ReviewAIExtracted, suite.q.documents[docID].ReviewState)
+			suite.Equal(SourceLLM, suite.q.sources[docID]["title"])
+		})
+	}
+}
+
+func (suite *DocumentRepositoryTestSuite) TestSaveMetadataRejectsInvalidTermID() {
+	docID := suite.seed()
+	update := suite.update(docID)
+	update.Terms.Keywords = []string{"not-a-uuid"}
+
+	_, err := suite.repo.SaveMetadata(context.Background(), update, RevisionInfo{})
+
+	suite.Error(err)
+	suite.Equal(1, suite.tx.rollbacks)
+	suite.Equal([]string{"Old"}, suite.q.names(kindAuthor, docID))
+}
+
+func TestRevisionNames(t *testing.T) {
+	names := []string{"b", "a"}
+
+	assert.Equal(t, []string{"a", "b"}, revisionNames(names))
+	assert.Equal(t, []string{"b", "a"}, names, "input must not be reordered")
+	assert.Equal(t, []string{}, revisionNames(nil))
+}
+
+func TestDocumentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(DocumentRepositoryTestSuite))
+}
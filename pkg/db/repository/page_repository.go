@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+)
+
+// PageRepository stores the text of documents' pages, which search excerpts
+// and near-duplicate detection read.
+type PageRepository struct {
+	tx TxRunner
+}
+
+// NewPageRepository creates the repository.
+func NewPageRepository(tx TxRunner) *PageRepository {
+	return &PageRepository{tx: tx}
+}
+
+// Page is the text of one page of a document, numbered from 1.
+type Page struct {
+	Number  int32
+	Content string
+}
+
+// ReplacePages replaces the document's rows in document_pages with pages. A
+// failure part way through leaves the document with the pages it had.
+func (r *PageRepository) ReplacePages(ctx context.Context, docID uuid.UUID, pages []Page) error {
+	return r.tx.WithTx(ctx, func(q Querier) error {
+		if err := q.DeleteDocumentPagesByDocID(ctx, docID); err != nil {
+			return fmt.Errorf("failed to delete existing pages: %w", err)
+		}
+		for _, page := range pages {
+			if err := q.InsertDocumentPage(ctx, db.InsertDocumentPageParams{
+				DocID:      docID,
+				PageNumber: page.Number,
+				Content:    page.Content,
+			}); err != nil {
+				return fmt.Errorf("failed to insert page %d: %w", page.Number, err)
+			}
+		}
+		return nil
+	})
+}
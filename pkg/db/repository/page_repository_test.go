@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PageRepositoryTestSuite struct {
+	suite.Suite
+	q    *fakeQuerier
+	tx   *fakeTxRunner
+	repo *PageRepository
+}
+
+func (suite *PageRepositoryTestSuite) SetupTest() {
+	suite.q = &fakeQuerier{fakeState: newFakeState()}
+	suite.tx = &fakeTxRunner{q: suite.q}
+	suite.repo = NewPageRepository(suite.tx)
+}
+
+func (suite *PageRepositoryTestSuite) TestReplacePages() {
+	docID := uuid.New()
+	suite.q.pages[docID] = map[int32]string{1: "old first page", 2: "old second page"}
+
+	err := suite.repo.ReplacePages(context.Background(), docID, []Page{{Number: 1, Content: "first"}, {Number: 3, Content: "third"}})
+
+	suite.Require().NoError(err)
+	suite.Equal(map[int32]string{1: "first", 3: "third"}, suite.q.pages[docID])
+}
+
+func (suite *PageRepositoryTestSuite) TestReplacePagesRollsBackOnFailure() {
+	for _, step := range []string{"DeleteDocumentPagesByDocID", "InsertDocumentPage"} {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			docID := uuid.New()
+			suite.q.pages[docID] = map[int32]string{1: "old first page", 2: "old second page"}
+			suite.q.failOn = step
+
+			err := suite.repo.ReplacePages(context.Background(), docID, []Page{{Number: 1, Content: "first"}, {Number: 2, Content: "second"}})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Equal(1, suite.tx.rollbacks)
+			suite.Equal(map[int32]string{1: "old first page", 2: "old second page"}, suite.q.pages[docID], "document lost its pages")
+		})
+	}
+}
+
+func TestPageRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PageRepositoryTestSuite))
+}
@@ -0,0 +1,513 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+var (
+	ErrTermNotFound = errors.New("term not found")
+	ErrTermExists   = errors.New("another term already has that name")
+	ErrTermInUse    = errors.New("term is used by documents")
+)
+
+// TermRepository approves, renames, merges, splits and deletes authors,
+// keywords, publishers, regions and categories, and records their aliases.
+type TermRepository struct {
+	tx                TxRunner
+	indexApprovedOnly bool
+}
+
+// NewTermRepository creates the repository. With indexApprovedOnly, changing
+// terms only queues approved documents for re-indexing, as in DocumentRepository.
+func NewTermRepository(tx TxRunner, indexApprovedOnly bool) *TermRepository {
+	return &TermRepository{tx: tx, indexApprovedOnly: indexApprovedOnly}
+}
+
+// termQueries are the queries for one taxonomy, so that the repository's
+// methods need not repeat themselves for each.
+type termQueries struct {
+	find           func(ctx context.Context, id uuid.UUID) (string, error)
+	findByName     func(ctx context.Context, name string) (uuid.UUID, error)
+	insert         func(ctx context.Context, id uuid.UUID, name string) error
+	rename         func(ctx context.Context, id uuid.UUID, name string) error
+	addAlias       func(ctx context.Context, key, alias string, id uuid.UUID) error
+	approve        func(ctx context.Context, id uuid.UUID) (int64, error)
+	moveDocs       func(ctx context.Context, from, to uuid.UUID) error
+	moveSomeDocs   func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error
+	unlinkDocs     func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error
+	moveAliases    func(ctx context.Context, from, to uuid.UUID) error
+	moveVocabulary func(ctx context.Context, from, to uuid.UUID) error
+	moveDetails    func(ctx context.Context, from, to uuid.UUID) error // what only some taxonomies have; nil for the others
+	remove         func(ctx context.Context, id uuid.UUID) (int64, error)
+	removeUnused   func(ctx context.Context, id uuid.UUID) (int64, error)
+	flag           func(ctx context.Context, id uuid.UUID, approvedOnly bool) error
+}
+
+func termQueriesFor(q Querier, kind string) (termQueries, error) {
+	switch kind {
+	case taxonomy.Authors:
+		return termQueries{
+			find: func(ctx context.Context, id uuid.UUID) (string, error) {
+				row, err := q.FindAuthorByID(ctx, id)
+				return row.Name, err
+			},
+			findByName: func(ctx context.Context, name string) (uuid.UUID, error) {
+				row, err := q.FindAuthorByName(ctx, name)
+				return row.ID, err
+			},
+			insert: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.InsertAuthor(ctx, db.InsertAuthorParams{ID: id, Name: name, Approved: true})
+			},
+			rename: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.RenameAuthor(ctx, db.RenameAuthorParams{ID: id, Name: name})
+			},
+			addAlias: func(ctx context.Context, key, alias string, id uuid.UUID) error {
+				return q.InsertAuthorAlias(ctx, db.InsertAuthorAliasParams{AliasKey: key, Alias: alias, AuthorID: id})
+			},
+			approve: q.ApproveAuthor,
+			moveDocs: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveDocAuthors(ctx, db.MoveDocAuthorsParams{FromID: from, ToID: to})
+			},
+			moveSomeDocs: func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error {
+				return q.MoveDocAuthorsForDocuments(ctx, db.MoveDocAuthorsForDocumentsParams{FromID: from, ToID: to, DocIds: docIDs})
+			},
+			unlinkDocs: func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error {
+				return q.DeleteDocAuthorsForDocuments(ctx, db.DeleteDocAuthorsForDocumentsParams{AuthorID: id, DocIds: docIDs})
+			},
+			moveAliases: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveAuthorAliases(ctx, db.MoveAuthorAliasesParams{FromID: from, ToID: to})
+			},
+			moveVocabulary: func(ctx context.Context, from, to uuid.UUID) error {
+				return nil // authors are not offered to the model
+			},
+			moveDetails: func(ctx context.Context, from, to uuid.UUID) error {
+				return moveAuthorDetails(ctx, q, from, to)
+			},
+			remove:       q.DeleteAuthor,
+			removeUnused: q.DeleteUnusedAuthor,
+			flag: func(ctx context.Context, id uuid.UUID, approvedOnly bool) error {
+				return q.FlagAuthorDocumentsForIndex(ctx, db.FlagAuthorDocumentsForIndexParams{AuthorID: id, ApprovedOnly: approvedOnly})
+			},
+		}, nil
+	case taxonomy.Keywords:
+		return termQueries{
+			find: func(ctx context.Context, id uuid.UUID) (string, error) {
+				row, err := q.FindKeywordByID(ctx, id)
+				return row.Name, err
+			},
+			findByName: func(ctx context.Context, name string) (uuid.UUID, error) {
+				row, err := q.FindKeywordByName(ctx, name)
+				return row.ID, err
+			},
+			insert: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.InsertKeyword(ctx, db.InsertKeywordParams{ID: id, Name: name, Approved: true})
+			},
+			rename: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.RenameKeyword(ctx, db.RenameKeywordParams{ID: id, Name: name})
+			},
+			addAlias: func(ctx context.Context, key, alias string, id uuid.UUID) error {
+				return q.InsertKeywordAlias(ctx, db.InsertKeywordAliasParams{AliasKey: key, Alias: alias, KeywordID: id})
+			},
+			approve: q.ApproveKeyword,
+			moveDocs: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveDocKeywords(ctx, db.MoveDocKeywordsParams{FromID: from, ToID: to})
+			},
+			moveSomeDocs: func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error {
+				return q.MoveDocKeywordsForDocuments(ctx, db.MoveDocKeywordsForDocumentsParams{FromID: from, ToID: to, DocIds: docIDs})
+			},
+			unlinkDocs: func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error {
+				return q.DeleteDocKeywordsForDocuments(ctx, db.DeleteDocKeywordsForDocumentsParams{KeywordID: id, DocIds: docIDs})
+			},
+			moveAliases: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveKeywordAliases(ctx, db.MoveKeywordAliasesParams{FromID: from, ToID: to})
+			},
+			moveVocabulary: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveVocabularyKeyword(ctx, db.MoveVocabularyKeywordParams{FromID: from, ToID: to})
+			},
+			remove:       q.DeleteKeyword,
+			removeUnused: q.DeleteUnusedKeyword,
+			flag: func(ctx context.Context, id uuid.UUID, approvedOnly bool) error {
+				return q.FlagKeywordDocumentsForIndex(ctx, db.FlagKeywordDocumentsForIndexParams{KeywordID: id, ApprovedOnly: approvedOnly})
+			},
+		}, nil
+	case taxonomy.Publishers:
+		return termQueries{
+			find: func(ctx context.Context, id uuid.UUID) (string, error) {
+				row, err := q.FindPublisherByID(ctx, id)
+				return row.Name, err
+			},
+			findByName: func(ctx context.Context, name string) (uuid.UUID, error) {
+				row, err := q.FindPublisherByName(ctx, name)
+				return row.ID, err
+			},
+			insert: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.InsertPublisher(ctx, db.InsertPublisherParams{ID: id, Name: name, Approved: true})
+			},
+			rename: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.RenamePublisher(ctx, db.RenamePublisherParams{ID: id, Name: name})
+			},
+			addAlias: func(ctx context.Context, key, alias string, id uuid.UUID) error {
+				return q.InsertPublisherAlias(ctx, db.InsertPublisherAliasParams{AliasKey: key, Alias: alias, PublisherID: id})
+			},
+			approve: q.ApprovePublisher,
+			moveDocs: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveDocPublishers(ctx, db.MoveDocPublishersParams{FromID: from, ToID: to})
+			},
+			moveSomeDocs: func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error {
+				return q.MoveDocPublishersForDocuments(ctx, db.MoveDocPublishersForDocumentsParams{FromID: from, ToID: to, DocIds: docIDs})
+			},
+			unlinkDocs: func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error {
+				return q.DeleteDocPublishersForDocuments(ctx, db.DeleteDocPublishersForDocumentsParams{PublisherID: id, DocIds: docIDs})
+			},
+			moveAliases: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MovePublisherAliases(ctx, db.MovePublisherAliasesParams{FromID: from, ToID: to})
+			},
+			moveVocabulary: func(ctx context.Context, from, to uuid.UUID) error {
+				return nil // publishers are not offered to the model
+			},
+			remove:       q.DeletePublisher,
+			removeUnused: q.DeleteUnusedPublisher,
+			flag: func(ctx context.Context, id uuid.UUID, approvedOnly bool) error {
+				return q.FlagPublisherDocumentsForIndex(ctx, db.FlagPublisherDocumentsForIndexParams{PublisherID: id, ApprovedOnly: approvedOnly})
+			},
+		}, nil
+	case taxonomy.Regions:
+		return termQueries{
+			find: func(ctx context.Context, id uuid.UUID) (string, error) {
+				row, err := q.FindRegionByID(ctx, id)
+				return row.Name, err
+			},
+			findByName: func(ctx context.Context, name string) (uuid.UUID, error) {
+				row, err := q.FindRegionByName(ctx, name)
+				return row.ID, err
+			},
+			insert: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.InsertRegion(ctx, db.InsertRegionParams{ID: id, Name: name, Approved: true})
+			},
+			rename: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.RenameRegion(ctx, db.RenameRegionParams{ID: id, Name: name})
+			},
+			addAlias: func(ctx context.Context, key, alias string, id uuid.UUID) error {
+				return q.InsertRegionAlias(ctx, db.InsertRegionAliasParams{AliasKey: key, Alias: alias, RegionID: id})
+			},
+			approve: q.ApproveRegion,
+			moveDocs: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveDocRegions(ctx, db.MoveDocRegionsParams{FromID: from, ToID: to})
+			},
+			moveSomeDocs: func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error {
+				return q.MoveDocRegionsForDocuments(ctx, db.MoveDocRegionsForDocumentsParams{FromID: from, ToID: to, DocIds: docIDs})
+			},
+			unlinkDocs: func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error {
+				return q.DeleteDocRegionsForDocuments(ctx, db.DeleteDocRegionsForDocumentsParams{RegionID: id, DocIds: docIDs})
+			},
+			moveAliases: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveRegionAliases(ctx, db.MoveRegionAliasesParams{FromID: from, ToID: to})
+			},
+			moveVocabulary: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveVocabularyRegion(ctx, db.MoveVocabularyRegionParams{FromID: from, ToID: to})
+			},
+			moveDetails: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveRegionChildren(ctx, db.MoveRegionChildrenParams{FromID: from, ToID: to})
+			},
+			remove:       q.DeleteRegion,
+			removeUnused: q.DeleteUnusedRegion,
+			flag: func(ctx context.Context, id uuid.UUID, approvedOnly bool) error {
+				return q.FlagRegionDocumentsForIndex(ctx, db.FlagRegionDocumentsForIndexParams{RegionID: id, ApprovedOnly: approvedOnly})
+			},
+		}, nil
+	case taxonomy.Categories:
+		return termQueries{
+			find: func(ctx context.Context, id uuid.UUID) (string, error) {
+				row, err := q.FindCategoryByID(ctx, id)
+				return row.Name, err
+			},
+			findByName: func(ctx context.Context, name string) (uuid.UUID, error) {
+				row, err := q.FindCategoryByName(ctx, name)
+				return row.ID, err
+			},
+			insert: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.InsertCategory(ctx, db.InsertCategoryParams{ID: id, Name: name, Approved: true})
+			},
+			rename: func(ctx context.Context, id uuid.UUID, name string) error {
+				return q.RenameCategory(ctx, db.RenameCategoryParams{ID: id, Name: name})
+			},
+			addAlias: func(ctx context.Context, key, alias string, id uuid.UUID) error {
+				return q.InsertCategoryAlias(ctx, db.InsertCategoryAliasParams{AliasKey: key, Alias: alias, CategoryID: id})
+			},
+			approve: q.ApproveCategory,
+			moveDocs: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveDocCategories(ctx, db.MoveDocCategoriesParams{FromID: from, ToID: to})
+			},
+			moveSomeDocs: func(ctx context.Context, from, to uuid.UUID, docIDs []uuid.UUID) error {
+				return q.MoveDocCategoriesForDocuments(ctx, db.MoveDocCategoriesForDocumentsParams{FromID: from, ToID: to, DocIds: docIDs})
+			},
+			unlinkDocs: func(ctx context.Context, id uuid.UUID, docIDs []uuid.UUID) error {
+				return q.DeleteDocCategoriesForDocuments(ctx, db.DeleteDocCategoriesForDocumentsParams{CategoryID: id, DocIds: docIDs})
+			},
+			moveAliases: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveCategoryAliases(ctx, db.MoveCategoryAliasesParams{FromID: from, ToID: to})
+			},
+			moveVocabulary: func(ctx context.Context, from, to uuid.UUID) error {
+				return q.MoveVocabularyCategory(ctx, db.MoveVocabularyCategoryParams{FromID: from, ToID: to})
+			},
+			remove:       q.DeleteCategory,
+			removeUnused: q.DeleteUnusedCategory,
+			flag: func(ctx context.Context, id uuid.UUID, approvedOnly bool) error {
+				return q.FlagCategoryDocumentsForIndex(ctx, db.FlagCategoryDocumentsForIndexParams{CategoryID: id, ApprovedOnly: approvedOnly})
+			},
+		}, nil
+	default:
+		return termQueries{}, fmt.Errorf("%w: %q", util.ErrUnknownTaxonomy, kind)
+	}
+}
+
+// withTerms runs fn in a transaction with the queries for kind.
+func (r *TermRepository) withTerms(ctx context.Context, kind string, fn func(tq termQueries) error) error {
+	return r.tx.WithTx(ctx, func(q Querier) error {
+		tq, err := termQueriesFor(q, kind)
+		if err != nil {
+			return err
+		}
+		return fn(tq)
+	})
+}
+
+// findName returns a term's name, or ErrTermNotFound.
+func findName(ctx context.Context, tq termQueries, id uuid.UUID) (string, error) {
+	name, err := tq.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s", ErrTermNotFound, id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read term: %w", err)
+	}
+	return name, nil
+}
+
+// Approve marks a term created from an unknown name as approved.
+func (r *TermRepository) Approve(ctx context.Context, kind string, id uuid.UUID) error {
+	return r.withTerms(ctx, kind, func(tq termQueries) error {
+		n, err := tq.approve(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to approve term: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: %s", ErrTermNotFound, id)
+		}
+		return nil
+	})
+}
+
+// AddAlias records alias as another name for the term, replacing whatever term
+// the alias named before.
+func (r *TermRepository) AddAlias(ctx context.Context, kind string, id uuid.UUID, alias string) error {
+	key := taxonomy.Fold(alias)
+	if key == "" {
+		return fmt.Errorf("alias %q has no letters or digits", alias)
+	}
+	return r.withTerms(ctx, kind, func(tq termQueries) error {
+		if _, err := findName(ctx, tq, id); err != nil {
+			return err
+		}
+		if err := tq.addAlias(ctx, key, alias, id); err != nil {
+			return fmt.Errorf("failed to add alias: %w", err)
+		}
+		return nil
+	})
+}
+
+// Merge folds the term from into the term to: documents that used from use to
+// instead and are queued for re-indexing, from's name and aliases become
+// aliases of to, to joins any vocabulary from was in, and from is deleted.
+func (r *TermRepository) Merge(ctx context.Context, kind string, from, to uuid.UUID) error {
+	if from == to {
+		return fmt.Errorf("cannot merge a term into itself")
+	}
+	return r.withTerms(ctx, kind, func(tq termQueries) error {
+		name, err := findName(ctx, tq, from)
+		if err != nil {
+			return err
+		}
+		if _, err := findName(ctx, tq, to); err != nil {
+			return err
+		}
+
+		if err := tq.moveDocs(ctx, from, to); err != nil {
+			return fmt.Errorf("failed to move documents: %w", err)
+		}
+		if err := tq.moveAliases(ctx, from, to); err != nil {
+			return fmt.Errorf("failed to move aliases: %w", err)
+		}
+		if key := taxonomy.Fold(name); key != "" {
+			if err := tq.addAlias(ctx, key, name, to); err != nil {
+				return fmt.Errorf("failed to add alias: %w", err)
+			}
+		}
+		if err := tq.moveVocabulary(ctx, from, to); err != nil {
+			return fmt.Errorf("failed to move vocabulary: %w", err)
+		}
+		if tq.moveDetails != nil {
+			if err := tq.moveDetails(ctx, from, to); err != nil {
+				return fmt.Errorf("failed to move details: %w", err)
+			}
+		}
+		if _, err := tq.remove(ctx, from); err != nil {
+			return fmt.Errorf("failed to delete term: %w", err)
+		}
+		if err := tq.flag(ctx, to, r.indexApprovedOnly); err != nil {
+			return fmt.Errorf("failed to queue documents for indexing: %w", err)
+		}
+		return nil
+	})
+}
+
+// Rename gives a term a new name and queues its documents for re-indexing. The
+// old name becomes an alias, so later uploads that use it still match.
+func (r *TermRepository) Rename(ctx context.Context, kind string, id uuid.UUID, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return r.withTerms(ctx, kind, func(tq termQueries) error {
+		old, err := findName(ctx, tq, id)
+		if err != nil {
+			return err
+		}
+		if err := checkNameFree(ctx, tq, name, id); err != nil {
+			return err
+		}
+
+		if err := tq.rename(ctx, id, name); err != nil {
+			return fmt.Errorf("failed to rename term: %w", err)
+		}
+		if key := taxonomy.Fold(old); key != "" && key != taxonomy.Fold(name) {
+			if err := tq.addAlias(ctx, key, old, id); err != nil {
+				return fmt.Errorf("failed to add alias: %w", err)
+			}
+		}
+		if err := tq.flag(ctx, id, r.indexApprovedOnly); err != nil {
+			return fmt.Errorf("failed to queue documents for indexing: %w", err)
+		}
+		return nil
+	})
+}
+
+// Split moves the given documents from the term from to the term named name,
+// creating it as an approved term if there is none, and queues them for
+// re-indexing. It returns the ID of the term the documents now use.
+func (r *TermRepository) Split(ctx context.Context, kind string, from uuid.UUID, name string, docIDs []uuid.UUID) (uuid.UUID, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return uuid.Nil, fmt.Errorf("name is required")
+	}
+	if len(docIDs) == 0 {
+		return uuid.Nil, fmt.Errorf("no documents selected")
+	}
+
+	var to uuid.UUID
+	err := r.withTerms(ctx, kind, func(tq termQueries) error {
+		if _, err := findName(ctx, tq, from); err != nil {
+			return err
+		}
+
+		id, err := tq.findByName(ctx, name)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			id = uuid.New()
+			if err := tq.insert(ctx, id, name); err != nil {
+				return fmt.Errorf("failed to create term: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to read term: %w", err)
+		case id == from:
+			return fmt.Errorf("cannot split a term into itself")
+		}
+
+		// Documents that already have the new term keep their link to it,
+		// and lose the one to the old term.
+		if err := tq.moveSomeDocs(ctx, from, id, docIDs); err != nil {
+			return fmt.Errorf("failed to move documents: %w", err)
+		}
+		if err := tq.unlinkDocs(ctx, from, docIDs); err != nil {
+			return fmt.Errorf("failed to move documents: %w", err)
+		}
+		if err := tq.flag(ctx, id, r.indexApprovedOnly); err != nil {
+			return fmt.Errorf("failed to queue documents for indexing: %w", err)
+		}
+		to = id
+		return nil
+	})
+	return to, err
+}
+
+// Delete removes a term that no document uses, with its aliases. It returns
+// ErrTermInUse for a term that is still used; merge it into another instead.
+func (r *TermRepository) Delete(ctx context.Context, kind string, id uuid.UUID) error {
+	return r.withTerms(ctx, kind, func(tq termQueries) error {
+		n, err := tq.removeUnused(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete term: %w", err)
+		}
+		if n > 0 {
+			return nil
+		}
+		name, err := findName(ctx, tq, id)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", ErrTermInUse, name)
+	})
+}
+
+// checkNameFree returns ErrTermExists if a term other than id already has name.
+func checkNameFree(ctx context.Context, tq termQueries, name string, id uuid.UUID) error {
+	other, err := tq.findByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read term: %w", err)
+	}
+	if other != id {
+		return fmt.Errorf("%w: %s", ErrTermExists, name)
+	}
+	return nil
+}
+
+// moveAuthorDetails copies the ORCID iD and affiliation of the author from to
+// the author to, where to has none of its own. from loses its ORCID iD first,
+// since an ORCID iD belongs to one author at most.
+func moveAuthorDetails(ctx context.Context, q Querier, from, to uuid.UUID) error {
+	source, err := q.FindAuthorByID(ctx, from)
+	if err != nil {
+		return err
+	}
+	if !source.Orcid.Valid && !source.Affiliation.Valid {
+		return nil
+	}
+	target, err := q.FindAuthorByID(ctx, to)
+	if err != nil {
+		return err
+	}
+	if _, err := q.UpdateAuthorDetails(ctx, db.UpdateAuthorDetailsParams{ID: from}); err != nil {
+		return err
+	}
+	if !target.Orcid.Valid {
+		target.Orcid = source.Orcid
+	}
+	if !target.Affiliation.Valid {
+		target.Affiliation = source.Affiliation
+	}
+	_, err = q.UpdateAuthorDetails(ctx, db.UpdateAuthorDetailsParams{ID: to, Orcid: target.Orcid, Affiliation: target.Affiliation})
+	return err
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+type TermRepositoryTestSuite struct {
+	suite.Suite
+	q    *fakeQuerier
+	tx   *fakeTxRunner
+	docs *DocumentRepository
+	repo *TermRepository
+}
+
+func (suite *TermRepositoryTestSuite) SetupTest() {
+	suite.q = &fakeQuerier{fakeState: newFakeState()}
+	suite.tx = &fakeTxRunner{q: suite.q}
+	suite.docs = NewDocumentRepository(suite.tx, true)
+	suite.repo = NewTermRepository(suite.tx, true)
+}
+
+// document stores a document in the given review state with the named regions.
+func (suite *TermRepositoryTestSuite) document(state string, regions ...string) uuid.UUID {
+	id := uuid.New()
+	suite.Require().NoError(suite.docs.CreateDocument(context.Background(), NewDocument{
+		ID:    id,
+		Title: "Report",
+		Terms: Terms{Regions: ByName(regions)},
+	}, RevisionInfo{}))
+	doc := suite.q.documents[id]
+	doc.ReviewState = state
+	suite.q.documents[id] = doc
+	return id
+}
+
+func (suite *TermRepositoryTestSuite) region(name string) uuid.UUID {
+	for id, n := range suite.q.terms[kindRegion] {
+		if n == name {
+			return id
+		}
+	}
+	suite.FailNow("no region " + name)
+	return uuid.Nil
+}
+
+func (suite *TermRepositoryTestSuite) TestApprove() {
+	suite.document(ReviewApproved, "Atlantis")
+	atlantis := suite.region("Atlantis")
+	suite.True(suite.q.pending[atlantis])
+
+	suite.Require().NoError(suite.repo.Approve(context.Background(), taxonomy.Regions, atlantis))
+	suite.False(suite.q.pending[atlantis])
+
+	suite.ErrorIs(suite.repo.Approve(context.Background(), taxonomy.Regions, uuid.New()), ErrTermNotFound)
+	suite.ErrorIs(suite.repo.Approve(context.Background(), "planets", atlantis), util.ErrUnknownTaxonomy)
+}
+
+func (suite *TermRepositoryTestSuite) TestAddAlias() {
+	suite.document(ReviewApproved, "Democratic Republic of the Congo")
+	congo := suite.region("Democratic Republic of the Congo")
+
+	suite.Require().NoError(suite.repo.AddAlias(context.Background(), taxonomy.Regions, congo, "DRC"))
+	suite.Equal(fakeAlias{name: "DRC", termID: congo}, suite.q.aliases[kindRegion]["drc"])
+
+	suite.ErrorIs(suite.repo.AddAlias(context.Background(), taxonomy.Regions, uuid.New(), "Zaire"), ErrTermNotFound)
+	suite.Error(suite.repo.AddAlias(context.Background(), taxonomy.Regions, congo, "..."))
+}
+
+func (suite *TermRepositoryTestSuite) TestMerge() {
+	approved := suite.document(ReviewApproved, "Oceania Region")
+	both := suite.document(ReviewApproved, "Oceania Region", "Pacific")
+	unapproved := suite.document(ReviewInReview, "Oceania Region")
+	from, to := suite.region("Oceania Region"), suite.region("Pacific")
+	suite.q.aliases[kindRegion]["oceanie"] = fakeAlias{name: "Océanie", termID: from}
+	suite.q.vocabulary[from] = true
+	fiji := uuid.New()
+	suite.q.parents[fiji] = from
+	for _, id := range []uuid.UUID{approved, both, unapproved} {
+		doc := suite.q.documents[id]
+		doc.ToIndex.Bool = false
+		suite.q.documents[id] = doc
+	}
+
+	suite.Require().NoError(suite.repo.Merge(context.Background(), taxonomy.Regions, from, to))
+
+	suite.NotContains(suite.q.terms[kindRegion], from)
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindRegion][approved])
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindRegion][both])
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindRegion][unapproved])
+	suite.Equal(fakeAlias{name: "Oceania Region", termID: to}, suite.q.aliases[kindRegion]["oceania region"])
+	suite.Equal(fakeAlias{name: "Océanie", termID: to}, suite.q.aliases[kindRegion]["oceanie"])
+	suite.True(suite.q.documents[approved].ToIndex.Bool)
+	suite.True(suite.q.documents[both].ToIndex.Bool)
+	suite.False(suite.q.documents[unapproved].ToIndex.Bool, "only approved documents are indexed")
+	suite.True(suite.q.vocabulary[to], "the vocabulary offers the merged term")
+	suite.Equal(to, suite.q.parents[fiji], "the merged region's countries move with it")
+}
+
+func (suite *TermRepositoryTestSuite) TestMergeRollsBackOnFailure() {
+	steps := []string{
+		"FindRegionByID", "MoveDocRegions", "MoveRegionAliases", "InsertRegionAlias", "MoveVocabularyRegion", "MoveRegionChildren", "DeleteRegion", "FlagRegionDocumentsForIndex",
+	}
+	for _, step := range steps {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			docID := suite.document(ReviewApproved, "Oceana", "Pacific")
+			from, to := suite.region("Oceana"), suite.region("Pacific")
+			suite.q.failOn = step
+
+			err := suite.repo.Merge(context.Background(), taxonomy.Regions, from, to)
+
+			suite.ErrorIs(err, errInjected)
+			suite.Equal(step, suite.q.calls[len(suite.q.calls)-1], "steps ran after the failure")
+			suite.Contains(suite.q.terms[kindRegion], from)
+			suite.Equal([]uuid.UUID{from, to}, suite.q.links[kindRegion][docID])
+			suite.Empty(suite.q.aliases[kindRegion])
+		})
+	}
+}
+
+func (suite *TermRepositoryTestSuite) TestMergePublishers() {
+	docID := uuid.New()
+	suite.Require().NoError(suite.docs.CreateDocument(context.Background(), NewDocument{
+		ID:    docID,
+		Title: "Report",
+		Terms: Terms{Publisher: PublisherByName("UNDP")},
+	}, RevisionInfo{}))
+	from := suite.q.links[kindPublisher][docID][0]
+	to := uuid.New()
+	suite.q.terms[kindPublisher][to] = "United Nations Development Programme"
+
+	suite.Require().NoError(suite.repo.Merge(context.Background(), taxonomy.Publishers, from, to))
+
+	suite.NotContains(suite.q.terms[kindPublisher], from)
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindPublisher][docID])
+	suite.Equal(fakeAlias{name: "UNDP", termID: to}, suite.q.aliases[kindPublisher]["undp"])
+}
+
+func (suite *TermRepositoryTestSuite) TestMergeKeepsAuthorDetails() {
+	from, to := uuid.New(), uuid.New()
+	suite.q.terms[kindAuthor][from] = "J. Smith"
+	suite.q.terms[kindAuthor][to] = "John Smith"
+	orcid := sql.NullString{String: "0000-0002-1825-0097", Valid: true}
+	suite.q.details[from] = db.UpdateAuthorDetailsParams{ID: from, Orcid: orcid, Affiliation: sql.NullString{String: "UN", Valid: true}}
+	suite.q.details[to] = db.UpdateAuthorDetailsParams{ID: to, Affiliation: sql.NullString{String: "UNDP", Valid: true}}
+
+	suite.Require().NoError(suite.repo.Merge(context.Background(), taxonomy.Authors, from, to))
+
+	author, err := suite.q.FindAuthorByID(context.Background(), to)
+	suite.Require().NoError(err)
+	suite.Equal(orcid, author.Orcid)
+	suite.Equal("UNDP", author.Affiliation.String, "the merged author keeps its own affiliation")
+	suite.NotContains(suite.q.terms[kindAuthor], from)
+}
+
+func (suite *TermRepositoryTestSuite) TestMergeIntoMissingTerm() {
+	suite.document(ReviewApproved, "Oceana")
+	from := suite.region("Oceana")
+
+	suite.ErrorIs(suite.repo.Merge(context.Background(), taxonomy.Regions, from, uuid.New()), ErrTermNotFound)
+	suite.Error(suite.repo.Merge(context.Background(), taxonomy.Regions, from, from))
+	suite.Contains(suite.q.terms[kindRegion], from)
+}
+
+// clearIndexFlags marks every document as already indexed.
+func (suite *TermRepositoryTestSuite) clearIndexFlags() {
+	for id, doc := range suite.q.documents {
+		doc.ToIndex.Bool = false
+		suite.q.documents[id] = doc
+	}
+}
+
+func (suite *TermRepositoryTestSuite) TestRename() {
+	docID := suite.document(ReviewApproved, "Oceana")
+	id := suite.region("Oceana")
+	suite.clearIndexFlags()
+
+	suite.Require().NoError(suite.repo.Rename(context.Background(), taxonomy.Regions, id, " Oceania "))
+
+	suite.Equal("Oceania", suite.q.terms[kindRegion][id])
+	suite.Equal(fakeAlias{name: "Oceana", termID: id}, suite.q.aliases[kindRegion]["oceana"])
+	suite.True(suite.q.documents[docID].ToIndex.Bool)
+}
+
+func (suite *TermRepositoryTestSuite) TestRenameCaseOnly() {
+	suite.document(ReviewApproved, "oceania")
+	id := suite.region("oceania")
+
+	suite.Require().NoError(suite.repo.Rename(context.Background(), taxonomy.Regions, id, "Oceania"))
+
+	suite.Equal("Oceania", suite.q.terms[kindRegion][id])
+	suite.Empty(suite.q.aliases[kindRegion], "a name that folds the same needs no alias")
+}
+
+func (suite *TermRepositoryTestSuite) TestRenameToTakenName() {
+	suite.document(ReviewApproved, "Oceana", "Pacific")
+	id := suite.region("Oceana")
+
+	suite.ErrorIs(suite.repo.Rename(context.Background(), taxonomy.Regions, id, "PACIFIC"), ErrTermExists)
+	suite.ErrorIs(suite.repo.Rename(context.Background(), taxonomy.Regions, uuid.New(), "Oceania"), ErrTermNotFound)
+	suite.Error(suite.repo.Rename(context.Background(), taxonomy.Regions, id, "  "))
+	suite.Equal("Oceana", suite.q.terms[kindRegion][id])
+}
+
+func (suite *TermRepositoryTestSuite) TestSplit() {
+	sudan := suite.document(ReviewApproved, "Sudan")
+	south := suite.document(ReviewApproved, "Sudan")
+	both := suite.document(ReviewApproved, "Sudan", "South Sudan")
+	from := suite.region("Sudan")
+	suite.clearIndexFlags()
+
+	to, err := suite.repo.Split(context.Background(), taxonomy.Regions, from, "South Sudan", []uuid.UUID{south, both})
+	suite.Require().NoError(err)
+
+	suite.Equal(suite.region("South Sudan"), to)
+	suite.Equal([]uuid.UUID{from}, suite.q.links[kindRegion][sudan])
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindRegion][south])
+	suite.Equal([]uuid.UUID{to}, suite.q.links[kindRegion][both])
+	suite.False(suite.q.documents[sudan].ToIndex.Bool)
+	suite.True(suite.q.documents[south].ToIndex.Bool)
+}
+
+func (suite *TermRepositoryTestSuite) TestSplitCreatesApprovedTerm() {
+	docID := suite.document(ReviewApproved, "Sudan")
+	from := suite.region("Sudan")
+
+	to, err := suite.repo.Split(context.Background(), taxonomy.Regions, from, "South Sudan", []uuid.UUID{docID})
+	suite.Require().NoError(err)
+
+	suite.Equal("South Sudan", suite.q.terms[kindRegion][to])
+	suite.False(suite.q.pending[to])
+
+	_, err = suite.repo.Split(context.Background(), taxonomy.Regions, to, "south sudan", []uuid.UUID{docID})
+	suite.Error(err)
+	_, err = suite.repo.Split(context.Background(), taxonomy.Regions, to, "Sudan", nil)
+	suite.Error(err)
+}
+
+func (suite *TermRepositoryTestSuite) TestSplitRollsBackOnFailure() {
+	steps := []string{"FindRegionByID", "FindRegionByName", "InsertRegion", "MoveDocRegionsForDocuments", "DeleteDocRegionsForDocuments", "FlagRegionDocumentsForIndex"}
+	for _, step := range steps {
+		suite.Run(step, func() {
+			suite.SetupTest()
+			docID := suite.document(ReviewApproved, "Sudan")
+			from := suite.region("Sudan")
+			suite.q.failOn = step
+
+			_, err := suite.repo.Split(context.Background(), taxonomy.Regions, from, "South Sudan", []uuid.UUID{docID})
+
+			suite.ErrorIs(err, errInjected)
+			suite.Len(suite.q.terms[kindRegion], 1)
+			suite.Equal([]uuid.UUID{from}, suite.q.links[kindRegion][docID])
+		})
+	}
+}
+
+func (suite *TermRepositoryTestSuite) TestDelete() {
+	suite.document(ReviewApproved, "Oceania", "Atlantis")
+	oceania, atlantis := suite.region("Oceania"), suite.region("Atlantis")
+	unused := uuid.New()
+	suite.q.terms[kindRegion][unused] = "Lemuria"
+	suite.q.aliases[kindRegion]["mu"] = fakeAlias{name: "Mu", termID: unused}
+
+	suite.Require().NoError(suite.repo.Delete(context.Background(), taxonomy.Regions, unused))
+	suite.NotContains(suite.q.terms[kindRegion], unused)
+	suite.Empty(suite.q.aliases[kindRegion])
+
+	suite.ErrorIs(suite.repo.Delete(context.Background(), taxonomy.Regions, atlantis), ErrTermInUse)
+	suite.Contains(suite.q.terms[kindRegion], oceania)
+	suite.Contains(suite.q.terms[kindRegion], atlantis)
+	suite.ErrorIs(suite.repo.Delete(context.Background(), taxonomy.Regions, unused), ErrTermNotFound)
+}
+
+func TestTermRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TermRepositoryTestSuite))
+}
@@ -0,0 +1,5 @@
+-- 1. Drop index
+DROP INDEX IF EXISTS idx_document_sync_status_status;
+
+-- 2. Drop the sync status table
+DROP TABLE IF EXISTS document_sync_status;
@@ -0,0 +1,5 @@
+-- 1. Drop index
+DROP INDEX IF EXISTS idx_documents_content_hash;
+
+-- 2. Drop the column
+ALTER TABLE documents DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,5 @@
+-- 1. Drop index
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+-- 2. Drop the API key table
+DROP TABLE IF EXISTS api_keys;
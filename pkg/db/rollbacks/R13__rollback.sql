@@ -0,0 +1,8 @@
+-- 1. Restore the old flag
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_master boolean DEFAULT false NOT NULL;
+
+-- 2. Carry admins back to masters
+UPDATE users SET is_master = (role = 'admin');
+
+-- 3. Drop the role column
+ALTER TABLE users DROP COLUMN IF EXISTS role;
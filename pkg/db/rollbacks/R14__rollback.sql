@@ -0,0 +1,6 @@
+-- 1. Drop indexes
+DROP INDEX IF EXISTS idx_audit_events_created_at;
+DROP INDEX IF EXISTS idx_audit_events_doc_id;
+
+-- 2. Drop the audit table
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,6 @@
+-- 1. Drop the immutability trigger
+DROP TRIGGER IF EXISTS document_revisions_no_update ON document_revisions;
+DROP FUNCTION IF EXISTS document_revisions_immutable();
+
+-- 2. Drop the revisions table
+DROP TABLE IF EXISTS document_revisions;
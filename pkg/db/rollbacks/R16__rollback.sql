@@ -0,0 +1,3 @@
+-- 1. Drop the ingest jobs table
+DROP INDEX IF EXISTS idx_ingest_jobs_status;
+DROP TABLE IF EXISTS ingest_jobs;
@@ -0,0 +1,5 @@
+-- 1. Drop the batch files table
+DROP TABLE IF EXISTS ingest_batch_files;
+
+-- 2. Drop the content hash index
+DROP INDEX IF EXISTS idx_ingest_jobs_content_hash;
@@ -0,0 +1,2 @@
+-- 1. Drop the supplied metadata
+ALTER TABLE ingest_jobs DROP COLUMN IF EXISTS provided_metadata;
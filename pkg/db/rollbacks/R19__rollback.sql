@@ -0,0 +1,11 @@
+-- 1. Drop the extractions table
+DROP INDEX IF EXISTS idx_metadata_extractions_job_id;
+DROP TABLE IF EXISTS metadata_extractions;
+
+-- 2. Drop the vocabulary tables. Terms created by the seed are kept.
+DROP TABLE IF EXISTS vocabulary_keywords;
+DROP TABLE IF EXISTS vocabulary_regions;
+DROP TABLE IF EXISTS vocabulary_categories;
+
+-- 3. Drop the prompt versions table
+DROP TABLE IF EXISTS extraction_prompts;
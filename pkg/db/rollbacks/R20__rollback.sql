@@ -0,0 +1,12 @@
+-- 1. Drop the date index
+DROP INDEX IF EXISTS idx_metadata_extractions_created_at;
+
+-- 2. Drop the error, user, usage, cost and latency columns
+ALTER TABLE metadata_extractions
+    DROP COLUMN IF EXISTS error,
+    DROP COLUMN IF EXISTS created_by_name,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS latency_ms,
+    DROP COLUMN IF EXISTS cost,
+    DROP COLUMN IF EXISTS output_tokens,
+    DROP COLUMN IF EXISTS input_tokens;
@@ -0,0 +1,12 @@
+-- 1. Drop the per-field provenance table
+DROP TABLE IF EXISTS document_field_sources;
+
+-- 2. Drop the reviewer queue index
+DROP INDEX IF EXISTS idx_documents_review_state;
+
+-- 3. Drop the review columns
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS approved_by_name,
+    DROP COLUMN IF EXISTS approved_by,
+    DROP COLUMN IF EXISTS review_state;
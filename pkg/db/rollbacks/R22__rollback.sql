@@ -0,0 +1,11 @@
+-- 1. Drop the alias tables and their indexes
+DROP TABLE IF EXISTS region_aliases;
+DROP TABLE IF EXISTS keyword_aliases;
+DROP TABLE IF EXISTS category_aliases;
+DROP TABLE IF EXISTS author_aliases;
+
+-- 2. Drop the approval flags
+ALTER TABLE regions DROP COLUMN IF EXISTS approved;
+ALTER TABLE keywords DROP COLUMN IF EXISTS approved;
+ALTER TABLE categories DROP COLUMN IF EXISTS approved;
+ALTER TABLE authors DROP COLUMN IF EXISTS approved;
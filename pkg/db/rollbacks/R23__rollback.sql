@@ -0,0 +1,7 @@
+-- 1. Drop the hierarchy and the country codes. The regions added by V23 are
+--    kept, since documents may use them by now.
+DROP INDEX IF EXISTS idx_regions_parent_id;
+ALTER TABLE regions DROP CONSTRAINT IF EXISTS regions_parent_check;
+ALTER TABLE regions DROP CONSTRAINT IF EXISTS regions_iso_code_key;
+ALTER TABLE regions DROP COLUMN IF EXISTS iso_code;
+ALTER TABLE regions DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,5 @@
+-- 1. Drop the ORCID iD and the affiliation
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_orcid_check;
+ALTER TABLE authors DROP CONSTRAINT IF EXISTS authors_orcid_key;
+ALTER TABLE authors DROP COLUMN IF EXISTS affiliation;
+ALTER TABLE authors DROP COLUMN IF EXISTS orcid;
@@ -0,0 +1,14 @@
+-- 1. Restore the free-text source from the publishers' names. The prompt
+--    version added by V25 is kept, since extractions may refer to it.
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS source character varying(255);
+
+UPDATE documents d
+SET source = p.name
+FROM publishers p
+WHERE p.id = d.publisher_id;
+
+-- 2. Drop the publishers
+DROP INDEX IF EXISTS idx_documents_publisher_id;
+ALTER TABLE documents DROP COLUMN IF EXISTS publisher_id;
+DROP TABLE IF EXISTS publisher_aliases;
+DROP TABLE IF EXISTS publishers;
@@ -0,0 +1,52 @@
+-- 1. Restore the search vector and trigger from V8. The prompt version added
+--    by V26 is kept, since extractions may refer to it.
+DROP TRIGGER IF EXISTS documents_search_refresh ON documents;
+CREATE TRIGGER documents_search_refresh
+    AFTER INSERT OR UPDATE OF title, abstract ON documents
+    FOR EACH ROW EXECUTE FUNCTION document_search_doc_trigger();
+
+CREATE OR REPLACE FUNCTION refresh_document_search(p_doc_id uuid) RETURNS void
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO document_search (doc_id, search_vector)
+    SELECT
+        d.id,
+        setweight(to_tsvector('english', COALESCE(d.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(a.name, ' ')
+            FROM doc_authors da JOIN authors a ON a.id = da.author_id
+            WHERE da.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(k.name, ' ')
+            FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+            WHERE dk.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(d.abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(r.name, ' ')
+            FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+            WHERE dr.doc_id = d.id), '')), 'D') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(c.name, ' ')
+            FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+            WHERE dc.doc_id = d.id), '')), 'D')
+    FROM documents d
+    WHERE d.id = p_doc_id
+    ON CONFLICT (doc_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+END;
+$$;
+
+-- 2. Drop the language columns
+ALTER TABLE document_revisions DROP COLUMN IF EXISTS english_abstract;
+ALTER TABLE document_revisions DROP COLUMN IF EXISTS english_title;
+ALTER TABLE document_revisions DROP COLUMN IF EXISTS language;
+
+DROP INDEX IF EXISTS idx_documents_translation_group;
+DROP INDEX IF EXISTS idx_documents_language;
+ALTER TABLE documents DROP COLUMN IF EXISTS translation_group;
+ALTER TABLE documents DROP COLUMN IF EXISTS english_abstract;
+ALTER TABLE documents DROP COLUMN IF EXISTS english_title;
+ALTER TABLE documents DROP COLUMN IF EXISTS language;
+
+-- 3. Rebuild the search vectors without the English translations
+SELECT refresh_document_search(id) FROM documents;
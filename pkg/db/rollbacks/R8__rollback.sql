@@ -0,0 +1,19 @@
+-- 1. Drop the search triggers
+DROP TRIGGER IF EXISTS documents_search_refresh ON documents;
+DROP TRIGGER IF EXISTS doc_authors_search_refresh ON doc_authors;
+DROP TRIGGER IF EXISTS doc_keywords_search_refresh ON doc_keywords;
+DROP TRIGGER IF EXISTS doc_regions_search_refresh ON doc_regions;
+DROP TRIGGER IF EXISTS doc_categories_search_refresh ON doc_categories;
+DROP TRIGGER IF EXISTS authors_search_refresh ON authors;
+DROP TRIGGER IF EXISTS keywords_search_refresh ON keywords;
+DROP TRIGGER IF EXISTS regions_search_refresh ON regions;
+DROP TRIGGER IF EXISTS categories_search_refresh ON categories;
+
+-- 2. Drop the trigger functions
+DROP FUNCTION IF EXISTS document_search_doc_trigger();
+DROP FUNCTION IF EXISTS document_search_join_trigger();
+DROP FUNCTION IF EXISTS document_search_term_trigger();
+DROP FUNCTION IF EXISTS refresh_document_search(uuid);
+
+-- 3. Drop the search table
+DROP TABLE IF EXISTS document_search;
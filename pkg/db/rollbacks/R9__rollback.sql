@@ -0,0 +1,6 @@
+-- 1. Drop indexes
+DROP INDEX IF EXISTS idx_document_pages_search_vector;
+DROP INDEX IF EXISTS idx_document_pages_doc_id;
+
+-- 2. Drop the pages table
+DROP TABLE IF EXISTS document_pages;
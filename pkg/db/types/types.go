@@ -14,3 +14,312 @@ type PDFMetadata struct {
 	RegionNames   []string
 	CategoryNames []string
 }
+
+// DuplicateGroup is a set of documents that look like copies of each other.
+type DuplicateGroup struct {
+	Reason    string
+	Documents []DuplicateDocument
+}
+
+type DuplicateDocument struct {
+	ID        string
+	Title     string
+	FileName  string
+	Link      string
+	CreatedAt string
+	// Similarity is the text similarity to the suggested document, from 0 to 1,
+	// or -1 when either document has no stored page text.
+	Similarity float64
+	Suggested  bool
+}
+
+// AuditEntry is one recorded change, formatted for the history tab.
+type AuditEntry struct {
+	Action    string
+	ActorName string
+	CreatedAt string
+	Changes   []AuditChange
+}
+
+type AuditChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// RevisionSummary is one saved metadata revision, formatted for the revisions tab.
+type RevisionSummary struct {
+	Revision      int32
+	RestoredFrom  int32 // 0 unless the revision restored an earlier one
+	CreatedByName string
+	CreatedAt     string
+}
+
+// PromptVersion is a saved extraction prompt, formatted for the extraction
+// settings page. Template is only set when a single version is loaded.
+type PromptVersion struct {
+	Version       int32
+	Template      string
+	Note          string
+	RestoredFrom  int32 // 0 unless the version restored an earlier one
+	CreatedByName string
+	CreatedAt     string
+}
+
+// ExtractionSettings is the extraction settings page. FilesDir is set when
+// extraction reads its settings from files instead of the database.
+type ExtractionSettings struct {
+	FilesDir     string
+	Prompt       PromptVersion
+	Versions     []PromptVersion
+	Vocabularies []VocabularyList
+}
+
+// VocabularyList is one of the lists of names offered to the model. Name is
+// used in URLs and Label is shown.
+type VocabularyList struct {
+	Name  string
+	Label string
+	Terms []string
+}
+
+// IngestJob is an upload being processed, formatted for the upload jobs pages.
+type IngestJob struct {
+	ID            string
+	DocID         string
+	FileName      string
+	Status        string // one of the IngestState values
+	Error         string
+	Attempts      int32
+	CreatedByName string
+	CreatedAt     string
+	UpdatedAt     string
+	Stages        []IngestStage
+	ExtractedBy   string // the extractor, model and prompt version, once metadata is extracted
+	Usage         string // the tokens and cost of the extraction
+}
+
+// Ingest states apply both to a job and to each of its stages.
+const (
+	IngestStatePending = "pending"
+	IngestStateRunning = "running"
+	IngestStateFailed  = "failed"
+	IngestStateDone    = "done"
+)
+
+type IngestStage struct {
+	Label string
+	State string
+}
+
+// UploadBatch is a multi-file upload, formatted for the batch summary page.
+type UploadBatch struct {
+	ID            string
+	CreatedByName string
+	CreatedAt     string
+	Files         []UploadBatchFile
+	Created       int
+	Duplicates    int
+	Failed        int
+	Processing    int
+}
+
+// Batch outcomes shown for each file. A queued file is processing until its
+// job is done (created) or fails.
+const (
+	BatchOutcomeCreated    = "created"
+	BatchOutcomeDuplicate  = "duplicate"
+	BatchOutcomeFailed     = "failed"
+	BatchOutcomeProcessing = "processing"
+)
+
+// UploadBatchFile is one file of a batch. DocID is the created or duplicated
+// document and JobID the file's upload job, or the unfinished job it duplicates.
+type UploadBatchFile struct {
+	FileName string
+	Outcome  string
+	Message  string
+	DocID    string
+	JobID    string
+}
+
+// ManifestPreview is what a manifest import would do, for the dry-run preview.
+// NewTerms are the taxonomy names it would add, such as "Author: Jane Doe".
+type ManifestPreview struct {
+	Rows       []ManifestPreviewRow
+	Creates    int
+	Duplicates int
+	Failed     int
+	NewTerms   []string
+}
+
+// Outcomes of a manifest row in the preview.
+const (
+	ManifestOutcomeCreate    = "create"
+	ManifestOutcomeDuplicate = "duplicate"
+	ManifestOutcomeFailed    = "failed"
+)
+
+// Where a manifest row's file comes from.
+const (
+	ManifestOriginUpload = "upload"
+	ManifestOriginBucket = "bucket"
+)
+
+// ManifestPreviewRow is one manifest row, or an uploaded file the manifest does
+// not list, in which case Line is 0. Extracted names the fields Bedrock will fill.
+type ManifestPreviewRow struct {
+	Line        int
+	FileName    string
+	Origin      string
+	Outcome     string
+	Message     string
+	Title       string
+	PublishDate string
+	Authors     []string
+	Extracted   []string
+}
+
+// SpendReport is what metadata extraction cost over the last Days days, in
+// US dollars. Unpriced counts extractions by models without a configured price.
+type SpendReport struct {
+	Days    int
+	Since   string
+	Total   SpendRow
+	ByDay   []SpendRow
+	ByUser  []SpendRow
+	ByModel []SpendRow
+}
+
+// SpendRow totals the extractions of one day, user or model. AvgLatency is
+// only set for models.
+type SpendRow struct {
+	Label        string
+	Extractions  int64
+	Failed       int64
+	InputTokens  int64
+	OutputTokens int64
+	Cost         string
+	Unpriced     int64
+	AvgLatency   string
+}
+
+// DocumentReview is a document's review state for the metadata page.
+// ModelFields names the fields whose values were written by a model and not
+// since changed by a person.
+type DocumentReview struct {
+	State       string
+	Approved    bool
+	StateLabel  string
+	ApprovedBy  string
+	ApprovedAt  string
+	ModelFields []string
+}
+
+// ReviewQueue lists the documents waiting for a reviewer, oldest first. Total
+// counts every waiting document, which may be more than Items holds.
+type ReviewQueue struct {
+	Items []ReviewItem
+	Total int64
+}
+
+type ReviewItem struct {
+	ID          string
+	Title       string
+	FileName    string
+	State       string
+	StateLabel  string
+	CreatedAt   string
+	ModelFields []string
+}
+
+// PendingTerm is an author, keyword, region or category created for a name that
+// matched no existing term, awaiting an admin's approval.
+type PendingTerm struct {
+	Taxonomy  string
+	Label     string
+	ID        string
+	Name      string
+	Documents int64
+	Suggested string // an existing term spelled like Name, to map it to if they are the same
+}
+
+// TermList is a page of one taxonomy's terms, for the taxonomy admin pages.
+type TermList struct {
+	Taxonomy string
+	Label    string
+	Query    string
+	Terms    []TermUsage
+	Total    int64 // the number of terms matching Query, which may exceed len(Terms)
+}
+
+// TermUsage is a term with its aliases and the number of documents using it.
+type TermUsage struct {
+	ID        string
+	Name      string
+	Approved  bool
+	Documents int64
+	Aliases   []string
+}
+
+// TermDetail is a term with the documents that use it, for splitting it.
+type TermDetail struct {
+	Taxonomy    string
+	Label       string
+	ID          string
+	Name        string
+	Parent      string // regions only: the region this one is within
+	ISOCode     string // regions only: the ISO 3166 code of a country
+	ORCID       string // authors only
+	Affiliation string // authors only
+	Documents   []TermDocument
+}
+
+type TermDocument struct {
+	ID    string
+	Title string
+}
+
+// AuthorProfile is an author's public page: who they are and what they wrote.
+type AuthorProfile struct {
+	ID          string
+	Name        string
+	ORCID       string
+	Affiliation string
+	Aliases     []string
+	Documents   []AuthorDocument // newest first, undated last
+}
+
+type AuthorDocument struct {
+	ID          string
+	Title       string
+	PublishDate string
+	Source      string
+	PublisherID string // empty when the document has no publisher
+	Link        string
+}
+
+// PublisherProfile is a publisher's public page: the organisation and what it
+// published.
+type PublisherProfile struct {
+	ID        string
+	Name      string
+	Aliases   []string
+	Documents []PublisherDocument // newest first, undated last
+}
+
+type PublisherDocument struct {
+	ID          string
+	Title       string
+	PublishDate string
+	Authors     []string
+	Link        string
+}
+
+// AuthorORCID is a document's author with their ORCID iD, for the metadata
+// edit form.
+type AuthorORCID struct {
+	ID    string
+	Name  string
+	ORCID string
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/DSSD-Madison/gmu/pkg/awskendra"
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
 	"github.com/DSSD-Madison/gmu/pkg/db/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/language"
 )
 
 func AddImagesToResults(ctx context.Context, results awskendra.KendraResults, queries *db.Queries) error {
@@ -47,11 +48,35 @@ func AddImagesToResults(ctx context.Context, results awskendra.KendraResults, qu
 		}
 
 		kendraResult.UUID = document.ID.String()
+		kendraResult.Source = document.Source.String
+		if document.PublisherID.Valid {
+			kendraResult.PublisherID = document.PublisherID.UUID.String()
+		}
+		kendraResult.Language = language.Name(document.Language.String)
+		kendraResult.EnglishTitle = document.EnglishTitle.String
+		kendraResult.EnglishAbstract = document.EnglishAbstract.String
+		kendraResult.Translations = nil
+		for i, lang := range document.TranslationLanguages {
+			if i >= len(document.TranslationFiles) {
+				break
+			}
+			name := language.Name(lang)
+			if name == "" {
+				name = "another language"
+			}
+			kendraResult.Translations = append(kendraResult.Translations, awskendra.Translation{
+				Language: name,
+				Link:     ConvertS3URIToURL(document.TranslationFiles[i]),
+			})
+		}
 
 		var tempScanner pq.StringArray
 		err := tempScanner.Scan(document.AuthorNames.(string))
 		if err == nil {
 			kendraResult.Authors = tempScanner
+			if len(document.AuthorIds) == len(kendraResult.Authors) {
+				kendraResult.AuthorIDs = document.AuthorIds
+			}
 		}
 
 		err = tempScanner.Scan(document.CategoryNames.(string))
@@ -144,3 +169,17 @@ func ConvertS3URIToURL(s3URI string) string {
 	// Construct the HTTPS URL
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucket, encodedPath)
 }
+
+// SplitS3URI splits an "s3://bucket/key" URI into its bucket and key.
+func SplitS3URI(s3URI string) (string, string, bool) {
+	if !strings.HasPrefix(s3URI, "s3://") {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(s3URI, "s3://"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
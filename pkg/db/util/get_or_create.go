@@ -5,84 +5,187 @@ package util
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
 	"github.com/google/uuid"
 )
 
-func GetOrCreateAuthor(ctx context.Context, q *db.Queries, name string) (uuid.UUID, error) {
-	author, err := q.FindAuthorByName(ctx, name)
-	if err == nil {
-		return author.ID, nil
-	}
-	if err == sql.ErrNoRows {
-		newID := uuid.New()
-		err := q.InsertAuthor(ctx, db.InsertAuthorParams{
-			ID:   newID,
-			Name: name,
-		})
+var ErrUnknownTaxonomy = errors.New("unknown taxonomy")
+
+// TermFinder is the subset of db.Queries used to match names to authors,
+// keywords, publishers, regions and categories.
+type TermFinder interface {
+	FindAuthorByName(ctx context.Context, lower string) (db.Author, error)
+	ListAuthorTerms(ctx context.Context) ([]db.ListAuthorTermsRow, error)
+	FindKeywordByName(ctx context.Context, lower string) (db.Keyword, error)
+	ListKeywordTerms(ctx context.Context) ([]db.ListKeywordTermsRow, error)
+	FindPublisherByName(ctx context.Context, lower string) (db.Publisher, error)
+	ListPublisherTerms(ctx context.Context) ([]db.ListPublisherTermsRow, error)
+	FindRegionByName(ctx context.Context, lower string) (db.Region, error)
+	ListRegionTerms(ctx context.Context) ([]db.ListRegionTermsRow, error)
+	FindCategoryByName(ctx context.Context, lower string) (db.Category, error)
+	ListCategoryTerms(ctx context.Context) ([]db.ListCategoryTermsRow, error)
+}
+
+// TermQuerier is the subset of db.Queries used to find or create authors,
+// keywords, publishers, regions and categories. A transaction's queries satisfy it too.
+type TermQuerier interface {
+	TermFinder
+	InsertAuthor(ctx context.Context, arg db.InsertAuthorParams) error
+	InsertKeyword(ctx context.Context, arg db.InsertKeywordParams) error
+	InsertPublisher(ctx context.Context, arg db.InsertPublisherParams) error
+	InsertRegion(ctx context.Context, arg db.InsertRegionParams) error
+	InsertCategory(ctx context.Context, arg db.InsertCategoryParams) error
+}
+
+// ListTerms returns every name and alias of a taxonomy's terms.
+func ListTerms(ctx context.Context, q TermFinder, kind string) ([]taxonomy.Term, error) {
+	var terms []taxonomy.Term
+	switch kind {
+	case taxonomy.Authors:
+		rows, err := q.ListAuthorTerms(ctx)
 		if err != nil {
-			return uuid.Nil, fmt.Errorf("insert failed for author %q: %w", name, err)
+			return nil, err
+		}
+		for _, row := range rows {
+			terms = append(terms, taxonomy.Term(row))
+		}
+	case taxonomy.Keywords:
+		rows, err := q.ListKeywordTerms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			terms = append(terms, taxonomy.Term(row))
 		}
-		return newID, nil
+	case taxonomy.Publishers:
+		rows, err := q.ListPublisherTerms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			terms = append(terms, taxonomy.Term(row))
+		}
+	case taxonomy.Regions:
+		rows, err := q.ListRegionTerms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			terms = append(terms, taxonomy.Term(row))
+		}
+	case taxonomy.Categories:
+		rows, err := q.ListCategoryTerms(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			terms = append(terms, taxonomy.Term(row))
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTaxonomy, kind)
 	}
-	return uuid.Nil, fmt.Errorf("find failed for author %q: %w", name, err)
+	return terms, nil
 }
 
-func GetOrCreateKeyword(ctx context.Context, q *db.Queries, name string) (uuid.UUID, error) {
-	keyword, err := q.FindKeywordByName(ctx, name)
+// FindTerm returns the term a name refers to: the term with that name ignoring
+// case, else the term whose name or alias matches it after taxonomy.Fold. It
+// returns sql.ErrNoRows if none does. Close spellings are not matched, since
+// "Mary Jones" and "Mark Jones" are different people; the new term is offered
+// for approval with the close one as a suggestion instead.
+func FindTerm(ctx context.Context, q TermFinder, kind, name string) (uuid.UUID, error) {
+	var id uuid.UUID
+	var err error
+	switch kind {
+	case taxonomy.Authors:
+		var row db.Author
+		row, err = q.FindAuthorByName(ctx, name)
+		id = row.ID
+	case taxonomy.Keywords:
+		var row db.Keyword
+		row, err = q.FindKeywordByName(ctx, name)
+		id = row.ID
+	case taxonomy.Publishers:
+		var row db.Publisher
+		row, err = q.FindPublisherByName(ctx, name)
+		id = row.ID
+	case taxonomy.Regions:
+		var row db.Region
+		row, err = q.FindRegionByName(ctx, name)
+		id = row.ID
+	case taxonomy.Categories:
+		var row db.Category
+		row, err = q.FindCategoryByName(ctx, name)
+		id = row.ID
+	default:
+		return uuid.Nil, fmt.Errorf("%w: %q", ErrUnknownTaxonomy, kind)
+	}
 	if err == nil {
-		return keyword.ID, nil
+		return id, nil
 	}
-	if err == sql.ErrNoRows {
-		newID := uuid.New()
-		err := q.InsertKeyword(ctx, db.InsertKeywordParams{
-			ID:   newID,
-			Name: name,
-		})
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("insert failed for keyword %q: %w", name, err)
-		}
-		return newID, nil
+	if !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("find failed for %s %q: %w", kind, name, err)
 	}
-	return uuid.Nil, fmt.Errorf("find failed for keyword %q: %w", name, err)
-}
 
-func GetOrCreateRegion(ctx context.Context, q *db.Queries, name string) (uuid.UUID, error) {
-	region, err := q.FindRegionByName(ctx, name)
-	if err == nil {
-		return region.ID, nil
+	terms, err := ListTerms(ctx, q, kind)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("list failed for %s: %w", kind, err)
 	}
-	if err == sql.ErrNoRows {
-		newID := uuid.New()
-		err := q.InsertRegion(ctx, db.InsertRegionParams{
-			ID:   newID,
-			Name: name,
-		})
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("insert failed for region %q: %w", name, err)
-		}
-		return newID, nil
+	if term, ok := taxonomy.Exact(name, terms); ok {
+		return term.ID, nil
 	}
-	return uuid.Nil, fmt.Errorf("find failed for region %q: %w", name, err)
+	return uuid.Nil, sql.ErrNoRows
 }
 
-func GetOrCreateCategory(ctx context.Context, q *db.Queries, name string) (uuid.UUID, error) {
-	category, err := q.FindCategoryByName(ctx, name)
+// getOrCreate returns the term a name refers to per FindTerm, or creates an
+// unapproved term for an admin to approve or map to an existing one.
+func getOrCreate(ctx context.Context, q TermQuerier, kind, name string) (uuid.UUID, error) {
+	id, err := FindTerm(ctx, q, kind, name)
 	if err == nil {
-		return category.ID, nil
+		return id, nil
 	}
-	if err == sql.ErrNoRows {
-		newID := uuid.New()
-		err := q.InsertCategory(ctx, db.InsertCategoryParams{
-			ID:   newID,
-			Name: name,
-		})
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("insert failed for category %q: %w", name, err)
-		}
-		return newID, nil
+	if !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	newID := uuid.New()
+	switch kind {
+	case taxonomy.Authors:
+		err = q.InsertAuthor(ctx, db.InsertAuthorParams{ID: newID, Name: name})
+	case taxonomy.Keywords:
+		err = q.InsertKeyword(ctx, db.InsertKeywordParams{ID: newID, Name: name})
+	case taxonomy.Publishers:
+		err = q.InsertPublisher(ctx, db.InsertPublisherParams{ID: newID, Name: name})
+	case taxonomy.Regions:
+		err = q.InsertRegion(ctx, db.InsertRegionParams{ID: newID, Name: name})
+	case taxonomy.Categories:
+		err = q.InsertCategory(ctx, db.InsertCategoryParams{ID: newID, Name: name})
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("insert failed for %s %q: %w", kind, name, err)
 	}
-	return uuid.Nil, fmt.Errorf("find failed for category %q: %w", name, err)
+	return newID, nil
+}
+
+func GetOrCreateAuthor(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error) {
+	return getOrCreate(ctx, q, taxonomy.Authors, name)
+}
+
+func GetOrCreateKeyword(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error) {
+	return getOrCreate(ctx, q, taxonomy.Keywords, name)
+}
+
+func GetOrCreatePublisher(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error) {
+	return getOrCreate(ctx, q, taxonomy.Publishers, name)
+}
+
+func GetOrCreateRegion(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error) {
+	return getOrCreate(ctx, q, taxonomy.Regions, name)
+}
+
+func GetOrCreateCategory(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error) {
+	return getOrCreate(ctx, q, taxonomy.Categories, name)
 }
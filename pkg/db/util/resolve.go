@@ -2,22 +2,29 @@ package util
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"strings"
 
-	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
 	"github.com/google/uuid"
 )
 
+// NewTermPrefix marks a form value as a term name to find or create, rather
+// than the UUID of an existing term.
+const NewTermPrefix = "new:"
+
 // ResolverFunc defines the signature for resolving a name to a UUID.
 // It's typically a getOrCreate function like GetOrCreateAuthor, etc.
-type ResolverFunc func(ctx context.Context, q *db.Queries, name string) (uuid.UUID, error)
+type ResolverFunc func(ctx context.Context, q TermQuerier, name string) (uuid.UUID, error)
 
 // ResolveIDs processes a list of raw form values and resolves them to UUIDs.
 // - If the value starts with "new:", it creates the item via the resolver.
 // - Otherwise, it expects a valid UUID string.
-func ResolveIDs(ctx context.Context, q *db.Queries, values []string, resolver ResolverFunc) []uuid.UUID {
+// It stops at the first value that cannot be resolved. Each ID is returned
+// once, in the order first seen, since two spellings or an alias and its
+// term's name resolve to the same term.
+func ResolveIDs(ctx context.Context, q TermQuerier, values []string, resolver ResolverFunc) ([]uuid.UUID, error) {
 	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
 
 	for _, raw := range values {
 		raw = strings.TrimSpace(raw)
@@ -25,23 +32,27 @@ func ResolveIDs(ctx context.Context, q *db.Queries, values []string, resolver Re
 			continue
 		}
 
-		if strings.HasPrefix(raw, "new:") {
-			name := strings.TrimPrefix(raw, "new:")
+		if strings.HasPrefix(raw, NewTermPrefix) {
+			name := strings.TrimPrefix(raw, NewTermPrefix)
 			id, err := resolver(ctx, q, name)
 			if err != nil {
-				log.Printf("[ERROR] resolving %q: %v", name, err)
-				continue
+				return nil, fmt.Errorf("resolving %q: %w", name, err)
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
 			}
-			ids = append(ids, id)
 		} else {
 			u, err := uuid.Parse(raw)
 			if err != nil {
-				log.Printf("[WARN] Skipping invalid UUID: %s", raw)
-				continue
+				return nil, fmt.Errorf("invalid UUID %q: %w", raw, err)
+			}
+			if !seen[u] {
+				seen[u] = true
+				ids = append(ids, u)
 			}
-			ids = append(ids, u)
 		}
 	}
 
-	return ids
+	return ids, nil
 }
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/api"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+)
+
+// APIHandler serves the versioned JSON API under /api/v1.
+type APIHandler struct {
+	log      logger.Logger
+	searcher services.Searcher
+	db       *db.Queries
+}
+
+func NewAPIHandler(log logger.Logger, searcher services.Searcher, db *db.Queries) *APIHandler {
+	handlerLogger := log.With("Handler", "API")
+	return &APIHandler{
+		log:      handlerLogger,
+		searcher: searcher,
+		db:       db,
+	}
+}
+
+// Search runs a query. Every query parameter other than query and page is a facet
+// filter, e.g. ?query=water&Region=Kenya&Region=Uganda.
+func (h *APIHandler) Search(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	query := strings.TrimSpace(c.QueryParam("query"))
+	if len(query) < MinQueryLength {
+		return c.JSON(http.StatusBadRequest, APIError{Message: fmt.Sprintf("query must be at least %d characters", MinQueryLength)})
+	}
+	pageNum := parsePageNum(c.QueryParam("page"))
+
+	filters := c.QueryParams()
+	delete(filters, "query")
+	delete(filters, "page")
+
+	results, err := h.searcher.SearchDocuments(ctx, query, filters, pageNum)
+	if err != nil {
+		h.log.ErrorContext(ctx, "Search service failed", "query", query, "error", err)
+		return c.JSON(http.StatusInternalServerError, APIError{Message: "search failed"})
+	}
+	selectFilters(filters, &results)
+
+	return c.JSON(http.StatusOK, newAPISearchResponse(query, pageNum, results))
+}
+
+// Document returns a single document by UUID.
+func (h *APIHandler) Document(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, APIError{Message: "invalid document id"})
+	}
+
+	doc, err := h.db.FindDocumentByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && doc.ToDelete) {
+		return c.JSON(http.StatusNotFound, APIError{Message: "document not found"})
+	}
+	if err != nil {
+		h.log.ErrorContext(ctx, "Failed to load document", "docID", id, "error", err)
+		return c.JSON(http.StatusInternalServerError, APIError{Message: "failed to load document"})
+	}
+	translations, err := h.db.ListDocumentTranslations(ctx, id)
+	if err != nil {
+		h.log.ErrorContext(ctx, "Failed to load translations", "docID", id, "error", err)
+		return c.JSON(http.StatusInternalServerError, APIError{Message: "failed to load document"})
+	}
+
+	return c.JSON(http.StatusOK, newAPIDocument(doc, translations))
+}
+
+// Facet lists every term of one facet: authors, keywords, regions or categories.
+func (h *APIHandler) Facet(c echo.Context) error {
+	ctx := c.Request().Context()
+	facet := c.Param("facet")
+
+	terms := []APITerm{}
+	var err error
+	switch facet {
+	case "authors":
+		var rows []db.Author
+		rows, err = h.db.ListAllAuthors(ctx)
+		for _, r := range rows {
+			terms = append(terms, APITerm{ID: r.ID.String(), Name: r.Name})
+		}
+	case "keywords":
+		var rows []db.Keyword
+		rows, err = h.db.ListAllKeywords(ctx)
+		for _, r := range rows {
+			terms = append(terms, APITerm{ID: r.ID.String(), Name: r.Name})
+		}
+	case "regions":
+		var rows []db.Region
+		rows, err = h.db.ListAllRegions(ctx)
+		for _, r := range rows {
+			terms = append(terms, APITerm{ID: r.ID.String(), Name: r.Name})
+		}
+	case "categories":
+		var rows []db.Category
+		rows, err = h.db.ListAllCategories(ctx)
+		for _, r := range rows {
+			terms = append(terms, APITerm{ID: r.ID.String(), Name: r.Name})
+		}
+	default:
+		return c.JSON(http.StatusNotFound, APIError{Message: fmt.Sprintf("unknown facet %q", facet)})
+	}
+	if err != nil {
+		h.log.ErrorContext(ctx, "Failed to list facet terms", "facet", facet, "error", err)
+		return c.JSON(http.StatusInternalServerError, APIError{Message: "failed to list facet terms"})
+	}
+
+	return c.JSON(http.StatusOK, APITermList{Facet: facet, Terms: terms})
+}
+
+// OpenAPI serves the OpenAPI document describing this API.
+func (h *APIHandler) OpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, api.Spec)
+}
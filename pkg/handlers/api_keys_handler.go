@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type APIKeysHandler struct {
+	log            logger.Logger
+	apiKeys        services.APIKeyManager
+	auditor        services.Auditor
+	db             *db.Queries
+	sessionManager services.SessionManager
+}
+
+func NewAPIKeysHandler(log logger.Logger, apiKeys services.APIKeyManager, auditor services.Auditor, db *db.Queries, sessionManager services.SessionManager) *APIKeysHandler {
+	handlerLogger := log.With("Handler", "APIKeys")
+	return &APIKeysHandler{
+		log:            handlerLogger,
+		apiKeys:        apiKeys,
+		auditor:        auditor,
+		db:             db,
+		sessionManager: sessionManager,
+	}
+}
+
+func (ah *APIKeysHandler) APIKeysPage(c echo.Context) error {
+	return ah.renderPage(c, http.StatusOK, "", "")
+}
+
+// CreateKey creates a key and shows it once on the page.
+func (ah *APIKeysHandler) CreateKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return ah.renderPage(c, http.StatusBadRequest, "Name is required", "")
+	}
+
+	user, err := ah.db.GetUserByUsername(ctx, c.FormValue("username"))
+	if err != nil {
+		return ah.renderPage(c, http.StatusBadRequest, "User not found", "")
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return ah.renderPage(c, http.StatusBadRequest, "Failed to parse form", "")
+	}
+
+	rateLimit := 0
+	if raw := c.FormValue("rate_limit"); raw != "" {
+		rateLimit, err = strconv.Atoi(raw)
+		if err != nil || rateLimit <= 0 {
+			return ah.renderPage(c, http.StatusBadRequest, "Rate limit must be a positive number", "")
+		}
+	}
+
+	var expiresAt sql.NullTime
+	if raw := c.FormValue("expires_at"); raw != "" {
+		t, err := time.Parse(dateFormat, raw)
+		if err != nil {
+			return ah.renderPage(c, http.StatusBadRequest, "Invalid expiry date", "")
+		}
+		expiresAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	key, err := ah.apiKeys.CreateKey(ctx, services.CreateAPIKeyParams{
+		UserID:    user.ID,
+		Name:      name,
+		Scopes:    form["scopes"],
+		RateLimit: rateLimit,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return ah.renderPage(c, http.StatusBadRequest, err.Error(), "")
+	}
+
+	ah.audit(c, services.AuditEvent{
+		Action: services.AuditAPIKeyCreated,
+		Target: name,
+		Changes: services.Changes{
+			"owner":  {After: user.Username},
+			"scopes": {After: form["scopes"]},
+		},
+	})
+
+	return ah.renderPage(c, http.StatusOK, "", key)
+}
+
+func (ah *APIKeysHandler) RevokeKey(c echo.Context) error {
+	id, err := uuid.Parse(c.FormValue("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid key ID")
+	}
+
+	if err := ah.apiKeys.RevokeKey(c.Request().Context(), id); err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to revoke key")
+	}
+	ah.audit(c, services.AuditEvent{
+		Action: services.AuditAPIKeyRevoked,
+		Target: id.String(),
+	})
+
+	return c.Redirect(http.StatusSeeOther, "/admin/api-keys")
+}
+
+// audit records a change made by the signed-in user.
+func (ah *APIKeysHandler) audit(c echo.Context, event services.AuditEvent) {
+	actor, _ := ah.sessionManager.Actor(c)
+	ah.auditor.Record(c.Request().Context(), actor, event)
+}
+
+func (ah *APIKeysHandler) renderPage(c echo.Context, status int, errMsg string, newKey string) error {
+	csrf, _ := c.Get("csrf").(string)
+	isAuthorized := ah.sessionManager.IsAuthenticated(c)
+	isMaster := ah.sessionManager.IsMaster(c)
+	ctx := c.Request().Context()
+
+	keys, err := ah.apiKeys.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+	users, err := ah.db.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	return web.Render(c, status, components.APIKeysPage(csrf, errMsg, newKey, keys, users, services.APIKeyScopes, isAuthorized, isMaster))
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+)
+
+// The types below are the JSON bodies returned by /api/v1. Their fields must stay
+// in step with the schemas in api/openapi.json; api_types_test.go checks this.
+
+type APISearchResponse struct {
+	Query      string           `json:"query"`
+	Page       int              `json:"page"`
+	TotalPages int              `json:"total_pages"`
+	Count      int              `json:"count"`
+	Results    []APISearchHit   `json:"results"`
+	Facets     []APIFacetCounts `json:"facets"`
+}
+
+type APISearchHit struct {
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	Link        string       `json:"link"`
+	Image       string       `json:"image"`
+	Abstract    string       `json:"abstract"`
+	PublishDate string       `json:"publish_date"`
+	Source      string       `json:"source"`
+	Authors     []string     `json:"authors"`
+	Regions     []string     `json:"regions"`
+	Keywords    []string     `json:"keywords"`
+	Categories  []string     `json:"categories"`
+	Excerpts    []APIExcerpt `json:"excerpts"`
+}
+
+type APIExcerpt struct {
+	Text       string         `json:"text"`
+	Page       int            `json:"page"`
+	Highlights []APIHighlight `json:"highlights"`
+}
+
+// APIHighlight is a matched term as byte offsets into the excerpt text.
+type APIHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type APIFacetCounts struct {
+	Facet   string           `json:"facet"`
+	Name    string           `json:"name"`
+	Options []APIFacetOption `json:"options"`
+}
+
+type APIFacetOption struct {
+	Label    string `json:"label"`
+	Count    int32  `json:"count"`
+	Selected bool   `json:"selected"`
+	Parent   string `json:"parent"`
+}
+
+type APIDocument struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	FileName    string   `json:"file_name"`
+	Abstract    string   `json:"abstract"`
+	PublishDate string   `json:"publish_date"`
+	Source      string   `json:"source"`
+	Link        string   `json:"link"`
+	Image       string   `json:"image"`
+	CreatedAt   string   `json:"created_at"`
+	Authors     []string `json:"authors"`
+	Regions     []string `json:"regions"`
+	Keywords    []string `json:"keywords"`
+	Categories  []string `json:"categories"`
+	// Language is an ISO 639-1 code, and the English title and abstract are
+	// empty for documents in English.
+	Language        string           `json:"language"`
+	EnglishTitle    string           `json:"english_title"`
+	EnglishAbstract string           `json:"english_abstract"`
+	Translations    []APITranslation `json:"translations"`
+}
+
+// APITranslation is another document that translates the one returned.
+type APITranslation struct {
+	ID       string `json:"id"`
+	Language string `json:"language"`
+	Link     string `json:"link"`
+}
+
+type APITermList struct {
+	Facet string    `json:"facet"`
+	Terms []APITerm `json:"terms"`
+}
+
+type APITerm struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type APIError struct {
+	Message string `json:"message"`
+}
+
+func newAPISearchResponse(query string, pageNum int, results awskendra.KendraResults) APISearchResponse {
+	hits := make([]APISearchHit, 0, len(results.Order))
+	for _, key := range results.Order {
+		r, ok := results.Results[key]
+		if !ok {
+			continue
+		}
+		excerpts := make([]APIExcerpt, 0, len(r.Excerpts))
+		for _, e := range r.Excerpts {
+			highlights := make([]APIHighlight, 0, len(e.Highlights))
+			for _, h := range e.Highlights {
+				highlights = append(highlights, APIHighlight{Start: h.Start, End: h.End})
+			}
+			excerpts = append(excerpts, APIExcerpt{Text: e.Text, Page: e.PageNum, Highlights: highlights})
+		}
+		hits = append(hits, APISearchHit{
+			ID:          r.UUID,
+			Title:       r.Title,
+			Link:        r.Link,
+			Image:       r.Image,
+			Abstract:    r.Abstract,
+			PublishDate: r.PublishDate,
+			Source:      r.Source,
+			Authors:     nonNil(r.Authors),
+			Regions:     nonNil(r.Regions),
+			Keywords:    nonNil(r.Keywords),
+			Categories:  nonNil(r.Categories),
+			Excerpts:    excerpts,
+		})
+	}
+
+	facets := make([]APIFacetCounts, 0, len(results.Filters))
+	for _, f := range results.Filters {
+		options := make([]APIFacetOption, 0, len(f.Options))
+		for _, o := range f.Options {
+			options = append(options, APIFacetOption{Label: o.Label, Count: o.Count, Selected: o.Selected, Parent: o.Parent})
+		}
+		facets = append(facets, APIFacetCounts{Facet: f.Category, Name: f.Name, Options: options})
+	}
+
+	return APISearchResponse{
+		Query:      query,
+		Page:       pageNum,
+		TotalPages: results.PageStatus.TotalPages,
+		Count:      results.Count,
+		Results:    hits,
+		Facets:     facets,
+	}
+}
+
+func newAPIDocument(doc db.FindDocumentByIDRow, translations []db.ListDocumentTranslationsRow) APIDocument {
+	apiDoc := APIDocument{
+		ID:              doc.ID.String(),
+		Title:           doc.Title,
+		FileName:        doc.FileName,
+		Abstract:        doc.Abstract.String,
+		Source:          doc.Source.String,
+		Link:            util.ConvertS3URIToURL(doc.S3File),
+		Authors:         nonNil(doc.AuthorNames),
+		Regions:         nonNil(doc.RegionNames),
+		Keywords:        nonNil(doc.KeywordNames),
+		Categories:      nonNil(doc.CategoryNames),
+		Language:        doc.Language.String,
+		EnglishTitle:    doc.EnglishTitle.String,
+		EnglishAbstract: doc.EnglishAbstract.String,
+		Translations:    make([]APITranslation, 0, len(translations)),
+	}
+	for _, t := range translations {
+		apiDoc.Translations = append(apiDoc.Translations, APITranslation{
+			ID:       t.ID.String(),
+			Language: t.Language.String,
+			Link:     util.ConvertS3URIToURL(t.S3File),
+		})
+	}
+	if doc.PublishDate.Valid {
+		apiDoc.PublishDate = doc.PublishDate.Time.Format(dateFormat)
+	}
+	if doc.CreatedAt.Valid {
+		apiDoc.CreatedAt = doc.CreatedAt.Time.Format(dateFormat)
+	}
+	if doc.S3FilePreview.Valid {
+		apiDoc.Image = util.ConvertS3URIToURL(doc.S3FilePreview.String)
+	}
+	return apiDoc
+}
+
+// nonNil keeps empty lists as [] rather than null in the JSON.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
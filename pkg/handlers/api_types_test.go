@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/api"
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/routes"
+)
+
+type openAPISpec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) openAPISpec {
+	t.Helper()
+	var spec openAPISpec
+	require.NoError(t, json.Unmarshal(api.Spec, &spec))
+	return spec
+}
+
+func jsonFields(v any) []string {
+	typ := reflect.TypeOf(v)
+	fields := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func Test_apiTypesMatchSpec(t *testing.T) {
+	spec := loadSpec(t)
+	tests := []struct {
+		schema string
+		value  any
+	}{
+		{"SearchResponse", handlers.APISearchResponse{}},
+		{"SearchHit", handlers.APISearchHit{}},
+		{"Excerpt", handlers.APIExcerpt{}},
+		{"Highlight", handlers.APIHighlight{}},
+		{"FacetCounts", handlers.APIFacetCounts{}},
+		{"FacetOption", handlers.APIFacetOption{}},
+		{"Document", handlers.APIDocument{}},
+		{"Translation", handlers.APITranslation{}},
+		{"TermList", handlers.APITermList{}},
+		{"Term", handlers.APITerm{}},
+		{"Error", handlers.APIError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, ok := spec.Components.Schemas[tt.schema]
+			require.True(t, ok, "schema missing from openapi.json")
+
+			properties := make([]string, 0, len(schema.Properties))
+			for name := range schema.Properties {
+				properties = append(properties, name)
+			}
+			sort.Strings(properties)
+			required := append([]string(nil), schema.Required...)
+			sort.Strings(required)
+
+			assert.Equal(t, properties, jsonFields(tt.value))
+			assert.Equal(t, properties, required)
+		})
+	}
+}
+
+func Test_apiRoutesMatchSpec(t *testing.T) {
+	spec := loadSpec(t)
+	e := echo.New()
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	routes.RegisterAPIRoutes(e, &handlers.APIHandler{}, services.NewAPIKeyService(log, nil, nil))
+
+	var registered []string
+	for _, r := range e.Routes() {
+		path, ok := strings.CutPrefix(r.Path, routes.APIPrefix)
+		if !ok {
+			continue
+		}
+		segments := strings.Split(path, "/")
+		for i, s := range segments {
+			if strings.HasPrefix(s, ":") {
+				segments[i] = "{" + s[1:] + "}"
+			}
+		}
+		registered = append(registered, strings.ToLower(r.Method)+" "+strings.Join(segments, "/"))
+	}
+
+	var documented []string
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, documented, registered)
+}
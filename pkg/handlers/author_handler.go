@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type AuthorHandler struct {
+	log            logger.Logger
+	authors        services.AuthorDirectory
+	terms          services.TermManager
+	auditor        services.Auditor
+	sessionManager services.SessionManager
+}
+
+func NewAuthorHandler(log logger.Logger, authors services.AuthorDirectory, terms services.TermManager, auditor services.Auditor, sessionManager services.SessionManager) *AuthorHandler {
+	handlerLogger := log.With("Handler", "Author")
+	return &AuthorHandler{
+		log:            handlerLogger,
+		authors:        authors,
+		terms:          terms,
+		auditor:        auditor,
+		sessionManager: sessionManager,
+	}
+}
+
+// ProfilePage shows an author with their documents, newest first.
+func (ah *AuthorHandler) ProfilePage(c echo.Context) error {
+	isAuthorized := ah.sessionManager.IsAuthenticated(c)
+	isMaster := ah.sessionManager.IsMaster(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Invalid author ID")
+	}
+	profile, err := ah.authors.Profile(c.Request().Context(), id)
+	if errors.Is(err, repository.ErrTermNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return web.Render(c, http.StatusOK, components.AuthorProfilePage(profile, isAuthorized, isMaster))
+}
+
+// UpdateDetails sets the ORCID iD and affiliation in the form and re-renders
+// the form.
+func (ah *AuthorHandler) UpdateDetails(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid author ID"))
+	}
+	detail, err := ah.terms.Term(ctx, taxonomy.Authors, id)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to load author")))
+	}
+	csrf, _ := c.Get("csrf").(string)
+
+	changes, err := ah.authors.SetDetails(ctx, id, c.FormValue("orcid"), c.FormValue("affiliation"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.AuthorDetailsForm(csrf, detail, authorMessage(err, "Failed to update author")))
+	}
+
+	if len(changes) > 0 {
+		actor, _ := ah.sessionManager.Actor(c)
+		ah.auditor.Record(ctx, actor, services.AuditEvent{
+			Action:  services.AuditAuthorUpdated,
+			Target:  detail.Name,
+			Changes: changes,
+		})
+	}
+	if detail, err = ah.terms.Term(ctx, taxonomy.Authors, id); err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to load author")))
+	}
+	return web.Render(c, http.StatusOK, components.AuthorDetailsForm(csrf, detail, ""))
+}
+
+// authorMessage returns err's message when it is the user's mistake, and fallback otherwise.
+func authorMessage(err error, fallback string) string {
+	if errors.Is(err, services.ErrInvalidORCID) || errors.Is(err, services.ErrORCIDTaken) || errors.Is(err, services.ErrInvalidTerm) ||
+		errors.Is(err, repository.ErrTermNotFound) {
+		return err.Error()
+	}
+	return fallback
+}
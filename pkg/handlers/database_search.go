@@ -1,28 +1,31 @@
 package handlers
 
 import (
-	"database/sql"
 	"net/http"
 	"strconv"
 	"strings"
 
-	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
 	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
 	"github.com/DSSD-Madison/gmu/web"
 	"github.com/DSSD-Madison/gmu/web/components"
 	"github.com/labstack/echo/v4"
 )
 
+// maxTags caps the terms of one kind on a document; a document has one publisher.
+const maxTags = 10
+
 type DatabaseHandler struct {
-	log logger.Logger
-	db  *db.Queries
+	log   logger.Logger
+	terms services.TermManager
 }
 
-func NewDatabaseHandler(log logger.Logger, db *db.Queries) *DatabaseHandler {
+func NewDatabaseHandler(log logger.Logger, terms services.TermManager) *DatabaseHandler {
 	handlerLogger := log.With("handler", "Database")
 	return &DatabaseHandler{
-		log: handlerLogger,
-		db:  db,
+		log:   handlerLogger,
+		terms: terms,
 	}
 }
 
@@ -37,25 +40,29 @@ func (dh *DatabaseHandler) DatabaseFieldSearch(c echo.Context, fieldName string)
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
-	if count >= 10 {
-		return web.Render(c, 200, components.TooManySuggestions())
-	}
-	var idPrefix string
+	var idPrefix, kind string
+	limit := maxTags
 
 	switch fieldName {
 	case "region_names":
-		idPrefix = "regions"
+		idPrefix, kind = "regions", taxonomy.Regions
 	case "keyword_names":
-		idPrefix = "keywords"
+		idPrefix, kind = "keywords", taxonomy.Keywords
 	case "author_names":
-		idPrefix = "authors"
+		idPrefix, kind = "authors", taxonomy.Authors
 	case "category_names":
-		idPrefix = "categories"
+		idPrefix, kind = "categories", taxonomy.Categories
+	case "publisher_names":
+		idPrefix, kind, limit = "publishers", taxonomy.Publishers, 1
 	default:
 		dh.log.Error("DatabaseFieldSearch called with unsupported fieldName", "fieldName", fieldName)
 		return c.String(http.StatusInternalServerError, "Internal server configuration error.")
 	}
 
+	if count >= limit {
+		return web.Render(c, 200, components.TooManySuggestions(limit))
+	}
+
 	searchQuery := c.QueryParam("name")
 	searchQuery = strings.TrimSpace(searchQuery)
 
@@ -63,78 +70,30 @@ func (dh *DatabaseHandler) DatabaseFieldSearch(c echo.Context, fieldName string)
 		return web.Render(c, http.StatusOK, components.SuggestionList(idPrefix, fieldName, []components.Pair{}))
 	}
 
-	var suggestions []components.Pair
+	// Terms are matched by their names and aliases, ignoring case, accents and
+	// punctuation, and by close spellings, so an existing term is offered
+	// before the typed name is.
+	terms, err := dh.terms.Suggest(ctx, kind, searchQuery)
+	if err != nil {
+		dh.log.Error("No suggestions for field", "fieldName", fieldName, "query", searchQuery, "error", err)
+	}
 
-	if len(searchQuery) > 3 {
+	var suggestions []components.Pair
+	exact := false
+	for _, term := range terms {
+		exact = exact || taxonomy.Fold(term.Label()) == taxonomy.Fold(searchQuery)
+	}
+	if len(searchQuery) > 3 && !exact {
 		suggestions = append(suggestions, components.Pair{
 			ID:   "NON",
 			Name: searchQuery,
 		})
 	}
-	var dbErr error
-
-	dbQuery := sql.NullString{String: searchQuery, Valid: true}
-
-	switch fieldName {
-	case "region_names":
-		items, err := dh.db.SearchRegionsByNamePrefix(ctx, dbQuery)
-		if err != nil {
-			dh.log.Error("Error searching regions for '%s': %v", searchQuery, err)
-			dh.log.Error("Error searching regions", "query", searchQuery, "error", err)
-			dbErr = err
-		} else {
-			for _, item := range items {
-				suggestions = append(suggestions, components.Pair{
-					ID:   item.ID.String(),
-					Name: item.Name,
-				})
-			}
-		}
-	case "keyword_names":
-		items, err := dh.db.SearchKeywordsByNamePrefix(ctx, dbQuery)
-		if err != nil {
-			dh.log.Error("Error searching keywords", "query", searchQuery, "error", err)
-			dbErr = err
-		} else {
-			for _, item := range items {
-				suggestions = append(suggestions, components.Pair{
-					ID:   item.ID.String(),
-					Name: item.Name,
-				})
-			}
-		}
-	case "author_names":
-		items, err := dh.db.SearchAuthorsByNamePrefix(ctx, dbQuery)
-		if err != nil {
-			dh.log.Error("Error searching authors", "query", searchQuery, "error", err)
-			dbErr = err
-		} else {
-			for _, item := range items {
-				suggestions = append(suggestions, components.Pair{
-					ID:   item.ID.String(),
-					Name: item.Name,
-				})
-			}
-		}
-	case "category_names":
-		items, err := dh.db.SearchCategoriesByNamePrefix(ctx, dbQuery)
-		if err != nil {
-			dh.log.Error("Error searching categories", "query", searchQuery, "error", err)
-			dbErr = err
-		} else {
-			for _, item := range items {
-				suggestions = append(suggestions, components.Pair{
-					ID:   item.ID.String(),
-					Name: item.Name,
-				})
-			}
-		}
-	}
-
-	if dbErr != nil && len(suggestions) > 0 {
-		dh.log.Warn("Issue returning suggestions", "field", fieldName, "count", len(suggestions), "query", searchQuery, "error", dbErr)
-	} else if dbErr != nil {
-		dh.log.Error("No suggestions for field", "fieldName", fieldName, "query", searchQuery, "error", dbErr)
+	for _, term := range terms {
+		suggestions = append(suggestions, components.Pair{
+			ID:   term.ID.String(),
+			Name: term.Name,
+		})
 	}
 
 	return web.Render(c, http.StatusOK, components.SuggestionList(idPrefix, fieldName, suggestions))
@@ -155,3 +114,7 @@ func (dh *DatabaseHandler) DatabaseSearchAuthors(c echo.Context) error {
 func (dh *DatabaseHandler) DatabaseSearchCategories(c echo.Context) error {
 	return dh.DatabaseFieldSearch(c, "category_names")
 }
+
+func (dh *DatabaseHandler) DatabaseSearchPublishers(c echo.Context) error {
+	return dh.DatabaseFieldSearch(c, "publisher_names")
+}
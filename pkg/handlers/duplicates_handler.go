@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type DuplicatesHandler struct {
+	log            logger.Logger
+	duplicates     services.DuplicateFinder
+	auditor        services.Auditor
+	sessionManager services.SessionManager
+}
+
+func NewDuplicatesHandler(log logger.Logger, duplicates services.DuplicateFinder, auditor services.Auditor, sessionManager services.SessionManager) *DuplicatesHandler {
+	handlerLogger := log.With("Handler", "Duplicates")
+	return &DuplicatesHandler{
+		log:            handlerLogger,
+		duplicates:     duplicates,
+		auditor:        auditor,
+		sessionManager: sessionManager,
+	}
+}
+
+func (dh *DuplicatesHandler) DuplicatesPage(c echo.Context) error {
+	csrf, _ := c.Get("csrf").(string)
+	isAuthorized := dh.sessionManager.IsAuthenticated(c)
+	isMaster := dh.sessionManager.IsMaster(c)
+
+	groups, err := dh.duplicates.FindDuplicateGroups(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return web.Render(c, http.StatusOK, components.DuplicatesPage(groups, csrf, isAuthorized, isMaster))
+}
+
+// KeepDocument keeps one document of a group and marks the others for deletion.
+func (dh *DuplicatesHandler) KeepDocument(c echo.Context) error {
+	keepID, err := uuid.Parse(c.FormValue("keep_id"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to parse form"))
+	}
+	groupIDs := make([]uuid.UUID, 0, len(form["doc_ids"]))
+	for _, raw := range form["doc_ids"] {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	if err := dh.duplicates.KeepDocument(c.Request().Context(), keepID, groupIDs); err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+
+	for _, id := range groupIDs {
+		if id != keepID {
+			dh.auditMarked(c, id)
+		}
+	}
+
+	marked := len(groupIDs) - 1
+	return web.Render(c, http.StatusOK, components.SuccessMessage(fmt.Sprintf("Kept 1 document and marked %d for deletion", marked)))
+}
+
+// MarkDuplicate marks a single document of a group for deletion.
+func (dh *DuplicatesHandler) MarkDuplicate(c echo.Context) error {
+	docID, err := uuid.Parse(c.FormValue("doc_id"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+
+	if err := dh.duplicates.MarkForDeletion(c.Request().Context(), docID); err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	dh.auditMarked(c, docID)
+
+	return web.Render(c, http.StatusOK, components.DuplicateRowMarked(c.FormValue("title")))
+}
+
+// auditMarked records that a duplicate was marked for deletion. Candidates are
+// never already marked, so the previous value is always false.
+func (dh *DuplicatesHandler) auditMarked(c echo.Context, docID uuid.UUID) {
+	actor, _ := dh.sessionManager.Actor(c)
+	dh.auditor.Record(c.Request().Context(), actor, services.AuditEvent{
+		Action:  services.AuditDeletionToggled,
+		DocID:   docID,
+		Changes: services.Changes{"to_delete": {Before: false, After: true}},
+	})
+}
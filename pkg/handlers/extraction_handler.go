@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+// vocabularyLabels are the vocabularies shown on the settings page, in order.
+var vocabularyLabels = []struct{ name, label string }{
+	{services.VocabularyCategories, "Categories"},
+	{services.VocabularyRegions, "Regions"},
+	{services.VocabularyKeywords, "Keywords"},
+}
+
+type ExtractionHandler struct {
+	log            logger.Logger
+	settings       services.ExtractionSettingsManager
+	auditor        services.Auditor
+	sessionManager services.SessionManager
+	filesDir       string
+}
+
+// NewExtractionHandler creates the handler for the extraction settings page.
+// filesDir is the directory extraction reads from when it does not use the
+// database, and is shown on the page so admins know their changes are unused.
+func NewExtractionHandler(log logger.Logger, settings services.ExtractionSettingsManager, auditor services.Auditor, sessionManager services.SessionManager, filesDir string) *ExtractionHandler {
+	handlerLogger := log.With("Handler", "Extraction")
+	return &ExtractionHandler{
+		log:            handlerLogger,
+		settings:       settings,
+		auditor:        auditor,
+		sessionManager: sessionManager,
+		filesDir:       filesDir,
+	}
+}
+
+// SettingsPage shows the prompt, its versions and the vocabularies.
+func (eh *ExtractionHandler) SettingsPage(c echo.Context) error {
+	ctx := c.Request().Context()
+	csrf := c.Get("csrf").(string)
+	isAuthorized := eh.sessionManager.IsAuthenticated(c)
+	isMaster := eh.sessionManager.IsMaster(c)
+
+	page := db_types.ExtractionSettings{FilesDir: eh.filesDir}
+	versions, err := eh.settings.Prompts(ctx)
+	if err != nil {
+		return err
+	}
+	page.Versions = versions
+	if len(versions) > 0 {
+		if page.Prompt, err = eh.settings.Prompt(ctx, versions[0].Version); err != nil {
+			return err
+		}
+	}
+
+	vocab, err := eh.settings.Vocabulary(ctx)
+	if err != nil {
+		return err
+	}
+	terms := map[string][]string{
+		services.VocabularyCategories: vocab.Categories,
+		services.VocabularyRegions:    vocab.Regions,
+		services.VocabularyKeywords:   vocab.Keywords,
+	}
+	for _, v := range vocabularyLabels {
+		page.Vocabularies = append(page.Vocabularies, db_types.VocabularyList{Name: v.name, Label: v.label, Terms: terms[v.name]})
+	}
+
+	return web.Render(c, http.StatusOK, components.ExtractionSettingsPage(csrf, page, isAuthorized, isMaster))
+}
+
+// PromptVersion renders one saved prompt version.
+func (eh *ExtractionHandler) PromptVersion(c echo.Context) error {
+	version, err := parseRevision(c.Param("version"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid prompt version"))
+	}
+
+	prompt, err := eh.settings.Prompt(c.Request().Context(), version)
+	if errors.Is(err, services.ErrPromptNotFound) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load prompt"))
+	}
+	return web.Render(c, http.StatusOK, components.PromptTemplate(prompt))
+}
+
+// SavePrompt stores the submitted template as the newest version and reloads the page.
+func (eh *ExtractionHandler) SavePrompt(c echo.Context) error {
+	ctx := c.Request().Context()
+	actor, _ := eh.sessionManager.Actor(c)
+
+	note := c.FormValue("note")
+	version, err := eh.settings.SavePrompt(ctx, c.FormValue("template"), note, actor)
+	if errors.Is(err, services.ErrInvalidPrompt) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to save prompt"))
+	}
+
+	eh.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditPromptSaved,
+		Target:  fmt.Sprintf("prompt version %d", version),
+		Changes: services.Changes{"note": {After: note}},
+	})
+
+	c.Response().Header().Set("HX-Redirect", "/admin/extraction")
+	return c.NoContent(http.StatusOK)
+}
+
+// RestorePrompt stores an earlier version as the newest and reloads the page.
+func (eh *ExtractionHandler) RestorePrompt(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	from, err := parseRevision(c.Param("version"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid prompt version"))
+	}
+
+	actor, _ := eh.sessionManager.Actor(c)
+	version, err := eh.settings.RestorePrompt(ctx, from, actor)
+	if errors.Is(err, services.ErrPromptNotFound) || errors.Is(err, services.ErrInvalidPrompt) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to restore prompt"))
+	}
+
+	eh.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditPromptSaved,
+		Target:  fmt.Sprintf("prompt version %d", version),
+		Changes: services.Changes{"restored_from": {After: from}},
+	})
+
+	c.Response().Header().Set("HX-Redirect", "/admin/extraction")
+	return c.NoContent(http.StatusOK)
+}
+
+// AddTerm adds a name to a vocabulary and re-renders the list.
+func (eh *ExtractionHandler) AddTerm(c echo.Context) error {
+	ctx := c.Request().Context()
+	vocabulary := c.Param("vocabulary")
+
+	name, err := eh.settings.AddTerm(ctx, vocabulary, c.FormValue("name"))
+	if err != nil {
+		return eh.renderVocabulary(c, vocabulary, termMessage(err, "Failed to add name"))
+	}
+
+	actor, _ := eh.sessionManager.Actor(c)
+	eh.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditVocabularyChanged,
+		Target:  vocabulary,
+		Changes: services.Changes{name: {After: "added"}},
+	})
+	return eh.renderVocabulary(c, vocabulary, "")
+}
+
+// RemoveTerm takes a name out of a vocabulary and re-renders the list.
+func (eh *ExtractionHandler) RemoveTerm(c echo.Context) error {
+	ctx := c.Request().Context()
+	vocabulary := c.Param("vocabulary")
+	name := c.FormValue("name")
+
+	if err := eh.settings.RemoveTerm(ctx, vocabulary, name); err != nil {
+		return eh.renderVocabulary(c, vocabulary, termMessage(err, "Failed to remove name"))
+	}
+
+	actor, _ := eh.sessionManager.Actor(c)
+	eh.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditVocabularyChanged,
+		Target:  vocabulary,
+		Changes: services.Changes{name: {After: "removed"}},
+	})
+	return eh.renderVocabulary(c, vocabulary, "")
+}
+
+func (eh *ExtractionHandler) renderVocabulary(c echo.Context, vocabulary, message string) error {
+	label := ""
+	for _, v := range vocabularyLabels {
+		if v.name == vocabulary {
+			label = v.label
+		}
+	}
+	if label == "" {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Unknown vocabulary"))
+	}
+
+	vocab, err := eh.settings.Vocabulary(c.Request().Context())
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load vocabulary"))
+	}
+	list := db_types.VocabularyList{Name: vocabulary, Label: label}
+	switch vocabulary {
+	case services.VocabularyCategories:
+		list.Terms = vocab.Categories
+	case services.VocabularyRegions:
+		list.Terms = vocab.Regions
+	case services.VocabularyKeywords:
+		list.Terms = vocab.Keywords
+	}
+
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.VocabularyList(csrf, list, message))
+}
+
+// termMessage returns err's message when it is the user's mistake, and fallback otherwise.
+func termMessage(err error, fallback string) string {
+	if errors.Is(err, services.ErrInvalidTerm) || errors.Is(err, services.ErrTermNotFound) || errors.Is(err, services.ErrUnknownVocabulary) {
+		return err.Error()
+	}
+	return fallback
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type IngestHandler struct {
+	log            logger.Logger
+	ingester       services.Ingester
+	uploads        services.Uploader
+	sessionManager services.SessionManager
+}
+
+func NewIngestHandler(log logger.Logger, ingester services.Ingester, uploads services.Uploader, sessionManager services.SessionManager) *IngestHandler {
+	handlerLogger := log.With("Handler", "Ingest")
+	return &IngestHandler{
+		log:            handlerLogger,
+		ingester:       ingester,
+		uploads:        uploads,
+		sessionManager: sessionManager,
+	}
+}
+
+// JobsPage lists recent upload jobs.
+func (ih *IngestHandler) JobsPage(c echo.Context) error {
+	jobs, err := ih.ingester.RecentJobs(c.Request().Context())
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load uploads"))
+	}
+
+	isAuthorized := ih.sessionManager.IsAuthenticated(c)
+	isMaster := ih.sessionManager.IsMaster(c)
+	return web.Render(c, http.StatusOK, components.IngestJobsPage(jobs, isAuthorized, isMaster))
+}
+
+// JobPage shows an upload job's stages.
+func (ih *IngestHandler) JobPage(c echo.Context) error {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		return web.Render(c, http.StatusNotFound, components.ErrorMessage("Invalid upload job ID"))
+	}
+
+	job, err := ih.ingester.Job(c.Request().Context(), jobID)
+	if errors.Is(err, services.ErrIngestJobNotFound) {
+		return web.Render(c, http.StatusNotFound, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load upload job"))
+	}
+
+	csrf, _ := c.Get("csrf").(string)
+	isAuthorized := ih.sessionManager.IsAuthenticated(c)
+	isMaster := ih.sessionManager.IsMaster(c)
+	return web.Render(c, http.StatusOK, components.IngestJobPage(job, csrf, isAuthorized, isMaster))
+}
+
+// JobStatus renders the job's stages for the status page to poll.
+func (ih *IngestHandler) JobStatus(c echo.Context) error {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid upload job ID"))
+	}
+	return ih.renderStatus(c, jobID)
+}
+
+// RetryJob queues a failed job again and renders its stages.
+func (ih *IngestHandler) RetryJob(c echo.Context) error {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid upload job ID"))
+	}
+
+	err = ih.ingester.Retry(c.Request().Context(), jobID)
+	if errors.Is(err, services.ErrIngestJobNotFound) || errors.Is(err, services.ErrIngestJobNotRetryable) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to retry upload job"))
+	}
+	return ih.renderStatus(c, jobID)
+}
+
+func (ih *IngestHandler) renderStatus(c echo.Context, jobID uuid.UUID) error {
+	job, err := ih.ingester.Job(c.Request().Context(), jobID)
+	if errors.Is(err, services.ErrIngestJobNotFound) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load upload job"))
+	}
+
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.IngestJobStatus(job, csrf))
+}
+
+// BatchPage summarizes the outcome of every file in a multi-file upload.
+func (ih *IngestHandler) BatchPage(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("batchId"))
+	if err != nil {
+		return web.Render(c, http.StatusNotFound, components.ErrorMessage("Invalid upload batch ID"))
+	}
+
+	batch, err := ih.uploads.Batch(c.Request().Context(), batchID)
+	if errors.Is(err, services.ErrUploadBatchNotFound) {
+		return web.Render(c, http.StatusNotFound, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load upload batch"))
+	}
+
+	isAuthorized := ih.sessionManager.IsAuthenticated(c)
+	isMaster := ih.sessionManager.IsMaster(c)
+	return web.Render(c, http.StatusOK, components.UploadBatchPage(batch, isAuthorized, isMaster))
+}
+
+// BatchStatus renders the batch summary for the batch page to poll.
+func (ih *IngestHandler) BatchStatus(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("batchId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid upload batch ID"))
+	}
+
+	batch, err := ih.uploads.Batch(c.Request().Context(), batchID)
+	if errors.Is(err, services.ErrUploadBatchNotFound) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load upload batch"))
+	}
+	return web.Render(c, http.StatusOK, components.UploadBatchSummary(batch))
+}
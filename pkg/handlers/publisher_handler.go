@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type PublisherHandler struct {
+	log            logger.Logger
+	publishers     services.PublisherDirectory
+	sessionManager services.SessionManager
+}
+
+func NewPublisherHandler(log logger.Logger, publishers services.PublisherDirectory, sessionManager services.SessionManager) *PublisherHandler {
+	handlerLogger := log.With("Handler", "Publisher")
+	return &PublisherHandler{
+		log:            handlerLogger,
+		publishers:     publishers,
+		sessionManager: sessionManager,
+	}
+}
+
+// ProfilePage shows a publisher with its documents, newest first.
+func (ph *PublisherHandler) ProfilePage(c echo.Context) error {
+	isAuthorized := ph.sessionManager.IsAuthenticated(c)
+	isMaster := ph.sessionManager.IsMaster(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Invalid publisher ID")
+	}
+	profile, err := ph.publishers.Profile(c.Request().Context(), id)
+	if errors.Is(err, repository.ErrTermNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return web.Render(c, http.StatusOK, components.PublisherProfilePage(profile, isAuthorized, isMaster))
+}
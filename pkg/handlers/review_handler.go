@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type ReviewHandler struct {
+	log            logger.Logger
+	reviews        services.ReviewQueue
+	sessionManager services.SessionManager
+}
+
+func NewReviewHandler(log logger.Logger, reviews services.ReviewQueue, sessionManager services.SessionManager) *ReviewHandler {
+	handlerLogger := log.With("Handler", "Review")
+	return &ReviewHandler{
+		log:            handlerLogger,
+		reviews:        reviews,
+		sessionManager: sessionManager,
+	}
+}
+
+// QueuePage lists the documents whose metadata has not been approved yet.
+func (rh *ReviewHandler) QueuePage(c echo.Context) error {
+	isAuthorized := rh.sessionManager.IsAuthenticated(c)
+	isMaster := rh.sessionManager.IsMaster(c)
+
+	queue, err := rh.reviews.Queue(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return web.Render(c, http.StatusOK, components.ReviewQueuePage(queue, isAuthorized, isMaster))
+}
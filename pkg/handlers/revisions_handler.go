@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type RevisionsHandler struct {
+	log            logger.Logger
+	revisions      services.DocumentRevisions
+	auditor        services.Auditor
+	sessionManager services.SessionManager
+}
+
+func NewRevisionsHandler(log logger.Logger, revisions services.DocumentRevisions, auditor services.Auditor, sessionManager services.SessionManager) *RevisionsHandler {
+	handlerLogger := log.With("Handler", "Revisions")
+	return &RevisionsHandler{
+		log:            handlerLogger,
+		revisions:      revisions,
+		auditor:        auditor,
+		sessionManager: sessionManager,
+	}
+}
+
+// Revisions renders the revisions tab of the metadata page.
+func (rh *RevisionsHandler) Revisions(c echo.Context) error {
+	docID, err := uuid.Parse(c.Param("fileId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+
+	revisions, err := rh.revisions.ListRevisions(c.Request().Context(), docID)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load revisions"))
+	}
+
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.DocumentRevisions(docID.String(), csrf, revisions))
+}
+
+// CompareRevisions renders the differences between the "from" and "to" revisions.
+func (rh *RevisionsHandler) CompareRevisions(c echo.Context) error {
+	docID, err := uuid.Parse(c.Param("fileId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+	from, err := parseRevision(c.QueryParam("from"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid revision"))
+	}
+	to, err := parseRevision(c.QueryParam("to"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid revision"))
+	}
+
+	changes, err := rh.revisions.CompareRevisions(c.Request().Context(), docID, from, to)
+	if errors.Is(err, services.ErrRevisionNotFound) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to compare revisions"))
+	}
+
+	return web.Render(c, http.StatusOK, components.RevisionDiff(from, to, changes))
+}
+
+// RestoreRevision sets the document back to an earlier revision and reloads the page.
+func (rh *RevisionsHandler) RestoreRevision(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("fileId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+	revision, err := parseRevision(c.Param("revision"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid revision"))
+	}
+
+	actor, _ := rh.sessionManager.Actor(c)
+	restored, err := rh.revisions.RestoreRevision(ctx, docID, revision, actor)
+	if errors.Is(err, services.ErrRevisionNotFound) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to restore revision"))
+	}
+
+	// The restored revision directly follows the one it replaced
+	changes, err := rh.revisions.CompareRevisions(ctx, docID, restored-1, restored)
+	if err != nil {
+		rh.log.WarnContext(ctx, "Failed to diff restored revision for audit log", "docID", docID, "error", err)
+	}
+	event := services.AuditEvent{
+		Action:  services.AuditRevisionRestored,
+		DocID:   docID,
+		Target:  fmt.Sprintf("revision %d", revision),
+		Changes: services.Changes{},
+	}
+	for _, change := range changes {
+		event.Changes[change.Field] = services.FieldChange{Before: change.Before, After: change.After}
+	}
+	rh.auditor.Record(ctx, actor, event)
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/edit-metadata/%s", docID))
+	return c.NoContent(http.StatusOK)
+}
+
+func parseRevision(raw string) (int32, error) {
+	n, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid revision %q", raw)
+	}
+	return int32(n), nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+type SpendHandler struct {
+	log            logger.Logger
+	spend          services.SpendReporter
+	sessionManager services.SessionManager
+}
+
+func NewSpendHandler(log logger.Logger, spend services.SpendReporter, sessionManager services.SessionManager) *SpendHandler {
+	handlerLogger := log.With("Handler", "Spend")
+	return &SpendHandler{
+		log:            handlerLogger,
+		spend:          spend,
+		sessionManager: sessionManager,
+	}
+}
+
+// SpendPage shows what metadata extraction cost over the last ?days= days.
+func (sh *SpendHandler) SpendPage(c echo.Context) error {
+	isAuthorized := sh.sessionManager.IsAuthenticated(c)
+	isMaster := sh.sessionManager.IsMaster(c)
+
+	days, err := strconv.Atoi(c.QueryParam("days"))
+	if err != nil {
+		days = services.DefaultSpendDays
+	}
+
+	report, err := sh.spend.Report(c.Request().Context(), days)
+	if err != nil {
+		return err
+	}
+
+	return web.Render(c, http.StatusOK, components.SpendPage(report, isAuthorized, isMaster))
+}
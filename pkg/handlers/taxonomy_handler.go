@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+	"github.com/DSSD-Madison/gmu/web"
+	"github.com/DSSD-Madison/gmu/web/components"
+)
+
+// taxonomyOptions are the taxonomies offered by the alias form, in order.
+var taxonomyOptions = []components.Pair{
+	{ID: taxonomy.Authors, Name: "Authors"},
+	{ID: taxonomy.Keywords, Name: "Keywords"},
+	{ID: taxonomy.Regions, Name: "Regions"},
+	{ID: taxonomy.Categories, Name: "Categories"},
+	{ID: taxonomy.Publishers, Name: "Publishers"},
+}
+
+type TaxonomyHandler struct {
+	log            logger.Logger
+	terms          services.TermManager
+	auditor        services.Auditor
+	sessionManager services.SessionManager
+}
+
+func NewTaxonomyHandler(log logger.Logger, terms services.TermManager, auditor services.Auditor, sessionManager services.SessionManager) *TaxonomyHandler {
+	handlerLogger := log.With("Handler", "Taxonomy")
+	return &TaxonomyHandler{
+		log:            handlerLogger,
+		terms:          terms,
+		auditor:        auditor,
+		sessionManager: sessionManager,
+	}
+}
+
+// TermsPage lists the terms awaiting approval, links to each taxonomy's terms
+// and a form to add aliases.
+func (th *TaxonomyHandler) TermsPage(c echo.Context) error {
+	csrf := c.Get("csrf").(string)
+	isAuthorized := th.sessionManager.IsAuthenticated(c)
+	isMaster := th.sessionManager.IsMaster(c)
+
+	pending, err := th.terms.Pending(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return web.Render(c, http.StatusOK, components.TaxonomyTermsPage(csrf, pending, taxonomyOptions, isAuthorized, isMaster))
+}
+
+// Approve keeps a pending term and re-renders the list.
+func (th *TaxonomyHandler) Approve(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderPending(c, "Invalid term ID")
+	}
+	name := c.FormValue("name")
+
+	if err := th.terms.Approve(ctx, kind, id); err != nil {
+		return th.renderPending(c, taxonomyMessage(err, "Failed to approve term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermApproved,
+		Target:  kind,
+		Changes: services.Changes{name: {After: "approved"}},
+	})
+	return th.renderPending(c, "")
+}
+
+// MapTerm merges a pending term into the existing term named in the form and
+// re-renders the list.
+func (th *TaxonomyHandler) MapTerm(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderPending(c, "Invalid term ID")
+	}
+	name := c.FormValue("name")
+
+	target, err := th.terms.MapTo(ctx, kind, id, c.FormValue("target"))
+	if err != nil {
+		return th.renderPending(c, taxonomyMessage(err, "Failed to map term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermMapped,
+		Target:  kind,
+		Changes: services.Changes{name: {Before: name, After: target}},
+	})
+	return th.renderPending(c, "")
+}
+
+// AddAlias records another name for an existing term.
+func (th *TaxonomyHandler) AddAlias(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.FormValue("taxonomy")
+	alias := c.FormValue("alias")
+
+	name, err := th.terms.AddAlias(ctx, kind, c.FormValue("name"), alias)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to add alias")))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermAliasAdded,
+		Target:  kind,
+		Changes: services.Changes{alias: {After: name}},
+	})
+	return web.Render(c, http.StatusOK, components.SuccessMessage(alias+" now means "+name))
+}
+
+func (th *TaxonomyHandler) renderPending(c echo.Context, message string) error {
+	pending, err := th.terms.Pending(c.Request().Context())
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load pending terms"))
+	}
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.PendingTermList(csrf, pending, message))
+}
+
+// TaxonomyPage lists one taxonomy's terms with their document counts and forms
+// to rename, merge and delete them. HTMX requests from the filter get the
+// table alone.
+func (th *TaxonomyHandler) TaxonomyPage(c echo.Context) error {
+	kind := c.Param("taxonomy")
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return th.renderTerms(c, kind, "")
+	}
+
+	csrf := c.Get("csrf").(string)
+	isAuthorized := th.sessionManager.IsAuthenticated(c)
+	isMaster := th.sessionManager.IsMaster(c)
+
+	list, err := th.terms.List(c.Request().Context(), kind, c.QueryParam("q"))
+	if errors.Is(err, util.ErrUnknownTaxonomy) {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown taxonomy")
+	}
+	if err != nil {
+		return err
+	}
+	return web.Render(c, http.StatusOK, components.TaxonomyPage(csrf, list, isAuthorized, isMaster))
+}
+
+// Rename gives a term the new name in the form and re-renders the table.
+func (th *TaxonomyHandler) Rename(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderTerms(c, kind, "Invalid term ID")
+	}
+	name, newName := c.FormValue("name"), c.FormValue("new_name")
+
+	if err := th.terms.Rename(ctx, kind, id, newName); err != nil {
+		return th.renderTerms(c, kind, taxonomyMessage(err, "Failed to rename term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermRenamed,
+		Target:  kind,
+		Changes: services.Changes{"name": {Before: name, After: newName}},
+	})
+	return th.renderTerms(c, kind, "")
+}
+
+// Merge folds a term into the existing term named in the form and re-renders
+// the table.
+func (th *TaxonomyHandler) Merge(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderTerms(c, kind, "Invalid term ID")
+	}
+	name := c.FormValue("name")
+
+	target, err := th.terms.MapTo(ctx, kind, id, c.FormValue("target"))
+	if err != nil {
+		return th.renderTerms(c, kind, taxonomyMessage(err, "Failed to merge term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermMerged,
+		Target:  kind,
+		Changes: services.Changes{name: {Before: name, After: target}},
+	})
+	return th.renderTerms(c, kind, "")
+}
+
+// Delete removes a term no document uses and re-renders the table.
+func (th *TaxonomyHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderTerms(c, kind, "Invalid term ID")
+	}
+	name := c.FormValue("name")
+
+	if err := th.terms.Delete(ctx, kind, id); err != nil {
+		return th.renderTerms(c, kind, taxonomyMessage(err, "Failed to delete term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermDeleted,
+		Target:  kind,
+		Changes: services.Changes{name: {Before: name}},
+	})
+	return th.renderTerms(c, kind, "")
+}
+
+// TermPage shows a term's documents with a form to move some of them to
+// another term.
+func (th *TaxonomyHandler) TermPage(c echo.Context) error {
+	csrf := c.Get("csrf").(string)
+	isAuthorized := th.sessionManager.IsAuthenticated(c)
+	isMaster := th.sessionManager.IsMaster(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Invalid term ID")
+	}
+	detail, err := th.terms.Term(c.Request().Context(), c.Param("taxonomy"), id)
+	if errors.Is(err, util.ErrUnknownTaxonomy) || errors.Is(err, repository.ErrTermNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return web.Render(c, http.StatusOK, components.TermPage(csrf, detail, isAuthorized, isMaster))
+}
+
+// Split moves the selected documents to the term named in the form and
+// re-renders the term's documents.
+func (th *TaxonomyHandler) Split(c echo.Context) error {
+	ctx := c.Request().Context()
+	kind := c.Param("taxonomy")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return th.renderSplit(c, kind, id, "Invalid term ID")
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return th.renderSplit(c, kind, id, "Invalid form")
+	}
+	var docIDs []uuid.UUID
+	for _, value := range form["doc_id"] {
+		docID, err := uuid.Parse(value)
+		if err != nil {
+			return th.renderSplit(c, kind, id, "Invalid document ID")
+		}
+		docIDs = append(docIDs, docID)
+	}
+	name := c.FormValue("name")
+
+	target, err := th.terms.Split(ctx, kind, id, c.FormValue("target"), docIDs)
+	if err != nil {
+		return th.renderSplit(c, kind, id, taxonomyMessage(err, "Failed to split term"))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditTermSplit,
+		Target:  kind,
+		Changes: services.Changes{name: {Before: name, After: fmt.Sprintf("%d documents moved to %s", len(docIDs), target)}},
+	})
+	return th.renderSplit(c, kind, id, "")
+}
+
+// SetRegionParent places a region within the region named in the form and
+// re-renders the form.
+func (th *TaxonomyHandler) SetRegionParent(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid term ID"))
+	}
+	detail, err := th.terms.Term(ctx, taxonomy.Regions, id)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to load term")))
+	}
+	csrf, _ := c.Get("csrf").(string)
+
+	parent, err := th.terms.SetRegionParent(ctx, id, c.FormValue("parent"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.RegionParentForm(csrf, detail, taxonomyMessage(err, "Failed to set parent region")))
+	}
+
+	actor, _ := th.sessionManager.Actor(c)
+	th.auditor.Record(ctx, actor, services.AuditEvent{
+		Action:  services.AuditRegionParentSet,
+		Target:  taxonomy.Regions,
+		Changes: services.Changes{detail.Name: {Before: detail.Parent, After: parent}},
+	})
+	detail.Parent = parent
+	return web.Render(c, http.StatusOK, components.RegionParentForm(csrf, detail, ""))
+}
+
+func (th *TaxonomyHandler) renderTerms(c echo.Context, kind, message string) error {
+	list, err := th.terms.List(c.Request().Context(), kind, c.FormValue("q"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to load terms")))
+	}
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.TermTable(csrf, list, message))
+}
+
+func (th *TaxonomyHandler) renderSplit(c echo.Context, kind string, id uuid.UUID, message string) error {
+	detail, err := th.terms.Term(c.Request().Context(), kind, id)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(taxonomyMessage(err, "Failed to load term")))
+	}
+	csrf, _ := c.Get("csrf").(string)
+	return web.Render(c, http.StatusOK, components.TermDocumentList(csrf, detail, message))
+}
+
+// taxonomyMessage returns err's message when it is the user's mistake, and fallback otherwise.
+func taxonomyMessage(err error, fallback string) string {
+	if errors.Is(err, services.ErrNoMatchingTerm) || errors.Is(err, services.ErrInvalidTerm) || errors.Is(err, util.ErrUnknownTaxonomy) ||
+		errors.Is(err, repository.ErrTermNotFound) || errors.Is(err, repository.ErrTermExists) || errors.Is(err, repository.ErrTermInUse) {
+		return err.Error()
+	}
+	return fallback
+}
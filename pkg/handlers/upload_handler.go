@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -16,9 +17,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
-	"github.com/DSSD-Madison/gmu/pkg/awskendra"
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
 	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/language"
 	"github.com/DSSD-Madison/gmu/pkg/logger"
 	"github.com/DSSD-Madison/gmu/pkg/services"
 	"github.com/DSSD-Madison/gmu/web"
@@ -28,20 +31,26 @@ import (
 // UploadHandler TODO: Separate Metadata logic and export into services
 type UploadHandler struct {
 	log            logger.Logger
-	bedrockManager services.BedrockManager
-	fileManager    *services.FilemanagerService
+	uploads        services.Uploader
+	auditor        services.Auditor
+	documents      *repository.DocumentRepository
+	reviews        services.ReviewQueue
+	authors        services.AuthorDirectory
 	sessionManager services.SessionManager
 	db             *db.Queries
 }
 
-func NewUploadHandler(log logger.Logger, db *db.Queries, bedrockManager services.BedrockManager, fms *services.FilemanagerService, sessionManager services.SessionManager) *UploadHandler {
+func NewUploadHandler(log logger.Logger, db *db.Queries, uploads services.Uploader, auditor services.Auditor, documents *repository.DocumentRepository, reviews services.ReviewQueue, authors services.AuthorDirectory, sessionManager services.SessionManager) *UploadHandler {
 	handlerLogger := log.With("Handler", "Upload")
 	return &UploadHandler{
 		log:            handlerLogger,
-		bedrockManager: bedrockManager,
+		uploads:        uploads,
 		sessionManager: sessionManager,
 		db:             db,
-		fileManager:    fms,
+		auditor:        auditor,
+		documents:      documents,
+		reviews:        reviews,
+		authors:        authors,
 	}
 }
 
@@ -54,71 +63,156 @@ func (uh *UploadHandler) PDFUploadPage(c echo.Context) error {
 
 const dateFormat = "2006-01-02"
 
+// HandlePDFUpload accepts the files in the "pdf" field as upload jobs. A single
+// file redirects to its job's status page; several files or a ZIP archive are
+// submitted as a batch and redirect to the batch summary. Storing the files,
+// extracting their metadata and saving them happen in the background.
 func (uh *UploadHandler) HandlePDFUpload(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	fileHeader, err := c.FormFile("pdf")
+	form, err := c.MultipartForm()
 	if err != nil {
 		return uh.renderError(c, http.StatusOK, "Failed to get file: %v", err)
 	}
-	clientMime := fileHeader.Header.Get("Content-Type")
-	filename := fileHeader.Filename
-	fileID := uuid.New()
-	s3Key := filename
-	s3Path := fmt.Sprintf("s3://manually-uploaded-bep/%s", filename)
+	fileHeaders := form.File["pdf"]
+	if len(fileHeaders) == 0 {
+		return uh.renderError(c, http.StatusOK, "No file selected")
+	}
+	files, err := uh.readUploadFiles(fileHeaders)
+	if err != nil {
+		return uh.renderError(c, http.StatusOK, "%v", err)
+	}
+
+	actor, _ := uh.sessionManager.Actor(c)
+	if len(files) == 1 && !services.IsArchive(files[0].Name) {
+		return uh.renderUploadResult(c, uh.uploads.Submit(ctx, files[0], actor))
+	}
+
+	batchID, err := uh.uploads.SubmitBatch(ctx, files, actor)
+	if errors.Is(err, services.ErrUploadTooLarge) {
+		return uh.renderError(c, http.StatusOK, "%v", err)
+	}
+	if err != nil {
+		return uh.renderError(c, http.StatusOK, "Could not accept the upload")
+	}
+
+	// Redirect to the batch summary
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/upload/batches/%s", batchID))
+	return c.NoContent(http.StatusOK)
+}
+
+// ManifestPage shows the form for importing files listed in a manifest.
+func (uh *UploadHandler) ManifestPage(c echo.Context) error {
+	csrf, _ := c.Get("csrf").(string)
+	isAuthorized := uh.sessionManager.IsAuthenticated(c)
+	isMaster := uh.sessionManager.IsMaster(c)
+	return web.Render(c, http.StatusOK, components.ManifestImportPage(csrf, isAuthorized, isMaster))
+}
+
+// PreviewManifest renders what importing the posted manifest and files would
+// do, without importing anything.
+func (uh *UploadHandler) PreviewManifest(c echo.Context) error {
+	manifest, files, err := uh.readManifestForm(c)
+	if err != nil {
+		return uh.renderError(c, http.StatusOK, "%v", err)
+	}
 
-	// Check for duplicate
-	if existing, err := uh.db.FindDocumentByS3Path(ctx, s3Path); err == nil {
-		return web.Render(c, http.StatusOK, components.DuplicateUploadResponse(existing.ID.String()))
+	preview, err := uh.uploads.PreviewManifest(c.Request().Context(), manifest, files)
+	if errors.Is(err, services.ErrInvalidManifest) || errors.Is(err, services.ErrUploadTooLarge) {
+		return uh.renderError(c, http.StatusOK, "%v", err)
 	}
+	if err != nil {
+		return uh.renderError(c, http.StatusOK, "Could not preview the import")
+	}
+	return web.Render(c, http.StatusOK, components.ManifestPreview(preview))
+}
 
-	// Read file bytes
-	fileBytes, err := uh.readMultipartFile(fileHeader)
+// ImportManifest submits the files the posted manifest lists as a batch and
+// redirects to the batch summary.
+func (uh *UploadHandler) ImportManifest(c echo.Context) error {
+	manifest, files, err := uh.readManifestForm(c)
 	if err != nil {
-		return uh.renderError(c, http.StatusOK, "Error reading file: %v", err)
+		return uh.renderError(c, http.StatusOK, "%v", err)
 	}
 
-	// Upload to S3
-	if err := uh.fileManager.UploadFile(ctx, s3Key, fileBytes, clientMime); err != nil {
-		return uh.renderError(c, http.StatusOK, "Error uploading file: %v", err)
+	actor, _ := uh.sessionManager.Actor(c)
+	batchID, err := uh.uploads.SubmitManifest(c.Request().Context(), manifest, files, actor)
+	if errors.Is(err, services.ErrInvalidManifest) || errors.Is(err, services.ErrUploadTooLarge) {
+		return uh.renderError(c, http.StatusOK, "%v", err)
 	}
+	if err != nil {
+		return uh.renderError(c, http.StatusOK, "Could not import the manifest")
+	}
+
+	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/upload/batches/%s", batchID))
+	return c.NoContent(http.StatusOK)
+}
 
-	// Extract metadata
-	metadata, err := uh.bedrockManager.ExtractPDFMetadata(ctx, fileBytes)
-	if err != nil || metadata == nil {
-		uh.cleanupOnError(ctx, filename)
-		return uh.renderError(c, http.StatusOK, "Error extracting metadata: %v", err)
+// readManifestForm reads the "manifest" file and any files in the "files" field.
+func (uh *UploadHandler) readManifestForm(c echo.Context) (services.UploadFile, []services.UploadFile, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return services.UploadFile{}, nil, fmt.Errorf("failed to read the form: %w", err)
+	}
+	manifestHeaders := form.File["manifest"]
+	if len(manifestHeaders) != 1 {
+		return services.UploadFile{}, nil, errors.New("select one manifest file")
+	}
+	data, err := uh.readMultipartFile(manifestHeaders[0])
+	if err != nil {
+		return services.UploadFile{}, nil, fmt.Errorf("error reading the manifest: %w", err)
 	}
+	manifest := services.UploadFile{Name: manifestHeaders[0].Filename, Data: data}
 
-	// Parse publish date
-	publishDate := uh.parsePublishDate(ctx, metadata.PublishDate)
+	files, err := uh.readUploadFiles(form.File["files"])
+	if err != nil {
+		return services.UploadFile{}, nil, err
+	}
+	return manifest, files, nil
+}
 
-	// Insert document record
-	if err := uh.db.InsertUploadedDocument(ctx, db.InsertUploadedDocumentParams{
-		ID:          fileID,
-		S3File:      s3Path,
-		FileName:    filename,
-		Title:       metadata.Title,
-		Abstract:    sql.NullString{String: metadata.Abstract, Valid: true},
-		PublishDate: publishDate,
-	}); err != nil {
-		uh.cleanupOnError(ctx, s3Key)
-		return uh.renderError(c, 200, "Could not save file metadata to database")
+// readUploadFiles reads uploaded files, rejecting oversized uploads before
+// reading them.
+func (uh *UploadHandler) readUploadFiles(fileHeaders []*multipart.FileHeader) ([]services.UploadFile, error) {
+	var size int64
+	for _, fh := range fileHeaders {
+		size += fh.Size
+	}
+	if err := uh.uploads.Limits().Check(len(fileHeaders), size); err != nil {
+		return nil, err
 	}
 
-	// Update many-to-many joins
-	if err := uh.addAndSaveAssociations(ctx, fileID, metadata); err != nil {
-		uh.cleanupOnError(ctx, s3Key)
-		err := uh.db.DeleteDocumentByID(ctx, fileID)
+	files := make([]services.UploadFile, 0, len(fileHeaders))
+	for _, fh := range fileHeaders {
+		fileBytes, err := uh.readMultipartFile(fh)
 		if err != nil {
-			uh.log.ErrorContext(ctx, "Error deleting document from db: %v", err)
+			return nil, fmt.Errorf("error reading file %s: %w", fh.Filename, err)
 		}
-		return uh.renderError(c, http.StatusOK, "Could not save associated metadata")
+		files = append(files, services.UploadFile{
+			Name:        fh.Filename,
+			ContentType: fh.Header.Get("Content-Type"),
+			Data:        fileBytes,
+		})
 	}
+	return files, nil
+}
 
-	// Redirect to metadata editor
-	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/edit-metadata/%s", fileID))
-	return c.NoContent(http.StatusOK)
+// renderUploadResult redirects to the job's status page, or explains why the
+// file was not accepted.
+func (uh *UploadHandler) renderUploadResult(c echo.Context, result services.UploadResult) error {
+	switch {
+	case result.Outcome == services.UploadOutcomeQueued:
+		c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/upload/jobs/%s", result.JobID))
+		return c.NoContent(http.StatusOK)
+	case result.Outcome == services.UploadOutcomeDuplicate && result.ExistingName != "":
+		return web.Render(c, http.StatusOK, components.DuplicateContentResponse(result.DocID.String(), result.ExistingName))
+	case result.Outcome == services.UploadOutcomeDuplicate && result.DocID != uuid.Nil:
+		return web.Render(c, http.StatusOK, components.DuplicateUploadResponse(result.DocID.String()))
+	case result.Outcome == services.UploadOutcomeDuplicate:
+		return web.Render(c, http.StatusOK, components.PendingUploadResponse(result.JobID.String()))
+	default:
+		return uh.renderError(c, http.StatusOK, "%s", result.Message)
+	}
 }
 
 func (uh *UploadHandler) readMultipartFile(fh *multipart.FileHeader) ([]byte, error) {
@@ -134,37 +228,36 @@ func (uh *UploadHandler) readMultipartFile(fh *multipart.FileHeader) ([]byte, er
 	return io.ReadAll(file)
 }
 
-func (uh *UploadHandler) parsePublishDate(ctx context.Context, raw string) sql.NullTime {
-	if raw == "" {
-		return sql.NullTime{}
-	}
-	t, err := time.Parse(dateFormat, raw)
+// snapshot returns the document's editable fields and review state for the
+// audit log, or nil if it cannot be read.
+func (uh *UploadHandler) snapshot(ctx context.Context, docID uuid.UUID) map[string]any {
+	doc, err := uh.db.FindDocumentByID(ctx, docID)
 	if err != nil {
-		uh.log.ErrorContext(ctx, "Invalid publish date format", "error", err)
-		return sql.NullTime{}
+		uh.log.WarnContext(ctx, "Failed to read document for audit log", "docID", docID, "error", err)
+		return nil
+	}
+	snapshot := services.DocumentSnapshot(doc)
+	snapshot["review_state"] = doc.ReviewState
+	if translations, err := uh.translationPairs(ctx, docID); err == nil {
+		labels := make([]string, 0, len(translations))
+		for _, t := range translations {
+			labels = append(labels, t.Name)
+		}
+		snapshot["translations"] = labels
 	}
-	return sql.NullTime{Time: t, Valid: true}
+	return snapshot
 }
 
-func (uh *UploadHandler) addAndSaveAssociations(ctx context.Context, docID uuid.UUID, m *awskendra.ExtractedMetadata) error {
-	uh.addNewToStr(ctx, m.AuthorName)
-	uh.addNewToStr(ctx, m.CategoryName)
-	uh.addNewToStr(ctx, m.KeywordName)
-	uh.addNewToStr(ctx, m.RegionName)
-	return uh.updateManyToManyFieldsMetadata(
-		ctx,
-		uuid.NullUUID{UUID: docID, Valid: true},
-		m.AuthorName,
-		m.KeywordName,
-		m.CategoryName,
-		m.RegionName,
-	)
+// audit records a change made by the signed-in user.
+func (uh *UploadHandler) audit(c echo.Context, event services.AuditEvent) {
+	actor, _ := uh.sessionManager.Actor(c)
+	uh.auditor.Record(c.Request().Context(), actor, event)
 }
 
-func (uh *UploadHandler) cleanupOnError(ctx context.Context, key string) {
-	if err := uh.fileManager.DeleteFile(ctx, key, "manually-uploaded-bep"); err != nil {
-		uh.log.ErrorContext(ctx, "Failed to delete S3 file during cleanup", "error", err)
-	}
+// revisionInfo names the signed-in user as the author of a new revision.
+func (uh *UploadHandler) revisionInfo(c echo.Context) repository.RevisionInfo {
+	actor, _ := uh.sessionManager.Actor(c)
+	return repository.RevisionInfo{CreatedBy: actor.ID, CreatedByName: actor.Username}
 }
 
 func (uh *UploadHandler) renderError(c echo.Context, code int, format string, args ...interface{}) error {
@@ -203,6 +296,23 @@ func (uh *UploadHandler) PDFMetadataEditPage(c echo.Context) error {
 	selectedKeywords := util.ToKeywordPairs(allKeywords, keywordNames)
 	selectedRegions := util.ToRegionPairs(allRegions, regionNames)
 	selectedCategories := util.ToCategoryPairs(allCategories, categoryNames)
+	orcids := authorORCIDs(allAuthors, selectedAuthors)
+	var selectedPublisher []components.Pair
+	if doc.PublisherID.Valid {
+		selectedPublisher = []components.Pair{{ID: doc.PublisherID.UUID.String(), Name: doc.Source.String}}
+	}
+
+	translations, err := uh.translationPairs(c.Request().Context(), docUUID)
+	if err != nil {
+		uh.log.WarnContext(c.Request().Context(), "Failed to load translations", "docID", docUUID, "error", err)
+	}
+
+	// The form still works without the review state, so a failure is only logged
+	review, err := uh.reviews.Review(c.Request().Context(), doc)
+	if err != nil {
+		uh.log.WarnContext(c.Request().Context(), "Failed to load review state", "docID", docUUID, "error", err)
+	}
+	canApprove := uh.sessionManager.HasPermission(c, services.PermReviewMetadata)
 
 	csrf, ok := c.Get("csrf").(string)
 	if !ok {
@@ -218,11 +328,15 @@ func (uh *UploadHandler) PDFMetadataEditPage(c echo.Context) error {
 		doc.Title,
 		doc.Abstract.String,
 		doc.PublishDate.Time.Format("2006-01-02"),
-		doc.Source.String,
+		doc.Language.String,
+		doc.EnglishTitle.String,
+		doc.EnglishAbstract.String,
+		selectedPublisher,
 		selectedRegions,
 		selectedKeywords,
 		selectedAuthors,
 		selectedCategories,
+		translations,
 		csrf,
 		allRegions,
 		allKeywords,
@@ -232,10 +346,83 @@ func (uh *UploadHandler) PDFMetadataEditPage(c echo.Context) error {
 		isMaster,
 		s3Link,
 		doc.ToDelete,
+		review,
+		canApprove,
+		orcids,
 	))
 
 }
 
+// translationPairs returns the document's translations as tags for the edit
+// form, each named by its title and language.
+func (uh *UploadHandler) translationPairs(ctx context.Context, docID uuid.UUID) ([]components.Pair, error) {
+	rows, err := uh.db.ListDocumentTranslations(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]components.Pair, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, components.Pair{ID: row.ID.String(), Name: translationLabel(row.Title, row.Language.String)})
+	}
+	return pairs, nil
+}
+
+// translationLabel names a document by its title and, if known, its language.
+func translationLabel(title, code string) string {
+	if name := language.Name(code); name != "" {
+		return title + " (" + name + ")"
+	}
+	return title
+}
+
+// SuggestTranslations suggests documents to link to the one being edited as
+// its translations, matched by title or file name.
+func (uh *UploadHandler) SuggestTranslations(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("fileId"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+	count, err := strconv.Atoi(c.QueryParam("tagCount"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+	if count >= maxTags {
+		return web.Render(c, http.StatusOK, components.TooManySuggestions(maxTags))
+	}
+
+	query := strings.TrimSpace(c.QueryParam("name"))
+	if query == "" {
+		return web.Render(c, http.StatusOK, components.SuggestionList("translations", "translation_ids", []components.Pair{}))
+	}
+
+	rows, err := uh.db.SuggestTranslations(ctx, db.SuggestTranslationsParams{DocID: docID, Query: query})
+	if err != nil {
+		uh.log.ErrorContext(ctx, "Failed to suggest translations", "docID", docID, "query", query, "error", err)
+	}
+	suggestions := make([]components.Pair, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, components.Pair{ID: row.ID.String(), Name: translationLabel(row.Title, row.Language.String)})
+	}
+	return web.Render(c, http.StatusOK, components.SuggestionList("translations", "translation_ids", suggestions))
+}
+
+// DocumentHistory renders the history tab of the metadata page.
+func (uh *UploadHandler) DocumentHistory(c echo.Context) error {
+	docID, err := uuid.Parse(c.Param("fileId"))
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Invalid document ID"))
+	}
+
+	entries, err := uh.auditor.DocumentHistory(c.Request().Context(), docID)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Failed to load history"))
+	}
+
+	return web.Render(c, http.StatusOK, components.DocumentHistory(entries))
+}
+
 func (uh *UploadHandler) HandleMetadataSave(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -243,7 +430,16 @@ func (uh *UploadHandler) HandleMetadataSave(c echo.Context) error {
 	title := c.FormValue("title")
 	abstract := c.FormValue("abstract")
 	publishDate := c.FormValue("publish_date")
-	source := c.FormValue("source")
+	englishTitle := strings.TrimSpace(c.FormValue("english_title"))
+	englishAbstract := strings.TrimSpace(c.FormValue("english_abstract"))
+	lang := language.Code(c.FormValue("language"))
+	if lang == "" && strings.TrimSpace(c.FormValue("language")) != "" {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(fmt.Sprintf("Unknown language %q", c.FormValue("language"))))
+	}
+	approve := c.FormValue("approve") == "true"
+	if approve && !uh.sessionManager.HasPermission(c, services.PermReviewMetadata) {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("Only reviewers can approve metadata"))
+	}
 
 	form, err := c.FormParams()
 	if err != nil {
@@ -254,12 +450,50 @@ func (uh *UploadHandler) HandleMetadataSave(c echo.Context) error {
 	keywordStrs := form["keyword_names"]
 	categoryStrs := form["category_names"]
 	regionStrs := form["region_names"]
+	publisherStrs := form["publisher_names"]
+	if len(publisherStrs) > 1 {
+		return web.Render(c, http.StatusOK, components.ErrorMessage("A document can have only one publisher"))
+	}
+	var publisher string
+	if len(publisherStrs) == 1 {
+		publisher = publisherStrs[0]
+	}
 
 	docID, err := uuid.Parse(fileId)
 	if err != nil {
 		uh.log.ErrorContext(c.Request().Context(), "Invalid UUID in form", "error", err)
 		return err
 	}
+	orcids, err := readAuthorORCIDs(form)
+	if err != nil {
+		return web.Render(c, http.StatusOK, components.ErrorMessage(err.Error()))
+	}
+	// ORCID iDs are checked before anything is saved, and saved with the rest
+	var orcidUpdates []repository.AuthorORCID
+	var authorEvents []services.AuditEvent
+	for _, author := range orcids {
+		changes, err := uh.authors.CheckORCID(ctx, author.ID, author.ORCID)
+		if err != nil {
+			return web.Render(c, http.StatusOK, components.ErrorMessage(fmt.Sprintf("ORCID iD of %s: %s", author.Name, authorMessage(err, "failed to check author"))))
+		}
+		if len(changes) > 0 {
+			orcidUpdates = append(orcidUpdates, repository.AuthorORCID{AuthorID: author.ID, ORCID: author.ORCID})
+			authorEvents = append(authorEvents, services.AuditEvent{
+				Action:  services.AuditAuthorUpdated,
+				Target:  author.Name,
+				Changes: changes,
+			})
+		}
+	}
+	translationIDs := make([]uuid.UUID, 0, len(form["translation_ids"]))
+	for _, raw := range form["translation_ids"] {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return web.Render(c, http.StatusOK, components.ErrorMessage(fmt.Sprintf("Invalid translation %q", raw)))
+		}
+		translationIDs = append(translationIDs, id)
+	}
+	before := uh.snapshot(ctx, docID)
 
 	var parsedDate sql.NullTime
 	if publishDate != "" {
@@ -269,160 +503,102 @@ func (uh *UploadHandler) HandleMetadataSave(c echo.Context) error {
 		}
 	}
 
-	err = uh.db.UpdateDocumentMetadata(ctx, db.UpdateDocumentMetadataParams{
-		ID:          docID,
-		Title:       title,
-		Abstract:    sql.NullString{String: abstract, Valid: abstract != ""},
-		PublishDate: parsedDate,
-		Source:      sql.NullString{String: source, Valid: source != ""},
-		ToIndex:     sql.NullBool{Bool: true, Valid: true},
-	})
+	_, err = uh.documents.SaveMetadata(ctx, repository.MetadataUpdate{
+		DocID:           docID,
+		Title:           title,
+		Abstract:        sql.NullString{String: abstract, Valid: abstract != ""},
+		PublishDate:     parsedDate,
+		Language:        sql.NullString{String: lang, Valid: lang != ""},
+		EnglishTitle:    sql.NullString{String: englishTitle, Valid: englishTitle != ""},
+		EnglishAbstract: sql.NullString{String: englishAbstract, Valid: englishAbstract != ""},
+		Terms: repository.Terms{
+			Authors:    authorStrs,
+			Keywords:   keywordStrs,
+			Categories: categoryStrs,
+			Regions:    regionStrs,
+			Publisher:  publisher,
+		},
+		Translations: translationIDs,
+		ORCIDs:       orcidUpdates,
+		Approve:      approve,
+	}, uh.revisionInfo(c))
 	if err != nil {
-		uh.log.ErrorContext(c.Request().Context(), "Error updating document metadata", "error", err)
+		uh.log.ErrorContext(ctx, "Error updating document metadata", "docID", docID, "error", err)
 		return web.Render(c, http.StatusOK, components.ErrorMessage(fmt.Sprintf("[ERROR] Error updating document metadata: %v", err)))
 	}
-	documentID := uuid.NullUUID{UUID: docID, Valid: true}
-
-	if err := uh.db.DeleteDocAuthorsByDocID(ctx, documentID); err != nil {
-		uh.log.ErrorContext(ctx, "Failed to delete doc authors", "error", err)
-	}
-	if err := uh.db.DeleteDocKeywordsByDocID(ctx, documentID); err != nil {
-		uh.log.ErrorContext(ctx, "Failed to delete doc keywords", "error", err)
-	}
-	if err := uh.db.DeleteDocCategoriesByDocID(ctx, documentID); err != nil {
-		uh.log.ErrorContext(ctx, "Failed to delete doc categories", "error", err)
-	}
-	if err := uh.db.DeleteDocRegionsByDocID(ctx, documentID); err != nil {
-		uh.log.ErrorContext(ctx, "Failed to delete doc regions", "error", err)
-	}
-
-	authors := util.ResolveIDs(ctx, uh.db, authorStrs, util.GetOrCreateAuthor)
-	keywords := util.ResolveIDs(ctx, uh.db, keywordStrs, util.GetOrCreateKeyword)
-	categories := util.ResolveIDs(ctx, uh.db, categoryStrs, util.GetOrCreateCategory)
-	regions := util.ResolveIDs(ctx, uh.db, regionStrs, util.GetOrCreateRegion)
-
-	uh.log.DebugContext(c.Request().Context(), "Author IDs", authors)
-	uh.log.DebugContext(c.Request().Context(), "Keyword IDs", keywords)
-	uh.log.DebugContext(c.Request().Context(), "Category IDs", categories)
-	uh.log.DebugContext(c.Request().Context(), "Region IDs", regions)
 
-	for _, authorID := range authors {
-		err := uh.db.InsertDocAuthor(ctx, db.InsertDocAuthorParams{
-			ID:       uuid.New(),
-			DocID:    documentID,
-			AuthorID: uuid.NullUUID{UUID: authorID, Valid: true},
-		})
-		if err != nil {
-			uh.log.WarnContext(c.Request().Context(), "Failed to insert into doc_authors", "error", err)
-		}
+	action := services.AuditMetadataUpdated
+	if approve {
+		action = services.AuditDocumentApproved
 	}
-
-	for _, keywordID := range keywords {
-		err := uh.db.InsertDocKeyword(ctx, db.InsertDocKeywordParams{
-			ID:        uuid.New(),
-			DocID:     documentID,
-			KeywordID: uuid.NullUUID{UUID: keywordID, Valid: true},
+	if changes := services.Diff(before, uh.snapshot(ctx, docID)); len(changes) > 0 || approve {
+		uh.audit(c, services.AuditEvent{
+			Action:  action,
+			DocID:   docID,
+			Changes: changes,
 		})
-		if err != nil {
-			uh.log.WarnContext(c.Request().Context(), "Failed to insert into doc_keywords", "error", err)
-		}
 	}
 
-	for _, categoryID := range categories {
-		err := uh.db.InsertDocCategory(ctx, db.InsertDocCategoryParams{
-			ID:         uuid.New(),
-			DocID:      documentID,
-			CategoryID: uuid.NullUUID{UUID: categoryID, Valid: true},
-		})
-		if err != nil {
-			uh.log.WarnContext(c.Request().Context(), "Failed to insert into doc_categories", "error", err)
-		}
+	for _, event := range authorEvents {
+		uh.audit(c, event)
 	}
 
-	for _, regionID := range regions {
-		err := uh.db.InsertDocRegion(ctx, db.InsertDocRegionParams{
-			ID:       uuid.New(),
-			DocID:    documentID,
-			RegionID: uuid.NullUUID{UUID: regionID, Valid: true},
-		})
-		if err != nil {
-			uh.log.WarnContext(c.Request().Context(), "Failed to insert into doc_regions", "error", err)
-		}
+	if approve {
+		uh.log.InfoContext(c.Request().Context(), "Metadata approved", "docID", docID.String())
+		return web.Render(c, http.StatusOK, components.SuccessMessage(fmt.Sprintf("Metadata saved and approved for fileId '%s'", docID)))
 	}
-
 	uh.log.InfoContext(c.Request().Context(), "Metadata updated successfully for fileId", "docID", docID.String())
 	return web.Render(c, http.StatusOK, components.SuccessMessage(fmt.Sprintf("Metadata updated successfully for fileId '%s'", docID)))
 }
 
-func (uh *UploadHandler) addNewToStr(ctx context.Context, strs []string) {
-	for i, s := range strs {
-		strs[i] = "new:" + s
-	}
+// authorORCID is an ORCID iD an editor entered for one of a document's authors.
+type authorORCID struct {
+	ID    uuid.UUID
+	Name  string
+	ORCID string
 }
 
-func (uh *UploadHandler) updateManyToManyFieldsMetadata(
-	ctx context.Context,
-	documentID uuid.NullUUID,
-	authorStrs []string,
-	keywordStrs []string,
-	categoryStrs []string,
-	regionStrs []string,
-) error {
-	authors := util.ResolveIDs(ctx, uh.db, authorStrs, util.GetOrCreateAuthor)
-	keywords := util.ResolveIDs(ctx, uh.db, keywordStrs, util.GetOrCreateKeyword)
-	categories := util.ResolveIDs(ctx, uh.db, categoryStrs, util.GetOrCreateCategory)
-	regions := util.ResolveIDs(ctx, uh.db, regionStrs, util.GetOrCreateRegion)
-
-	fmt.Println(documentID)
-
-	for _, authorID := range authors {
-		err := uh.db.InsertDocAuthor(ctx, db.InsertDocAuthorParams{
-			ID:       uuid.New(),
-			DocID:    documentID,
-			AuthorID: uuid.NullUUID{UUID: authorID, Valid: true},
-		})
+// readAuthorORCIDs reads the ORCID iDs of the edit form, which lists each
+// author as orcid_author_id, orcid_author_name and orcid in that order. Two
+// authors cannot be given the same iD.
+func readAuthorORCIDs(form map[string][]string) ([]authorORCID, error) {
+	ids, names, values := form["orcid_author_id"], form["orcid_author_name"], form["orcid"]
+	if len(names) != len(ids) || len(values) != len(ids) {
+		return nil, errors.New("Invalid ORCID iDs in form")
+	}
+	owners := make(map[string]string, len(ids))
+	authors := make([]authorORCID, len(ids))
+	for i, value := range ids {
+		id, err := uuid.Parse(value)
 		if err != nil {
-			fmt.Printf("[ERROR] Failed to insert into doc_authors: %v\n", err)
-			return err
+			return nil, errors.New("Invalid author ID in form")
 		}
-	}
-
-	for _, keywordID := range keywords {
-		err := uh.db.InsertDocKeyword(ctx, db.InsertDocKeywordParams{
-			ID:        uuid.New(),
-			DocID:     documentID,
-			KeywordID: uuid.NullUUID{UUID: keywordID, Valid: true},
-		})
-		if err != nil {
-			log.Printf("[ERROR] Failed to insert into doc_keywords: %v", err)
-			return err
+		authors[i] = authorORCID{ID: id, Name: names[i], ORCID: strings.TrimSpace(values[i])}
+		if authors[i].ORCID == "" {
+			continue
 		}
-	}
-
-	for _, categoryID := range categories {
-		err := uh.db.InsertDocCategory(ctx, db.InsertDocCategoryParams{
-			ID:         uuid.New(),
-			DocID:      documentID,
-			CategoryID: uuid.NullUUID{UUID: categoryID, Valid: true},
-		})
-		if err != nil {
-			log.Printf("[ERROR] Failed to insert into doc_categories: %v", err)
-			return err
+		if authors[i].ORCID, err = services.NormalizeORCID(authors[i].ORCID); err != nil {
+			return nil, fmt.Errorf("%s: %w", names[i], err)
 		}
+		if owner, ok := owners[authors[i].ORCID]; ok {
+			return nil, fmt.Errorf("%s and %s cannot share the ORCID iD %s", owner, names[i], authors[i].ORCID)
+		}
+		owners[authors[i].ORCID] = names[i]
 	}
+	return authors, nil
+}
 
-	for _, regionID := range regions {
-		err := uh.db.InsertDocRegion(ctx, db.InsertDocRegionParams{
-			ID:       uuid.New(),
-			DocID:    documentID,
-			RegionID: uuid.NullUUID{UUID: regionID, Valid: true},
-		})
-		if err != nil {
-			log.Printf("[ERROR] Failed to insert into doc_regions: %v", err)
-			return err
-		}
+// authorORCIDs lists a document's authors with their ORCID iDs for the edit form.
+func authorORCIDs(all []db.Author, selected []components.Pair) []db_types.AuthorORCID {
+	orcids := make(map[string]string, len(all))
+	for _, author := range all {
+		orcids[author.ID.String()] = author.Orcid.String
+	}
+	authors := make([]db_types.AuthorORCID, len(selected))
+	for i, pair := range selected {
+		authors[i] = db_types.AuthorORCID{ID: pair.ID, Name: pair.Name, ORCID: orcids[pair.ID]}
 	}
-	return nil
+	return authors
 }
 
 func (uh *UploadHandler) ToggleDelete(c echo.Context) error {
@@ -441,6 +617,11 @@ func (uh *UploadHandler) ToggleDelete(c echo.Context) error {
 		return web.Render(c, 200, components.ErrorMessage(err.Error()))
 	}
 
+	doc, err := uh.db.FindDocumentByID(ctx, id)
+	if err != nil {
+		return web.Render(c, 200, components.ErrorMessage(err.Error()))
+	}
+
 	if err := uh.db.UpdateDocumentDeletionStatus(ctx, db.UpdateDocumentDeletionStatusParams{
 		ID:       id,
 		ToDelete: toDelete,
@@ -448,6 +629,14 @@ func (uh *UploadHandler) ToggleDelete(c echo.Context) error {
 		return web.Render(c, 200, components.ErrorMessage(err.Error()))
 	}
 
+	if doc.ToDelete != toDelete {
+		uh.audit(c, services.AuditEvent{
+			Action:  services.AuditDeletionToggled,
+			DocID:   id,
+			Changes: services.Changes{"to_delete": {Before: doc.ToDelete, After: toDelete}},
+		})
+	}
+
 	buttonText := "Delete"
 	if toDelete {
 		buttonText = "Undo Delete"
@@ -17,14 +17,16 @@ import (
 type UserManagementHandler struct {
 	log            logger.Logger
 	sessionManager services.SessionManager
+	auditor        services.Auditor
 	db             *db.Queries
 }
 
-func NewUserManagementHandler(log logger.Logger, db *db.Queries, sessionManager services.SessionManager) *UserManagementHandler {
+func NewUserManagementHandler(log logger.Logger, db *db.Queries, auditor services.Auditor, sessionManager services.SessionManager) *UserManagementHandler {
 	handlerLogger := log.With("handler", "UserManagement")
 	return &UserManagementHandler{
 		log:            handlerLogger,
 		sessionManager: sessionManager,
+		auditor:        auditor,
 		db:             db,
 	}
 }
@@ -34,17 +36,12 @@ func (uh *UserManagementHandler) ManageUsersPage(c echo.Context) error {
 	isAuthorized := uh.sessionManager.IsAuthenticated(c)
 	isMaster := uh.sessionManager.IsMaster(c)
 
-	if !isMaster {
-		uh.log.WarnContext(c.Request().Context(), "access denied")
-		return c.String(http.StatusForbidden, "Access denied")
-	}
-
 	users, err := uh.db.ListUsers(c.Request().Context())
 	if err != nil {
 		return err
 	}
 
-	return web.Render(c, http.StatusOK, components.ManageUsersForm(csrf, "", users, isAuthorized, isMaster))
+	return web.Render(c, http.StatusOK, components.ManageUsersForm(csrf, "", users, roleNames(), isAuthorized, isMaster))
 }
 
 func (uh *UserManagementHandler) CreateNewUser(c echo.Context) error {
@@ -52,49 +49,51 @@ func (uh *UserManagementHandler) CreateNewUser(c echo.Context) error {
 	isAuthorized := uh.sessionManager.IsAuthenticated(c)
 	isMaster := uh.sessionManager.IsMaster(c)
 
-	if !isMaster {
-		return c.String(http.StatusForbidden, "Access denied")
-	}
-
 	username := strings.TrimSpace(c.FormValue("username"))
 	password := c.FormValue("password")
 	confirm := c.FormValue("confirm_password")
 
 	users, _ := uh.db.ListUsers(c.Request().Context()) // Get users up front for reuse
 
+	role, ok := services.ParseRole(c.FormValue("role"))
+	if !ok {
+		return web.Render(c, http.StatusBadRequest, components.ManageUsersForm(csrf, "Unknown role", users, roleNames(), isAuthorized, isMaster))
+	}
+
 	if password != confirm {
-		return web.Render(c, http.StatusBadRequest, components.ManageUsersForm(csrf, "Passwords do not match", users, isAuthorized, isMaster))
+		return web.Render(c, http.StatusBadRequest, components.ManageUsersForm(csrf, "Passwords do not match", users, roleNames(), isAuthorized, isMaster))
 	}
 
 	// Optional: check if user already exists
 	_, err := uh.db.GetUserByUsername(c.Request().Context(), username)
 	if err == nil {
-		return web.Render(c, http.StatusConflict, components.ManageUsersForm(csrf, "User already exists", users, isAuthorized, isMaster))
+		return web.Render(c, http.StatusConflict, components.ManageUsersForm(csrf, "User already exists", users, roleNames(), isAuthorized, isMaster))
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return web.Render(c, http.StatusInternalServerError, components.ManageUsersForm(csrf, "Error hashing password", users, isAuthorized, isMaster))
+		return web.Render(c, http.StatusInternalServerError, components.ManageUsersForm(csrf, "Error hashing password", users, roleNames(), isAuthorized, isMaster))
 	}
 
 	err = uh.db.CreateUser(c.Request().Context(), db.CreateUserParams{
 		Username:     username,
 		PasswordHash: string(hash),
-		IsMaster:     false,
+		Role:         string(role),
 	})
 	if err != nil {
-		return web.Render(c, http.StatusInternalServerError, components.ManageUsersForm(csrf, "Failed to create user", users, isAuthorized, isMaster))
+		return web.Render(c, http.StatusInternalServerError, components.ManageUsersForm(csrf, "Failed to create user", users, roleNames(), isAuthorized, isMaster))
 	}
 
+	uh.audit(c, services.AuditEvent{
+		Action:  services.AuditUserCreated,
+		Target:  username,
+		Changes: services.Changes{"role": {After: string(role)}},
+	})
+
 	return c.Redirect(http.StatusSeeOther, "/admin/users")
 }
 
 func (uh *UserManagementHandler) DeleteUser(c echo.Context) error {
-	isMaster := uh.sessionManager.IsMaster(c)
-	if !isMaster {
-		return c.String(http.StatusForbidden, "Access denied")
-	}
-
 	username := c.FormValue("username")
 	if username == "" {
 		return c.String(http.StatusBadRequest, "Username required")
@@ -105,7 +104,7 @@ func (uh *UserManagementHandler) DeleteUser(c echo.Context) error {
 	if err != nil {
 		return c.String(http.StatusNotFound, "User not found")
 	}
-	if user.IsMaster {
+	if services.Role(user.Role) == services.RoleAdmin {
 		return c.String(http.StatusForbidden, "Cannot delete admin users")
 	}
 
@@ -114,5 +113,70 @@ func (uh *UserManagementHandler) DeleteUser(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Failed to delete user")
 	}
 
+	uh.audit(c, services.AuditEvent{
+		Action:  services.AuditUserDeleted,
+		Target:  username,
+		Changes: services.Changes{"role": {Before: user.Role}},
+	})
+
+	return c.Redirect(http.StatusSeeOther, "/admin/users")
+}
+
+// UpdateRole changes a user's role. The last admin cannot be demoted, so the
+// site always has someone who can manage users.
+func (uh *UserManagementHandler) UpdateRole(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	username := c.FormValue("username")
+	role, ok := services.ParseRole(c.FormValue("role"))
+	if username == "" || !ok {
+		return c.String(http.StatusBadRequest, "Username and a valid role are required")
+	}
+
+	user, err := uh.db.GetUserByUsername(ctx, username)
+	if err != nil {
+		return c.String(http.StatusNotFound, "User not found")
+	}
+
+	if services.Role(user.Role) == services.RoleAdmin && role != services.RoleAdmin {
+		admins, err := uh.db.CountUsersWithRole(ctx, string(services.RoleAdmin))
+		if err != nil {
+			return c.String(http.StatusInternalServerError, "Failed to update role")
+		}
+		if admins <= 1 {
+			return c.String(http.StatusConflict, "Cannot demote the last admin")
+		}
+	}
+
+	err = uh.db.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: username,
+		Role:     string(role),
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to update role")
+	}
+
+	uh.log.InfoContext(ctx, "User role updated", "username", username, "from", user.Role, "to", role)
+	if user.Role != string(role) {
+		uh.audit(c, services.AuditEvent{
+			Action:  services.AuditUserRoleChanged,
+			Target:  username,
+			Changes: services.Changes{"role": {Before: user.Role, After: string(role)}},
+		})
+	}
 	return c.Redirect(http.StatusSeeOther, "/admin/users")
 }
+
+// audit records a change made by the signed-in user.
+func (uh *UserManagementHandler) audit(c echo.Context, event services.AuditEvent) {
+	actor, _ := uh.sessionManager.Actor(c)
+	uh.auditor.Record(c.Request().Context(), actor, event)
+}
+
+func roleNames() []string {
+	names := make([]string, len(services.Roles))
+	for i, role := range services.Roles {
+		names[i] = string(role)
+	}
+	return names
+}
@@ -0,0 +1,373 @@
+// Package heuristic extracts document metadata without a model. It reads the
+// PDF info dictionary or the DOCX core properties and matches the document's
+// text against the known categories, regions and keywords. The same document
+// always gives the same metadata, so it works offline and in tests.
+package heuristic
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+const (
+	maxTitleLength    = 255
+	maxAbstractLength = 1500
+	maxCategories     = 3
+	maxListItems      = 10
+)
+
+var (
+	// pdfDatePattern matches the start of a PDF date such as "D:20200131120000Z".
+	pdfDatePattern = regexp.MustCompile(`^(?:D:)?(\d{4})(\d{2})?(\d{2})?`)
+	yearPattern    = regexp.MustCompile(`\b(19[5-9]\d|20\d{2})\b`)
+	nameSeparators = regexp.MustCompile(`\s*(?:;|,|\band\b|&)\s*`)
+	abstractTitles = []string{"abstract", "summary", "executive summary"}
+
+	// placeholderAuthors are names office software fills in, not real authors.
+	placeholderAuthors = map[string]bool{
+		"administrator":         true,
+		"admin":                 true,
+		"user":                  true,
+		"owner":                 true,
+		"microsoft office user": true,
+		"windows user":          true,
+		"author":                true,
+	}
+)
+
+// properties are the document's own metadata fields.
+type properties struct {
+	Title    string
+	Subject  string
+	Authors  []string
+	Keywords []string
+	Date     string
+}
+
+// Extractor matches documents against a vocabulary.
+type Extractor struct {
+	now func() time.Time
+}
+
+func New() *Extractor {
+	return &Extractor{now: time.Now}
+}
+
+// ProcessDocAndExtractMetadata reads the document's properties and text and
+// fills in what it can from them and vocab. It uses no prompt. It fails only if
+// no title can be found.
+func (e *Extractor) ProcessDocAndExtractMetadata(_ context.Context, docBytes []byte, _ awskendra.Prompt, vocab awskendra.Vocabulary) (*awskendra.Extraction, error) {
+	format, err := awskendra.DetectFormat(docBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var props properties
+	var text string
+	if format == "pdf" {
+		props = pdfProperties(docBytes)
+		// Scanned PDFs have no text; their properties may still be enough
+		text, _ = awskendra.ExtractDocumentText(docBytes)
+	} else {
+		props = docxProperties(docBytes)
+		text = docxText(docBytes)
+	}
+
+	metadata := awskendra.ExtractedMetadata{
+		Title:        props.Title,
+		Abstract:     props.Subject,
+		PublishDate:  props.Date,
+		AuthorName:   awskendra.ClipList(props.Authors, maxListItems),
+		KeywordName:  awskendra.ClipList(append(props.Keywords, matchTerms(text, vocab.Keywords)...), maxListItems),
+		RegionName:   awskendra.ClipList(matchTerms(text, vocab.Regions), maxListItems),
+		CategoryName: awskendra.ClipList(matchTerms(text, vocab.Categories), maxCategories),
+	}
+	if metadata.Title == "" {
+		metadata.Title = firstLine(text)
+	}
+	if metadata.Title == "" {
+		return nil, errors.New("no title found in the document properties or text")
+	}
+	if metadata.Abstract == "" {
+		metadata.Abstract = abstract(text)
+	}
+	if metadata.PublishDate == "" {
+		metadata.PublishDate = e.firstYear(text)
+	}
+	if len(metadata.CategoryName) > 0 {
+		metadata.Category = metadata.CategoryName[0]
+	}
+	return &awskendra.Extraction{Metadata: metadata}, nil
+}
+
+func pdfProperties(docBytes []byte) properties {
+	r, err := pdf.NewReader(bytes.NewReader(docBytes), int64(len(docBytes)))
+	if err != nil {
+		return properties{}
+	}
+	info := r.Trailer().Key("Info")
+	if info.IsNull() {
+		return properties{}
+	}
+	return properties{
+		Title:    clean(info.Key("Title").Text(), maxTitleLength),
+		Subject:  clean(info.Key("Subject").Text(), maxAbstractLength),
+		Authors:  splitNames(info.Key("Author").Text()),
+		Keywords: splitNames(info.Key("Keywords").Text()),
+		Date:     pdfDate(info.Key("CreationDate").Text()),
+	}
+}
+
+// pdfDate turns a PDF date into YYYY-MM-DD, defaulting the month and day.
+func pdfDate(raw string) string {
+	m := pdfDatePattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if m == nil {
+		return ""
+	}
+	date := m[1]
+	if m[2] != "" {
+		date += "-" + m[2]
+		if m[3] != "" {
+			date += "-" + m[3]
+		}
+	}
+	normalized, err := awskendra.NormalizeDate(date)
+	if err != nil {
+		return ""
+	}
+	return normalized
+}
+
+// coreProperties is docProps/core.xml. Element names are matched without
+// their dc:, cp: and dcterms: namespaces.
+type coreProperties struct {
+	Title       string `xml:"title"`
+	Subject     string `xml:"subject"`
+	Description string `xml:"description"`
+	Creator     string `xml:"creator"`
+	Keywords    string `xml:"keywords"`
+	Created     string `xml:"created"`
+}
+
+func docxProperties(docBytes []byte) properties {
+	data, err := zipEntry(docBytes, "docProps/core.xml")
+	if err != nil {
+		return properties{}
+	}
+	var core coreProperties
+	if err := xml.Unmarshal(data, &core); err != nil {
+		return properties{}
+	}
+
+	subject := core.Description
+	if subject == "" {
+		subject = core.Subject
+	}
+	var date string
+	if len(core.Created) >= len("2006-01-02") {
+		date, _ = awskendra.NormalizeDate(core.Created[:len("2006-01-02")])
+	}
+	return properties{
+		Title:    clean(core.Title, maxTitleLength),
+		Subject:  clean(subject, maxAbstractLength),
+		Authors:  splitNames(core.Creator),
+		Keywords: splitNames(core.Keywords),
+		Date:     date,
+	}
+}
+
+// docxText returns the document's paragraphs, one per line.
+func docxText(docBytes []byte) string {
+	data, err := zipEntry(docBytes, "word/document.xml")
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			inText = t.Name.Local == "t"
+		case xml.EndElement:
+			inText = false
+			if t.Name.Local == "p" {
+				b.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String()
+}
+
+func zipEntry(docBytes []byte, name string) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(docBytes), int64(len(docBytes)))
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return io.ReadAll(f)
+}
+
+// matchTerms returns the terms that appear in text as whole words, most
+// frequent first, ties kept in vocabulary order. Longer terms are matched first
+// and claim their words, so "South Sudan" is not also counted as "Sudan".
+func matchTerms(text string, terms []string) []string {
+	words := strings.Fields(normalize(text))
+	claimed := make([]bool, len(words))
+
+	type match struct {
+		term   string
+		phrase []string
+		count  int
+		index  int
+	}
+	matches := make([]match, 0, len(terms))
+	for i, term := range terms {
+		if phrase := strings.Fields(normalize(term)); len(phrase) > 0 {
+			matches = append(matches, match{term: term, phrase: phrase, index: i})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i].phrase) > len(matches[j].phrase)
+	})
+	for i := range matches {
+		matches[i].count = claimPhrase(words, claimed, matches[i].phrase)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].count != matches[j].count {
+			return matches[i].count > matches[j].count
+		}
+		return matches[i].index < matches[j].index
+	})
+
+	var names []string
+	for _, m := range matches {
+		if m.count > 0 {
+			names = append(names, m.term)
+		}
+	}
+	return names
+}
+
+// claimPhrase counts the unclaimed places where phrase appears as consecutive
+// words and claims them.
+func claimPhrase(words []string, claimed []bool, phrase []string) int {
+	count := 0
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) && !slices.Contains(claimed[i:i+len(phrase)], true) {
+			for j := i; j < i+len(phrase); j++ {
+				claimed[j] = true
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// normalize lowercases text and replaces everything but letters and digits with
+// single spaces, so that terms match across punctuation and line breaks.
+func normalize(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	})
+	return strings.Join(fields, " ")
+}
+
+// firstLine returns the first line long enough to be a title.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); len(line) >= 6 {
+			return clean(line, maxTitleLength)
+		}
+	}
+	return ""
+}
+
+// abstract returns the paragraph after an "Abstract" or "Summary" heading.
+func abstract(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		heading := strings.Trim(strings.ToLower(strings.TrimSpace(line)), ":")
+		for _, title := range abstractTitles {
+			if heading != title {
+				continue
+			}
+			var paragraph []string
+			for _, next := range lines[i+1:] {
+				next = strings.TrimSpace(next)
+				if next == "" && len(paragraph) > 0 {
+					break
+				}
+				if next != "" {
+					paragraph = append(paragraph, next)
+				}
+			}
+			return clean(strings.Join(paragraph, " "), maxAbstractLength)
+		}
+	}
+	return ""
+}
+
+// firstYear returns January 1st of the first plausible year in the text.
+func (e *Extractor) firstYear(text string) string {
+	for _, year := range yearPattern.FindAllString(text, -1) {
+		if year <= fmt.Sprint(e.now().Year()) {
+			return year + "-01-01"
+		}
+	}
+	return ""
+}
+
+func splitNames(raw string) []string {
+	var names []string
+	for _, name := range nameSeparators.Split(raw, -1) {
+		name = strings.TrimSpace(name)
+		if name == "" || placeholderAuthors[strings.ToLower(name)] {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// clean collapses whitespace and cuts s to at most max bytes on a word boundary.
+func clean(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= max {
+		return s
+	}
+	cut := strings.LastIndex(s[:max], " ")
+	if cut <= 0 {
+		cut = max
+	}
+	return s[:cut]
+}
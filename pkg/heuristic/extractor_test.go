@@ -0,0 +1,158 @@
+package heuristic
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+var testVocab = awskendra.Vocabulary{
+	Categories: []string{"report", "policy brief"},
+	Regions:    []string{"Kenya", "South Sudan", "Sudan"},
+	Keywords:   []string{"peacebuilding", "land rights"},
+}
+
+func newDocx(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func documentXML(paragraphs ...string) string {
+	body := ""
+	for _, p := range paragraphs {
+		body += `<w:p><w:r><w:t>` + p + `</w:t></w:r></w:p>`
+	}
+	return `<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body + `</w:body></w:document>`
+}
+
+func TestExtractor_Docx(t *testing.T) {
+	doc := newDocx(t, map[string]string{
+		"word/document.xml": documentXML(
+			"Land and Peace in South Sudan",
+			"Abstract",
+			"Land rights shape peacebuilding in South Sudan.",
+			"",
+			"This report covers South Sudan and Kenya in 2019.",
+		),
+		"docProps/core.xml": `<?xml version="1.0"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">
+<dc:creator>Amy Ochieng; Administrator</dc:creator>
+<cp:keywords>land</cp:keywords>
+<dcterms:created>2021-06-30T10:00:00Z</dcterms:created>
+</cp:coreProperties>`,
+	})
+
+	extraction, err := New().ProcessDocAndExtractMetadata(context.Background(), doc, awskendra.Prompt{}, testVocab)
+	require.NoError(t, err)
+
+	assert.Equal(t, awskendra.ExtractedMetadata{
+		Title:        "Land and Peace in South Sudan",
+		Abstract:     "Land rights shape peacebuilding in South Sudan.",
+		PublishDate:  "2021-06-30",
+		Category:     "report",
+		AuthorName:   []string{"Amy Ochieng"},
+		KeywordName:  []string{"land", "peacebuilding", "land rights"},
+		RegionName:   []string{"South Sudan", "Kenya"},
+		CategoryName: []string{"report"},
+	}, extraction.Metadata)
+}
+
+func TestExtractor_NoTitle(t *testing.T) {
+	doc := newDocx(t, map[string]string{"word/document.xml": documentXML("")})
+
+	_, err := New().ProcessDocAndExtractMetadata(context.Background(), doc, awskendra.Prompt{}, testVocab)
+	assert.Error(t, err)
+}
+
+func TestExtractor_UnsupportedFormat(t *testing.T) {
+	_, err := New().ProcessDocAndExtractMetadata(context.Background(), []byte("plain text"), awskendra.Prompt{}, testVocab)
+	assert.Error(t, err)
+}
+
+func Test_matchTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  []string
+	}{
+		{
+			name:  "whole words only",
+			text:  "South Sudan borders Sudan. Sudanese refugees.",
+			terms: []string{"Sudan", "South Sudan", "Kenya"},
+			want:  []string{"Sudan", "South Sudan"},
+		},
+		{
+			name:  "longer terms claim their words",
+			text:  "Juba, South Sudan",
+			terms: []string{"Sudan", "South Sudan"},
+			want:  []string{"South Sudan"},
+		},
+		{
+			name:  "most frequent first",
+			text:  "Kenya, kenya and Uganda",
+			terms: []string{"Uganda", "Kenya"},
+			want:  []string{"Kenya", "Uganda"},
+		},
+		{
+			name:  "matches across punctuation and line breaks",
+			text:  "a policy\nbrief on land-rights",
+			terms: []string{"policy brief", "land rights"},
+			want:  []string{"policy brief", "land rights"},
+		},
+		{
+			name:  "no text",
+			text:  "",
+			terms: []string{"Kenya"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchTerms(tt.text, tt.terms))
+		})
+	}
+}
+
+func Test_pdfDate(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "D:20200131120000Z", want: "2020-01-31"},
+		{raw: "D:202003", want: "2020-03-01"},
+		{raw: "2019", want: "2019-01-01"},
+		{raw: "D:20201340", want: ""},
+		{raw: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.want, pdfDate(tt.raw))
+		})
+	}
+}
+
+func TestExtractor_firstYear(t *testing.T) {
+	e := &Extractor{now: func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }}
+
+	assert.Equal(t, "2018-01-01", e.firstYear("Projected for 2030, based on 2018 data"))
+	assert.Equal(t, "", e.firstYear("no years here, just 1234"))
+}
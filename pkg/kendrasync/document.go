@@ -0,0 +1,118 @@
+package kendrasync
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/kendra/types"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/language"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+// maxAttributeLength is Kendra's limit on string attribute values and titles.
+const maxAttributeLength = 2048
+
+// buildKendraDocument converts a pending document into a BatchPutDocument entry.
+// The Kendra document ID is the document's S3 URI, matching what the index already holds.
+func buildKendraDocument(doc db.ListDocumentsToIndexRow) (types.Document, error) {
+	title := strings.TrimSpace(doc.Title)
+	if strings.EqualFold(title, "untitled") || title == "" {
+		return types.Document{}, fmt.Errorf("document has no title")
+	}
+
+	s3URI := doc.S3File
+	bucket, key, ok := db_util.SplitS3URI(s3URI)
+	if !ok {
+		return types.Document{}, fmt.Errorf("invalid S3 URI: %s", s3URI)
+	}
+	if strings.HasSuffix(key, ".temp") || strings.HasPrefix(path.Base(key), ".") {
+		return types.Document{}, fmt.Errorf("temp/system file: %s", s3URI)
+	}
+
+	ext := strings.ToLower(path.Ext(key))
+	var contentType types.ContentType
+	switch ext {
+	case ".pdf":
+		contentType = types.ContentTypePdf
+	case ".docx":
+		contentType = types.ContentTypeMsWord
+	default:
+		return types.Document{}, fmt.Errorf("unsupported file type: %s", ext)
+	}
+
+	attributes := []types.DocumentAttribute{
+		stringAttribute("_file_type", strings.ToUpper(strings.TrimPrefix(ext, "."))),
+		stringAttribute("_source_uri", db_util.ConvertS3URIToURL(s3URI)),
+	}
+	for _, list := range []struct {
+		key    string
+		values []string
+	}{
+		{"Region", doc.RegionNames},
+		{"Keyword", doc.KeywordNames},
+		{"Author", doc.AuthorNames},
+		{"Category", doc.CategoryNames},
+	} {
+		if values := normalizeList(list.values); len(values) > 0 {
+			attributes = append(attributes, types.DocumentAttribute{
+				Key:   &list.key,
+				Value: &types.DocumentAttributeValue{StringListValue: values},
+			})
+		}
+	}
+	if source := strings.TrimSpace(doc.Source.String); source != "" {
+		attributes = append(attributes, stringAttribute("Source", truncate(source)))
+	}
+	if name := language.Name(doc.Language.String); name != "" {
+		attributes = append(attributes, stringAttribute("Language", name))
+	}
+
+	title = truncate(title)
+	return types.Document{
+		Id:          &s3URI,
+		Title:       &title,
+		S3Path:      &types.S3Path{Bucket: &bucket, Key: &key},
+		ContentType: contentType,
+		Attributes:  attributes,
+	}, nil
+}
+
+func stringAttribute(key string, value string) types.DocumentAttribute {
+	return types.DocumentAttribute{
+		Key:   &key,
+		Value: &types.DocumentAttributeValue{StringValue: &value},
+	}
+}
+
+// normalizeList title-cases, de-duplicates and sorts attribute values so facets
+// group consistently regardless of how names were entered.
+func normalizeList(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		v = taxonomy.TitleCase(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		normalized = append(normalized, v)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) > maxAttributeLength {
+		return string(runes[:maxAttributeLength])
+	}
+	return s
+}
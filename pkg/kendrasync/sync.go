@@ -0,0 +1,384 @@
+package kendrasync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kendra"
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const (
+	// batchSize is the maximum number of documents Kendra accepts per batch call.
+	batchSize = 10
+
+	workerCount = 2
+	bufferSize  = 5
+)
+
+const (
+	OperationIndex  = "index"
+	OperationDelete = "delete"
+
+	StatusIndexed = "indexed"
+	StatusDeleted = "deleted"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// KendraAPI is the subset of the Kendra SDK client used by the Syncer.
+type KendraAPI interface {
+	BatchPutDocument(ctx context.Context, params *kendra.BatchPutDocumentInput, optFns ...func(*kendra.Options)) (*kendra.BatchPutDocumentOutput, error)
+	BatchDeleteDocument(ctx context.Context, params *kendra.BatchDeleteDocumentInput, optFns ...func(*kendra.Options)) (*kendra.BatchDeleteDocumentOutput, error)
+}
+
+// FileDeleter removes objects from S3. It is satisfied by *awskendra.S3Client.
+type FileDeleter interface {
+	Delete(ctx context.Context, bucket string, key string) error
+}
+
+// Store is the subset of db.Queries used by the Syncer.
+type Store interface {
+	ListDocumentsToIndex(ctx context.Context) ([]db.ListDocumentsToIndexRow, error)
+	ListDocumentsToDelete(ctx context.Context) ([]db.ListDocumentsToDeleteRow, error)
+	MarkDocumentIndexed(ctx context.Context, id uuid.UUID) error
+	MarkDocumentSkipped(ctx context.Context, id uuid.UUID) error
+	DeleteDocumentByID(ctx context.Context, id uuid.UUID) error
+	UpsertDocumentSyncStatus(ctx context.Context, arg db.UpsertDocumentSyncStatusParams) error
+	DeleteOrphanedTerms(ctx context.Context) (int64, error)
+}
+
+// Report summarizes one sync pass.
+type Report struct {
+	Indexed int
+	Deleted int
+	Skipped int
+	Failed  int
+}
+
+func (r *Report) add(other Report) {
+	r.Indexed += other.Indexed
+	r.Deleted += other.Deleted
+	r.Skipped += other.Skipped
+	r.Failed += other.Failed
+}
+
+type batch struct {
+	operation string
+	toIndex   []db.ListDocumentsToIndexRow
+	toDelete  []db.ListDocumentsToDeleteRow
+}
+
+// Syncer pushes pending documents to Kendra and removes documents marked for deletion
+// from Kendra, S3 and the database. Batches are processed on a generic queue.
+type Syncer struct {
+	log     logger.Logger
+	store   Store
+	kendra  KendraAPI
+	files   FileDeleter
+	indexID string
+	roleArn string
+	queue   awskendra.Queue[batch, Report]
+}
+
+func NewSyncer(log logger.Logger, store Store, kendraAPI KendraAPI, files FileDeleter, indexID string, roleArn string) *Syncer {
+	syncLogger := log.With("component", "KendraSync")
+	s := &Syncer{
+		log:     syncLogger,
+		store:   store,
+		kendra:  kendraAPI,
+		files:   files,
+		indexID: indexID,
+		roleArn: roleArn,
+	}
+	s.queue = awskendra.NewGenericQueue(workerCount, bufferSize, syncLogger, s.processBatch)
+	return s
+}
+
+// NewKendraAPI creates the Kendra SDK client used for syncing.
+func NewKendraAPI(config awskendra.Config) KendraAPI {
+	return kendra.New(kendra.Options{
+		Credentials:      config.Credentials,
+		Region:           config.Region,
+		RetryMaxAttempts: config.RetryMaxAttempts,
+	})
+}
+
+// Run syncs every interval until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) {
+	s.log.Info("Kendra sync started", "interval", interval.String())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SyncOnce(ctx); err != nil {
+			s.log.ErrorContext(ctx, "Kendra sync pass failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("Kendra sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncOnce indexes every pending document and deletes every marked document.
+func (s *Syncer) SyncOnce(ctx context.Context) (Report, error) {
+	var report Report
+
+	toIndex, err := s.store.ListDocumentsToIndex(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list documents to index: %w", err)
+	}
+	toDelete, err := s.store.ListDocumentsToDelete(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to list documents to delete: %w", err)
+	}
+	if len(toIndex) == 0 && len(toDelete) == 0 {
+		s.log.DebugContext(ctx, "Nothing to sync")
+		return report, nil
+	}
+	s.log.InfoContext(ctx, "Starting Kendra sync", "to_index", len(toIndex), "to_delete", len(toDelete))
+
+	var batches []batch
+	for start := 0; start < len(toIndex); start += batchSize {
+		end := min(start+batchSize, len(toIndex))
+		batches = append(batches, batch{operation: OperationIndex, toIndex: toIndex[start:end]})
+	}
+	for start := 0; start < len(toDelete); start += batchSize {
+		end := min(start+batchSize, len(toDelete))
+		batches = append(batches, batch{operation: OperationDelete, toDelete: toDelete[start:end]})
+	}
+
+	resultChans := make([]chan Report, 0, len(batches))
+	for _, b := range batches {
+		resultChan := make(chan Report, 1)
+		if !s.queue.Enqueue(awskendra.NewJob[batch, Report](ctx, b, resultChan)) {
+			// The batches already queued still run, so their work is counted.
+			// A worker closes each result channel once its batch is done.
+			for _, queued := range resultChans {
+				for result := range queued {
+					report.add(result)
+				}
+			}
+			return report, fmt.Errorf("failed to enqueue sync batch")
+		}
+		resultChans = append(resultChans, resultChan)
+	}
+
+	for _, resultChan := range resultChans {
+		select {
+		case result := <-resultChan:
+			report.add(result)
+		case <-ctx.Done():
+			return report, ctx.Err()
+		}
+	}
+
+	if report.Deleted > 0 {
+		s.deleteOrphanedTerms(ctx)
+	}
+
+	s.log.InfoContext(ctx, "Kendra sync complete",
+		"indexed", report.Indexed,
+		"deleted", report.Deleted,
+		"skipped", report.Skipped,
+		"failed", report.Failed,
+	)
+	return report, nil
+}
+
+// Shutdown stops the sync workers.
+func (s *Syncer) Shutdown(ctx context.Context) error {
+	return s.queue.Shutdown(ctx)
+}
+
+// deleteOrphanedTerms removes the authors, keywords, regions and categories
+// that only deleted documents referenced. Terms an admin curated (aliases,
+// the controlled vocabulary, the region hierarchy and author profiles) are
+// kept even when no document uses them.
+func (s *Syncer) deleteOrphanedTerms(ctx context.Context) {
+	deleted, err := s.store.DeleteOrphanedTerms(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to delete orphaned terms", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.log.InfoContext(ctx, "Deleted orphaned terms", "terms", deleted)
+	}
+}
+
+func (s *Syncer) processBatch(ctx context.Context, b batch) Report {
+	if b.operation == OperationDelete {
+		return s.deleteBatch(ctx, b.toDelete)
+	}
+	return s.indexBatch(ctx, b.toIndex)
+}
+
+func (s *Syncer) indexBatch(ctx context.Context, docs []db.ListDocumentsToIndexRow) Report {
+	var report Report
+
+	input := &kendra.BatchPutDocumentInput{
+		IndexId: &s.indexID,
+		RoleArn: &s.roleArn,
+	}
+	valid := make([]db.ListDocumentsToIndexRow, 0, len(docs))
+	for _, doc := range docs {
+		kendraDoc, err := buildKendraDocument(doc)
+		if err != nil {
+			s.log.WarnContext(ctx, "Skipping document", "docID", doc.ID, "s3File", doc.S3File, "reason", err)
+			s.recordStatus(ctx, doc.ID, OperationIndex, StatusSkipped, err.Error())
+			// A skipped document cannot be indexed until it is edited, which
+			// queues it again.
+			if err := s.store.MarkDocumentSkipped(ctx, doc.ID); err != nil {
+				s.log.ErrorContext(ctx, "Failed to clear to_index", "docID", doc.ID, "error", err)
+			}
+			report.Skipped++
+			continue
+		}
+		input.Documents = append(input.Documents, kendraDoc)
+		valid = append(valid, doc)
+	}
+	if len(valid) == 0 {
+		return report
+	}
+
+	out, err := s.kendra.BatchPutDocument(ctx, input)
+	if err != nil {
+		s.log.ErrorContext(ctx, "BatchPutDocument failed", "documents", len(valid), "error", err)
+		for _, doc := range valid {
+			s.recordStatus(ctx, doc.ID, OperationIndex, StatusFailed, err.Error())
+		}
+		report.Failed += len(valid)
+		return report
+	}
+
+	failed := make(map[string]string, len(out.FailedDocuments))
+	for _, f := range out.FailedDocuments {
+		if f.Id != nil {
+			failed[*f.Id] = derefString(f.ErrorMessage)
+		}
+	}
+
+	for _, doc := range valid {
+		if msg, ok := failed[doc.S3File]; ok {
+			s.log.ErrorContext(ctx, "Failed to index document", "docID", doc.ID, "s3File", doc.S3File, "error", msg)
+			s.recordStatus(ctx, doc.ID, OperationIndex, StatusFailed, msg)
+			report.Failed++
+			continue
+		}
+		if err := s.store.MarkDocumentIndexed(ctx, doc.ID); err != nil {
+			s.log.ErrorContext(ctx, "Failed to clear to_index", "docID", doc.ID, "error", err)
+			s.recordStatus(ctx, doc.ID, OperationIndex, StatusFailed, err.Error())
+			report.Failed++
+			continue
+		}
+		s.recordStatus(ctx, doc.ID, OperationIndex, StatusIndexed, "")
+		report.Indexed++
+	}
+
+	return report
+}
+
+func (s *Syncer) deleteBatch(ctx context.Context, docs []db.ListDocumentsToDeleteRow) Report {
+	var report Report
+
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.S3File
+	}
+
+	out, err := s.kendra.BatchDeleteDocument(ctx, &kendra.BatchDeleteDocumentInput{
+		IndexId:        &s.indexID,
+		DocumentIdList: ids,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "BatchDeleteDocument failed", "documents", len(docs), "error", err)
+		for _, doc := range docs {
+			s.recordStatus(ctx, doc.ID, OperationDelete, StatusFailed, err.Error())
+		}
+		report.Failed += len(docs)
+		return report
+	}
+
+	failed := make(map[string]string, len(out.FailedDocuments))
+	for _, f := range out.FailedDocuments {
+		if f.Id != nil {
+			failed[*f.Id] = derefString(f.ErrorMessage)
+		}
+	}
+
+	for _, doc := range docs {
+		if msg, ok := failed[doc.S3File]; ok {
+			s.log.ErrorContext(ctx, "Failed to delete document from Kendra", "docID", doc.ID, "s3File", doc.S3File, "error", msg)
+			s.recordStatus(ctx, doc.ID, OperationDelete, StatusFailed, msg)
+			report.Failed++
+			continue
+		}
+
+		if err := s.deleteFiles(ctx, doc); err != nil {
+			s.log.ErrorContext(ctx, "Failed to delete document from S3", "docID", doc.ID, "s3File", doc.S3File, "error", err)
+			s.recordStatus(ctx, doc.ID, OperationDelete, StatusFailed, err.Error())
+			report.Failed++
+			continue
+		}
+
+		// The sync status row is removed along with the document.
+		if err := s.store.DeleteDocumentByID(ctx, doc.ID); err != nil {
+			s.log.ErrorContext(ctx, "Failed to delete document from database", "docID", doc.ID, "error", err)
+			s.recordStatus(ctx, doc.ID, OperationDelete, StatusFailed, err.Error())
+			report.Failed++
+			continue
+		}
+		s.log.InfoContext(ctx, "Deleted document", "docID", doc.ID, "s3File", doc.S3File)
+		report.Deleted++
+	}
+
+	return report
+}
+
+func (s *Syncer) deleteFiles(ctx context.Context, doc db.ListDocumentsToDeleteRow) error {
+	uris := []string{doc.S3File}
+	if doc.S3FilePreview.Valid && doc.S3FilePreview.String != "" {
+		uris = append(uris, doc.S3FilePreview.String)
+	}
+
+	for _, uri := range uris {
+		bucket, key, ok := db_util.SplitS3URI(uri)
+		if !ok {
+			return fmt.Errorf("invalid S3 URI: %s", uri)
+		}
+		if err := s.files.Delete(ctx, bucket, key); err != nil {
+			return fmt.Errorf("deleting %s: %w", uri, err)
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) recordStatus(ctx context.Context, docID uuid.UUID, operation string, status string, errMsg string) {
+	err := s.store.UpsertDocumentSyncStatus(ctx, db.UpsertDocumentSyncStatusParams{
+		DocID:     docID,
+		Operation: operation,
+		Status:    status,
+		Error:     sql.NullString{String: errMsg, Valid: errMsg != ""},
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to record sync status", "docID", docID, "status", status, "error", err)
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
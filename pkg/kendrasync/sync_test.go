@@ -0,0 +1,316 @@
+package kendrasync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kendra"
+	"github.com/aws/aws-sdk-go-v2/service/kendra/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeKendra struct {
+	mu        sync.Mutex
+	putInputs []*kendra.BatchPutDocumentInput
+	delInputs []*kendra.BatchDeleteDocumentInput
+	putErr    error
+	putFailed []types.BatchPutDocumentResponseFailedDocument
+	delFailed []types.BatchDeleteDocumentResponseFailedDocument
+}
+
+func (f *fakeKendra) BatchPutDocument(ctx context.Context, params *kendra.BatchPutDocumentInput, optFns ...func(*kendra.Options)) (*kendra.BatchPutDocumentOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.putInputs = append(f.putInputs, params)
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
+	return &kendra.BatchPutDocumentOutput{FailedDocuments: f.putFailed}, nil
+}
+
+func (f *fakeKendra) BatchDeleteDocument(ctx context.Context, params *kendra.BatchDeleteDocumentInput, optFns ...func(*kendra.Options)) (*kendra.BatchDeleteDocumentOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delInputs = append(f.delInputs, params)
+	return &kendra.BatchDeleteDocumentOutput{FailedDocuments: f.delFailed}, nil
+}
+
+type fakeFiles struct {
+	mu      sync.Mutex
+	deleted []string
+	failKey string
+}
+
+func (f *fakeFiles) Delete(ctx context.Context, bucket string, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if key == f.failKey {
+		return errors.New("access denied")
+	}
+	f.deleted = append(f.deleted, bucket+"/"+key)
+	return nil
+}
+
+type fakeStore struct {
+	mu       sync.Mutex
+	toIndex  []db.ListDocumentsToIndexRow
+	toDelete []db.ListDocumentsToDeleteRow
+	indexed  []uuid.UUID
+	skipped  []uuid.UUID
+	removed  []uuid.UUID
+	statuses map[uuid.UUID]db.UpsertDocumentSyncStatusParams
+	orphans  int
+}
+
+func (f *fakeStore) ListDocumentsToIndex(ctx context.Context) ([]db.ListDocumentsToIndexRow, error) {
+	return f.toIndex, nil
+}
+
+func (f *fakeStore) ListDocumentsToDelete(ctx context.Context) ([]db.ListDocumentsToDeleteRow, error) {
+	return f.toDelete, nil
+}
+
+func (f *fakeStore) MarkDocumentIndexed(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.indexed = append(f.indexed, id)
+	return nil
+}
+
+func (f *fakeStore) MarkDocumentSkipped(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.skipped = append(f.skipped, id)
+	return nil
+}
+
+func (f *fakeStore) DeleteDocumentByID(ctx context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, id)
+	return nil
+}
+
+func (f *fakeStore) UpsertDocumentSyncStatus(ctx context.Context, arg db.UpsertDocumentSyncStatusParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses[arg.DocID] = arg
+	return nil
+}
+
+func (f *fakeStore) DeleteOrphanedTerms(ctx context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.orphans++
+	return 0, nil
+}
+
+// limitedQueue runs the first accept jobs as they are enqueued and refuses
+// the rest.
+type limitedQueue struct {
+	accept  int
+	process func(ctx context.Context, b batch) Report
+}
+
+func (q *limitedQueue) Enqueue(job awskendra.Job[batch, Report]) bool {
+	if q.accept == 0 {
+		return false
+	}
+	q.accept--
+	job.ResultChan <- q.process(context.Background(), job.Payload)
+	close(job.ResultChan)
+	return true
+}
+
+func (q *limitedQueue) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+type SyncerTestSuite struct {
+	suite.Suite
+	kendra *fakeKendra
+	files  *fakeFiles
+	store  *fakeStore
+	syncer *Syncer
+}
+
+func (suite *SyncerTestSuite) SetupTest() {
+	suite.kendra = &fakeKendra{}
+	suite.files = &fakeFiles{}
+	suite.store = &fakeStore{statuses: make(map[uuid.UUID]db.UpsertDocumentSyncStatusParams)}
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	suite.syncer = NewSyncer(log, suite.store, suite.kendra, suite.files, "index-id", "role-arn")
+}
+
+func (suite *SyncerTestSuite) TearDownTest() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	suite.NoError(suite.syncer.Shutdown(ctx))
+}
+
+func (suite *SyncerTestSuite) TestIndexesPendingDocuments() {
+	valid := db.ListDocumentsToIndexRow{
+		ID:          uuid.New(),
+		Title:       "Peacebuilding in Practice",
+		S3File:      "s3://manually-uploaded-bep/report.pdf",
+		Source:      sql.NullString{String: "USIP", Valid: true},
+		Language:    sql.NullString{String: "fr", Valid: true},
+		AuthorNames: []string{"jane doe", "Jane Doe"},
+		RegionNames: []string{"east africa"},
+	}
+	untitled := db.ListDocumentsToIndexRow{
+		ID:     uuid.New(),
+		Title:  "Untitled",
+		S3File: "s3://manually-uploaded-bep/untitled.pdf",
+	}
+	suite.store.toIndex = []db.ListDocumentsToIndexRow{valid, untitled}
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), Report{Indexed: 1, Skipped: 1}, report)
+	suite.Require().Len(suite.kendra.putInputs, 1)
+	input := suite.kendra.putInputs[0]
+	assert.Equal(suite.T(), "index-id", *input.IndexId)
+	assert.Equal(suite.T(), "role-arn", *input.RoleArn)
+	suite.Require().Len(input.Documents, 1)
+
+	doc := input.Documents[0]
+	assert.Equal(suite.T(), valid.S3File, *doc.Id)
+	assert.Equal(suite.T(), types.ContentTypePdf, doc.ContentType)
+	assert.Equal(suite.T(), "manually-uploaded-bep", *doc.S3Path.Bucket)
+	assert.Equal(suite.T(), "report.pdf", *doc.S3Path.Key)
+
+	attrs := make(map[string]*types.DocumentAttributeValue)
+	for _, a := range doc.Attributes {
+		attrs[*a.Key] = a.Value
+	}
+	assert.Equal(suite.T(), "PDF", *attrs["_file_type"].StringValue)
+	assert.Equal(suite.T(), []string{"Jane Doe"}, attrs["Author"].StringListValue)
+	assert.Equal(suite.T(), []string{"East Africa"}, attrs["Region"].StringListValue)
+	assert.Equal(suite.T(), "USIP", *attrs["Source"].StringValue)
+	assert.Equal(suite.T(), "French", *attrs["Language"].StringValue)
+	assert.NotContains(suite.T(), attrs, "Keyword")
+
+	assert.Equal(suite.T(), []uuid.UUID{valid.ID}, suite.store.indexed)
+	assert.Equal(suite.T(), []uuid.UUID{untitled.ID}, suite.store.skipped)
+	assert.Zero(suite.T(), suite.store.orphans)
+	assert.Equal(suite.T(), StatusIndexed, suite.store.statuses[valid.ID].Status)
+	assert.Equal(suite.T(), StatusSkipped, suite.store.statuses[untitled.ID].Status)
+}
+
+func (suite *SyncerTestSuite) TestRecordsKendraFailures() {
+	ok := db.ListDocumentsToIndexRow{ID: uuid.New(), Title: "A", S3File: "s3://bucket/a.pdf"}
+	bad := db.ListDocumentsToIndexRow{ID: uuid.New(), Title: "B", S3File: "s3://bucket/b.docx"}
+	suite.store.toIndex = []db.ListDocumentsToIndexRow{ok, bad}
+	suite.kendra.putFailed = []types.BatchPutDocumentResponseFailedDocument{
+		{Id: &bad.S3File, ErrorMessage: stringPtr("document too large")},
+	}
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), Report{Indexed: 1, Failed: 1}, report)
+	assert.Equal(suite.T(), []uuid.UUID{ok.ID}, suite.store.indexed)
+	assert.Equal(suite.T(), StatusFailed, suite.store.statuses[bad.ID].Status)
+	assert.Equal(suite.T(), "document too large", suite.store.statuses[bad.ID].Error.String)
+}
+
+func (suite *SyncerTestSuite) TestBatchPutErrorFailsWholeBatch() {
+	doc := db.ListDocumentsToIndexRow{ID: uuid.New(), Title: "A", S3File: "s3://bucket/a.pdf"}
+	suite.store.toIndex = []db.ListDocumentsToIndexRow{doc}
+	suite.kendra.putErr = errors.New("throttled")
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), Report{Failed: 1}, report)
+	assert.Empty(suite.T(), suite.store.indexed)
+	assert.Equal(suite.T(), "throttled", suite.store.statuses[doc.ID].Error.String)
+}
+
+func (suite *SyncerTestSuite) TestDeletesMarkedDocuments() {
+	withPreview := db.ListDocumentsToDeleteRow{
+		ID:            uuid.New(),
+		S3File:        "s3://bucket/a.pdf",
+		S3FilePreview: sql.NullString{String: "s3://previews/a.webp", Valid: true},
+	}
+	s3Failure := db.ListDocumentsToDeleteRow{ID: uuid.New(), S3File: "s3://bucket/locked.pdf"}
+	suite.store.toDelete = []db.ListDocumentsToDeleteRow{withPreview, s3Failure}
+	suite.files.failKey = "locked.pdf"
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), Report{Deleted: 1, Failed: 1}, report)
+	suite.Require().Len(suite.kendra.delInputs, 1)
+	assert.Equal(suite.T(), []string{withPreview.S3File, s3Failure.S3File}, suite.kendra.delInputs[0].DocumentIdList)
+	assert.Equal(suite.T(), []string{"bucket/a.pdf", "previews/a.webp"}, suite.files.deleted)
+	assert.Equal(suite.T(), []uuid.UUID{withPreview.ID}, suite.store.removed)
+	assert.Equal(suite.T(), StatusFailed, suite.store.statuses[s3Failure.ID].Status)
+	assert.Equal(suite.T(), 1, suite.store.orphans)
+}
+
+func (suite *SyncerTestSuite) TestKeepsTermsWhenNothingWasDeleted() {
+	locked := db.ListDocumentsToDeleteRow{ID: uuid.New(), S3File: "s3://bucket/locked.pdf"}
+	suite.store.toDelete = []db.ListDocumentsToDeleteRow{locked}
+	suite.files.failKey = "locked.pdf"
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), Report{Failed: 1}, report)
+	assert.Zero(suite.T(), suite.store.orphans)
+}
+
+func (suite *SyncerTestSuite) TestBatchesOfTen() {
+	for i := 0; i < 25; i++ {
+		suite.store.toIndex = append(suite.store.toIndex, db.ListDocumentsToIndexRow{
+			ID:     uuid.New(),
+			Title:  "Doc",
+			S3File: "s3://bucket/doc-" + uuid.NewString() + ".pdf",
+		})
+	}
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.NoError(err)
+	assert.Equal(suite.T(), 25, report.Indexed)
+	assert.Len(suite.T(), suite.kendra.putInputs, 3)
+}
+
+func (suite *SyncerTestSuite) TestCountsQueuedBatchesWhenEnqueueFails() {
+	for i := 0; i < 15; i++ {
+		suite.store.toIndex = append(suite.store.toIndex, db.ListDocumentsToIndexRow{
+			ID:     uuid.New(),
+			Title:  "Doc",
+			S3File: "s3://bucket/doc-" + uuid.NewString() + ".pdf",
+		})
+	}
+	suite.Require().NoError(suite.syncer.Shutdown(context.Background()))
+	suite.syncer.queue = &limitedQueue{accept: 1, process: suite.syncer.processBatch}
+
+	report, err := suite.syncer.SyncOnce(context.Background())
+
+	suite.Error(err)
+	assert.Equal(suite.T(), Report{Indexed: 10}, report)
+}
+
+func TestSyncerTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncerTestSuite))
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,164 @@
+// Package language detects the language a document is written in and names
+// the languages the archive recognises.
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Language is a language the archive recognises, identified by its ISO 639-1
+// code.
+type Language struct {
+	Code string
+	Name string
+}
+
+// English is the language the archive's interface, vocabularies and search
+// index are written in.
+const English = "en"
+
+// Supported are the languages Detect can tell apart, in the order the edit
+// form lists them.
+var Supported = []Language{
+	{"en", "English"},
+	{"fr", "French"},
+	{"es", "Spanish"},
+	{"pt", "Portuguese"},
+	{"de", "German"},
+	{"it", "Italian"},
+	{"ar", "Arabic"},
+	{"ru", "Russian"},
+}
+
+// minWords is the fewest words Detect needs before it guesses. Shorter texts,
+// such as a scanned PDF with a few stray characters, are left undetected.
+const minWords = 20
+
+// maxWords is how many words of the text Detect reads. The opening pages are
+// enough and keep detection of long documents cheap.
+const maxWords = 5000
+
+// minHits is the fewest stopwords a language needs among the words read
+// before Detect chooses it.
+const minHits = 5
+
+// stopwords are short, frequent words that are common in one language and
+// rare in the others. Words shared by several languages, such as "de" and
+// "la", count for each of them, so the distinctive words decide.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "that", "for", "with", "this", "are", "was", "which", "have", "from", "by", "its", "their", "has", "been", "were"},
+	"fr": {"le", "la", "les", "des", "et", "est", "une", "du", "dans", "pour", "que", "qui", "sur", "au", "aux", "pas", "sont", "cette", "ces", "avec"},
+	"es": {"el", "la", "los", "las", "y", "es", "una", "del", "en", "para", "que", "por", "con", "se", "su", "sus", "como", "está", "son", "fue"},
+	"pt": {"o", "os", "as", "e", "é", "uma", "do", "da", "dos", "das", "em", "para", "que", "com", "não", "no", "na", "são", "pelo", "pela"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "den", "dem", "des", "mit", "für", "auf", "sich", "von", "zu", "im", "wird", "werden"},
+	"it": {"il", "lo", "gli", "le", "di", "è", "una", "del", "della", "che", "per", "con", "non", "sono", "nel", "nella", "dei", "delle", "questo", "anche"},
+}
+
+// Detect returns the code of the language text is written in, or "" when the
+// text is too short or matches no supported language. Arabic and Russian are
+// recognised by their scripts, and the others by counting stopwords.
+func Detect(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) > maxWords {
+		words = words[:maxWords]
+	}
+	if len(words) < minWords {
+		return ""
+	}
+
+	var latin, arabic, cyrillic int
+	for _, word := range words {
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			case unicode.Is(unicode.Arabic, r):
+				arabic++
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+			}
+		}
+	}
+	switch {
+	case arabic > latin && arabic >= cyrillic:
+		return "ar"
+	case cyrillic > latin:
+		return "ru"
+	}
+
+	hits := make(map[string]int, len(stopwords))
+	for _, word := range words {
+		for code, list := range stopwords {
+			for _, stopword := range list {
+				if word == stopword {
+					hits[code]++
+					break
+				}
+			}
+		}
+	}
+	best := ""
+	for _, l := range Supported {
+		if hits[l.Code] >= minHits && (best == "" || hits[l.Code] > hits[best]) {
+			best = l.Code
+		}
+	}
+	return best
+}
+
+// ForDocument returns the language of a document from its text, or from its
+// path when the text is too short to tell, as with scanned PDFs.
+func ForDocument(text, path string) string {
+	if code := Detect(text); code != "" {
+		return code
+	}
+	return FromPath(path)
+}
+
+// FromPath guesses the language of a document from its file path, for
+// collections that keep translations in folders such as "French/". It returns
+// "" unless the path names exactly one supported language as a whole word, so
+// that "Germany" is not taken for German.
+func FromPath(path string) string {
+	words := strings.FieldsFunc(strings.ToLower(path), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	found := ""
+	for _, l := range Supported {
+		for _, word := range words {
+			if word == strings.ToLower(l.Name) {
+				if found != "" && found != l.Code {
+					return ""
+				}
+				found = l.Code
+			}
+		}
+	}
+	return found
+}
+
+// Name returns the English name of the language with the given code, or ""
+// if it is not supported.
+func Name(code string) string {
+	for _, l := range Supported {
+		if l.Code == code {
+			return l.Name
+		}
+	}
+	return ""
+}
+
+// Code returns the code of a supported language given its code or its English
+// name in any case, or "" if it is not supported.
+func Code(name string) string {
+	name = strings.TrimSpace(name)
+	for _, l := range Supported {
+		if strings.EqualFold(l.Code, name) || strings.EqualFold(l.Name, name) {
+			return l.Code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The peace agreement was signed in 2016 and it has been praised by observers for the way that it placed victims at the centre of the transitional justice process, which was designed with their participation.", "en"},
+		{"french", "L'accord de paix a été signé en 2016 et il est salué par les observateurs pour la place qu'il accorde aux victimes dans le processus de justice transitionnelle, qui a été conçu avec leur participation.", "fr"},
+		{"spanish", "El acuerdo de paz fue firmado en 2016 y ha sido elogiado por los observadores por el lugar que da a las víctimas en el proceso de justicia transicional, que se diseñó con su participación y la de sus familias.", "es"},
+		{"portuguese", "O acordo de paz foi assinado em 2016 e é elogiado pelos observadores pelo lugar que dá às vítimas no processo de justiça de transição, que foi desenhado com a participação das vítimas e dos seus familiares.", "pt"},
+		{"german", "Das Friedensabkommen wurde 2016 unterzeichnet und wird von Beobachtern für die Rolle gelobt, die es den Opfern im Prozess der Übergangsjustiz gibt, der mit ihrer Beteiligung entworfen wurde und sich auf die Wahrheit stützt.", "de"},
+		{"italian", "L'accordo di pace è stato firmato nel 2016 ed è lodato dagli osservatori per il posto che dà alle vittime nel processo di giustizia di transizione, che è stato progettato con la loro partecipazione e quella delle famiglie.", "it"},
+		{"arabic", "تم توقيع اتفاق السلام في عام 2016 وقد أشاد به المراقبون لأنه وضع الضحايا في قلب عملية العدالة الانتقالية التي صممت بمشاركتهم ومشاركة عائلاتهم ومنظمات المجتمع المدني في جميع أنحاء البلاد", "ar"},
+		{"russian", "Мирное соглашение было подписано в 2016 году и получило высокую оценку наблюдателей за то, что оно поставило жертв в центр процесса правосудия переходного периода, разработанного при их участии и участии их семей.", "ru"},
+		{"too short", "Rapport annuel", ""},
+		{"no stopwords", "lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua ut enim ad minim veniam quis nostrud", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Detect(tt.text), tt.name)
+	}
+}
+
+func TestFromPath(t *testing.T) {
+	assert.Equal(t, "fr", FromPath("reports/French/rapport-annuel.pdf"))
+	assert.Equal(t, "es", FromPath("reports/annual-report-spanish.pdf"))
+	assert.Equal(t, "", FromPath("reports/annual-report.pdf"))
+	assert.Equal(t, "", FromPath("reports/germany-elections.pdf"), "a country is not a language")
+	assert.Equal(t, "", FromPath("reports/french-spanish-glossary.pdf"), "a path naming two languages is ambiguous")
+}
+
+func TestForDocument(t *testing.T) {
+	text := "The peace agreement was signed in 2016 and it has been praised by observers for the way that it placed victims at the centre of the transitional justice process."
+	assert.Equal(t, "en", ForDocument(text, "reports/French/report.pdf"), "the text decides when it is long enough")
+	assert.Equal(t, "fr", ForDocument("", "reports/French/rapport.pdf"))
+	assert.Equal(t, "", ForDocument("", "reports/rapport.pdf"))
+}
+
+func TestNameAndCode(t *testing.T) {
+	assert.Equal(t, "French", Name("fr"))
+	assert.Equal(t, "", Name("xx"))
+	assert.Equal(t, "fr", Code("French"))
+	assert.Equal(t, "fr", Code(" FR "))
+	assert.Equal(t, "", Code("Klingon"))
+}
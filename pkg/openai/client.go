@@ -0,0 +1,155 @@
+// Package openai extracts document metadata with any server that implements
+// the OpenAI chat completions API, such as a local model server.
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	maxTokens      = 512
+	temperature    = 0.3
+
+	// maxErrorBody caps how much of an error response is kept in the error.
+	maxErrorBody = 512
+)
+
+// Config says where the server is. BaseURL includes the API version, as in
+// "http://localhost:11434/v1". APIKey may be empty for local servers.
+// RepairAttempts is how many times the model is asked to fix a reply that does
+// not match awskendra.MetadataSchema.
+type Config struct {
+	BaseURL        string
+	APIKey         string
+	Model          string
+	Timeout        time.Duration
+	RepairAttempts int
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Client sends the same prompt as the Bedrock client to a chat completions endpoint.
+type Client struct {
+	httpClient *http.Client
+	config     Config
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("openai: base URL is required")
+	}
+	if cfg.Model == "" {
+		return nil, errors.New("openai: model is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		config:     cfg,
+	}, nil
+}
+
+// ProcessDocAndExtractMetadata extracts the document's text, calls the model, and parses metadata.
+func (c *Client) ProcessDocAndExtractMetadata(ctx context.Context, docBytes []byte, prompt awskendra.Prompt, vocab awskendra.Vocabulary) (*awskendra.Extraction, error) {
+	text, err := awskendra.ExtractDocumentText(docBytes)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := prompt.Render(vocab, text)
+	if err != nil {
+		return nil, err
+	}
+
+	extraction := &awskendra.Extraction{Model: c.config.Model, PromptVersion: prompt.Version}
+	if err := awskendra.ExtractWithRepair(ctx, c.chat, rendered, c.config.RepairAttempts, extraction); err != nil {
+		return nil, fmt.Errorf("error extracting metadata from model response: %w", err)
+	}
+	return extraction, nil
+}
+
+// chat sends a conversation and returns the model's reply.
+func (c *Client) chat(ctx context.Context, messages []awskendra.ChatMessage) (awskendra.Reply, error) {
+	chatMessages := make([]chatMessage, len(messages))
+	for i, m := range messages {
+		chatMessages[i] = chatMessage{Role: m.Role, Content: m.Content}
+	}
+	body, err := json.Marshal(chatRequest{
+		Model:       c.config.Model,
+		Messages:    chatMessages,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	})
+	if err != nil {
+		return awskendra.Reply{}, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return awskendra.Reply{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return awskendra.Reply{}, fmt.Errorf("failed to call model server: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return awskendra.Reply{}, fmt.Errorf("model server returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return awskendra.Reply{}, fmt.Errorf("failed to decode model server response: %w", err)
+	}
+	reply := awskendra.Reply{
+		InputTokens:  chat.Usage.PromptTokens,
+		OutputTokens: chat.Usage.CompletionTokens,
+	}
+	if len(chat.Choices) == 0 {
+		return reply, errors.New("model server returned no choices")
+	}
+	reply.Text = strings.TrimSpace(chat.Choices[0].Message.Content)
+	return reply, nil
+}
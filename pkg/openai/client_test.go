@@ -0,0 +1,86 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Model: "llama3"})
+	assert.Error(t, err, "base URL is required")
+
+	_, err = New(Config{BaseURL: "http://localhost:11434/v1"})
+	assert.Error(t, err, "model is required")
+}
+
+func TestClient_chat(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   string
+		status   int
+		response string
+		want     awskendra.Reply
+		wantErr  string
+	}{
+		{
+			name:     "returns the first choice",
+			apiKey:   "secret",
+			status:   http.StatusOK,
+			response: `{"choices": [{"message": {"role": "assistant", "content": " {\"title\": \"Report\"} "}}], "usage": {"prompt_tokens": 120, "completion_tokens": 30}}`,
+			want:     awskendra.Reply{Text: `{"title": "Report"}`, InputTokens: 120, OutputTokens: 30},
+		},
+		{
+			name:     "no choices",
+			status:   http.StatusOK,
+			response: `{"choices": []}`,
+			wantErr:  "model server returned no choices",
+		},
+		{
+			name:     "error status",
+			status:   http.StatusUnauthorized,
+			response: `{"error": "bad key"}`,
+			wantErr:  `model server returned 401 Unauthorized: {"error": "bad key"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got chatRequest
+			var auth string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+				auth = r.Header.Get("Authorization")
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client, err := New(Config{BaseURL: server.URL + "/v1/", APIKey: tt.apiKey, Model: "llama3"})
+			require.NoError(t, err)
+
+			reply, err := client.chat(context.Background(), []awskendra.ChatMessage{{Role: "user", Content: "prompt"}})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, reply)
+			assert.Equal(t, "llama3", got.Model)
+			assert.Equal(t, []chatMessage{{Role: "user", Content: "prompt"}}, got.Messages)
+			if tt.apiKey != "" {
+				assert.Equal(t, "Bearer "+tt.apiKey, auth)
+			} else {
+				assert.Empty(t, auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package pgsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/language"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// maxFacetOptions caps the number of options returned per facet, mirroring Kendra's default.
+const maxFacetOptions = 10
+
+// excerptsPerDocument is the number of page excerpts shown for each result.
+const excerptsPerDocument = 3
+
+// highlightStart and highlightStop delimit matched terms in ts_headline output.
+// They must match the StartSel/StopSel options used in search.sql and document_pages.sql.
+const (
+	highlightStart = "⟦"
+	highlightStop  = "⟧"
+)
+
+var filterNamesMap = map[string]string{
+	"Author":     "Authors",
+	"Keyword":    "Keywords",
+	"Region":     "Regions",
+	"Category":   "Categories",
+	"Source":     "Publisher",
+	"Language":   "Language",
+	"_file_type": "File Type",
+}
+
+// facetOrder is the order facets are returned in, matching the order the Kendra index reports them.
+var facetOrder = []string{"Author", "Category", "Keyword", "Language", "Region", "Source", "_file_type"}
+
+type postgresClientImpl struct {
+	dbQuerier *db.Queries
+	log       logger.Logger
+}
+
+// New returns an awskendra.Client backed by Postgres full-text search over the document_search table.
+func New(dbQuerier *db.Queries, log logger.Logger) awskendra.Client {
+	pkgLogger := log.With("package", "pgsearch")
+
+	return &postgresClientImpl{
+		dbQuerier: dbQuerier,
+		log:       pkgLogger,
+	}
+}
+
+func (c *postgresClientImpl) MakeQuery(ctx context.Context, query string, filters map[string][]string, pageNum int) (awskendra.KendraResults, error) {
+	c.log.DebugContext(ctx, "Building postgres query", "query", query, "page", pageNum, "filter_count", len(filters))
+
+	if pageNum < 1 {
+		pageNum = 1
+	}
+
+	params := buildSearchParams(query, filters, pageNum)
+	rows, err := c.dbQuerier.SearchDocumentsFullText(ctx, params)
+	if err != nil {
+		c.log.ErrorContext(ctx, "Postgres full-text query failed", "error", err)
+		return awskendra.KendraResults{}, fmt.Errorf("full-text query failed: %w", err)
+	}
+
+	facets, err := c.dbQuerier.CountSearchFacets(ctx, db.CountSearchFacetsParams{
+		Query:      params.Query,
+		Authors:    params.Authors,
+		Keywords:   params.Keywords,
+		Regions:    params.Regions,
+		Categories: params.Categories,
+		Sources:    params.Sources,
+		FileTypes:  params.FileTypes,
+		Languages:  params.Languages,
+	})
+	if err != nil {
+		c.log.ErrorContext(ctx, "Postgres facet query failed", "error", err)
+		return awskendra.KendraResults{}, fmt.Errorf("facet query failed: %w", err)
+	}
+
+	pageExcerpts := c.findPageExcerpts(ctx, params.Query, rows)
+
+	results := rowsToResults(rows, pageExcerpts)
+	results.Filters = facetsToFilters(facets)
+	results.PageStatus = awskendra.NewPageStatus(pageNum, results.Count)
+	results.Query = query
+
+	c.log.DebugContext(ctx, "Postgres query executed successfully", "result_count", results.Count)
+	return results, nil
+}
+
+// findPageExcerpts looks up the best matching pages of each result. Failures are logged and
+// the results fall back to excerpts of the abstract.
+func (c *postgresClientImpl) findPageExcerpts(ctx context.Context, query string, rows []db.SearchDocumentsFullTextRow) map[uuid.UUID][]awskendra.Excerpt {
+	if query == "" || len(rows) == 0 {
+		return nil
+	}
+
+	docIDs := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		docIDs[i] = row.ID
+	}
+
+	pages, err := c.dbQuerier.SearchPageExcerpts(ctx, db.SearchPageExcerptsParams{
+		Query:  query,
+		DocIds: docIDs,
+		PerDoc: excerptsPerDocument,
+	})
+	if err != nil {
+		c.log.WarnContext(ctx, "Postgres page excerpt query failed", "error", err)
+		return nil
+	}
+
+	excerpts := make(map[uuid.UUID][]awskendra.Excerpt)
+	for _, page := range pages {
+		excerpt := parseHeadline(page.Excerpt)
+		if excerpt.Text == "" {
+			continue
+		}
+		excerpt.PageNum = int(page.PageNumber)
+		excerpts[page.DocID] = append(excerpts[page.DocID], excerpt)
+	}
+	return excerpts
+}
+
+func (c *postgresClientImpl) GetSuggestions(ctx context.Context, query string) (awskendra.KendraSuggestions, error) {
+	c.log.DebugContext(ctx, "Requesting postgres suggestions", "query", query)
+
+	suggestions := awskendra.KendraSuggestions{
+		Suggestions: make([]string, 0),
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return suggestions, nil
+	}
+
+	terms, err := c.dbQuerier.SuggestSearchTerms(ctx, escapeLike(query))
+	if err != nil {
+		c.log.ErrorContext(ctx, "Postgres suggestion query failed", "error", err)
+		return awskendra.KendraSuggestions{}, err
+	}
+	suggestions.Suggestions = append(suggestions.Suggestions, terms...)
+
+	c.log.DebugContext(ctx, "Postgres suggestions retrieved", "count", len(suggestions.Suggestions))
+	return suggestions, nil
+}
+
+// likeEscaper escapes the characters that are special in a LIKE pattern with
+// the backslash, the ESCAPE character of the suggestion query.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match itself when used in a LIKE pattern, so that typing
+// "%" or "_" does not match every keyword and title.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// buildSearchParams maps the Kendra-style filter map onto the query parameters.
+// Filter values are lowercased since the query compares them case-insensitively,
+// and languages, which are filtered by name as in Kendra, become codes.
+func buildSearchParams(query string, filters map[string][]string, pageNum int) db.SearchDocumentsFullTextParams {
+	lower := func(key string) []string {
+		values := make([]string, 0, len(filters[key]))
+		for _, v := range filters[key] {
+			values = append(values, strings.ToLower(v))
+		}
+		return values
+	}
+	languages := lower("Language")
+	for i, name := range languages {
+		if code := language.Code(name); code != "" {
+			languages[i] = code
+		}
+	}
+
+	return db.SearchDocumentsFullTextParams{
+		Query:      strings.TrimSpace(query),
+		Authors:    lower("Author"),
+		Keywords:   lower("Keyword"),
+		Regions:    lower("Region"),
+		Categories: lower("Category"),
+		Sources:    lower("Source"),
+		FileTypes:  lower("_file_type"),
+		Languages:  languages,
+		PageSize:   awskendra.ResultsPerPage,
+		PageOffset: int32((pageNum - 1) * awskendra.ResultsPerPage),
+	}
+}
+
+func rowsToResults(rows []db.SearchDocumentsFullTextRow, pageExcerpts map[uuid.UUID][]awskendra.Excerpt) awskendra.KendraResults {
+	results := awskendra.KendraResults{
+		Results: make(map[string]awskendra.KendraResult),
+	}
+
+	for _, row := range rows {
+		results.Count = int(row.TotalCount)
+
+		title := awskendra.TrimExtension(row.Title)
+		res, ok := results.Results[title]
+		if !ok {
+			res = awskendra.KendraResult{
+				Title:    title,
+				Excerpts: make([]awskendra.Excerpt, 0),
+				Link:     db_util.ConvertS3URIToURL(row.S3File),
+			}
+			results.Order = append(results.Order, title)
+		}
+
+		if excerpts, found := pageExcerpts[row.ID]; found {
+			res.Excerpts = append(res.Excerpts, excerpts...)
+		} else if excerpt := parseHeadline(row.Excerpt); excerpt.Text != "" {
+			excerpt.PageNum = 1
+			res.Excerpts = append(res.Excerpts, excerpt)
+		}
+		results.Results[title] = res
+	}
+
+	return results
+}
+
+// parseHeadline strips the highlight delimiters from ts_headline output and records
+// the position of each highlighted term.
+func parseHeadline(headline string) awskendra.Excerpt {
+	var excerpt awskendra.Excerpt
+	var text strings.Builder
+
+	rest := strings.TrimSpace(headline)
+	for {
+		start := strings.Index(rest, highlightStart)
+		if start < 0 {
+			break
+		}
+		stop := strings.Index(rest[start:], highlightStop)
+		if stop < 0 {
+			break
+		}
+		stop += start
+
+		text.WriteString(rest[:start])
+		term := rest[start+len(highlightStart) : stop]
+		excerpt.Highlights = append(excerpt.Highlights, awskendra.Highlight{
+			Start: text.Len(),
+			End:   text.Len() + len(term),
+		})
+		text.WriteString(term)
+		rest = rest[stop+len(highlightStop):]
+	}
+	text.WriteString(rest)
+
+	excerpt.Text = text.String()
+	return excerpt
+}
+
+func facetsToFilters(rows []db.CountSearchFacetsRow) []awskendra.FilterCategory {
+	byFacet := make(map[string]*awskendra.FilterCategory)
+	for _, row := range rows {
+		category, ok := byFacet[row.Facet]
+		if !ok {
+			name, found := filterNamesMap[row.Facet]
+			if !found {
+				name = row.Facet
+			}
+			category = &awskendra.FilterCategory{
+				Category: row.Facet,
+				Options:  make([]awskendra.FilterOption, 0),
+				Name:     name,
+			}
+			byFacet[row.Facet] = category
+		}
+		if len(category.Options) >= maxFacetOptions {
+			continue
+		}
+		label := row.Label
+		if name := language.Name(label); row.Facet == "Language" && name != "" {
+			label = name
+		}
+		category.Options = append(category.Options, awskendra.FilterOption{
+			Label: label,
+			Count: row.DocCount,
+		})
+	}
+
+	filters := make([]awskendra.FilterCategory, 0, len(byFacet))
+	for _, facet := range facetOrder {
+		if category, ok := byFacet[facet]; ok {
+			filters = append(filters, *category)
+		}
+	}
+	return filters
+}
@@ -0,0 +1,207 @@
+package pgsearch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+)
+
+func Test_buildSearchParams(t *testing.T) {
+	type args struct {
+		query   string
+		filters map[string][]string
+		pageNum int
+	}
+	tests := []struct {
+		name string
+		args args
+		want db.SearchDocumentsFullTextParams
+	}{
+		{
+			name: "no filters on first page",
+			args: args{
+				query:   " peacebuilding ",
+				filters: nil,
+				pageNum: 1,
+			},
+			want: db.SearchDocumentsFullTextParams{
+				Query:      "peacebuilding",
+				Authors:    []string{},
+				Keywords:   []string{},
+				Regions:    []string{},
+				Categories: []string{},
+				Sources:    []string{},
+				FileTypes:  []string{},
+				Languages:  []string{},
+				PageSize:   10,
+				PageOffset: 0,
+			},
+		},
+		{
+			name: "filters are lowercased and offset follows page",
+			args: args{
+				query: "conflict",
+				filters: map[string][]string{
+					"Region":     {"East Africa"},
+					"_file_type": {"PDF"},
+				},
+				pageNum: 3,
+			},
+			want: db.SearchDocumentsFullTextParams{
+				Query:      "conflict",
+				Authors:    []string{},
+				Keywords:   []string{},
+				Regions:    []string{"east africa"},
+				Categories: []string{},
+				Sources:    []string{},
+				FileTypes:  []string{"pdf"},
+				Languages:  []string{},
+				PageSize:   10,
+				PageOffset: 20,
+			},
+		},
+		{
+			name: "languages are filtered by code",
+			args: args{
+				query: "conflict",
+				filters: map[string][]string{
+					"Language": {"French", "Klingon"},
+				},
+				pageNum: 1,
+			},
+			want: db.SearchDocumentsFullTextParams{
+				Query:      "conflict",
+				Authors:    []string{},
+				Keywords:   []string{},
+				Regions:    []string{},
+				Categories: []string{},
+				Sources:    []string{},
+				FileTypes:  []string{},
+				Languages:  []string{"fr", "klingon"},
+				PageSize:   10,
+				PageOffset: 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSearchParams(tt.args.query, tt.args.filters, tt.args.pageNum); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildSearchParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_facetsToFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []db.CountSearchFacetsRow
+		want []awskendra.FilterCategory
+	}{
+		{
+			name: "empty",
+			rows: nil,
+			want: []awskendra.FilterCategory{},
+		},
+		{
+			name: "facets are grouped and ordered",
+			rows: []db.CountSearchFacetsRow{
+				{Facet: "_file_type", Label: "PDF", DocCount: 4},
+				{Facet: "Author", Label: "Jane Doe", DocCount: 2},
+				{Facet: "Author", Label: "John Doe", DocCount: 1},
+				{Facet: "Language", Label: "fr", DocCount: 3},
+			},
+			want: []awskendra.FilterCategory{
+				{
+					Category: "Author",
+					Name:     "Authors",
+					Options: []awskendra.FilterOption{
+						{Label: "Jane Doe", Count: 2},
+						{Label: "John Doe", Count: 1},
+					},
+				},
+				{
+					Category: "Language",
+					Name:     "Language",
+					Options: []awskendra.FilterOption{
+						{Label: "French", Count: 3},
+					},
+				},
+				{
+					Category: "_file_type",
+					Name:     "File Type",
+					Options: []awskendra.FilterOption{
+						{Label: "PDF", Count: 4},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := facetsToFilters(tt.rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("facetsToFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_escapeLike(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{name: "plain", prefix: "peace", want: "peace"},
+		{name: "percent", prefix: "100%", want: `100\%`},
+		{name: "only a wildcard", prefix: "%", want: `\%`},
+		{name: "underscore", prefix: "un_women", want: `un\_women`},
+		{name: "backslash", prefix: `a\b`, want: `a\\b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLike(tt.prefix); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseHeadline(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     awskendra.Excerpt
+	}{
+		{
+			name:     "no highlights",
+			headline: "  plain text ",
+			want:     awskendra.Excerpt{Text: "plain text"},
+		},
+		{
+			name:     "two highlights",
+			headline: "the ⟦peace⟧ process in ⟦Colombia⟧.",
+			want: awskendra.Excerpt{
+				Text: "the peace process in Colombia.",
+				Highlights: []awskendra.Highlight{
+					{Start: 4, End: 9},
+					{Start: 21, End: 29},
+				},
+			},
+		},
+		{
+			name:     "unterminated highlight is kept as text",
+			headline: "broken ⟦marker",
+			want:     awskendra.Excerpt{Text: "broken ⟦marker"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseHeadline(tt.headline); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHeadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+// FixedWindowRateLimiter limits how many requests a key may make per window.
+// Unlike InMemoryRateLimiter every attempt counts, successful or not, so it suits
+// request quotas rather than login throttling.
+type FixedWindowRateLimiter struct {
+	limit  int           // Max attempts per window
+	window time.Duration // Length of each window
+
+	count       map[string]int
+	windowStart map[string]time.Time
+
+	mu sync.Mutex
+}
+
+// NewFixedWindowRateLimiter creates a limiter allowing limit attempts per window.
+func NewFixedWindowRateLimiter(limit int, window time.Duration) *FixedWindowRateLimiter {
+	return &FixedWindowRateLimiter{
+		limit:       limit,
+		window:      window,
+		count:       make(map[string]int),
+		windowStart: make(map[string]time.Time),
+	}
+}
+
+// IsLimited checks if the key has used up its attempts in the current window.
+func (l *FixedWindowRateLimiter) IsLimited(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(key, time.Now())
+	return l.count[key] >= l.limit
+}
+
+// RecordAttempt counts an attempt against the key's current window.
+func (l *FixedWindowRateLimiter) RecordAttempt(key string, success bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.expire(key, now)
+	if _, ok := l.windowStart[key]; !ok {
+		l.windowStart[key] = now
+	}
+	l.count[key]++
+}
+
+// expire resets the key once its window has passed. Callers must hold l.mu.
+func (l *FixedWindowRateLimiter) expire(key string, now time.Time) {
+	if start, ok := l.windowStart[key]; ok && now.After(start.Add(l.window)) {
+		delete(l.count, key)
+		delete(l.windowStart, key)
+	}
+}
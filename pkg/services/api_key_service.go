@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/ratelimiter"
+)
+
+const (
+	ScopeRead   = "read"
+	ScopeSearch = "search"
+	ScopeUpload = "upload"
+	ScopeAdmin  = "admin"
+
+	// DefaultAPIKeyRateLimit is the number of requests per minute a new key may make.
+	DefaultAPIKeyRateLimit = 60
+
+	apiKeyPrefix     = "gmu_"
+	apiKeyBytes      = 32
+	apiKeyShownChars = 12
+	apiKeyRateWindow = time.Minute
+
+	contextKeyAPIKey = "api_key"
+)
+
+// APIKeyScopes lists every scope a key can be granted.
+var APIKeyScopes = []string{ScopeRead, ScopeSearch, ScopeUpload, ScopeAdmin}
+
+var (
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrInvalidScope  = errors.New("invalid scope")
+)
+
+// APIKeyIdentity is the user a request was authenticated as through an API key.
+type APIKeyIdentity struct {
+	KeyID     uuid.UUID
+	UserID    uuid.UUID
+	Username  string
+	Role      Role
+	Scopes    []string
+	RateLimit int
+}
+
+// HasScope reports whether the key was granted scope. The admin scope grants all others.
+func (id APIKeyIdentity) HasScope(scope string) bool {
+	return slices.Contains(id.Scopes, scope) || slices.Contains(id.Scopes, ScopeAdmin)
+}
+
+// apiKeyIdentityFromContext returns the identity set by RequireScope, if any.
+func apiKeyIdentityFromContext(c echo.Context) (APIKeyIdentity, bool) {
+	identity, ok := c.Get(contextKeyAPIKey).(APIKeyIdentity)
+	return identity, ok
+}
+
+// APIKeyStore is the subset of db.Queries used by APIKeyService.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (uuid.UUID, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (db.GetAPIKeyByHashRow, error)
+	TouchAPIKey(ctx context.Context, id uuid.UUID) error
+	ListAPIKeys(ctx context.Context) ([]db.ListAPIKeysRow, error)
+	DeleteAPIKey(ctx context.Context, id uuid.UUID) error
+}
+
+type APIKeyService struct {
+	log       logger.Logger
+	store     APIKeyStore
+	ipLimiter ratelimiter.RateLimiter
+
+	mu       sync.Mutex
+	limiters map[uuid.UUID]ratelimiter.RateLimiter
+}
+
+// NewAPIKeyService creates the API key service. Failed key lookups are recorded
+// against the caller's IP in ipLimiter, which must not be the limiter used for
+// logins, or a script with a bad key would lock people at its IP out.
+func NewAPIKeyService(log logger.Logger, store APIKeyStore, ipLimiter ratelimiter.RateLimiter) *APIKeyService {
+	serviceLogger := log.With("service", "APIKey")
+	return &APIKeyService{
+		log:       serviceLogger,
+		store:     store,
+		ipLimiter: ipLimiter,
+		limiters:  make(map[uuid.UUID]ratelimiter.RateLimiter),
+	}
+}
+
+// CreateKey stores a new key for the user and returns it. The key itself is not
+// stored and cannot be shown again.
+func (s *APIKeyService) CreateKey(ctx context.Context, arg CreateAPIKeyParams) (string, error) {
+	if len(arg.Scopes) == 0 {
+		return "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range arg.Scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	if arg.RateLimit <= 0 {
+		arg.RateLimit = DefaultAPIKeyRateLimit
+	}
+
+	key, err := generateAPIKey()
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to generate API key", "error", err)
+		return "", err
+	}
+
+	id, err := s.store.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+		Prefix:    key[:apiKeyShownChars],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    arg.Scopes,
+		RateLimit: int32(arg.RateLimit),
+		ExpiresAt: arg.ExpiresAt,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to create API key", "userID", arg.UserID, "error", err)
+		return "", fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "API key created", "keyID", id, "userID", arg.UserID, "scopes", arg.Scopes)
+	return key, nil
+}
+
+// ListKeys returns every key, newest first.
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]db.ListAPIKeysRow, error) {
+	keys, err := s.store.ListAPIKeys(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list API keys", "error", err)
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeKey deletes the key; requests using it fail from then on.
+func (s *APIKeyService) RevokeKey(ctx context.Context, id uuid.UUID) error {
+	if err := s.store.DeleteAPIKey(ctx, id); err != nil {
+		s.log.ErrorContext(ctx, "Failed to revoke API key", "keyID", id, "error", err)
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.limiters, id)
+	s.mu.Unlock()
+
+	s.log.InfoContext(ctx, "API key revoked", "keyID", id)
+	return nil
+}
+
+// Authenticate looks up the key and returns who it belongs to.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (APIKeyIdentity, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return APIKeyIdentity{}, ErrInvalidAPIKey
+	}
+
+	row, err := s.store.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIKeyIdentity{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to look up API key", "error", err)
+		return APIKeyIdentity{}, fmt.Errorf("failed to look up API key: %w", err)
+	}
+	if row.ExpiresAt.Valid && time.Now().After(row.ExpiresAt.Time) {
+		s.log.InfoContext(ctx, "Expired API key used", "keyID", row.ID)
+		return APIKeyIdentity{}, ErrInvalidAPIKey
+	}
+
+	if err := s.store.TouchAPIKey(ctx, row.ID); err != nil {
+		s.log.WarnContext(ctx, "Failed to update API key last use", "keyID", row.ID, "error", err)
+	}
+
+	return APIKeyIdentity{
+		KeyID:     row.ID,
+		UserID:    row.UserID,
+		Username:  row.Username,
+		Role:      Role(row.Role),
+		Scopes:    row.Scopes,
+		RateLimit: int(row.RateLimit),
+	}, nil
+}
+
+// RequireScope authenticates requests carrying an "Authorization: Bearer" key.
+// The key must have the given scope and be within its rate limit. Requests
+// without a bearer key are passed on unchanged, so the route's session check
+// still applies to browsers.
+func (s *APIKeyService) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, ok := bearerToken(c.Request())
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			ip := c.RealIP()
+			if s.ipLimiter.IsLimited(ip) {
+				s.log.WarnContext(ctx, "IP rate limited", "ip", ip)
+				return apiKeyError(c, http.StatusTooManyRequests, "Too many failed attempts")
+			}
+
+			identity, err := s.Authenticate(ctx, key)
+			if errors.Is(err, ErrInvalidAPIKey) {
+				s.ipLimiter.RecordAttempt(ip, false)
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return apiKeyError(c, http.StatusUnauthorized, "Invalid or expired API key")
+			}
+			if err != nil {
+				return apiKeyError(c, http.StatusInternalServerError, "Could not check API key")
+			}
+
+			if !identity.HasScope(scope) {
+				s.log.WarnContext(ctx, "API key missing scope", "keyID", identity.KeyID, "scope", scope)
+				return apiKeyError(c, http.StatusForbidden, "API key lacks the "+scope+" scope")
+			}
+
+			limiter := s.limiterFor(identity)
+			limitKey := identity.KeyID.String()
+			if limiter.IsLimited(limitKey) {
+				s.log.WarnContext(ctx, "API key rate limited", "keyID", identity.KeyID)
+				c.Response().Header().Set("Retry-After", fmt.Sprint(int(apiKeyRateWindow.Seconds())))
+				return apiKeyError(c, http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+			limiter.RecordAttempt(limitKey, true)
+
+			c.Set(contextKeyAPIKey, identity)
+			// The CSRF middleware is skipped for bearer requests; pages still read the token.
+			if c.Get("csrf") == nil {
+				c.Set("csrf", "")
+			}
+			return next(c)
+		}
+	}
+}
+
+// limiterFor returns the key's own limiter, sized to its rate limit.
+func (s *APIKeyService) limiterFor(identity APIKeyIdentity) ratelimiter.RateLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limiter, ok := s.limiters[identity.KeyID]
+	if !ok {
+		limiter = ratelimiter.NewFixedWindowRateLimiter(identity.RateLimit, apiKeyRateWindow)
+		s.limiters[identity.KeyID] = limiter
+	}
+	return limiter
+}
+
+// CreateAPIKeyParams describes a key to create.
+type CreateAPIKeyParams struct {
+	UserID    uuid.UUID
+	Name      string
+	Scopes    []string
+	RateLimit int
+	ExpiresAt sql.NullTime
+}
+
+// HasBearerToken reports whether the request authenticates with an API key.
+func HasBearerToken(r *http.Request) bool {
+	_, ok := bearerToken(r)
+	return ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func apiKeyError(c echo.Context, status int, message string) error {
+	return c.JSON(status, map[string]string{"message": message})
+}
+
+func generateAPIKey() (string, error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/ratelimiter"
+)
+
+const testIPLimit = 10
+
+type fakeAPIKeyStore struct {
+	keys    map[string]db.GetAPIKeyByHashRow
+	created []db.CreateAPIKeyParams
+	touched []uuid.UUID
+}
+
+func (f *fakeAPIKeyStore) CreateAPIKey(ctx context.Context, arg db.CreateAPIKeyParams) (uuid.UUID, error) {
+	f.created = append(f.created, arg)
+	id := uuid.New()
+	f.keys[arg.KeyHash] = db.GetAPIKeyByHashRow{
+		ID:        id,
+		UserID:    arg.UserID,
+		Scopes:    arg.Scopes,
+		RateLimit: arg.RateLimit,
+		ExpiresAt: arg.ExpiresAt,
+		Username:  "script",
+	}
+	return id, nil
+}
+
+func (f *fakeAPIKeyStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (db.GetAPIKeyByHashRow, error) {
+	row, ok := f.keys[keyHash]
+	if !ok {
+		return row, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+func (f *fakeAPIKeyStore) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func (f *fakeAPIKeyStore) ListAPIKeys(ctx context.Context) ([]db.ListAPIKeysRow, error) {
+	return nil, nil
+}
+
+func (f *fakeAPIKeyStore) DeleteAPIKey(ctx context.Context, id uuid.UUID) error {
+	for hash, row := range f.keys {
+		if row.ID == id {
+			delete(f.keys, hash)
+		}
+	}
+	return nil
+}
+
+type APIKeyServiceTestSuite struct {
+	suite.Suite
+	store   *fakeAPIKeyStore
+	service *APIKeyService
+	e       *echo.Echo
+}
+
+func (suite *APIKeyServiceTestSuite) SetupTest() {
+	suite.store = &fakeAPIKeyStore{keys: make(map[string]db.GetAPIKeyByHashRow)}
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	ipLimiter := ratelimiter.NewFixedWindowRateLimiter(testIPLimit, time.Minute)
+	suite.service = NewAPIKeyService(log, suite.store, ipLimiter)
+	suite.e = echo.New()
+}
+
+func (suite *APIKeyServiceTestSuite) createKey(scopes []string, rateLimit int, expiresAt sql.NullTime) string {
+	key, err := suite.service.CreateKey(context.Background(), CreateAPIKeyParams{
+		UserID:    uuid.New(),
+		Name:      "test",
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		ExpiresAt: expiresAt,
+	})
+	suite.Require().NoError(err)
+	return key
+}
+
+// serve runs a request with the given Authorization header through RequireScope.
+func (suite *APIKeyServiceTestSuite) serve(scope string, authorization string) (*httptest.ResponseRecorder, echo.Context, bool) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	rec := httptest.NewRecorder()
+	c := suite.e.NewContext(req, rec)
+
+	called := false
+	handler := suite.service.RequireScope(scope)(func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusOK)
+	})
+	suite.Require().NoError(handler(c))
+	return rec, c, called
+}
+
+func (suite *APIKeyServiceTestSuite) TestCreateKeyStoresOnlyHash() {
+	key := suite.createKey([]string{ScopeRead}, 0, sql.NullTime{})
+
+	suite.True(strings.HasPrefix(key, apiKeyPrefix))
+	suite.Require().Len(suite.store.created, 1)
+	created := suite.store.created[0]
+	assert.Equal(suite.T(), hashAPIKey(key), created.KeyHash)
+	assert.Equal(suite.T(), key[:apiKeyShownChars], created.Prefix)
+	assert.NotContains(suite.T(), created.KeyHash, key)
+	assert.EqualValues(suite.T(), DefaultAPIKeyRateLimit, created.RateLimit)
+}
+
+func (suite *APIKeyServiceTestSuite) TestCreateKeyRejectsUnknownScope() {
+	_, err := suite.service.CreateKey(context.Background(), CreateAPIKeyParams{Name: "test", Scopes: []string{"delete"}})
+	suite.ErrorIs(err, ErrInvalidScope)
+
+	_, err = suite.service.CreateKey(context.Background(), CreateAPIKeyParams{Name: "test"})
+	suite.ErrorIs(err, ErrInvalidScope)
+}
+
+func (suite *APIKeyServiceTestSuite) TestNoBearerPassesThrough() {
+	rec, c, called := suite.serve(ScopeRead, "")
+
+	suite.True(called)
+	suite.Equal(http.StatusOK, rec.Code)
+	_, ok := apiKeyIdentityFromContext(c)
+	suite.False(ok)
+}
+
+func (suite *APIKeyServiceTestSuite) TestValidKeySetsIdentity() {
+	key := suite.createKey([]string{ScopeRead}, 0, sql.NullTime{})
+
+	rec, c, called := suite.serve(ScopeRead, "Bearer "+key)
+
+	suite.True(called)
+	suite.Equal(http.StatusOK, rec.Code)
+	identity, ok := apiKeyIdentityFromContext(c)
+	suite.Require().True(ok)
+	suite.Equal("script", identity.Username)
+	suite.Equal("", c.Get("csrf"))
+	suite.Len(suite.store.touched, 1)
+}
+
+func (suite *APIKeyServiceTestSuite) TestRejectsBadKeys() {
+	expired := suite.createKey([]string{ScopeRead}, 0, sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true})
+	readOnly := suite.createKey([]string{ScopeRead}, 0, sql.NullTime{})
+
+	tests := []struct {
+		name          string
+		scope         string
+		authorization string
+		want          int
+	}{
+		{"unknown key", ScopeRead, "Bearer gmu_doesnotexist", http.StatusUnauthorized},
+		{"wrong prefix", ScopeRead, "Bearer abc", http.StatusUnauthorized},
+		{"expired key", ScopeRead, "Bearer " + expired, http.StatusUnauthorized},
+		{"missing scope", ScopeUpload, "Bearer " + readOnly, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			rec, _, called := suite.serve(tt.scope, tt.authorization)
+			suite.False(called)
+			suite.Equal(tt.want, rec.Code)
+		})
+	}
+}
+
+func (suite *APIKeyServiceTestSuite) TestAdminScopeGrantsAll() {
+	key := suite.createKey([]string{ScopeAdmin}, 0, sql.NullTime{})
+
+	_, _, called := suite.serve(ScopeUpload, "Bearer "+key)
+
+	suite.True(called)
+}
+
+func (suite *APIKeyServiceTestSuite) TestPerKeyRateLimit() {
+	limited := suite.createKey([]string{ScopeSearch}, 2, sql.NullTime{})
+	other := suite.createKey([]string{ScopeSearch}, 2, sql.NullTime{})
+
+	for i := 0; i < 2; i++ {
+		rec, _, _ := suite.serve(ScopeSearch, "Bearer "+limited)
+		suite.Equal(http.StatusOK, rec.Code)
+	}
+	rec, _, called := suite.serve(ScopeSearch, "Bearer "+limited)
+	suite.False(called)
+	suite.Equal(http.StatusTooManyRequests, rec.Code)
+	suite.NotEmpty(rec.Header().Get("Retry-After"))
+
+	rec, _, _ = suite.serve(ScopeSearch, "Bearer "+other)
+	suite.Equal(http.StatusOK, rec.Code)
+}
+
+func (suite *APIKeyServiceTestSuite) TestFailedKeysLimitIP() {
+	valid := suite.createKey([]string{ScopeRead}, 0, sql.NullTime{})
+	for i := 0; i < testIPLimit; i++ {
+		suite.serve(ScopeRead, "Bearer gmu_guess")
+	}
+
+	rec, _, called := suite.serve(ScopeRead, "Bearer "+valid)
+
+	suite.False(called)
+	suite.Equal(http.StatusTooManyRequests, rec.Code)
+}
+
+func (suite *APIKeyServiceTestSuite) TestSessionManagerSeesKeyIdentity() {
+	sm, err := NewGorillaSessionManager(sessions.NewCookieStore([]byte("test-key")), "test", logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}), nil)
+	suite.Require().NoError(err)
+
+	c := suite.e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	suite.False(sm.IsAuthenticated(c))
+
+	userID := uuid.New()
+	c.Set(contextKeyAPIKey, APIKeyIdentity{UserID: userID, Role: RoleAdmin, Scopes: []string{ScopeUpload}})
+	suite.True(sm.IsAuthenticated(c))
+	suite.False(sm.IsMaster(c), "admin users need the admin scope to act as admin")
+	gotID, ok := sm.GetUserID(c)
+	suite.True(ok)
+	suite.Equal(userID.String(), gotID)
+
+	c.Set(contextKeyAPIKey, APIKeyIdentity{UserID: userID, Role: RoleAdmin, Scopes: []string{ScopeAdmin}})
+	suite.True(sm.IsMaster(c))
+}
+
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceTestSuite))
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const (
+	AuditDocumentUploaded  = "document_uploaded"
+	AuditMetadataUpdated   = "metadata_updated"
+	AuditDeletionToggled   = "deletion_toggled"
+	AuditRevisionRestored  = "revision_restored"
+	AuditUserCreated       = "user_created"
+	AuditUserDeleted       = "user_deleted"
+	AuditUserRoleChanged   = "user_role_changed"
+	AuditAPIKeyCreated     = "api_key_created"
+	AuditAPIKeyRevoked     = "api_key_revoked"
+	AuditPromptSaved       = "extraction_prompt_saved"
+	AuditVocabularyChanged = "vocabulary_changed"
+	AuditDocumentApproved  = "document_approved"
+	AuditTermApproved      = "term_approved"
+	AuditTermMapped        = "term_mapped"
+	AuditTermAliasAdded    = "term_alias_added"
+	AuditTermRenamed       = "term_renamed"
+	AuditTermMerged        = "term_merged"
+	AuditTermSplit         = "term_split"
+	AuditTermDeleted       = "term_deleted"
+	AuditRegionParentSet   = "region_parent_set"
+	AuditAuthorUpdated     = "author_updated"
+
+	unknownActor = "unknown"
+)
+
+// auditActionLabels are shown on the history tab.
+var auditActionLabels = map[string]string{
+	AuditDocumentUploaded: "Uploaded",
+	AuditMetadataUpdated:  "Edited metadata",
+	AuditDeletionToggled:  "Changed deletion mark",
+	AuditRevisionRestored: "Restored revision",
+	AuditDocumentApproved: "Approved metadata",
+}
+
+// Actor is the user responsible for a change.
+type Actor struct {
+	ID       uuid.UUID
+	Username string
+}
+
+// FieldChange is a field's value before and after an action. A nil side means
+// the field did not exist, as for a new upload.
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Changes maps field names to what happened to them.
+type Changes map[string]FieldChange
+
+// AuditEvent describes one action. DocID is uuid.Nil for actions that do not
+// concern a document, and Target names what they do concern, such as a username.
+type AuditEvent struct {
+	Action  string
+	DocID   uuid.UUID
+	Target  string
+	Changes Changes
+}
+
+type auditService struct {
+	log       logger.Logger
+	dbQuerier *db.Queries
+}
+
+func NewAuditService(log logger.Logger, dbQuerier *db.Queries) Auditor {
+	serviceLogger := log.With("service", "Audit")
+	return &auditService{
+		log:       serviceLogger,
+		dbQuerier: dbQuerier,
+	}
+}
+
+// Record stores the event. The change it describes has already happened, so a
+// failure is logged rather than returned to the user.
+func (s *auditService) Record(ctx context.Context, actor Actor, event AuditEvent) {
+	if event.Changes == nil {
+		event.Changes = Changes{}
+	}
+	diff, err := json.Marshal(event.Changes)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to encode audit diff", "action", event.Action, "error", err)
+		return
+	}
+
+	actorName := actor.Username
+	if actorName == "" {
+		actorName = unknownActor
+	}
+
+	err = s.dbQuerier.InsertAuditEvent(ctx, db.InsertAuditEventParams{
+		ActorID:   uuid.NullUUID{UUID: actor.ID, Valid: actor.ID != uuid.Nil},
+		ActorName: actorName,
+		Action:    event.Action,
+		DocID:     uuid.NullUUID{UUID: event.DocID, Valid: event.DocID != uuid.Nil},
+		Target:    sql.NullString{String: event.Target, Valid: event.Target != ""},
+		Diff:      diff,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to record audit event", "action", event.Action, "docID", event.DocID, "actor", actorName, "error", err)
+	}
+}
+
+// DocumentHistory returns the document's events, newest first.
+func (s *auditService) DocumentHistory(ctx context.Context, docID uuid.UUID) ([]db_types.AuditEntry, error) {
+	rows, err := s.dbQuerier.ListDocumentAuditEvents(ctx, uuid.NullUUID{UUID: docID, Valid: true})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list audit events", "docID", docID, "error", err)
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	entries := make([]db_types.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		var changes Changes
+		if err := json.Unmarshal(row.Diff, &changes); err != nil {
+			s.log.WarnContext(ctx, "Skipping unreadable audit diff", "eventID", row.ID, "error", err)
+			continue
+		}
+		label, ok := auditActionLabels[row.Action]
+		if !ok {
+			label = row.Action
+		}
+		entries = append(entries, db_types.AuditEntry{
+			Action:    label,
+			ActorName: row.ActorName,
+			CreatedAt: row.CreatedAt.Format("2006-01-02 15:04"),
+			Changes:   changes.entries(),
+		})
+	}
+	return entries, nil
+}
+
+// Diff returns the fields whose values differ between two snapshots.
+func Diff(before, after map[string]any) Changes {
+	changes := Changes{}
+	for field, a := range after {
+		b := before[field]
+		if !reflect.DeepEqual(b, a) {
+			changes[field] = FieldChange{Before: b, After: a}
+		}
+	}
+	for field, b := range before {
+		if _, ok := after[field]; !ok {
+			changes[field] = FieldChange{Before: b, After: nil}
+		}
+	}
+	return changes
+}
+
+// DocumentSnapshot returns the editable fields of a document in the form used for diffs.
+func DocumentSnapshot(doc db.FindDocumentByIDRow) map[string]any {
+	return metadataSnapshot(doc.Title, doc.Abstract, doc.PublishDate, doc.Source,
+		doc.Language, doc.EnglishTitle, doc.EnglishAbstract,
+		doc.AuthorNames, doc.KeywordNames, doc.RegionNames, doc.CategoryNames)
+}
+
+// RevisionSnapshot returns a saved revision in the same form as DocumentSnapshot.
+func RevisionSnapshot(rev db.DocumentRevision) map[string]any {
+	return metadataSnapshot(rev.Title, rev.Abstract, rev.PublishDate, rev.Source,
+		rev.Language, rev.EnglishTitle, rev.EnglishAbstract,
+		rev.Authors, rev.Keywords, rev.Regions, rev.Categories)
+}
+
+func metadataSnapshot(title string, abstract sql.NullString, publishDate sql.NullTime, source sql.NullString,
+	language, englishTitle, englishAbstract sql.NullString,
+	authors, keywords, regions, categories []string) map[string]any {
+	date := ""
+	if publishDate.Valid {
+		date = publishDate.Time.Format("2006-01-02")
+	}
+	return map[string]any{
+		"title":            title,
+		"abstract":         abstract.String,
+		"publish_date":     date,
+		"source":           source.String,
+		"language":         language.String,
+		"english_title":    englishTitle.String,
+		"english_abstract": englishAbstract.String,
+		"authors":          sortedNames(authors),
+		"keywords":         sortedNames(keywords),
+		"regions":          sortedNames(regions),
+		"categories":       sortedNames(categories),
+	}
+}
+
+// entries flattens the changes into display rows, sorted by field.
+func (c Changes) entries() []db_types.AuditChange {
+	out := make([]db_types.AuditChange, 0, len(c))
+	for field, change := range c {
+		out = append(out, db_types.AuditChange{
+			Field:  field,
+			Before: formatAuditValue(change.Before),
+			After:  formatAuditValue(change.After),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+func formatAuditValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, p := range v {
+			parts[i] = fmt.Sprint(p)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedNames makes list fields compare equal regardless of join order.
+// Lists are stored as []any so that snapshots match values decoded from JSON.
+func sortedNames(names []string) []any {
+	sorted := slices.Clone(names)
+	slices.Sort(sorted)
+	out := make([]any, len(sorted))
+	for i, n := range sorted {
+		out[i] = n
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before map[string]any
+		after  map[string]any
+		want   Changes
+	}{
+		{
+			name:   "unchanged",
+			before: map[string]any{"title": "A", "authors": []any{"X"}},
+			after:  map[string]any{"title": "A", "authors": []any{"X"}},
+			want:   Changes{},
+		},
+		{
+			name:   "changed field",
+			before: map[string]any{"title": "A", "source": "S"},
+			after:  map[string]any{"title": "B", "source": "S"},
+			want:   Changes{"title": {Before: "A", After: "B"}},
+		},
+		{
+			name:   "new document",
+			before: nil,
+			after:  map[string]any{"title": "A"},
+			want:   Changes{"title": {Before: nil, After: "A"}},
+		},
+		{
+			name:   "removed field",
+			before: map[string]any{"title": "A"},
+			after:  map[string]any{},
+			want:   Changes{"title": {Before: "A", After: nil}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Diff(tt.before, tt.after))
+		})
+	}
+}
+
+func TestDocumentSnapshot_IgnoresListOrder(t *testing.T) {
+	doc := db.FindDocumentByIDRow{
+		Title:       "Report",
+		Abstract:    sql.NullString{String: "About", Valid: true},
+		AuthorNames: []string{"Zed", "Amy"},
+	}
+	reordered := doc
+	reordered.AuthorNames = []string{"Amy", "Zed"}
+
+	assert.Empty(t, Diff(DocumentSnapshot(doc), DocumentSnapshot(reordered)))
+	assert.Equal(t, []any{"Amy", "Zed"}, DocumentSnapshot(doc)["authors"])
+}
+
+func TestDocumentSnapshot_MatchesStoredDiff(t *testing.T) {
+	snapshot := DocumentSnapshot(db.FindDocumentByIDRow{
+		Title:        "Report",
+		KeywordNames: []string{"peace", "conflict"},
+	})
+
+	encoded, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	assert.Empty(t, Diff(snapshot, decoded))
+}
+
+func TestChanges_entries(t *testing.T) {
+	changes := Changes{
+		"title":     {Before: "Old", After: "New"},
+		"authors":   {Before: nil, After: []any{"Amy", "Zed"}},
+		"to_delete": {Before: false, After: true},
+	}
+
+	assert.Equal(t, []db_types.AuditChange{
+		{Field: "authors", Before: "", After: "Amy, Zed"},
+		{Field: "title", Before: "Old", After: "New"},
+		{Field: "to_delete", Before: "false", After: "true"},
+	}, changes.entries())
+}
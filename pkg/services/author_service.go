@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// maxAffiliationLength matches authors.affiliation.
+const maxAffiliationLength = 255
+
+var (
+	ErrInvalidORCID = errors.New("invalid ORCID iD")
+	ErrORCIDTaken   = errors.New("ORCID iD belongs to another author")
+)
+
+var orcidPattern = regexp.MustCompile(`^[0-9]{4}-?[0-9]{4}-?[0-9]{4}-?[0-9]{3}[0-9X]$`)
+
+// AuthorStore is the subset of db.Queries used by the author service.
+type AuthorStore interface {
+	FindAuthorByID(ctx context.Context, id uuid.UUID) (db.Author, error)
+	FindAuthorByORCID(ctx context.Context, orcid string) (db.Author, error)
+	ListAuthorAliasNames(ctx context.Context, authorID uuid.UUID) ([]string, error)
+	ListAuthorPublications(ctx context.Context, authorID uuid.UUID) ([]db.ListAuthorPublicationsRow, error)
+	UpdateAuthorDetails(ctx context.Context, arg db.UpdateAuthorDetailsParams) (int64, error)
+}
+
+type authorService struct {
+	log   logger.Logger
+	store AuthorStore
+}
+
+// NewAuthorService creates the service behind author profile pages and the
+// ORCID iDs and affiliations editors attach to authors.
+func NewAuthorService(log logger.Logger, store AuthorStore) AuthorDirectory {
+	serviceLogger := log.With("service", "Author")
+	return &authorService{
+		log:   serviceLogger,
+		store: store,
+	}
+}
+
+// NormalizeORCID returns an ORCID iD in its hyphenated form. It accepts the
+// bare iD, with or without hyphens, and the https://orcid.org/ URL, and checks
+// the final check digit.
+func NormalizeORCID(orcid string) (string, error) {
+	id := strings.ToUpper(strings.TrimSpace(orcid))
+	for _, prefix := range []string{"HTTPS://", "HTTP://", "ORCID.ORG/", "WWW.ORCID.ORG/"} {
+		id = strings.TrimPrefix(id, prefix)
+	}
+	if !orcidPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: %q is not of the form 0000-0000-0000-0000", ErrInvalidORCID, orcid)
+	}
+	digits := strings.ReplaceAll(id, "-", "")
+
+	// ISO 7064 MOD 11-2, as specified by ORCID
+	total := 0
+	for _, d := range digits[:15] {
+		total = (total + int(d-'0')) * 2
+	}
+	check := byte('0' + (12-total%11)%11)
+	if check == '0'+10 {
+		check = 'X'
+	}
+	if digits[15] != check {
+		return "", fmt.Errorf("%w: the check digit of %s is wrong", ErrInvalidORCID, orcid)
+	}
+	return digits[0:4] + "-" + digits[4:8] + "-" + digits[8:12] + "-" + digits[12:16], nil
+}
+
+// Profile returns an author with their aliases and documents.
+func (s *authorService) Profile(ctx context.Context, id uuid.UUID) (db_types.AuthorProfile, error) {
+	author, err := s.find(ctx, id)
+	if err != nil {
+		return db_types.AuthorProfile{}, err
+	}
+	profile := db_types.AuthorProfile{
+		ID:          author.ID.String(),
+		Name:        author.Name,
+		ORCID:       author.Orcid.String,
+		Affiliation: author.Affiliation.String,
+	}
+
+	if profile.Aliases, err = s.store.ListAuthorAliasNames(ctx, id); err != nil {
+		s.log.ErrorContext(ctx, "Failed to list author aliases", "id", id, "error", err)
+		return db_types.AuthorProfile{}, fmt.Errorf("failed to list author aliases: %w", err)
+	}
+	rows, err := s.store.ListAuthorPublications(ctx, id)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list author documents", "id", id, "error", err)
+		return db_types.AuthorProfile{}, fmt.Errorf("failed to list author documents: %w", err)
+	}
+	for _, row := range rows {
+		doc := db_types.AuthorDocument{
+			ID:     row.ID.String(),
+			Title:  row.Title,
+			Source: row.Source.String,
+			Link:   util.ConvertS3URIToURL(row.S3File),
+		}
+		if row.PublisherID.Valid {
+			doc.PublisherID = row.PublisherID.UUID.String()
+		}
+		if row.PublishDate.Valid {
+			doc.PublishDate = row.PublishDate.Time.Format("2006-01-02")
+		}
+		profile.Documents = append(profile.Documents, doc)
+	}
+	return profile, nil
+}
+
+// CheckORCID reports what setting an author's ORCID iD, or removing it when
+// orcid is blank, would change. It changes nothing, but fails as SetDetails
+// would, so that a form can be checked before any of it is saved.
+func (s *authorService) CheckORCID(ctx context.Context, id uuid.UUID, orcid string) (Changes, error) {
+	author, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	changes, _, _, err := s.check(ctx, author, orcid, author.Affiliation.String)
+	return changes, err
+}
+
+// SetDetails sets an author's ORCID iD and affiliation; blank values remove
+// them. It returns what changed.
+func (s *authorService) SetDetails(ctx context.Context, id uuid.UUID, orcid, affiliation string) (Changes, error) {
+	author, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	changes, orcid, affiliation, err := s.check(ctx, author, orcid, affiliation)
+	if err != nil || len(changes) == 0 {
+		return changes, err
+	}
+
+	_, err = s.store.UpdateAuthorDetails(ctx, db.UpdateAuthorDetailsParams{
+		ID:          author.ID,
+		Orcid:       sql.NullString{String: orcid, Valid: orcid != ""},
+		Affiliation: sql.NullString{String: affiliation, Valid: affiliation != ""},
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to update author", "id", author.ID, "error", err)
+		return nil, fmt.Errorf("failed to update author: %w", err)
+	}
+	s.log.InfoContext(ctx, "Author updated", "id", author.ID, "orcid", orcid)
+	return changes, nil
+}
+
+// check normalizes an author's new ORCID iD and affiliation and returns them
+// with what they change. An ORCID iD another author has is an error.
+func (s *authorService) check(ctx context.Context, author db.Author, orcid, affiliation string) (Changes, string, string, error) {
+	if orcid = strings.TrimSpace(orcid); orcid != "" {
+		var err error
+		if orcid, err = NormalizeORCID(orcid); err != nil {
+			return nil, "", "", err
+		}
+	}
+	affiliation = strings.Join(strings.Fields(affiliation), " ")
+	if len(affiliation) > maxAffiliationLength {
+		return nil, "", "", fmt.Errorf("%w: an affiliation must have at most %d characters", ErrInvalidTerm, maxAffiliationLength)
+	}
+
+	changes := Changes{}
+	if orcid != author.Orcid.String {
+		changes["orcid"] = FieldChange{Before: author.Orcid.String, After: orcid}
+	}
+	if affiliation != author.Affiliation.String {
+		changes["affiliation"] = FieldChange{Before: author.Affiliation.String, After: affiliation}
+	}
+	if len(changes) == 0 {
+		return changes, orcid, affiliation, nil
+	}
+
+	if orcid != "" {
+		other, err := s.store.FindAuthorByORCID(ctx, orcid)
+		if err == nil && other.ID != author.ID {
+			return nil, "", "", fmt.Errorf("%w: %s is %s's; if they are the same person, merge the two authors", ErrORCIDTaken, orcid, other.Name)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			s.log.ErrorContext(ctx, "Failed to look up ORCID iD", "orcid", orcid, "error", err)
+			return nil, "", "", fmt.Errorf("failed to look up ORCID iD: %w", err)
+		}
+	}
+	return changes, orcid, affiliation, nil
+}
+
+func (s *authorService) find(ctx context.Context, id uuid.UUID) (db.Author, error) {
+	author, err := s.store.FindAuthorByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Author{}, fmt.Errorf("%w: %s", repository.ErrTermNotFound, id)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to load author", "id", id, "error", err)
+		return db.Author{}, fmt.Errorf("failed to load author: %w", err)
+	}
+	return author, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+var (
+	carberryID = uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	hopperID   = uuid.MustParse("66666666-6666-6666-6666-666666666666")
+)
+
+type fakeAuthorStore struct {
+	authors map[uuid.UUID]db.Author
+}
+
+func newFakeAuthorStore() *fakeAuthorStore {
+	return &fakeAuthorStore{authors: map[uuid.UUID]db.Author{
+		carberryID: {ID: carberryID, Name: "Josiah Carberry", Orcid: sql.NullString{String: "0000-0002-1825-0097", Valid: true}},
+		hopperID:   {ID: hopperID, Name: "Grace Hopper"},
+	}}
+}
+
+func (f *fakeAuthorStore) FindAuthorByID(_ context.Context, id uuid.UUID) (db.Author, error) {
+	author, ok := f.authors[id]
+	if !ok {
+		return db.Author{}, sql.ErrNoRows
+	}
+	return author, nil
+}
+
+func (f *fakeAuthorStore) FindAuthorByORCID(_ context.Context, orcid string) (db.Author, error) {
+	for _, author := range f.authors {
+		if author.Orcid.String == orcid {
+			return author, nil
+		}
+	}
+	return db.Author{}, sql.ErrNoRows
+}
+
+func (f *fakeAuthorStore) ListAuthorAliasNames(_ context.Context, id uuid.UUID) ([]string, error) {
+	if id == carberryID {
+		return []string{"J. Carberry"}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeAuthorStore) ListAuthorPublications(_ context.Context, id uuid.UUID) ([]db.ListAuthorPublicationsRow, error) {
+	if id != carberryID {
+		return nil, nil
+	}
+	return []db.ListAuthorPublicationsRow{
+		{ID: reportID, Title: "Psychoceramics", PublishDate: sql.NullTime{Time: time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true}, S3File: "s3://bucket/psychoceramics.pdf"},
+		{ID: congoID, Title: "Undated notes", PublisherID: uuid.NullUUID{UUID: brownID, Valid: true}, Source: sql.NullString{String: "Brown University", Valid: true}, S3File: "s3://bucket/notes.pdf"},
+	}, nil
+}
+
+func (f *fakeAuthorStore) UpdateAuthorDetails(_ context.Context, arg db.UpdateAuthorDetailsParams) (int64, error) {
+	author := f.authors[arg.ID]
+	author.Orcid, author.Affiliation = arg.Orcid, arg.Affiliation
+	f.authors[arg.ID] = author
+	return 1, nil
+}
+
+func newTestAuthorService(store AuthorStore) AuthorDirectory {
+	return NewAuthorService(logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}), store)
+}
+
+func TestNormalizeORCID(t *testing.T) {
+	for input, want := range map[string]string{
+		"0000-0002-1825-0097":                    "0000-0002-1825-0097",
+		" https://orcid.org/0000-0002-1825-0097": "0000-0002-1825-0097",
+		"0000000218250097":                       "0000-0002-1825-0097",
+		"0000-0002-1694-233x":                    "0000-0002-1694-233X",
+	} {
+		got, err := NormalizeORCID(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"0000-0002-1825-0098", "1234", "not an orcid", ""} {
+		_, err := NormalizeORCID(input)
+		assert.ErrorIs(t, err, ErrInvalidORCID, input)
+	}
+}
+
+func TestAuthorService_Profile(t *testing.T) {
+	authors := newTestAuthorService(newFakeAuthorStore())
+
+	profile, err := authors.Profile(context.Background(), carberryID)
+	require.NoError(t, err)
+	assert.Equal(t, "Josiah Carberry", profile.Name)
+	assert.Equal(t, "0000-0002-1825-0097", profile.ORCID)
+	assert.Equal(t, []string{"J. Carberry"}, profile.Aliases)
+	require.Len(t, profile.Documents, 2)
+	assert.Equal(t, "2019-04-01", profile.Documents[0].PublishDate)
+	assert.Empty(t, profile.Documents[1].PublishDate)
+	assert.Equal(t, "Brown University", profile.Documents[1].Source)
+	assert.Equal(t, brownID.String(), profile.Documents[1].PublisherID)
+	assert.Empty(t, profile.Documents[0].PublisherID)
+
+	_, err = authors.Profile(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, repository.ErrTermNotFound)
+}
+
+func TestAuthorService_SetDetails(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeAuthorStore()
+	authors := newTestAuthorService(store)
+
+	changes, err := authors.SetDetails(ctx, hopperID, "https://orcid.org/0000-0002-1694-233X", "  US   Navy ")
+	require.NoError(t, err)
+	assert.Equal(t, Changes{
+		"orcid":       {Before: "", After: "0000-0002-1694-233X"},
+		"affiliation": {Before: "", After: "US Navy"},
+	}, changes)
+	assert.Equal(t, "US Navy", store.authors[hopperID].Affiliation.String)
+
+	changes, err = authors.SetDetails(ctx, hopperID, "0000-0002-1694-233X", "US Navy")
+	require.NoError(t, err)
+	assert.Empty(t, changes, "an unchanged iD is not an update")
+
+	_, err = authors.SetDetails(ctx, hopperID, "0000-0002-1825-0097", "US Navy")
+	assert.ErrorIs(t, err, ErrORCIDTaken)
+
+	changes, err = authors.SetDetails(ctx, hopperID, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, Changes{
+		"orcid":       {Before: "0000-0002-1694-233X", After: ""},
+		"affiliation": {Before: "US Navy", After: ""},
+	}, changes)
+	assert.False(t, store.authors[hopperID].Orcid.Valid)
+}
+
+func TestAuthorService_CheckORCID(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeAuthorStore()
+	authors := newTestAuthorService(store)
+
+	changes, err := authors.CheckORCID(ctx, hopperID, "https://orcid.org/0000-0002-1694-233X")
+	require.NoError(t, err)
+	assert.Equal(t, Changes{"orcid": {Before: "", After: "0000-0002-1694-233X"}}, changes)
+	assert.False(t, store.authors[hopperID].Orcid.Valid, "checking changes nothing")
+
+	changes, err = authors.CheckORCID(ctx, carberryID, "0000-0002-1825-0097")
+	require.NoError(t, err)
+	assert.Empty(t, changes, "an unchanged iD is not an update")
+
+	_, err = authors.CheckORCID(ctx, hopperID, "0000-0002-1825-0097")
+	assert.ErrorIs(t, err, ErrORCIDTaken)
+
+	_, err = authors.CheckORCID(ctx, hopperID, "0000-0002-1825-0098")
+	assert.ErrorIs(t, err, ErrInvalidORCID)
+
+	changes, err = authors.CheckORCID(ctx, carberryID, "")
+	require.NoError(t, err)
+	assert.Equal(t, Changes{"orcid": {Before: "0000-0002-1825-0097", After: ""}}, changes)
+
+	_, err = authors.CheckORCID(ctx, uuid.New(), "")
+	assert.ErrorIs(t, err, repository.ErrTermNotFound)
+}
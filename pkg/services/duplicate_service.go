@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const (
+	// comparedPages is how many leading pages are compared between near-duplicates.
+	comparedPages = 3
+	// nearDuplicateThreshold is the minimum text similarity for two documents with
+	// the same normalized title to be listed as duplicates.
+	nearDuplicateThreshold = 0.6
+	shingleSize            = 3
+
+	ReasonIdenticalContent = "Identical content"
+	ReasonSimilarTitle     = "Same title"
+)
+
+// preferredLanguages are kept over their English copies, following the old
+// process_duplicates script.
+var preferredLanguages = []string{"french", "spanish"}
+
+type duplicateService struct {
+	log       logger.Logger
+	dbQuerier *db.Queries
+}
+
+func NewDuplicateService(log logger.Logger, dbQuerier *db.Queries) DuplicateFinder {
+	serviceLogger := log.With("service", "Duplicate")
+	return &duplicateService{
+		log:       serviceLogger,
+		dbQuerier: dbQuerier,
+	}
+}
+
+// ContentHash returns the hex-encoded SHA-256 of a file.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FindByContentHash returns the document whose file has the given hash, if any.
+func (s *duplicateService) FindByContentHash(ctx context.Context, hash string) (db.FindDocumentByContentHashRow, bool, error) {
+	doc, err := s.dbQuerier.FindDocumentByContentHash(ctx, sql.NullString{String: hash, Valid: true})
+	if errors.Is(err, sql.ErrNoRows) {
+		return doc, false, nil
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to look up content hash", "error", err)
+		return doc, false, fmt.Errorf("failed to look up content hash: %w", err)
+	}
+	return doc, true, nil
+}
+
+// FindDuplicateGroups lists documents that share a file hash, or that share a
+// normalized title and have similar text on their first pages.
+func (s *duplicateService) FindDuplicateGroups(ctx context.Context) ([]db_types.DuplicateGroup, error) {
+	candidates, err := s.dbQuerier.ListDuplicateCandidates(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list duplicate candidates", "error", err)
+		return nil, fmt.Errorf("failed to list duplicate candidates: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []db_types.DuplicateGroup{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.ID
+	}
+	pages, err := s.dbQuerier.ListLeadingPageText(ctx, db.ListLeadingPageTextParams{
+		DocIds:  ids,
+		MaxPage: comparedPages,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to load page text", "error", err)
+		return nil, fmt.Errorf("failed to load page text: %w", err)
+	}
+	texts := make(map[uuid.UUID]string, len(pages))
+	for _, p := range pages {
+		texts[p.DocID] = p.Content
+	}
+
+	groups := groupDuplicates(candidates, texts)
+	s.log.DebugContext(ctx, "Found duplicate groups", "candidates", len(candidates), "groups", len(groups))
+	return groups, nil
+}
+
+// KeepDocument marks every other document in the group for deletion.
+func (s *duplicateService) KeepDocument(ctx context.Context, keepID uuid.UUID, groupIDs []uuid.UUID) error {
+	others := make([]uuid.UUID, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		if id != keepID {
+			others = append(others, id)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	if err := s.dbQuerier.MarkDocumentsToDelete(ctx, others); err != nil {
+		s.log.ErrorContext(ctx, "Failed to mark duplicates for deletion", "keep", keepID, "error", err)
+		return fmt.Errorf("failed to mark duplicates for deletion: %w", err)
+	}
+	s.log.InfoContext(ctx, "Resolved duplicate group", "keep", keepID, "marked", len(others))
+	return nil
+}
+
+// MarkForDeletion marks a single document for deletion.
+func (s *duplicateService) MarkForDeletion(ctx context.Context, docID uuid.UUID) error {
+	if err := s.dbQuerier.MarkDocumentsToDelete(ctx, []uuid.UUID{docID}); err != nil {
+		s.log.ErrorContext(ctx, "Failed to mark duplicate for deletion", "docID", docID, "error", err)
+		return fmt.Errorf("failed to mark document for deletion: %w", err)
+	}
+	return nil
+}
+
+// groupDuplicates joins candidates that share a hash or normalized title into groups.
+// Title-only matches are dropped when their text is known to differ. As in
+// ListDuplicateCandidates, empty and "untitled" titles match nothing.
+func groupDuplicates(candidates []db.ListDuplicateCandidatesRow, texts map[uuid.UUID]string) []db_types.DuplicateGroup {
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	byKey := make(map[string]int)
+	for i, c := range candidates {
+		var keys []string
+		if c.NormalizedTitle != "" && c.NormalizedTitle != "untitled" {
+			keys = append(keys, "title:"+c.NormalizedTitle)
+		}
+		if c.ContentHash.Valid {
+			keys = append(keys, "hash:"+c.ContentHash.String)
+		}
+		for _, key := range keys {
+			if j, ok := byKey[key]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				byKey[key] = i
+			}
+		}
+	}
+
+	components := make(map[int][]db.ListDuplicateCandidatesRow)
+	var roots []int
+	for i, c := range candidates {
+		root := find(i)
+		if _, ok := components[root]; !ok {
+			roots = append(roots, root)
+		}
+		components[root] = append(components[root], c)
+	}
+
+	groups := make([]db_types.DuplicateGroup, 0, len(roots))
+	for _, root := range roots {
+		if group, ok := buildGroup(components[root], texts); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+func buildGroup(members []db.ListDuplicateCandidatesRow, texts map[uuid.UUID]string) (db_types.DuplicateGroup, bool) {
+	if len(members) < 2 {
+		return db_types.DuplicateGroup{}, false
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].CreatedAt.Time.Before(members[j].CreatedAt.Time)
+	})
+
+	keep := suggestedKeep(members)
+	reference := members[keep]
+	referenceShingles := shingles(texts[reference.ID])
+
+	docs := make([]db_types.DuplicateDocument, 0, len(members))
+	identical := true
+	for i, m := range members {
+		similarity := -1.0
+		sameHash := m.ContentHash.Valid && m.ContentHash == reference.ContentHash
+		switch {
+		case i == keep || sameHash:
+			similarity = 1
+		case texts[m.ID] != "" && texts[reference.ID] != "":
+			similarity = jaccard(referenceShingles, shingles(texts[m.ID]))
+		}
+		if similarity >= 0 && similarity < nearDuplicateThreshold {
+			continue
+		}
+		if !sameHash {
+			identical = false
+		}
+		docs = append(docs, db_types.DuplicateDocument{
+			ID:         m.ID.String(),
+			Title:      m.Title,
+			FileName:   m.FileName,
+			Link:       db_util.ConvertS3URIToURL(m.S3File),
+			CreatedAt:  formatCreatedAt(m.CreatedAt),
+			Similarity: similarity,
+			Suggested:  i == keep,
+		})
+	}
+	if len(docs) < 2 {
+		return db_types.DuplicateGroup{}, false
+	}
+
+	reason := ReasonSimilarTitle
+	if identical {
+		reason = ReasonIdenticalContent
+	}
+	return db_types.DuplicateGroup{Reason: reason, Documents: docs}, true
+}
+
+// suggestedKeep prefers a translated copy, then the oldest document.
+func suggestedKeep(members []db.ListDuplicateCandidatesRow) int {
+	for i, m := range members {
+		lower := strings.ToLower(m.S3File)
+		for _, lang := range preferredLanguages {
+			if strings.Contains(lower, lang) {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// shingles returns the set of consecutive word triples in the text.
+func shingles(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]struct{})
+	if len(words) < shingleSize {
+		if len(words) > 0 {
+			set[strings.Join(words, " ")] = struct{}{}
+		}
+		return set
+	}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[strings.Join(words[i:i+shingleSize], " ")] = struct{}{}
+	}
+	return set
+}
+
+// jaccard returns the size of the intersection over the size of the union.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	intersection := 0
+	for s := range a {
+		if _, ok := b[s]; ok {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+func formatCreatedAt(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
+}
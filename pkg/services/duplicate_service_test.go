@@ -0,0 +1,157 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+)
+
+func candidate(title string, s3File string, hash string, created int) db.ListDuplicateCandidatesRow {
+	return db.ListDuplicateCandidatesRow{
+		ID:              uuid.New(),
+		Title:           title,
+		FileName:        s3File,
+		S3File:          "s3://bucket/" + s3File,
+		ContentHash:     sql.NullString{String: hash, Valid: hash != ""},
+		CreatedAt:       sql.NullTime{Time: time.Date(2024, 1, created, 0, 0, 0, 0, time.UTC), Valid: true},
+		NormalizedTitle: title,
+	}
+}
+
+func Test_groupDuplicates(t *testing.T) {
+	sameText := "the quick brown fox jumps over the lazy dog near the river bank"
+	otherText := "an entirely different report about regional security cooperation"
+
+	identicalA := candidate("annual report", "a.pdf", "abc", 1)
+	identicalB := candidate("annual report 2024", "b.pdf", "abc", 2)
+
+	nearA := candidate("peace process", "peace.pdf", "", 1)
+	nearB := candidate("peace process", "peace_french.pdf", "", 2)
+
+	differentA := candidate("overview", "overview-1.pdf", "", 1)
+	differentB := candidate("overview", "overview-2.pdf", "", 2)
+
+	noTextA := candidate("field notes", "notes-1.pdf", "", 1)
+	noTextB := candidate("field notes", "notes-2.pdf", "", 2)
+
+	untitledA := candidate("untitled", "a.pdf", "h1", 1)
+	untitledB := candidate("untitled", "b.pdf", "h1", 2)
+	untitledC := candidate("untitled", "c.pdf", "h2", 3)
+	untitledD := candidate("untitled", "d.pdf", "h2", 4)
+
+	texts := map[uuid.UUID]string{
+		nearA.ID:      sameText,
+		nearB.ID:      sameText,
+		differentA.ID: sameText,
+		differentB.ID: otherText,
+	}
+
+	tests := []struct {
+		name          string
+		candidates    []db.ListDuplicateCandidatesRow
+		wantGroups    int
+		wantReason    string
+		wantSuggested string
+		wantSimilar   float64
+		wantSize      int
+	}{
+		{
+			name:          "same hash with different titles",
+			candidates:    []db.ListDuplicateCandidatesRow{identicalB, identicalA},
+			wantGroups:    1,
+			wantReason:    ReasonIdenticalContent,
+			wantSuggested: "a.pdf",
+			wantSimilar:   1,
+		},
+		{
+			name:          "same title and text prefers translation",
+			candidates:    []db.ListDuplicateCandidatesRow{nearA, nearB},
+			wantGroups:    1,
+			wantReason:    ReasonSimilarTitle,
+			wantSuggested: "peace_french.pdf",
+			wantSimilar:   1,
+		},
+		{
+			name:       "same title but different text",
+			candidates: []db.ListDuplicateCandidatesRow{differentA, differentB},
+			wantGroups: 0,
+		},
+		{
+			name:          "same title without page text",
+			candidates:    []db.ListDuplicateCandidatesRow{noTextA, noTextB},
+			wantGroups:    1,
+			wantReason:    ReasonSimilarTitle,
+			wantSuggested: "notes-1.pdf",
+			wantSimilar:   -1,
+		},
+		{
+			name:          "untitled documents only group by hash",
+			candidates:    []db.ListDuplicateCandidatesRow{untitledA, untitledB, untitledC, untitledD},
+			wantGroups:    2,
+			wantReason:    ReasonIdenticalContent,
+			wantSuggested: "a.pdf",
+			wantSimilar:   1,
+			wantSize:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := groupDuplicates(tt.candidates, texts)
+			if len(groups) != tt.wantGroups {
+				t.Fatalf("groupDuplicates() returned %d groups, want %d", len(groups), tt.wantGroups)
+			}
+			if tt.wantGroups == 0 {
+				return
+			}
+			for _, group := range groups {
+				if tt.wantSize > 0 && len(group.Documents) != tt.wantSize {
+					t.Errorf("group has %d documents, want %d", len(group.Documents), tt.wantSize)
+				}
+			}
+			group := groups[0]
+			if group.Reason != tt.wantReason {
+				t.Errorf("Reason = %v, want %v", group.Reason, tt.wantReason)
+			}
+			for _, doc := range group.Documents {
+				if doc.Suggested && doc.FileName != tt.wantSuggested {
+					t.Errorf("suggested %v, want %v", doc.FileName, tt.wantSuggested)
+				}
+				if !doc.Suggested && doc.Similarity != tt.wantSimilar {
+					t.Errorf("Similarity of %v = %v, want %v", doc.FileName, doc.Similarity, tt.wantSimilar)
+				}
+			}
+		})
+	}
+}
+
+func Test_jaccard(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{name: "identical", a: "one two three four", b: "One, two; three four", want: 1},
+		{name: "disjoint", a: "one two three", b: "four five six", want: 0},
+		{name: "half overlap", a: "one two three four", b: "two three four five", want: 1.0 / 3.0},
+		{name: "both empty", a: "", b: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jaccard(shingles(tt.a), shingles(tt.b)); got != tt.want {
+				t.Errorf("jaccard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got := ContentHash([]byte("hello")); got != want {
+		t.Errorf("ContentHash() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// Vocabulary lists, as named in the extraction settings routes.
+const (
+	VocabularyCategories = "categories"
+	VocabularyRegions    = "regions"
+	VocabularyKeywords   = "keywords"
+)
+
+// maxTermLength is the length of the name column of the term tables.
+const maxTermLength = 255
+
+var (
+	ErrPromptNotFound    = errors.New("prompt version not found")
+	ErrInvalidPrompt     = errors.New("invalid prompt")
+	ErrUnknownVocabulary = errors.New("unknown vocabulary")
+	ErrInvalidTerm       = errors.New("invalid term")
+	ErrTermNotFound      = errors.New("term is not in the vocabulary")
+)
+
+// ExtractionSettingsStore is the subset of db.Queries used by the extraction
+// settings service.
+type ExtractionSettingsStore interface {
+	GetLatestExtractionPrompt(ctx context.Context) (db.ExtractionPrompt, error)
+	GetExtractionPrompt(ctx context.Context, version int32) (db.ExtractionPrompt, error)
+	ListExtractionPrompts(ctx context.Context) ([]db.ListExtractionPromptsRow, error)
+	InsertExtractionPrompt(ctx context.Context, arg db.InsertExtractionPromptParams) (int32, error)
+
+	ListVocabularyCategories(ctx context.Context) ([]string, error)
+	ListVocabularyRegions(ctx context.Context) ([]string, error)
+	ListVocabularyKeywords(ctx context.Context) ([]string, error)
+	AddVocabularyCategory(ctx context.Context, categoryID uuid.UUID) error
+	AddVocabularyRegion(ctx context.Context, regionID uuid.UUID) error
+	AddVocabularyKeyword(ctx context.Context, keywordID uuid.UUID) error
+	RemoveVocabularyCategory(ctx context.Context, categoryID uuid.UUID) (int64, error)
+	RemoveVocabularyRegion(ctx context.Context, regionID uuid.UUID) (int64, error)
+	RemoveVocabularyKeyword(ctx context.Context, keywordID uuid.UUID) (int64, error)
+
+	FindCategoryByName(ctx context.Context, lower string) (db.Category, error)
+	FindRegionByName(ctx context.Context, lower string) (db.Region, error)
+	FindKeywordByName(ctx context.Context, lower string) (db.Keyword, error)
+	InsertCategory(ctx context.Context, arg db.InsertCategoryParams) error
+	InsertRegion(ctx context.Context, arg db.InsertRegionParams) error
+	InsertKeyword(ctx context.Context, arg db.InsertKeywordParams) error
+}
+
+type extractionSettingsService struct {
+	log   logger.Logger
+	store ExtractionSettingsStore
+}
+
+// NewExtractionSettingsService creates the service that keeps the extraction
+// prompt and vocabularies in the database. The newest prompt version is the
+// one in use, and the vocabularies are the categories, regions and keywords
+// marked in the vocabulary tables. Changes apply to the next extraction.
+func NewExtractionSettingsService(log logger.Logger, store ExtractionSettingsStore) ExtractionSettingsManager {
+	serviceLogger := log.With("service", "ExtractionSettings")
+	return &extractionSettingsService{
+		log:   serviceLogger,
+		store: store,
+	}
+}
+
+// Current returns the newest prompt and the vocabularies.
+func (s *extractionSettingsService) Current(ctx context.Context) (awskendra.Prompt, awskendra.Vocabulary, error) {
+	row, err := s.store.GetLatestExtractionPrompt(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return awskendra.Prompt{}, awskendra.Vocabulary{}, fmt.Errorf("%w: no prompt has been saved", ErrPromptNotFound)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get extraction prompt", "error", err)
+		return awskendra.Prompt{}, awskendra.Vocabulary{}, fmt.Errorf("failed to get extraction prompt: %w", err)
+	}
+
+	vocab, err := s.Vocabulary(ctx)
+	if err != nil {
+		return awskendra.Prompt{}, awskendra.Vocabulary{}, err
+	}
+	return databasePrompt(row.Version, row.Template), vocab, nil
+}
+
+// Prompts lists the prompt versions, newest first.
+func (s *extractionSettingsService) Prompts(ctx context.Context) ([]db_types.PromptVersion, error) {
+	rows, err := s.store.ListExtractionPrompts(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list extraction prompts", "error", err)
+		return nil, fmt.Errorf("failed to list extraction prompts: %w", err)
+	}
+
+	versions := make([]db_types.PromptVersion, 0, len(rows))
+	for _, row := range rows {
+		versions = append(versions, db_types.PromptVersion{
+			Version:       row.Version,
+			Note:          row.Note,
+			RestoredFrom:  row.RestoredFrom.Int32,
+			CreatedByName: row.CreatedByName,
+			CreatedAt:     row.CreatedAt.Format("2006-01-02 15:04"),
+		})
+	}
+	return versions, nil
+}
+
+// Prompt returns one prompt version with its template.
+func (s *extractionSettingsService) Prompt(ctx context.Context, version int32) (db_types.PromptVersion, error) {
+	row, err := s.store.GetExtractionPrompt(ctx, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db_types.PromptVersion{}, fmt.Errorf("%w: %d", ErrPromptNotFound, version)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get extraction prompt", "version", version, "error", err)
+		return db_types.PromptVersion{}, fmt.Errorf("failed to get extraction prompt: %w", err)
+	}
+	return db_types.PromptVersion{
+		Version:       row.Version,
+		Template:      row.Template,
+		Note:          row.Note,
+		RestoredFrom:  row.RestoredFrom.Int32,
+		CreatedByName: row.CreatedByName,
+		CreatedAt:     row.CreatedAt.Format("2006-01-02 15:04"),
+	}, nil
+}
+
+// SavePrompt stores template as a new version, which extraction uses from then on.
+func (s *extractionSettingsService) SavePrompt(ctx context.Context, template, note string, actor Actor) (int32, error) {
+	return s.insertPrompt(ctx, template, note, 0, actor)
+}
+
+// RestorePrompt stores an earlier version's template as a new version.
+func (s *extractionSettingsService) RestorePrompt(ctx context.Context, version int32, actor Actor) (int32, error) {
+	old, err := s.Prompt(ctx, version)
+	if err != nil {
+		return 0, err
+	}
+	return s.insertPrompt(ctx, old.Template, fmt.Sprintf("Restored version %d", version), version, actor)
+}
+
+func (s *extractionSettingsService) insertPrompt(ctx context.Context, template, note string, restoredFrom int32, actor Actor) (int32, error) {
+	template = strings.ReplaceAll(template, "\r\n", "\n")
+	if err := (awskendra.Prompt{Version: "new", Template: template}).Validate(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPrompt, err)
+	}
+
+	version, err := s.store.InsertExtractionPrompt(ctx, db.InsertExtractionPromptParams{
+		Template:      template,
+		Note:          strings.TrimSpace(note),
+		RestoredFrom:  sql.NullInt32{Int32: restoredFrom, Valid: restoredFrom != 0},
+		CreatedBy:     uuid.NullUUID{UUID: actor.ID, Valid: actor.ID != uuid.Nil},
+		CreatedByName: actor.Username,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to save extraction prompt", "error", err)
+		return 0, fmt.Errorf("failed to save extraction prompt: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "Extraction prompt saved", "version", version, "restoredFrom", restoredFrom, "actor", actor.Username)
+	return version, nil
+}
+
+// Vocabulary returns the names listed in the prompt.
+func (s *extractionSettingsService) Vocabulary(ctx context.Context) (awskendra.Vocabulary, error) {
+	var vocab awskendra.Vocabulary
+	var err error
+	if vocab.Categories, err = s.store.ListVocabularyCategories(ctx); err != nil {
+		return vocab, s.vocabularyError(ctx, err)
+	}
+	if vocab.Regions, err = s.store.ListVocabularyRegions(ctx); err != nil {
+		return vocab, s.vocabularyError(ctx, err)
+	}
+	if vocab.Keywords, err = s.store.ListVocabularyKeywords(ctx); err != nil {
+		return vocab, s.vocabularyError(ctx, err)
+	}
+	return vocab, nil
+}
+
+func (s *extractionSettingsService) vocabularyError(ctx context.Context, err error) error {
+	s.log.ErrorContext(ctx, "Failed to list vocabulary", "error", err)
+	return fmt.Errorf("failed to list vocabulary: %w", err)
+}
+
+// AddTerm adds a name to a vocabulary, creating the term if it does not exist.
+// It returns the name as stored, which may differ in case from name.
+func (s *extractionSettingsService) AddTerm(ctx context.Context, vocabulary, name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || len(name) > maxTermLength {
+		return "", fmt.Errorf("%w: a name must have 1 to %d characters", ErrInvalidTerm, maxTermLength)
+	}
+
+	id, stored, err := s.findTerm(ctx, vocabulary, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		id, stored = uuid.New(), name
+		err = s.insertTerm(ctx, vocabulary, id, name)
+	}
+	if err != nil {
+		return "", s.termError(ctx, "Failed to add vocabulary term", vocabulary, name, err)
+	}
+
+	switch vocabulary {
+	case VocabularyCategories:
+		err = s.store.AddVocabularyCategory(ctx, id)
+	case VocabularyRegions:
+		err = s.store.AddVocabularyRegion(ctx, id)
+	case VocabularyKeywords:
+		err = s.store.AddVocabularyKeyword(ctx, id)
+	}
+	if err != nil {
+		return "", s.termError(ctx, "Failed to add vocabulary term", vocabulary, name, err)
+	}
+
+	s.log.InfoContext(ctx, "Vocabulary term added", "vocabulary", vocabulary, "name", stored)
+	return stored, nil
+}
+
+// RemoveTerm takes a name out of a vocabulary. The term itself, and the
+// documents that use it, are not changed.
+func (s *extractionSettingsService) RemoveTerm(ctx context.Context, vocabulary, name string) error {
+	id, stored, err := s.findTerm(ctx, vocabulary, strings.TrimSpace(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrTermNotFound, name)
+	}
+	if err != nil {
+		return s.termError(ctx, "Failed to remove vocabulary term", vocabulary, name, err)
+	}
+
+	var n int64
+	switch vocabulary {
+	case VocabularyCategories:
+		n, err = s.store.RemoveVocabularyCategory(ctx, id)
+	case VocabularyRegions:
+		n, err = s.store.RemoveVocabularyRegion(ctx, id)
+	case VocabularyKeywords:
+		n, err = s.store.RemoveVocabularyKeyword(ctx, id)
+	}
+	if err != nil {
+		return s.termError(ctx, "Failed to remove vocabulary term", vocabulary, name, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s", ErrTermNotFound, name)
+	}
+
+	s.log.InfoContext(ctx, "Vocabulary term removed", "vocabulary", vocabulary, "name", stored)
+	return nil
+}
+
+// findTerm looks a term up by name, ignoring case.
+func (s *extractionSettingsService) findTerm(ctx context.Context, vocabulary, name string) (uuid.UUID, string, error) {
+	switch vocabulary {
+	case VocabularyCategories:
+		row, err := s.store.FindCategoryByName(ctx, name)
+		return row.ID, row.Name, err
+	case VocabularyRegions:
+		row, err := s.store.FindRegionByName(ctx, name)
+		return row.ID, row.Name, err
+	case VocabularyKeywords:
+		row, err := s.store.FindKeywordByName(ctx, name)
+		return row.ID, row.Name, err
+	default:
+		return uuid.Nil, "", fmt.Errorf("%w: %q", ErrUnknownVocabulary, vocabulary)
+	}
+}
+
+func (s *extractionSettingsService) insertTerm(ctx context.Context, vocabulary string, id uuid.UUID, name string) error {
+	switch vocabulary {
+	case VocabularyCategories:
+		return s.store.InsertCategory(ctx, db.InsertCategoryParams{ID: id, Name: name, Approved: true})
+	case VocabularyRegions:
+		return s.store.InsertRegion(ctx, db.InsertRegionParams{ID: id, Name: name, Approved: true})
+	default:
+		return s.store.InsertKeyword(ctx, db.InsertKeywordParams{ID: id, Name: name, Approved: true})
+	}
+}
+
+func (s *extractionSettingsService) termError(ctx context.Context, msg, vocabulary, name string, err error) error {
+	if errors.Is(err, ErrUnknownVocabulary) {
+		return err
+	}
+	s.log.ErrorContext(ctx, msg, "vocabulary", vocabulary, "name", name, "error", err)
+	return fmt.Errorf("failed to update vocabulary: %w", err)
+}
+
+func databasePrompt(version int32, template string) awskendra.Prompt {
+	return awskendra.Prompt{Version: fmt.Sprintf("db:%d", version), Template: template}
+}
+
+type fileExtractionSettings struct {
+	prompt awskendra.Prompt
+	vocab  awskendra.Vocabulary
+}
+
+// NewFileExtractionSettings reads the prompt and vocabularies once from a
+// versioned directory such as extraction/v1. Changing them means deploying a
+// new directory.
+func NewFileExtractionSettings(dir string) (ExtractionSettings, error) {
+	prompt, vocab, err := awskendra.LoadExtractionDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load extraction settings from %s: %w", dir, err)
+	}
+	return &fileExtractionSettings{prompt: prompt, vocab: vocab}, nil
+}
+
+func (f *fileExtractionSettings) Current(context.Context) (awskendra.Prompt, awskendra.Vocabulary, error) {
+	return f.prompt, f.vocab, nil
+}
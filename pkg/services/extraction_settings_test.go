@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// fakeExtractionStore keeps each vocabulary's terms by ID, and the IDs that are
+// in the vocabulary.
+type fakeExtractionStore struct {
+	prompts []db.ExtractionPrompt
+	terms   map[string]map[uuid.UUID]string
+	members map[string][]uuid.UUID
+}
+
+func newFakeExtractionStore() *fakeExtractionStore {
+	f := &fakeExtractionStore{terms: map[string]map[uuid.UUID]string{}, members: map[string][]uuid.UUID{}}
+	for _, v := range []string{VocabularyCategories, VocabularyRegions, VocabularyKeywords} {
+		f.terms[v] = map[uuid.UUID]string{}
+	}
+	return f
+}
+
+func (f *fakeExtractionStore) GetLatestExtractionPrompt(context.Context) (db.ExtractionPrompt, error) {
+	if len(f.prompts) == 0 {
+		return db.ExtractionPrompt{}, sql.ErrNoRows
+	}
+	return f.prompts[len(f.prompts)-1], nil
+}
+
+func (f *fakeExtractionStore) GetExtractionPrompt(_ context.Context, version int32) (db.ExtractionPrompt, error) {
+	for _, p := range f.prompts {
+		if p.Version == version {
+			return p, nil
+		}
+	}
+	return db.ExtractionPrompt{}, sql.ErrNoRows
+}
+
+func (f *fakeExtractionStore) ListExtractionPrompts(context.Context) ([]db.ListExtractionPromptsRow, error) {
+	var rows []db.ListExtractionPromptsRow
+	for _, p := range slices.Backward(f.prompts) {
+		rows = append(rows, db.ListExtractionPromptsRow{Version: p.Version, Note: p.Note, RestoredFrom: p.RestoredFrom, CreatedByName: p.CreatedByName, CreatedAt: p.CreatedAt})
+	}
+	return rows, nil
+}
+
+func (f *fakeExtractionStore) InsertExtractionPrompt(_ context.Context, arg db.InsertExtractionPromptParams) (int32, error) {
+	version := int32(len(f.prompts) + 1)
+	f.prompts = append(f.prompts, db.ExtractionPrompt{
+		Version:       version,
+		Template:      arg.Template,
+		Note:          arg.Note,
+		RestoredFrom:  arg.RestoredFrom,
+		CreatedBy:     arg.CreatedBy,
+		CreatedByName: arg.CreatedByName,
+		CreatedAt:     time.Now(),
+	})
+	return version, nil
+}
+
+func (f *fakeExtractionStore) list(vocabulary string) []string {
+	var names []string
+	for _, id := range f.members[vocabulary] {
+		names = append(names, f.terms[vocabulary][id])
+	}
+	return names
+}
+
+func (f *fakeExtractionStore) add(vocabulary string, id uuid.UUID) error {
+	if !slices.Contains(f.members[vocabulary], id) {
+		f.members[vocabulary] = append(f.members[vocabulary], id)
+	}
+	return nil
+}
+
+func (f *fakeExtractionStore) remove(vocabulary string, id uuid.UUID) (int64, error) {
+	i := slices.Index(f.members[vocabulary], id)
+	if i < 0 {
+		return 0, nil
+	}
+	f.members[vocabulary] = slices.Delete(f.members[vocabulary], i, i+1)
+	return 1, nil
+}
+
+func (f *fakeExtractionStore) find(vocabulary, name string) (uuid.UUID, string, error) {
+	for id, stored := range f.terms[vocabulary] {
+		if strings.EqualFold(stored, name) {
+			return id, stored, nil
+		}
+	}
+	return uuid.Nil, "", sql.ErrNoRows
+}
+
+func (f *fakeExtractionStore) ListVocabularyCategories(context.Context) ([]string, error) {
+	return f.list(VocabularyCategories), nil
+}
+
+func (f *fakeExtractionStore) ListVocabularyRegions(context.Context) ([]string, error) {
+	return f.list(VocabularyRegions), nil
+}
+
+func (f *fakeExtractionStore) ListVocabularyKeywords(context.Context) ([]string, error) {
+	return f.list(VocabularyKeywords), nil
+}
+
+func (f *fakeExtractionStore) AddVocabularyCategory(_ context.Context, id uuid.UUID) error {
+	return f.add(VocabularyCategories, id)
+}
+
+func (f *fakeExtractionStore) AddVocabularyRegion(_ context.Context, id uuid.UUID) error {
+	return f.add(VocabularyRegions, id)
+}
+
+func (f *fakeExtractionStore) AddVocabularyKeyword(_ context.Context, id uuid.UUID) error {
+	return f.add(VocabularyKeywords, id)
+}
+
+func (f *fakeExtractionStore) RemoveVocabularyCategory(_ context.Context, id uuid.UUID) (int64, error) {
+	return f.remove(VocabularyCategories, id)
+}
+
+func (f *fakeExtractionStore) RemoveVocabularyRegion(_ context.Context, id uuid.UUID) (int64, error) {
+	return f.remove(VocabularyRegions, id)
+}
+
+func (f *fakeExtractionStore) RemoveVocabularyKeyword(_ context.Context, id uuid.UUID) (int64, error) {
+	return f.remove(VocabularyKeywords, id)
+}
+
+func (f *fakeExtractionStore) FindCategoryByName(_ context.Context, name string) (db.Category, error) {
+	id, stored, err := f.find(VocabularyCategories, name)
+	return db.Category{ID: id, Name: stored}, err
+}
+
+func (f *fakeExtractionStore) FindRegionByName(_ context.Context, name string) (db.Region, error) {
+	id, stored, err := f.find(VocabularyRegions, name)
+	return db.Region{ID: id, Name: stored}, err
+}
+
+func (f *fakeExtractionStore) FindKeywordByName(_ context.Context, name string) (db.Keyword, error) {
+	id, stored, err := f.find(VocabularyKeywords, name)
+	return db.Keyword{ID: id, Name: stored}, err
+}
+
+func (f *fakeExtractionStore) InsertCategory(_ context.Context, arg db.InsertCategoryParams) error {
+	f.terms[VocabularyCategories][arg.ID] = arg.Name
+	return nil
+}
+
+func (f *fakeExtractionStore) InsertRegion(_ context.Context, arg db.InsertRegionParams) error {
+	f.terms[VocabularyRegions][arg.ID] = arg.Name
+	return nil
+}
+
+func (f *fakeExtractionStore) InsertKeyword(_ context.Context, arg db.InsertKeywordParams) error {
+	f.terms[VocabularyKeywords][arg.ID] = arg.Name
+	return nil
+}
+
+func newTestExtractionSettings(store ExtractionSettingsStore) ExtractionSettingsManager {
+	return NewExtractionSettingsService(logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}), store)
+}
+
+func TestExtractionSettings_Prompts(t *testing.T) {
+	store := newFakeExtractionStore()
+	settings := newTestExtractionSettings(store)
+	ctx := context.Background()
+	actor := Actor{ID: uuid.New(), Username: "admin"}
+
+	_, _, err := settings.Current(ctx)
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+
+	_, err = settings.SavePrompt(ctx, "Describe this document.", "", actor)
+	assert.ErrorIs(t, err, ErrInvalidPrompt, "a prompt must include the text")
+	_, err = settings.SavePrompt(ctx, "{{.Text", "", actor)
+	assert.ErrorIs(t, err, ErrInvalidPrompt)
+
+	first, err := settings.SavePrompt(ctx, "Regions: {{.Regions}}\r\n{{.Text}}", " first ", actor)
+	require.NoError(t, err)
+	second, err := settings.SavePrompt(ctx, "{{.Text}}", "shorter", actor)
+	require.NoError(t, err)
+
+	prompt, _, err := settings.Current(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "db:2", prompt.Version)
+	assert.Equal(t, "{{.Text}}", prompt.Template)
+
+	restored, err := settings.RestorePrompt(ctx, first, actor)
+	require.NoError(t, err)
+	assert.Equal(t, second+1, restored)
+
+	prompt, _, err = settings.Current(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "db:3", prompt.Version)
+	assert.Equal(t, "Regions: {{.Regions}}\n{{.Text}}", prompt.Template, "line endings are normalized")
+
+	versions, err := settings.Prompts(ctx)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, "Restored version 1", versions[0].Note)
+	assert.Equal(t, int32(1), versions[0].RestoredFrom)
+	assert.Equal(t, "first", versions[2].Note)
+	assert.Equal(t, "admin", versions[2].CreatedByName)
+
+	_, err = settings.RestorePrompt(ctx, 9, actor)
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+}
+
+func TestExtractionSettings_Vocabulary(t *testing.T) {
+	store := newFakeExtractionStore()
+	existing := uuid.New()
+	store.terms[VocabularyRegions][existing] = "South Sudan"
+	settings := newTestExtractionSettings(store)
+	ctx := context.Background()
+
+	name, err := settings.AddTerm(ctx, VocabularyRegions, "  south   sudan ")
+	require.NoError(t, err)
+	assert.Equal(t, "South Sudan", name, "an existing term is reused")
+	assert.Equal(t, []uuid.UUID{existing}, store.members[VocabularyRegions])
+
+	name, err = settings.AddTerm(ctx, VocabularyRegions, "Sahel")
+	require.NoError(t, err)
+	assert.Equal(t, "Sahel", name)
+	assert.Len(t, store.terms[VocabularyRegions], 2, "a new term is created")
+
+	_, err = settings.AddTerm(ctx, VocabularyRegions, " ")
+	assert.ErrorIs(t, err, ErrInvalidTerm)
+	_, err = settings.AddTerm(ctx, "authors", "Amy")
+	assert.ErrorIs(t, err, ErrUnknownVocabulary)
+
+	vocab, err := settings.Vocabulary(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"South Sudan", "Sahel"}, vocab.Regions)
+	assert.Empty(t, vocab.Keywords)
+
+	require.NoError(t, settings.RemoveTerm(ctx, VocabularyRegions, "south sudan"))
+	assert.Contains(t, store.terms[VocabularyRegions], existing, "the term itself is kept")
+	assert.ErrorIs(t, settings.RemoveTerm(ctx, VocabularyRegions, "South Sudan"), ErrTermNotFound)
+	assert.ErrorIs(t, settings.RemoveTerm(ctx, VocabularyRegions, "Atlantis"), ErrTermNotFound)
+	assert.ErrorIs(t, settings.RemoveTerm(ctx, VocabularyKeywords, "Sahel"), ErrTermNotFound)
+}
+
+func TestFileExtractionSettings(t *testing.T) {
+	settings, err := NewFileExtractionSettings("../../extraction/v1")
+	require.NoError(t, err)
+
+	prompt, vocab, err := settings.Current(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "file:v1", prompt.Version)
+	assert.Contains(t, vocab.Regions, "South Sudan")
+	assert.NotEmpty(t, vocab.Categories)
+	assert.NotEmpty(t, vocab.Keywords)
+
+	_, err = NewFileExtractionSettings(t.TempDir())
+	assert.Error(t, err)
+}
@@ -23,3 +23,7 @@ func (fs *FilemanagerService) UploadFile(ctx context.Context, key string, data [
 func (fs *FilemanagerService) DeleteFile(ctx context.Context, key string, bucket string) error {
 	return fs.s3Client.Delete(ctx, bucket, key)
 }
+
+func (fs *FilemanagerService) DownloadFile(ctx context.Context, key string, bucket string) ([]byte, error) {
+	return fs.s3Client.Download(ctx, bucket, key)
+}
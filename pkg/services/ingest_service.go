@@ -0,0 +1,630 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/language"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const (
+	uploadBucket = "manually-uploaded-bep"
+
+	ingestWorkerCount = 2
+	ingestBufferSize  = 100
+	ingestJobTimeout  = 10 * time.Minute
+	recentIngestJobs  = 50
+
+	previewTimeout = 2 * time.Minute
+	dateFormat     = "2006-01-02"
+)
+
+// Ingest stages, in order. A job's stage is the last one it completed.
+const (
+	IngestStageReceived          = "received"
+	IngestStageStored            = "stored"
+	IngestStageTextExtracted     = "text_extracted"
+	IngestStageMetadataExtracted = "metadata_extracted"
+	IngestStageSaved             = "saved"
+)
+
+const (
+	IngestStatusPending = "pending"
+	IngestStatusRunning = "running"
+	IngestStatusFailed  = "failed"
+	IngestStatusDone    = "done"
+)
+
+// ingestStages are the stages a job goes through after it is received.
+var ingestStages = []string{
+	IngestStageStored,
+	IngestStageTextExtracted,
+	IngestStageMetadataExtracted,
+	IngestStageSaved,
+}
+
+// ingestStageLabels are shown on the job status page.
+var ingestStageLabels = map[string]string{
+	IngestStageStored:            "Stored",
+	IngestStageTextExtracted:     "Text extracted",
+	IngestStageMetadataExtracted: "Metadata extracted",
+	IngestStageSaved:             "Saved",
+}
+
+var (
+	ErrIngestJobNotFound     = errors.New("upload job not found")
+	ErrIngestJobNotRetryable = errors.New("only failed upload jobs can be retried")
+)
+
+// IngestStore is the subset of db.Queries used by the ingest service.
+type IngestStore interface {
+	CreateIngestJob(ctx context.Context, arg db.CreateIngestJobParams) error
+	GetIngestJob(ctx context.Context, id uuid.UUID) (db.GetIngestJobRow, error)
+	GetIngestJobFile(ctx context.Context, id uuid.UUID) ([]byte, error)
+	ListRecentIngestJobs(ctx context.Context, limit int32) ([]db.ListRecentIngestJobsRow, error)
+	ListUnfinishedIngestJobs(ctx context.Context) ([]uuid.UUID, error)
+	ResetRunningIngestJobs(ctx context.Context) error
+	StartIngestJob(ctx context.Context, id uuid.UUID) (int64, error)
+	SetIngestJobStored(ctx context.Context, id uuid.UUID) error
+	SetIngestJobPages(ctx context.Context, arg db.SetIngestJobPagesParams) error
+	SetIngestJobMetadata(ctx context.Context, arg db.SetIngestJobMetadataParams) error
+	CompleteIngestJob(ctx context.Context, id uuid.UUID) error
+	FailIngestJob(ctx context.Context, arg db.FailIngestJobParams) error
+	RetryIngestJob(ctx context.Context, id uuid.UUID) (int64, error)
+	InsertMetadataExtraction(ctx context.Context, arg db.InsertMetadataExtractionParams) error
+
+	FindDocumentByID(ctx context.Context, id uuid.UUID) (db.FindDocumentByIDRow, error)
+}
+
+// FileUploader stores uploaded files in S3. It is satisfied by *FilemanagerService.
+type FileUploader interface {
+	UploadFile(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+// DocumentCreator saves a new document. It is satisfied by *repository.DocumentRepository.
+type DocumentCreator interface {
+	CreateDocument(ctx context.Context, doc repository.NewDocument, info repository.RevisionInfo) error
+}
+
+// IngestUpload is a file accepted for processing. Stored means the file is
+// already in the upload bucket under FileName. Metadata holds any fields
+// provided with the file; extraction only fills in the blank ones.
+type IngestUpload struct {
+	FileName    string
+	ContentType string
+	ContentHash string
+	Data        []byte
+	Stored      bool
+	Metadata    awskendra.ExtractedMetadata
+}
+
+// UploadedS3Path is where an uploaded file with this name is stored.
+func UploadedS3Path(fileName string) string {
+	return fmt.Sprintf("s3://%s/%s", uploadBucket, fileName)
+}
+
+type ingestService struct {
+	log          logger.Logger
+	store        IngestStore
+	files        FileUploader
+	extractor    MetadataExtractor
+	documents    DocumentCreator
+	pages        PageIndexer
+	previews     PreviewGenerator
+	auditor      Auditor
+	extractPages func(docBytes []byte) ([]string, error)
+	queue        awskendra.Queue[uuid.UUID, error]
+}
+
+// NewIngestService creates the service that processes uploads in the background.
+// Each job is stored, has its text and metadata extracted and is saved as a
+// document, recording its progress in ingest_jobs so a failed stage can be retried.
+func NewIngestService(log logger.Logger, store IngestStore, files FileUploader, extractor MetadataExtractor, documents DocumentCreator, pages PageIndexer, previews PreviewGenerator, auditor Auditor) Ingester {
+	serviceLogger := log.With("service", "Ingest")
+	s := &ingestService{
+		log:          serviceLogger,
+		store:        store,
+		files:        files,
+		extractor:    extractor,
+		documents:    documents,
+		pages:        pages,
+		previews:     previews,
+		auditor:      auditor,
+		extractPages: awskendra.ExtractPages,
+	}
+	s.queue = awskendra.NewGenericQueue(ingestWorkerCount, ingestBufferSize, serviceLogger, s.processJob)
+	return s
+}
+
+// Submit records the upload as a pending job and queues it. It returns the job ID.
+func (s *ingestService) Submit(ctx context.Context, upload IngestUpload, actor Actor) (uuid.UUID, error) {
+	provided, err := json.Marshal(upload.Metadata)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to encode provided metadata: %w", err)
+	}
+	stage := IngestStageReceived
+	if upload.Stored {
+		stage = IngestStageStored
+	}
+
+	jobID := uuid.New()
+	err = s.store.CreateIngestJob(ctx, db.CreateIngestJobParams{
+		ID:               jobID,
+		DocID:            uuid.New(),
+		FileName:         upload.FileName,
+		S3File:           UploadedS3Path(upload.FileName),
+		ContentType:      upload.ContentType,
+		ContentHash:      upload.ContentHash,
+		FileData:         upload.Data,
+		Stage:            stage,
+		ProvidedMetadata: provided,
+		CreatedBy:        uuid.NullUUID{UUID: actor.ID, Valid: actor.ID != uuid.Nil},
+		CreatedByName:    actorName(actor),
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to create upload job", "fileName", upload.FileName, "error", err)
+		return uuid.Nil, fmt.Errorf("failed to create upload job: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "Upload job created", "jobID", jobID, "fileName", upload.FileName)
+	s.enqueue(jobID)
+	return jobID, nil
+}
+
+// Job returns the job with its stages.
+func (s *ingestService) Job(ctx context.Context, id uuid.UUID) (db_types.IngestJob, error) {
+	row, err := s.store.GetIngestJob(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db_types.IngestJob{}, ErrIngestJobNotFound
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get upload job", "jobID", id, "error", err)
+		return db_types.IngestJob{}, fmt.Errorf("failed to get upload job: %w", err)
+	}
+	job := ingestJobView(row.ID, row.DocID, row.FileName, row.Stage, row.Status, row.Error, row.Attempts, row.CreatedByName, row.CreatedAt, row.UpdatedAt)
+	job.ExtractedBy = describeExtraction(row.Extractor.String, row.Model.String, row.PromptVersion.String)
+	if row.InputTokens.Int32 > 0 || row.OutputTokens.Int32 > 0 {
+		job.Usage = fmt.Sprintf("%d input and %d output tokens", row.InputTokens.Int32, row.OutputTokens.Int32)
+		if row.Cost.Valid {
+			job.Usage += ", " + formatCost(row.Cost.Float64)
+		}
+	}
+	return job, nil
+}
+
+// describeExtraction names what produced a job's metadata, such as
+// "bedrock (anthropic.claude-3-haiku, prompt db:2)".
+func describeExtraction(extractor, model, promptVersion string) string {
+	if extractor == "" {
+		return ""
+	}
+	var details []string
+	if model != "" {
+		details = append(details, model)
+	}
+	if promptVersion != "" {
+		details = append(details, "prompt "+promptVersion)
+	}
+	if len(details) == 0 {
+		return extractor
+	}
+	return fmt.Sprintf("%s (%s)", extractor, strings.Join(details, ", "))
+}
+
+// RecentJobs returns the most recent jobs, newest first.
+func (s *ingestService) RecentJobs(ctx context.Context) ([]db_types.IngestJob, error) {
+	rows, err := s.store.ListRecentIngestJobs(ctx, recentIngestJobs)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list upload jobs", "error", err)
+		return nil, fmt.Errorf("failed to list upload jobs: %w", err)
+	}
+
+	jobs := make([]db_types.IngestJob, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, ingestJobView(row.ID, row.DocID, row.FileName, row.Stage, row.Status, row.Error, row.Attempts, row.CreatedByName, row.CreatedAt, row.UpdatedAt))
+	}
+	return jobs, nil
+}
+
+// Retry queues a failed job again. It resumes after the last stage that completed.
+func (s *ingestService) Retry(ctx context.Context, id uuid.UUID) error {
+	n, err := s.store.RetryIngestJob(ctx, id)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to retry upload job", "jobID", id, "error", err)
+		return fmt.Errorf("failed to retry upload job: %w", err)
+	}
+	if n == 0 {
+		if _, err := s.store.GetIngestJob(ctx, id); errors.Is(err, sql.ErrNoRows) {
+			return ErrIngestJobNotFound
+		}
+		return ErrIngestJobNotRetryable
+	}
+
+	s.log.InfoContext(ctx, "Upload job retried", "jobID", id)
+	s.enqueue(id)
+	return nil
+}
+
+// Resume queues every unfinished job. Jobs left running by a previous process
+// are made pending again first.
+func (s *ingestService) Resume(ctx context.Context) error {
+	if err := s.store.ResetRunningIngestJobs(ctx); err != nil {
+		return fmt.Errorf("failed to reset running upload jobs: %w", err)
+	}
+	ids, err := s.store.ListUnfinishedIngestJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list unfinished upload jobs: %w", err)
+	}
+	for _, id := range ids {
+		s.enqueue(id)
+	}
+	s.log.InfoContext(ctx, "Resumed upload jobs", "jobs", len(ids))
+	return nil
+}
+
+// Shutdown stops the ingest workers.
+func (s *ingestService) Shutdown(ctx context.Context) error {
+	return s.queue.Shutdown(ctx)
+}
+
+// enqueue hands the job to the workers without waiting for room in the queue,
+// so a large batch does not hold up the request that submitted it. Jobs outlive
+// that request and are not bound to its context. A job that cannot be queued
+// stays pending and is picked up by the next Resume.
+func (s *ingestService) enqueue(id uuid.UUID) {
+	go func() {
+		resultChan := make(chan error, 1)
+		if !s.queue.Enqueue(awskendra.NewJob[uuid.UUID, error](context.Background(), id, resultChan)) {
+			s.log.Error("Failed to enqueue upload job", "jobID", id)
+		}
+	}()
+}
+
+func (s *ingestService) processJob(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, ingestJobTimeout)
+	defer cancel()
+
+	// Only one worker may pick up a pending job
+	n, err := s.store.StartIngestJob(ctx, id)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to start upload job", "jobID", id, "error", err)
+		return err
+	}
+	if n == 0 {
+		s.log.DebugContext(ctx, "Upload job is not pending", "jobID", id)
+		return nil
+	}
+
+	job, err := s.store.GetIngestJob(ctx, id)
+	if err != nil {
+		return s.fail(ctx, id, fmt.Errorf("failed to load job: %w", err))
+	}
+	data, err := s.store.GetIngestJobFile(ctx, id)
+	if err != nil {
+		return s.fail(ctx, id, fmt.Errorf("failed to load uploaded file: %w", err))
+	}
+	if len(data) == 0 {
+		return s.fail(ctx, id, errors.New("uploaded file is missing"))
+	}
+
+	if err := s.runStages(ctx, job, data); err != nil {
+		return s.fail(ctx, id, err)
+	}
+
+	if err := s.store.CompleteIngestJob(ctx, id); err != nil {
+		return s.fail(ctx, id, fmt.Errorf("failed to complete job: %w", err))
+	}
+	s.log.InfoContext(ctx, "Upload job complete", "jobID", id, "docID", job.DocID)
+	return nil
+}
+
+// runStages runs every stage after the job's last completed one.
+func (s *ingestService) runStages(ctx context.Context, job db.GetIngestJobRow, data []byte) error {
+	completed := stageIndex(job.Stage)
+
+	if completed < stageIndex(IngestStageStored) {
+		if err := s.files.UploadFile(ctx, job.FileName, data, job.ContentType); err != nil {
+			return fmt.Errorf("storing the file failed: %w", err)
+		}
+		if err := s.store.SetIngestJobStored(ctx, job.ID); err != nil {
+			return fmt.Errorf("failed to record stage: %w", err)
+		}
+	}
+
+	pages := job.Pages
+	if completed < stageIndex(IngestStageTextExtracted) {
+		var err error
+		pages, err = s.extractPages(data)
+		if err != nil {
+			return fmt.Errorf("text extraction failed: %w", err)
+		}
+		if pages == nil {
+			pages = []string{}
+		}
+		if err := s.store.SetIngestJobPages(ctx, db.SetIngestJobPagesParams{ID: job.ID, Pages: pages}); err != nil {
+			return fmt.Errorf("failed to record stage: %w", err)
+		}
+	}
+
+	var metadata awskendra.ExtractedMetadata
+	if completed < stageIndex(IngestStageMetadataExtracted) {
+		var err error
+		metadata, err = s.extractMetadata(ctx, job, data)
+		if err != nil {
+			return err
+		}
+
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		if err := s.store.SetIngestJobMetadata(ctx, db.SetIngestJobMetadataParams{ID: job.ID, Metadata: raw}); err != nil {
+			return fmt.Errorf("failed to record stage: %w", err)
+		}
+	} else if err := json.Unmarshal(job.Metadata, &metadata); err != nil {
+		return fmt.Errorf("failed to decode stored metadata: %w", err)
+	}
+
+	return s.save(ctx, job, metadata, pages, data)
+}
+
+// extractMetadata fills in the fields not provided with the upload, skipping
+// extraction entirely when every field was provided. It records which
+// extractor, model and prompt version produced the metadata and what it cost,
+// and records the cost of extractions that failed after using a model.
+func (s *ingestService) extractMetadata(ctx context.Context, job db.GetIngestJobRow, data []byte) (awskendra.ExtractedMetadata, error) {
+	var provided awskendra.ExtractedMetadata
+	if err := json.Unmarshal(job.ProvidedMetadata, &provided); err != nil {
+		return provided, fmt.Errorf("failed to decode provided metadata: %w", err)
+	}
+	if len(MissingMetadata(provided)) == 0 {
+		return provided, nil
+	}
+
+	extraction, err := s.extractor.ExtractMetadata(ctx, data)
+	for _, failed := range failedExtractions(err) {
+		if recordErr := s.recordExtraction(ctx, job, failed.Extraction, failed.Err); recordErr != nil {
+			s.log.ErrorContext(ctx, "Failed to record failed extraction", "jobID", job.ID, "error", recordErr)
+		}
+	}
+	if err != nil {
+		return provided, fmt.Errorf("metadata extraction failed: %w", err)
+	}
+
+	if err := s.recordExtraction(ctx, job, extraction, nil); err != nil {
+		return provided, fmt.Errorf("failed to record extraction: %w", err)
+	}
+	return MergeMetadata(provided, extraction.Metadata), nil
+}
+
+func (s *ingestService) recordExtraction(ctx context.Context, job db.GetIngestJobRow, extraction *awskendra.Extraction, extractErr error) error {
+	params := db.InsertMetadataExtractionParams{
+		JobID:         uuid.NullUUID{UUID: job.ID, Valid: true},
+		DocID:         job.DocID,
+		Extractor:     extraction.Extractor,
+		Model:         extraction.Model,
+		PromptVersion: extraction.PromptVersion,
+		InputTokens:   int32(extraction.InputTokens),
+		OutputTokens:  int32(extraction.OutputTokens),
+		LatencyMs:     int32(extraction.Latency.Milliseconds()),
+		CreatedBy:     job.CreatedBy,
+		CreatedByName: job.CreatedByName,
+	}
+	if extraction.Cost != nil {
+		params.Cost = sql.NullFloat64{Float64: *extraction.Cost, Valid: true}
+	}
+	if extractErr != nil {
+		params.Error = sql.NullString{String: extractErr.Error(), Valid: true}
+	}
+	return s.store.InsertMetadataExtraction(ctx, params)
+}
+
+// failedExtractions finds the extractions in err that used a model before
+// failing. A fallback extractor joins one error per extractor it tried.
+func failedExtractions(err error) []*awskendra.ExtractionError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *awskendra.ExtractionError:
+		return []*awskendra.ExtractionError{e}
+	case interface{ Unwrap() []error }:
+		var all []*awskendra.ExtractionError
+		for _, inner := range e.Unwrap() {
+			all = append(all, failedExtractions(inner)...)
+		}
+		return all
+	default:
+		return failedExtractions(errors.Unwrap(err))
+	}
+}
+
+// metadataSources says where each filled field of merged came from: the
+// provided metadata, written by a person, or a model. Blank fields are left out.
+func metadataSources(provided, merged awskendra.ExtractedMetadata) map[string]string {
+	sources := make(map[string]string)
+	for _, field := range MissingMetadata(awskendra.ExtractedMetadata{}) {
+		sources[field] = repository.SourceHuman
+	}
+	for _, field := range MissingMetadata(provided) {
+		sources[field] = repository.SourceLLM
+	}
+	for _, field := range MissingMetadata(merged) {
+		delete(sources, field)
+	}
+	// Manifests have no columns for the English title and abstract, so only a
+	// model writes them
+	if merged.EnglishTitle != "" {
+		sources["english_title"] = repository.SourceLLM
+	}
+	if merged.EnglishAbstract != "" {
+		sources["english_abstract"] = repository.SourceLLM
+	}
+	return sources
+}
+
+// save creates the document unless an earlier attempt already did, then stores
+// its page text and renders its preview.
+func (s *ingestService) save(ctx context.Context, job db.GetIngestJobRow, metadata awskendra.ExtractedMetadata, pages []string, data []byte) error {
+	actor := Actor{ID: job.CreatedBy.UUID, Username: job.CreatedByName}
+
+	_, err := s.store.FindDocumentByID(ctx, job.DocID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		var provided awskendra.ExtractedMetadata
+		if err := json.Unmarshal(job.ProvidedMetadata, &provided); err != nil {
+			return fmt.Errorf("failed to decode provided metadata: %w", err)
+		}
+		lang := language.ForDocument(strings.Join(pages, "\n"), job.S3File)
+		if lang == language.English {
+			// A model sometimes translates an English document into itself
+			metadata.EnglishTitle, metadata.EnglishAbstract = "", ""
+		}
+		if err := s.documents.CreateDocument(ctx, repository.NewDocument{
+			ID:              job.DocID,
+			S3File:          job.S3File,
+			FileName:        job.FileName,
+			Title:           metadata.Title,
+			Abstract:        sql.NullString{String: metadata.Abstract, Valid: true},
+			PublishDate:     s.parsePublishDate(ctx, metadata.PublishDate),
+			ContentHash:     sql.NullString{String: job.ContentHash, Valid: true},
+			Language:        sql.NullString{String: lang, Valid: lang != ""},
+			EnglishTitle:    sql.NullString{String: metadata.EnglishTitle, Valid: metadata.EnglishTitle != ""},
+			EnglishAbstract: sql.NullString{String: metadata.EnglishAbstract, Valid: metadata.EnglishAbstract != ""},
+			Terms: repository.Terms{
+				Authors:    repository.ByName(metadata.AuthorName),
+				Keywords:   repository.ByName(metadata.KeywordName),
+				Categories: repository.ByName(metadata.CategoryName),
+				Regions:    repository.ByName(metadata.RegionName),
+				Publisher:  repository.PublisherByName(metadata.Source),
+			},
+			Sources: metadataSources(provided, metadata),
+		}, repository.RevisionInfo{CreatedBy: actor.ID, CreatedByName: actor.Username}); err != nil {
+			return fmt.Errorf("saving the document failed: %w", err)
+		}
+
+		var snapshot map[string]any
+		if doc, err := s.store.FindDocumentByID(ctx, job.DocID); err == nil {
+			snapshot = DocumentSnapshot(doc)
+		} else {
+			s.log.WarnContext(ctx, "Failed to read document for audit log", "docID", job.DocID, "error", err)
+		}
+		s.auditor.Record(ctx, actor, AuditEvent{
+			Action:  AuditDocumentUploaded,
+			DocID:   job.DocID,
+			Target:  job.FileName,
+			Changes: Diff(nil, snapshot),
+		})
+	case err != nil:
+		return fmt.Errorf("saving the document failed: %w", err)
+	default:
+		s.log.InfoContext(ctx, "Document already saved by an earlier attempt", "jobID", job.ID, "docID", job.DocID)
+	}
+
+	// Page text is only used for excerpts; the document is usable without it
+	if _, err := s.pages.SavePages(ctx, job.DocID, pages); err != nil {
+		s.log.WarnContext(ctx, "Failed to save document pages", "docID", job.DocID, "error", err)
+	}
+
+	// to_generate_preview stays set on failure so the preview backfill picks
+	// the document up later
+	go s.generatePreview(job.DocID, job.S3File, data)
+	return nil
+}
+
+func (s *ingestService) generatePreview(docID uuid.UUID, s3Path string, data []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+	if _, err := s.previews.GeneratePreview(ctx, docID, s3Path, data); err != nil {
+		s.log.WarnContext(ctx, "Failed to generate preview", "docID", docID, "error", err)
+	}
+}
+
+func (s *ingestService) fail(ctx context.Context, id uuid.UUID, cause error) error {
+	s.log.ErrorContext(ctx, "Upload job failed", "jobID", id, "error", cause)
+	// The job context may have timed out, but the failure still needs recording
+	err := s.store.FailIngestJob(context.WithoutCancel(ctx), db.FailIngestJobParams{
+		ID:    id,
+		Error: sql.NullString{String: cause.Error(), Valid: true},
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to record upload job failure", "jobID", id, "error", err)
+	}
+	return cause
+}
+
+func (s *ingestService) parsePublishDate(ctx context.Context, raw string) sql.NullTime {
+	if raw == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(dateFormat, raw)
+	if err != nil {
+		s.log.WarnContext(ctx, "Invalid publish date format", "value", raw, "error", err)
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// stageIndex returns the position of stage in ingestStages, or -1 for
+// IngestStageReceived.
+func stageIndex(stage string) int {
+	for i, s := range ingestStages {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// ingestJobStages reports the state of every stage: done up to the last
+// completed stage, then running or failed for the next one if the job is.
+func ingestJobStages(stage, status string) []db_types.IngestStage {
+	completed := stageIndex(stage)
+	stages := make([]db_types.IngestStage, len(ingestStages))
+	for i, name := range ingestStages {
+		state := db_types.IngestStatePending
+		switch {
+		case i <= completed:
+			state = db_types.IngestStateDone
+		case i == completed+1 && status == IngestStatusRunning:
+			state = db_types.IngestStateRunning
+		case i == completed+1 && status == IngestStatusFailed:
+			state = db_types.IngestStateFailed
+		}
+		stages[i] = db_types.IngestStage{Label: ingestStageLabels[name], State: state}
+	}
+	return stages
+}
+
+func ingestJobView(id, docID uuid.UUID, fileName, stage, status string, errMsg sql.NullString, attempts int32, createdByName string, createdAt, updatedAt time.Time) db_types.IngestJob {
+	return db_types.IngestJob{
+		ID:            id.String(),
+		DocID:         docID.String(),
+		FileName:      fileName,
+		Status:        status,
+		Error:         errMsg.String,
+		Attempts:      attempts,
+		CreatedByName: createdByName,
+		CreatedAt:     createdAt.Format("2006-01-02 15:04"),
+		UpdatedAt:     updatedAt.Format("2006-01-02 15:04:05"),
+		Stages:        ingestJobStages(stage, status),
+	}
+}
+
+func actorName(actor Actor) string {
+	if actor.Username == "" {
+		return "unknown"
+	}
+	return actor.Username
+}
@@ -0,0 +1,611 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeIngestStore struct {
+	jobs        map[uuid.UUID]*db.IngestJob
+	docs        map[uuid.UUID]repository.NewDocument
+	extractions []db.InsertMetadataExtractionParams
+}
+
+func newFakeIngestStore() *fakeIngestStore {
+	return &fakeIngestStore{
+		jobs: map[uuid.UUID]*db.IngestJob{},
+		docs: map[uuid.UUID]repository.NewDocument{},
+	}
+}
+
+func (f *fakeIngestStore) InsertMetadataExtraction(_ context.Context, arg db.InsertMetadataExtractionParams) error {
+	f.extractions = append(f.extractions, arg)
+	return nil
+}
+
+func (f *fakeIngestStore) CreateIngestJob(_ context.Context, arg db.CreateIngestJobParams) error {
+	f.jobs[arg.ID] = &db.IngestJob{
+		ID:               arg.ID,
+		DocID:            arg.DocID,
+		FileName:         arg.FileName,
+		S3File:           arg.S3File,
+		ContentType:      arg.ContentType,
+		ContentHash:      arg.ContentHash,
+		FileData:         arg.FileData,
+		Stage:            arg.Stage,
+		Status:           IngestStatusPending,
+		Metadata:         []byte("{}"),
+		ProvidedMetadata: arg.ProvidedMetadata,
+		CreatedBy:        arg.CreatedBy,
+		CreatedByName:    arg.CreatedByName,
+	}
+	return nil
+}
+
+func (f *fakeIngestStore) GetIngestJob(_ context.Context, id uuid.UUID) (db.GetIngestJobRow, error) {
+	job, ok := f.jobs[id]
+	if !ok {
+		return db.GetIngestJobRow{}, sql.ErrNoRows
+	}
+	return db.GetIngestJobRow{
+		ID:               job.ID,
+		DocID:            job.DocID,
+		FileName:         job.FileName,
+		S3File:           job.S3File,
+		ContentType:      job.ContentType,
+		ContentHash:      job.ContentHash,
+		Stage:            job.Stage,
+		Status:           job.Status,
+		Pages:            job.Pages,
+		Metadata:         job.Metadata,
+		ProvidedMetadata: job.ProvidedMetadata,
+		Error:            job.Error,
+		Attempts:         job.Attempts,
+		CreatedBy:        job.CreatedBy,
+		CreatedByName:    job.CreatedByName,
+	}, nil
+}
+
+func (f *fakeIngestStore) GetIngestJobFile(_ context.Context, id uuid.UUID) ([]byte, error) {
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return job.FileData, nil
+}
+
+func (f *fakeIngestStore) ListRecentIngestJobs(context.Context, int32) ([]db.ListRecentIngestJobsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeIngestStore) ListUnfinishedIngestJobs(context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, job := range f.jobs {
+		if job.Status == IngestStatusPending || job.Status == IngestStatusRunning {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeIngestStore) ResetRunningIngestJobs(context.Context) error {
+	for _, job := range f.jobs {
+		if job.Status == IngestStatusRunning {
+			job.Status = IngestStatusPending
+		}
+	}
+	return nil
+}
+
+func (f *fakeIngestStore) StartIngestJob(_ context.Context, id uuid.UUID) (int64, error) {
+	job, ok := f.jobs[id]
+	if !ok || job.Status != IngestStatusPending {
+		return 0, nil
+	}
+	job.Status = IngestStatusRunning
+	job.Error = sql.NullString{}
+	job.Attempts++
+	return 1, nil
+}
+
+func (f *fakeIngestStore) SetIngestJobStored(_ context.Context, id uuid.UUID) error {
+	f.jobs[id].Stage = IngestStageStored
+	return nil
+}
+
+func (f *fakeIngestStore) SetIngestJobPages(_ context.Context, arg db.SetIngestJobPagesParams) error {
+	f.jobs[arg.ID].Stage = IngestStageTextExtracted
+	f.jobs[arg.ID].Pages = arg.Pages
+	return nil
+}
+
+func (f *fakeIngestStore) SetIngestJobMetadata(_ context.Context, arg db.SetIngestJobMetadataParams) error {
+	f.jobs[arg.ID].Stage = IngestStageMetadataExtracted
+	f.jobs[arg.ID].Metadata = arg.Metadata
+	return nil
+}
+
+func (f *fakeIngestStore) CompleteIngestJob(_ context.Context, id uuid.UUID) error {
+	job := f.jobs[id]
+	job.Stage = IngestStageSaved
+	job.Status = IngestStatusDone
+	job.FileData = nil
+	job.Pages = nil
+	return nil
+}
+
+func (f *fakeIngestStore) FailIngestJob(_ context.Context, arg db.FailIngestJobParams) error {
+	f.jobs[arg.ID].Status = IngestStatusFailed
+	f.jobs[arg.ID].Error = arg.Error
+	return nil
+}
+
+func (f *fakeIngestStore) RetryIngestJob(_ context.Context, id uuid.UUID) (int64, error) {
+	job, ok := f.jobs[id]
+	if !ok || job.Status != IngestStatusFailed {
+		return 0, nil
+	}
+	job.Status = IngestStatusPending
+	job.Error = sql.NullString{}
+	return 1, nil
+}
+
+func (f *fakeIngestStore) FindDocumentByID(_ context.Context, id uuid.UUID) (db.FindDocumentByIDRow, error) {
+	doc, ok := f.docs[id]
+	if !ok {
+		return db.FindDocumentByIDRow{}, sql.ErrNoRows
+	}
+	return db.FindDocumentByIDRow{ID: doc.ID, Title: doc.Title}, nil
+}
+
+// CreateDocument lets the store act as the DocumentCreator too.
+func (f *fakeIngestStore) CreateDocument(_ context.Context, doc repository.NewDocument, _ repository.RevisionInfo) error {
+	f.docs[doc.ID] = doc
+	return nil
+}
+
+type fakeUploader struct{ uploads int }
+
+func (f *fakeUploader) UploadFile(context.Context, string, []byte, string) error {
+	f.uploads++
+	return nil
+}
+
+type fakeExtractor struct {
+	calls int
+	errs  []error
+}
+
+func (f *fakeExtractor) Name() string { return "fake" }
+
+func (f *fakeExtractor) ExtractMetadata(context.Context, []byte) (*awskendra.Extraction, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return &awskendra.Extraction{
+		Metadata: awskendra.ExtractedMetadata{
+			Title:       "Peacebuilding in Practice",
+			Abstract:    "About",
+			PublishDate: "2024-03-01",
+			AuthorName:  []string{"Amy"},
+			KeywordName: []string{"peace"},
+		},
+		Extractor:     "fake",
+		Model:         "fake-model",
+		PromptVersion: "db:1",
+	}, nil
+}
+
+type fakePageSaver struct{ saved map[uuid.UUID][]string }
+
+func (f *fakePageSaver) IndexDocumentPages(context.Context, uuid.UUID, []byte) (int, error) {
+	return 0, nil
+}
+
+func (f *fakePageSaver) SavePages(_ context.Context, docID uuid.UUID, pages []string) (int, error) {
+	f.saved[docID] = pages
+	return len(pages), nil
+}
+
+type fakePreviews struct{}
+
+func (fakePreviews) GeneratePreview(context.Context, uuid.UUID, string, []byte) (string, error) {
+	return "", nil
+}
+
+type fakeAuditor struct{ events []AuditEvent }
+
+func (f *fakeAuditor) Record(_ context.Context, _ Actor, event AuditEvent) {
+	f.events = append(f.events, event)
+}
+
+func (f *fakeAuditor) DocumentHistory(context.Context, uuid.UUID) ([]db_types.AuditEntry, error) {
+	return nil, nil
+}
+
+type fakeJobQueue struct {
+	mu     sync.Mutex
+	queued []uuid.UUID
+}
+
+func (f *fakeJobQueue) Enqueue(job awskendra.Job[uuid.UUID, error]) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queued = append(f.queued, job.Payload)
+	return true
+}
+
+// waitFor waits until n jobs have been queued, since jobs are queued in the background.
+func (f *fakeJobQueue) waitFor(t *testing.T, n int) []uuid.UUID {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return len(f.queued) >= n
+	}, time.Second, time.Millisecond)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.queued)
+}
+
+func (f *fakeJobQueue) Shutdown(context.Context) error { return nil }
+
+type ingestFixture struct {
+	service   *ingestService
+	store     *fakeIngestStore
+	uploader  *fakeUploader
+	extractor *fakeExtractor
+	pages     *fakePageSaver
+	auditor   *fakeAuditor
+	queue     *fakeJobQueue
+	extracted int
+}
+
+func newIngestFixture() *ingestFixture {
+	f := &ingestFixture{
+		store:     newFakeIngestStore(),
+		uploader:  &fakeUploader{},
+		extractor: &fakeExtractor{},
+		pages:     &fakePageSaver{saved: map[uuid.UUID][]string{}},
+		auditor:   &fakeAuditor{},
+		queue:     &fakeJobQueue{},
+	}
+	f.service = &ingestService{
+		log:       logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}),
+		store:     f.store,
+		files:     f.uploader,
+		extractor: f.extractor,
+		documents: f.store,
+		pages:     f.pages,
+		previews:  fakePreviews{},
+		auditor:   f.auditor,
+		extractPages: func([]byte) ([]string, error) {
+			f.extracted++
+			return []string{"page one", "page two"}, nil
+		},
+		queue: f.queue,
+	}
+	return f
+}
+
+func (f *ingestFixture) submit(t *testing.T) uuid.UUID {
+	t.Helper()
+	id, err := f.service.Submit(context.Background(), IngestUpload{
+		FileName:    "report.pdf",
+		ContentType: "application/pdf",
+		ContentHash: "abc",
+		Data:        []byte("%PDF"),
+	}, Actor{ID: uuid.New(), Username: "editor"})
+	require.NoError(t, err)
+	return id
+}
+
+func TestIngestService_Submit(t *testing.T) {
+	f := newIngestFixture()
+	id := f.submit(t)
+
+	job := f.store.jobs[id]
+	assert.Equal(t, IngestStatusPending, job.Status)
+	assert.Equal(t, IngestStageReceived, job.Stage)
+	assert.Equal(t, UploadedS3Path("report.pdf"), job.S3File)
+	assert.Equal(t, "editor", job.CreatedByName)
+	assert.Equal(t, []uuid.UUID{id}, f.queue.waitFor(t, 1))
+}
+
+func TestIngestService_processJob(t *testing.T) {
+	f := newIngestFixture()
+	id := f.submit(t)
+	ctx := context.Background()
+
+	require.NoError(t, f.service.processJob(ctx, id))
+
+	job := f.store.jobs[id]
+	assert.Equal(t, IngestStatusDone, job.Status)
+	assert.Equal(t, IngestStageSaved, job.Stage)
+	assert.Nil(t, job.FileData, "file data is cleared once saved")
+	assert.Equal(t, 1, f.uploader.uploads)
+
+	doc, ok := f.store.docs[job.DocID]
+	require.True(t, ok)
+	assert.Equal(t, "Peacebuilding in Practice", doc.Title)
+	assert.Equal(t, "abc", doc.ContentHash.String)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), doc.PublishDate.Time)
+	assert.Equal(t, []string{"new:Amy"}, doc.Terms.Authors)
+	assert.Equal(t, []string{"page one", "page two"}, f.pages.saved[job.DocID])
+
+	require.Len(t, f.store.extractions, 1)
+	extraction := f.store.extractions[0]
+	assert.Equal(t, uuid.NullUUID{UUID: id, Valid: true}, extraction.JobID)
+	assert.Equal(t, job.DocID, extraction.DocID)
+	assert.Equal(t, "fake", extraction.Extractor)
+	assert.Equal(t, "fake-model", extraction.Model)
+	assert.Equal(t, "db:1", extraction.PromptVersion)
+
+	require.Len(t, f.auditor.events, 1)
+	assert.Equal(t, AuditDocumentUploaded, f.auditor.events[0].Action)
+
+	// A job that is no longer pending is left alone
+	require.NoError(t, f.service.processJob(ctx, id))
+	assert.Equal(t, 1, f.uploader.uploads)
+}
+
+func TestIngestService_RetryResumesFailedStage(t *testing.T) {
+	f := newIngestFixture()
+	failed := &awskendra.Extraction{Extractor: "fake", Model: "fake-model", InputTokens: 900, OutputTokens: 20}
+	f.extractor.errs = []error{errors.Join(
+		errors.New("model server is down"),
+		fmt.Errorf("fake: %w", &awskendra.ExtractionError{Extraction: failed, Err: errors.New("invalid JSON in model response")}),
+	)}
+	id := f.submit(t)
+	ctx := context.Background()
+
+	require.Error(t, f.service.processJob(ctx, id))
+	require.Len(t, f.store.extractions, 1, "the failed extraction is recorded since its tokens were paid for")
+	assert.EqualValues(t, 900, f.store.extractions[0].InputTokens)
+	assert.Equal(t, "invalid JSON in model response", f.store.extractions[0].Error.String)
+	assert.False(t, f.store.extractions[0].Cost.Valid)
+
+	job := f.store.jobs[id]
+	assert.Equal(t, IngestStatusFailed, job.Status)
+	assert.Equal(t, IngestStageTextExtracted, job.Stage)
+	assert.Contains(t, job.Error.String, "metadata extraction failed")
+	assert.Empty(t, f.store.docs)
+
+	view, err := f.service.Job(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []db_types.IngestStage{
+		{Label: "Stored", State: db_types.IngestStateDone},
+		{Label: "Text extracted", State: db_types.IngestStateDone},
+		{Label: "Metadata extracted", State: db_types.IngestStateFailed},
+		{Label: "Saved", State: db_types.IngestStatePending},
+	}, view.Stages)
+
+	require.NoError(t, f.service.Retry(ctx, id))
+	assert.Equal(t, []uuid.UUID{id, id}, f.queue.waitFor(t, 2))
+	require.NoError(t, f.service.processJob(ctx, id))
+
+	assert.Equal(t, IngestStatusDone, job.Status)
+	assert.EqualValues(t, 2, job.Attempts)
+	assert.Equal(t, 1, f.uploader.uploads, "the file is not stored again")
+	assert.Equal(t, 1, f.extracted, "text is not extracted again")
+	assert.Equal(t, 2, f.extractor.calls)
+	assert.Len(t, f.store.docs, 1)
+}
+
+func TestIngestService_SaveSkipsExistingDocument(t *testing.T) {
+	f := newIngestFixture()
+	id := f.submit(t)
+	ctx := context.Background()
+
+	// An earlier attempt saved the document but failed before completing the job
+	job := f.store.jobs[id]
+	job.Stage = IngestStageMetadataExtracted
+	job.Metadata = []byte(`{"title":"Stored title"}`)
+	job.Pages = []string{"text"}
+	f.store.docs[job.DocID] = repository.NewDocument{ID: job.DocID, Title: "Stored title"}
+
+	require.NoError(t, f.service.processJob(ctx, id))
+	assert.Equal(t, IngestStatusDone, job.Status)
+	assert.Len(t, f.store.docs, 1)
+	assert.Empty(t, f.auditor.events)
+	assert.Zero(t, f.extractor.calls)
+}
+
+func TestIngestService_DetectsLanguage(t *testing.T) {
+	french := "L'accord de paix a été signé en 2016 et il est salué par les observateurs pour la place qu'il accorde aux victimes dans le processus de justice transitionnelle, qui a été conçu avec leur participation."
+	english := "The peace agreement was signed in 2016 and it has been praised by observers for the way that it placed victims at the centre of the transitional justice process, which was designed with their participation."
+	tests := []struct {
+		name         string
+		pages        []string
+		language     string
+		englishTitle string
+	}{
+		{"french", []string{french}, "fr", "Peace Agreement"},
+		{"english drops the translation", []string{english}, "en", ""},
+		{"undetected", []string{"scan"}, "", "Peace Agreement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newIngestFixture()
+			id := f.submit(t)
+			job := f.store.jobs[id]
+			job.Stage = IngestStageMetadataExtracted
+			job.Metadata = []byte(`{"title":"Accord de paix","english_title":"Peace Agreement"}`)
+			job.Pages = tt.pages
+
+			require.NoError(t, f.service.processJob(context.Background(), id))
+
+			doc := f.store.docs[job.DocID]
+			assert.Equal(t, tt.language, doc.Language.String)
+			assert.Equal(t, tt.language != "", doc.Language.Valid)
+			assert.Equal(t, "Accord de paix", doc.Title)
+			assert.Equal(t, tt.englishTitle, doc.EnglishTitle.String)
+		})
+	}
+}
+
+func TestIngestService_ProvidedMetadata(t *testing.T) {
+	complete := awskendra.ExtractedMetadata{
+		Title:        "From the manifest",
+		Abstract:     "Summary",
+		PublishDate:  "2021-06-30",
+		Source:       "UNDP",
+		AuthorName:   []string{"Zed"},
+		KeywordName:  []string{"mediation"},
+		RegionName:   []string{"Kenya"},
+		CategoryName: []string{"Report"},
+	}
+
+	tests := []struct {
+		name          string
+		provided      awskendra.ExtractedMetadata
+		wantTitle     string
+		wantAuthors   []string
+		wantKeywords  []string
+		wantSources   map[string]string
+		wantExtracted int
+	}{
+		{
+			name:         "provided fields override extraction",
+			provided:     awskendra.ExtractedMetadata{Title: "From the manifest", AuthorName: []string{"Zed"}},
+			wantTitle:    "From the manifest",
+			wantAuthors:  []string{"new:Zed"},
+			wantKeywords: []string{"new:peace"},
+			wantSources: map[string]string{
+				"title":        repository.SourceHuman,
+				"authors":      repository.SourceHuman,
+				"abstract":     repository.SourceLLM,
+				"publish_date": repository.SourceLLM,
+				"keywords":     repository.SourceLLM,
+			},
+			wantExtracted: 1,
+		},
+		{
+			name:         "complete metadata skips extraction",
+			provided:     complete,
+			wantTitle:    "From the manifest",
+			wantAuthors:  []string{"new:Zed"},
+			wantKeywords: []string{"new:mediation"},
+			wantSources: map[string]string{
+				"title":        repository.SourceHuman,
+				"abstract":     repository.SourceHuman,
+				"publish_date": repository.SourceHuman,
+				"source":       repository.SourceHuman,
+				"authors":      repository.SourceHuman,
+				"keywords":     repository.SourceHuman,
+				"regions":      repository.SourceHuman,
+				"categories":   repository.SourceHuman,
+			},
+			wantExtracted: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newIngestFixture()
+			ctx := context.Background()
+			id, err := f.service.Submit(ctx, IngestUpload{
+				FileName: "report.pdf",
+				Data:     []byte("%PDF"),
+				Metadata: tt.provided,
+			}, Actor{})
+			require.NoError(t, err)
+
+			require.NoError(t, f.service.processJob(ctx, id))
+
+			doc := f.store.docs[f.store.jobs[id].DocID]
+			assert.Equal(t, tt.wantTitle, doc.Title)
+			assert.Equal(t, tt.wantAuthors, doc.Terms.Authors)
+			assert.Equal(t, tt.wantKeywords, doc.Terms.Keywords)
+			assert.Equal(t, tt.wantSources, doc.Sources)
+			assert.Equal(t, tt.wantExtracted, f.extractor.calls)
+			assert.Len(t, f.store.extractions, tt.wantExtracted)
+		})
+	}
+}
+
+func TestIngestService_StoredUploadIsNotStoredAgain(t *testing.T) {
+	f := newIngestFixture()
+	ctx := context.Background()
+	id, err := f.service.Submit(ctx, IngestUpload{
+		FileName: "archive/report.pdf",
+		Data:     []byte("%PDF"),
+		Stored:   true,
+	}, Actor{})
+	require.NoError(t, err)
+	assert.Equal(t, IngestStageStored, f.store.jobs[id].Stage)
+
+	require.NoError(t, f.service.processJob(ctx, id))
+	assert.Equal(t, IngestStatusDone, f.store.jobs[id].Status)
+	assert.Zero(t, f.uploader.uploads)
+}
+
+func TestIngestService_Retry(t *testing.T) {
+	f := newIngestFixture()
+	id := f.submit(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, f.service.Retry(ctx, id), ErrIngestJobNotRetryable)
+	assert.ErrorIs(t, f.service.Retry(ctx, uuid.New()), ErrIngestJobNotFound)
+}
+
+func TestIngestService_Resume(t *testing.T) {
+	f := newIngestFixture()
+	id := f.submit(t)
+	f.queue.waitFor(t, 1)
+	f.store.jobs[id].Status = IngestStatusRunning
+
+	require.NoError(t, f.service.Resume(context.Background()))
+	assert.Equal(t, IngestStatusPending, f.store.jobs[id].Status)
+	assert.Equal(t, []uuid.UUID{id, id}, f.queue.waitFor(t, 2))
+}
+
+func TestIngestJobStages(t *testing.T) {
+	states := func(stages []db_types.IngestStage) []string {
+		out := make([]string, len(stages))
+		for i, s := range stages {
+			out[i] = s.State
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		stage  string
+		status string
+		want   []string
+	}{
+		{name: "queued", stage: IngestStageReceived, status: IngestStatusPending, want: []string{"pending", "pending", "pending", "pending"}},
+		{name: "storing", stage: IngestStageReceived, status: IngestStatusRunning, want: []string{"running", "pending", "pending", "pending"}},
+		{name: "storage failed", stage: IngestStageReceived, status: IngestStatusFailed, want: []string{"failed", "pending", "pending", "pending"}},
+		{name: "extracting metadata", stage: IngestStageTextExtracted, status: IngestStatusRunning, want: []string{"done", "done", "running", "pending"}},
+		{name: "save failed", stage: IngestStageMetadataExtracted, status: IngestStatusFailed, want: []string{"done", "done", "done", "failed"}},
+		{name: "done", stage: IngestStageSaved, status: IngestStatusDone, want: []string{"done", "done", "done", "done"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, states(ingestJobStages(tt.stage, tt.status)))
+		})
+	}
+}
@@ -6,6 +6,9 @@ import (
 
 	"github.com/DSSD-Madison/gmu/pkg/awskendra"
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -34,8 +37,78 @@ type FileManager interface {
 	UpdateMetadata()
 }
 
-type BedrockManager interface {
-	ExtractPDFMetadata(ctx context.Context, pdfBytes []byte) (*awskendra.ExtractedMetadata, error)
+type MetadataExtractor interface {
+	Name() string
+	ExtractMetadata(ctx context.Context, docBytes []byte) (*awskendra.Extraction, error)
+}
+
+// ExtractionSettings supplies the prompt and vocabularies for each extraction.
+type ExtractionSettings interface {
+	Current(ctx context.Context) (awskendra.Prompt, awskendra.Vocabulary, error)
+}
+
+type ExtractionSettingsManager interface {
+	ExtractionSettings
+	Prompts(ctx context.Context) ([]db_types.PromptVersion, error)
+	Prompt(ctx context.Context, version int32) (db_types.PromptVersion, error)
+	SavePrompt(ctx context.Context, template, note string, actor Actor) (int32, error)
+	RestorePrompt(ctx context.Context, version int32, actor Actor) (int32, error)
+	Vocabulary(ctx context.Context) (awskendra.Vocabulary, error)
+	AddTerm(ctx context.Context, vocabulary, name string) (string, error)
+	RemoveTerm(ctx context.Context, vocabulary, name string) error
+}
+
+type PageIndexer interface {
+	IndexDocumentPages(ctx context.Context, docID uuid.UUID, docBytes []byte) (int, error)
+	SavePages(ctx context.Context, docID uuid.UUID, pages []string) (int, error)
+}
+
+type PreviewGenerator interface {
+	GeneratePreview(ctx context.Context, docID uuid.UUID, s3URI string, docBytes []byte) (string, error)
+}
+
+type PreviewRenderer interface {
+	RenderFirstPage(ctx context.Context, docBytes []byte, ext string) ([]byte, error)
+}
+
+type DuplicateFinder interface {
+	FindByContentHash(ctx context.Context, hash string) (db.FindDocumentByContentHashRow, bool, error)
+	FindDuplicateGroups(ctx context.Context) ([]db_types.DuplicateGroup, error)
+	KeepDocument(ctx context.Context, keepID uuid.UUID, groupIDs []uuid.UUID) error
+	MarkForDeletion(ctx context.Context, docID uuid.UUID) error
+}
+
+type ReviewQueue interface {
+	Queue(ctx context.Context) (db_types.ReviewQueue, error)
+	Review(ctx context.Context, doc db.FindDocumentByIDRow) (db_types.DocumentReview, error)
+}
+
+type TermManager interface {
+	Suggest(ctx context.Context, kind, query string) ([]taxonomy.Term, error)
+	Pending(ctx context.Context) ([]db_types.PendingTerm, error)
+	Approve(ctx context.Context, kind string, id uuid.UUID) error
+	MapTo(ctx context.Context, kind string, id uuid.UUID, name string) (string, error)
+	AddAlias(ctx context.Context, kind, name, alias string) (string, error)
+	List(ctx context.Context, kind, query string) (db_types.TermList, error)
+	Term(ctx context.Context, kind string, id uuid.UUID) (db_types.TermDetail, error)
+	Rename(ctx context.Context, kind string, id uuid.UUID, name string) error
+	Split(ctx context.Context, kind string, id uuid.UUID, name string, docIDs []uuid.UUID) (string, error)
+	Delete(ctx context.Context, kind string, id uuid.UUID) error
+	SetRegionParent(ctx context.Context, id uuid.UUID, parent string) (string, error)
+}
+
+type AuthorDirectory interface {
+	Profile(ctx context.Context, id uuid.UUID) (db_types.AuthorProfile, error)
+	CheckORCID(ctx context.Context, id uuid.UUID, orcid string) (Changes, error)
+	SetDetails(ctx context.Context, id uuid.UUID, orcid, affiliation string) (Changes, error)
+}
+
+type PublisherDirectory interface {
+	Profile(ctx context.Context, id uuid.UUID) (db_types.PublisherProfile, error)
+}
+
+type SpendReporter interface {
+	Report(ctx context.Context, days int) (db_types.SpendReport, error)
 }
 
 type SessionManager interface {
@@ -44,5 +117,48 @@ type SessionManager interface {
 	GetUserID(c echo.Context) (string, bool)
 	IsAuthenticated(c echo.Context) bool
 	IsMaster(c echo.Context) bool
+	Role(c echo.Context) (Role, bool)
+	Actor(c echo.Context) (Actor, bool)
+	HasPermission(c echo.Context, perm Permission) bool
 	RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
+	RequirePermission(perm Permission) echo.MiddlewareFunc
+}
+
+type APIKeyManager interface {
+	CreateKey(ctx context.Context, arg CreateAPIKeyParams) (string, error)
+	ListKeys(ctx context.Context) ([]db.ListAPIKeysRow, error)
+	RevokeKey(ctx context.Context, id uuid.UUID) error
+}
+
+type APIKeyAuthenticator interface {
+	RequireScope(scope string) echo.MiddlewareFunc
+}
+
+type Auditor interface {
+	Record(ctx context.Context, actor Actor, event AuditEvent)
+	DocumentHistory(ctx context.Context, docID uuid.UUID) ([]db_types.AuditEntry, error)
+}
+
+type DocumentRevisions interface {
+	ListRevisions(ctx context.Context, docID uuid.UUID) ([]db_types.RevisionSummary, error)
+	CompareRevisions(ctx context.Context, docID uuid.UUID, from, to int32) ([]db_types.AuditChange, error)
+	RestoreRevision(ctx context.Context, docID uuid.UUID, revision int32, actor Actor) (int32, error)
+}
+
+type Ingester interface {
+	Submit(ctx context.Context, upload IngestUpload, actor Actor) (uuid.UUID, error)
+	Job(ctx context.Context, id uuid.UUID) (db_types.IngestJob, error)
+	RecentJobs(ctx context.Context) ([]db_types.IngestJob, error)
+	Retry(ctx context.Context, id uuid.UUID) error
+	Resume(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+}
+
+type Uploader interface {
+	Limits() UploadLimits
+	Submit(ctx context.Context, file UploadFile, actor Actor) UploadResult
+	SubmitBatch(ctx context.Context, files []UploadFile, actor Actor) (uuid.UUID, error)
+	PreviewManifest(ctx context.Context, manifest UploadFile, files []UploadFile) (db_types.ManifestPreview, error)
+	SubmitManifest(ctx context.Context, manifest UploadFile, files []UploadFile, actor Actor) (uuid.UUID, error)
+	Batch(ctx context.Context, id uuid.UUID) (db_types.UploadBatch, error)
 }
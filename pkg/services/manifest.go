@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// manifestListSeparator separates the values of a list column in a CSV manifest.
+const manifestListSeparator = ";"
+
+// Manifest columns, as CSV headers or JSON keys. Only file is required.
+const (
+	manifestFile        = "file"
+	manifestTitle       = "title"
+	manifestAbstract    = "abstract"
+	manifestPublishDate = "publish_date"
+	manifestSource      = "source"
+	manifestAuthors     = "authors"
+	manifestKeywords    = "keywords"
+	manifestRegions     = "regions"
+	manifestCategories  = "categories"
+)
+
+// manifestAliases maps the other accepted column names, including the keys
+// extracted metadata uses, to the column they mean.
+var manifestAliases = map[string]string{
+	"file_name":     manifestFile,
+	"author_name":   manifestAuthors,
+	"keyword_name":  manifestKeywords,
+	"region_name":   manifestRegions,
+	"category_name": manifestCategories,
+}
+
+var manifestColumns = []string{
+	manifestFile,
+	manifestTitle,
+	manifestAbstract,
+	manifestPublishDate,
+	manifestSource,
+	manifestAuthors,
+	manifestKeywords,
+	manifestRegions,
+	manifestCategories,
+}
+
+// ManifestRow is one document listed in a manifest. File is the name of an
+// uploaded file or the key of a file already in the upload bucket. Metadata
+// holds only the fields the manifest provides. Problem says why the row cannot
+// be imported.
+type ManifestRow struct {
+	Line     int
+	File     string
+	Metadata awskendra.ExtractedMetadata
+	Problem  string
+}
+
+// ParseManifest reads a CSV manifest with a header row, or a JSON manifest that
+// is an array of objects, choosing by the file's extension. List columns hold
+// several names separated by semicolons in CSV, or an array in JSON. Line is the
+// CSV line number or the position in the JSON array.
+func ParseManifest(name string, data []byte) ([]ManifestRow, error) {
+	var records []map[string]any
+	var firstLine int
+	var err error
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		records, err = readCSVManifest(data)
+		firstLine = 2
+	case ".json":
+		records, err = readJSONManifest(data)
+		firstLine = 1
+	default:
+		return nil, fmt.Errorf("%w: the manifest must be a .csv or .json file", ErrInvalidManifest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ManifestRow, 0, len(records))
+	for i, record := range records {
+		row, err := manifestRow(record)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d: %v", ErrInvalidManifest, i+firstLine, err)
+		}
+		row.Line = i + firstLine
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the manifest lists no files", ErrInvalidManifest)
+	}
+	return rows, nil
+}
+
+func readCSVManifest(data []byte) ([]map[string]any, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read the header row: %v", ErrInvalidManifest, err)
+	}
+
+	var records []map[string]any
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+		}
+		record := make(map[string]any, len(header))
+		for i, column := range header {
+			if i < len(fields) {
+				record[column] = fields[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func readJSONManifest(data []byte) ([]map[string]any, error) {
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("%w: expected an array of objects: %v", ErrInvalidManifest, err)
+	}
+	return records, nil
+}
+
+// manifestRow reads a record's columns. Unknown columns are an error so that a
+// misspelt header is not silently ignored; bad values only mark the row.
+func manifestRow(record map[string]any) (ManifestRow, error) {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var row ManifestRow
+	var problems []string
+	for _, key := range keys {
+		value := record[key]
+		column := strings.ToLower(strings.TrimSpace(key))
+		if alias, ok := manifestAliases[column]; ok {
+			column = alias
+		}
+
+		var err error
+		switch column {
+		case manifestFile:
+			row.File, err = manifestString(value)
+		case manifestTitle:
+			row.Metadata.Title, err = manifestString(value)
+		case manifestAbstract:
+			row.Metadata.Abstract, err = manifestString(value)
+		case manifestPublishDate:
+			row.Metadata.PublishDate, err = manifestString(value)
+		case manifestSource:
+			row.Metadata.Source, err = manifestString(value)
+		case manifestAuthors:
+			row.Metadata.AuthorName, err = manifestList(value)
+		case manifestKeywords:
+			row.Metadata.KeywordName, err = manifestList(value)
+		case manifestRegions:
+			row.Metadata.RegionName, err = manifestList(value)
+		case manifestCategories:
+			row.Metadata.CategoryName, err = manifestList(value)
+		default:
+			return ManifestRow{}, fmt.Errorf("unknown column %q, expected one of %s", key, strings.Join(manifestColumns, ", "))
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", column, err))
+		}
+	}
+
+	if row.File == "" {
+		problems = append(problems, "no file given")
+	}
+	if row.Metadata.PublishDate != "" {
+		if _, err := time.Parse(dateFormat, row.Metadata.PublishDate); err != nil {
+			problems = append(problems, fmt.Sprintf("publish_date %q is not a YYYY-MM-DD date", row.Metadata.PublishDate))
+		}
+	}
+	row.Problem = strings.Join(problems, "; ")
+	return row, nil
+}
+
+func manifestString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	default:
+		return "", fmt.Errorf("expected text, got %v", value)
+	}
+}
+
+// manifestList accepts a semicolon-separated string or an array of strings,
+// dropping blank names.
+func manifestList(value any) ([]string, error) {
+	var names []string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		names = strings.Split(v, manifestListSeparator)
+	case []any:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of names, got %v", item)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("expected a list of names, got %v", value)
+	}
+
+	var cleaned []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			cleaned = append(cleaned, name)
+		}
+	}
+	return cleaned, nil
+}
+
+// MergeMetadata keeps every field provided and takes the rest from extracted.
+func MergeMetadata(provided, extracted awskendra.ExtractedMetadata) awskendra.ExtractedMetadata {
+	merged := provided
+	if merged.Title == "" {
+		merged.Title = extracted.Title
+	}
+	if merged.Abstract == "" {
+		merged.Abstract = extracted.Abstract
+	}
+	if merged.PublishDate == "" {
+		merged.PublishDate = extracted.PublishDate
+	}
+	if merged.Source == "" {
+		merged.Source = extracted.Source
+	}
+	if len(merged.AuthorName) == 0 {
+		merged.AuthorName = extracted.AuthorName
+	}
+	if len(merged.KeywordName) == 0 {
+		merged.KeywordName = extracted.KeywordName
+	}
+	if len(merged.RegionName) == 0 {
+		merged.RegionName = extracted.RegionName
+	}
+	if len(merged.CategoryName) == 0 {
+		merged.CategoryName = extracted.CategoryName
+	}
+	return merged
+}
+
+// MissingMetadata names the fields that metadata leaves blank, in manifest
+// column order. Extraction is skipped when there are none.
+func MissingMetadata(metadata awskendra.ExtractedMetadata) []string {
+	var missing []string
+	fields := []struct {
+		column string
+		blank  bool
+	}{
+		{manifestTitle, metadata.Title == ""},
+		{manifestAbstract, metadata.Abstract == ""},
+		{manifestPublishDate, metadata.PublishDate == ""},
+		{manifestSource, metadata.Source == ""},
+		{manifestAuthors, len(metadata.AuthorName) == 0},
+		{manifestKeywords, len(metadata.KeywordName) == 0},
+		{manifestRegions, len(metadata.RegionName) == 0},
+		{manifestCategories, len(metadata.CategoryName) == 0},
+	}
+	for _, field := range fields {
+		if field.blank {
+			missing = append(missing, field.column)
+		}
+	}
+	return missing
+}
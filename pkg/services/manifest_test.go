@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+)
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     string
+		want     []ManifestRow
+	}{
+		{
+			name:     "csv",
+			fileName: "manifest.CSV",
+			data: "\xef\xbb\xbfFile,Title,Authors,Region_Name,publish_date\n" +
+				"a.pdf,\"Peace, Now\",Amy; Zed ;,Kenya,2020-05-01\n" +
+				"b.pdf,,,,\n",
+			want: []ManifestRow{
+				{Line: 2, File: "a.pdf", Metadata: awskendra.ExtractedMetadata{
+					Title:       "Peace, Now",
+					PublishDate: "2020-05-01",
+					AuthorName:  []string{"Amy", "Zed"},
+					RegionName:  []string{"Kenya"},
+				}},
+				{Line: 3, File: "b.pdf"},
+			},
+		},
+		{
+			name:     "json",
+			fileName: "manifest.json",
+			data:     `[{"file": "a.pdf", "keywords": ["peace", " "], "categories": "Report; Brief", "source": null}]`,
+			want: []ManifestRow{
+				{Line: 1, File: "a.pdf", Metadata: awskendra.ExtractedMetadata{
+					KeywordName:  []string{"peace"},
+					CategoryName: []string{"Report", "Brief"},
+				}},
+			},
+		},
+		{
+			name:     "bad values mark the row",
+			fileName: "manifest.json",
+			data:     `[{"title": 3, "publish_date": "May 2020"}]`,
+			want: []ManifestRow{
+				{Line: 1, Metadata: awskendra.ExtractedMetadata{PublishDate: "May 2020"}, Problem: `title: expected text, got 3; no file given; publish_date "May 2020" is not a YYYY-MM-DD date`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseManifest(tt.fileName, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rows)
+		})
+	}
+}
+
+func TestParseManifest_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     string
+	}{
+		{name: "unsupported format", fileName: "manifest.xlsx", data: "file\na.pdf\n"},
+		{name: "unknown column", fileName: "manifest.csv", data: "file,titel\na.pdf,Report\n"},
+		{name: "no rows", fileName: "manifest.csv", data: "file,title\n"},
+		{name: "not an array", fileName: "manifest.json", data: `{"file": "a.pdf"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseManifest(tt.fileName, []byte(tt.data))
+			assert.ErrorIs(t, err, ErrInvalidManifest)
+		})
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	provided := awskendra.ExtractedMetadata{Title: "Provided", RegionName: []string{"Kenya"}}
+	extracted := awskendra.ExtractedMetadata{
+		Title:      "Extracted",
+		Abstract:   "Extracted abstract",
+		RegionName: []string{"Uganda"},
+		AuthorName: []string{"Amy"},
+	}
+
+	merged := MergeMetadata(provided, extracted)
+
+	assert.Equal(t, "Provided", merged.Title)
+	assert.Equal(t, "Extracted abstract", merged.Abstract)
+	assert.Equal(t, []string{"Kenya"}, merged.RegionName)
+	assert.Equal(t, []string{"Amy"}, merged.AuthorName)
+	assert.Equal(t, []string{"publish_date", "source", "keywords", "categories"}, MissingMetadata(merged))
+}
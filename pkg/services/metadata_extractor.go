@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// Metadata extractor names, as listed in METADATA_EXTRACTORS.
+const (
+	ExtractorBedrock   = "bedrock"
+	ExtractorOpenAI    = "openai"
+	ExtractorHeuristic = "heuristic"
+)
+
+// MetadataClient is implemented by awskendra.BedrockClient, openai.Client and
+// heuristic.Extractor.
+type MetadataClient interface {
+	ProcessDocAndExtractMetadata(ctx context.Context, docBytes []byte, prompt awskendra.Prompt, vocab awskendra.Vocabulary) (*awskendra.Extraction, error)
+}
+
+type metadataExtractor struct {
+	log      logger.Logger
+	name     string
+	client   MetadataClient
+	settings ExtractionSettings
+	prices   ModelPrices
+	now      func() time.Time
+}
+
+// NewMetadataExtractor creates an extractor that gives client the current
+// prompt and vocabularies from settings for each document. It times each
+// extraction and prices its tokens with prices.
+func NewMetadataExtractor(log logger.Logger, name string, client MetadataClient, settings ExtractionSettings, prices ModelPrices) MetadataExtractor {
+	serviceLogger := log.With("service", "MetadataExtractor", "extractor", name)
+	return &metadataExtractor{
+		log:      serviceLogger,
+		name:     name,
+		client:   client,
+		settings: settings,
+		prices:   prices,
+		now:      time.Now,
+	}
+}
+
+func (e *metadataExtractor) Name() string {
+	return e.name
+}
+
+func (e *metadataExtractor) ExtractMetadata(ctx context.Context, docBytes []byte) (*awskendra.Extraction, error) {
+	prompt, vocab, err := e.settings.Current(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load extraction settings: %w", err)
+	}
+
+	start := e.now()
+	extraction, err := e.client.ProcessDocAndExtractMetadata(ctx, docBytes, prompt, vocab)
+	latency := e.now().Sub(start)
+	if err != nil {
+		var failed *awskendra.ExtractionError
+		if errors.As(err, &failed) {
+			e.account(failed.Extraction, latency)
+		}
+		e.log.ErrorContext(ctx, "failed to extract metadata from document", "promptVersion", prompt.Version, "error", err)
+		return nil, err
+	}
+	if extraction == nil {
+		return nil, errors.New("no metadata returned")
+	}
+
+	if len(extraction.Problems) > 0 {
+		e.log.WarnContext(ctx, "model reply still invalid after repairs, keeping the valid fields", "repairs", extraction.Repairs, "problems", extraction.Problems)
+	}
+	e.account(extraction, latency)
+	args := []any{"model", extraction.Model, "inputTokens", extraction.InputTokens, "outputTokens", extraction.OutputTokens, "latency", latency}
+	if extraction.Cost != nil {
+		args = append(args, "cost", *extraction.Cost)
+	}
+	e.log.InfoContext(ctx, "metadata extracted", args...)
+	return extraction, nil
+}
+
+// account fills in who made the extraction, how long it took and what it cost.
+func (e *metadataExtractor) account(extraction *awskendra.Extraction, latency time.Duration) {
+	extraction.Extractor = e.name
+	extraction.Latency = latency
+	if cost, ok := e.prices.Cost(extraction.Model, extraction.InputTokens, extraction.OutputTokens); ok {
+		extraction.Cost = &cost
+	} else {
+		e.log.Warn("no price configured for model, cost not recorded", "model", extraction.Model)
+	}
+}
+
+type fallbackExtractor struct {
+	log        logger.Logger
+	extractors []MetadataExtractor
+}
+
+// NewFallbackExtractor tries each extractor in order and returns the first
+// metadata found, so a model that is down or returns bad JSON can fall back to
+// another model or to the heuristic extractor.
+func NewFallbackExtractor(log logger.Logger, extractors ...MetadataExtractor) MetadataExtractor {
+	serviceLogger := log.With("service", "MetadataExtractor")
+	return &fallbackExtractor{
+		log:        serviceLogger,
+		extractors: extractors,
+	}
+}
+
+func (f *fallbackExtractor) Name() string {
+	names := make([]string, len(f.extractors))
+	for i, extractor := range f.extractors {
+		names[i] = extractor.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *fallbackExtractor) ExtractMetadata(ctx context.Context, docBytes []byte) (*awskendra.Extraction, error) {
+	if len(f.extractors) == 0 {
+		return nil, errors.New("no metadata extractors configured")
+	}
+
+	var errs []error
+	for i, extractor := range f.extractors {
+		extraction, err := extractor.ExtractMetadata(ctx, docBytes)
+		if err == nil {
+			return extraction, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", extractor.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(f.extractors)-1 {
+			f.log.WarnContext(ctx, "metadata extractor failed, falling back", "extractor", extractor.Name(), "next", f.extractors[i+1].Name(), "error", err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeMetadataClient struct {
+	calls      int
+	prompt     awskendra.Prompt
+	extraction *awskendra.Extraction
+	err        error
+}
+
+func (f *fakeMetadataClient) ProcessDocAndExtractMetadata(_ context.Context, _ []byte, prompt awskendra.Prompt, _ awskendra.Vocabulary) (*awskendra.Extraction, error) {
+	f.calls++
+	f.prompt = prompt
+	return f.extraction, f.err
+}
+
+type fakeSettings struct {
+	prompt awskendra.Prompt
+	err    error
+}
+
+func (f fakeSettings) Current(context.Context) (awskendra.Prompt, awskendra.Vocabulary, error) {
+	return f.prompt, awskendra.Vocabulary{}, f.err
+}
+
+func TestMetadataExtractor(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	prompt := awskendra.Prompt{Version: "db:3", Template: "{{.Text}}"}
+	prices := ModelPrices{"haiku": {InputPerMillion: 0.25, OutputPerMillion: 1.25}}
+	client := &fakeMetadataClient{extraction: &awskendra.Extraction{
+		Metadata:      awskendra.ExtractedMetadata{Title: "Report"},
+		Model:         "haiku",
+		PromptVersion: "db:3",
+		InputTokens:   4000,
+		OutputTokens:  400,
+	}}
+
+	extractor := NewMetadataExtractor(log, "first", client, fakeSettings{prompt: prompt}, prices).(*metadataExtractor)
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	extractor.now = func() time.Time {
+		clock = clock.Add(1500 * time.Millisecond)
+		return clock
+	}
+	extraction, err := extractor.ExtractMetadata(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, prompt, client.prompt)
+	assert.Equal(t, "first", extraction.Extractor)
+	assert.Equal(t, "Report", extraction.Metadata.Title)
+	assert.Equal(t, 1500*time.Millisecond, extraction.Latency)
+	require.NotNil(t, extraction.Cost)
+	assert.Equal(t, 0.0015, *extraction.Cost)
+
+	// A failed extraction is still accounted for
+	failed := &awskendra.Extraction{Model: "llama3", InputTokens: 10}
+	client.extraction, client.err = nil, &awskendra.ExtractionError{Extraction: failed, Err: errors.New("no title")}
+	_, err = extractor.ExtractMetadata(context.Background(), nil)
+	assert.EqualError(t, err, "no title")
+	assert.Equal(t, "first", failed.Extractor)
+	assert.Nil(t, failed.Cost, "llama3 has no price")
+	client.err = nil
+
+	// Without settings the model is not called
+	client.calls = 0
+	_, err = NewMetadataExtractor(log, "first", client, fakeSettings{err: ErrPromptNotFound}, nil).ExtractMetadata(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrPromptNotFound)
+	assert.Zero(t, client.calls)
+}
+
+func TestFallbackExtractor(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	found := &awskendra.Extraction{Metadata: awskendra.ExtractedMetadata{Title: "Report"}}
+
+	tests := []struct {
+		name      string
+		clients   []*fakeMetadataClient
+		want      *awskendra.Extraction
+		wantErr   []string
+		wantCalls []int
+	}{
+		{
+			name:      "first extractor succeeds",
+			clients:   []*fakeMetadataClient{{extraction: found}, {extraction: found}},
+			want:      found,
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "falls back after an error",
+			clients:   []*fakeMetadataClient{{err: errors.New("throttled")}, {extraction: found}},
+			want:      found,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "no metadata counts as a failure",
+			clients:   []*fakeMetadataClient{{}, {extraction: found}},
+			want:      found,
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "every extractor fails",
+			clients:   []*fakeMetadataClient{{err: errors.New("throttled")}, {err: errors.New("no title")}},
+			wantErr:   []string{"first: throttled", "second: no title"},
+			wantCalls: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{"first", "second"}
+			var extractors []MetadataExtractor
+			for i, client := range tt.clients {
+				extractors = append(extractors, NewMetadataExtractor(log, names[i], client, fakeSettings{}, nil))
+			}
+			extractor := NewFallbackExtractor(log, extractors...)
+			assert.Equal(t, "first,second", extractor.Name())
+
+			extraction, err := extractor.ExtractMetadata(context.Background(), []byte("%PDF"))
+
+			for i, client := range tt.clients {
+				assert.Equal(t, tt.wantCalls[i], client.calls, names[i])
+			}
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				for _, msg := range tt.wantErr {
+					assert.Contains(t, err.Error(), msg)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, extraction)
+		})
+	}
+}
+
+func TestFallbackExtractor_StopsWhenCanceled(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	first := &fakeMetadataClient{err: context.Canceled}
+	second := &fakeMetadataClient{extraction: &awskendra.Extraction{Metadata: awskendra.ExtractedMetadata{Title: "Report"}}}
+
+	extractor := NewFallbackExtractor(log, NewMetadataExtractor(log, "first", first, fakeSettings{}, nil), NewMetadataExtractor(log, "second", second, fakeSettings{}, nil))
+	_, err := extractor.ExtractMetadata(ctx, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, second.calls)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// PageStore replaces the stored pages of a document. It is satisfied by
+// *repository.PageRepository.
+type PageStore interface {
+	ReplacePages(ctx context.Context, docID uuid.UUID, pages []repository.Page) error
+}
+
+type pageIndexService struct {
+	log   logger.Logger
+	store PageStore
+}
+
+func NewPageIndexService(log logger.Logger, store PageStore) PageIndexer {
+	serviceLogger := log.With("service", "PageIndex")
+	return &pageIndexService{
+		log:   serviceLogger,
+		store: store,
+	}
+}
+
+// IndexDocumentPages extracts the text of every page of the document and replaces
+// its rows in document_pages. Pages without text are skipped but keep their numbering.
+func (s *pageIndexService) IndexDocumentPages(ctx context.Context, docID uuid.UUID, docBytes []byte) (int, error) {
+	pages, err := awskendra.ExtractPages(docBytes)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to extract pages", "docID", docID, "error", err)
+		return 0, fmt.Errorf("failed to extract pages: %w", err)
+	}
+	return s.SavePages(ctx, docID, pages)
+}
+
+// SavePages replaces the document's rows in document_pages with already extracted
+// page text, all at once. Pages without text are skipped but keep their numbering.
+func (s *pageIndexService) SavePages(ctx context.Context, docID uuid.UUID, pages []string) (int, error) {
+	rows := make([]repository.Page, 0, len(pages))
+	for i, content := range pages {
+		content = strings.TrimSpace(strings.ToValidUTF8(strings.ReplaceAll(content, "\x00", ""), ""))
+		if content == "" {
+			continue
+		}
+		rows = append(rows, repository.Page{Number: int32(i + 1), Content: content})
+	}
+
+	if err := s.store.ReplacePages(ctx, docID, rows); err != nil {
+		s.log.ErrorContext(ctx, "Failed to save pages", "docID", docID, "error", err)
+		return 0, fmt.Errorf("failed to save pages: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "Indexed document pages", "docID", docID, "pages", len(pages), "indexed", len(rows))
+	return len(rows), nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakePageStore struct {
+	pages map[uuid.UUID][]repository.Page
+	err   error
+}
+
+func (f *fakePageStore) ReplacePages(_ context.Context, docID uuid.UUID, pages []repository.Page) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.pages[docID] = pages
+	return nil
+}
+
+func TestPageIndexService_SavePages(t *testing.T) {
+	store := &fakePageStore{pages: make(map[uuid.UUID][]repository.Page)}
+	pages := NewPageIndexService(logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}), store)
+	docID := uuid.New()
+
+	indexed, err := pages.SavePages(context.Background(), docID, []string{" first ", "  ", "th\x00ird"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+	assert.Equal(t, []repository.Page{{Number: 1, Content: "first"}, {Number: 3, Content: "third"}}, store.pages[docID])
+
+	store.err = errors.New("connection reset")
+	indexed, err = pages.SavePages(context.Background(), docID, []string{"first"})
+
+	assert.Error(t, err)
+	assert.Zero(t, indexed, "no page is saved when one fails")
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// previewHeight is the thumbnail height in pixels; the width keeps the page's aspect ratio.
+const previewHeight = 120
+
+var ErrUnsupportedPreviewType = errors.New("unsupported file type for preview")
+
+type commandRenderer struct {
+	height int
+}
+
+// NewCommandRenderer renders previews with poppler's pdftoppm. DOCX files are first
+// converted to PDF with LibreOffice. Both must be on the PATH.
+func NewCommandRenderer() PreviewRenderer {
+	return &commandRenderer{height: previewHeight}
+}
+
+// RenderFirstPage returns the first page of the document as a PNG thumbnail.
+func (r *commandRenderer) RenderFirstPage(ctx context.Context, docBytes []byte, ext string) ([]byte, error) {
+	if ext != ".pdf" && ext != ".docx" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPreviewType, ext)
+	}
+
+	dir, err := os.MkdirTemp("", "doc_preview")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	input := filepath.Join(dir, "document"+ext)
+	if err := os.WriteFile(input, docBytes, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+
+	pdfPath := filepath.Join(dir, "document.pdf")
+	if ext == ".docx" {
+		// A private profile directory lets several conversions run at once.
+		err := runCommand(ctx, "libreoffice",
+			"-env:UserInstallation=file://"+filepath.Join(dir, "profile"),
+			"--headless",
+			"--convert-to", "pdf",
+			"--outdir", dir,
+			input,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert docx to pdf: %w", err)
+		}
+	}
+
+	outPrefix := filepath.Join(dir, "preview")
+	err = runCommand(ctx, "pdftoppm",
+		"-png",
+		"-f", "1",
+		"-l", "1",
+		"-singlefile",
+		"-scale-to-x", "-1",
+		"-scale-to-y", strconv.Itoa(r.height),
+		pdfPath,
+		outPrefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render first page: %w", err)
+	}
+
+	return os.ReadFile(outPrefix + ".png")
+}
+
+func runCommand(ctx context.Context, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
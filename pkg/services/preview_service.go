@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const previewContentType = "image/png"
+
+type previewService struct {
+	log       logger.Logger
+	dbQuerier *db.Queries
+	s3Client  *awskendra.S3Client
+	renderer  PreviewRenderer
+}
+
+func NewPreviewService(log logger.Logger, dbQuerier *db.Queries, s3Client *awskendra.S3Client, renderer PreviewRenderer) PreviewGenerator {
+	serviceLogger := log.With("service", "Preview")
+	return &previewService{
+		log:       serviceLogger,
+		dbQuerier: dbQuerier,
+		s3Client:  s3Client,
+		renderer:  renderer,
+	}
+}
+
+// GeneratePreview renders the first page of the document, uploads it next to the
+// document in S3 and stores its URI in s3_file_preview. It returns the preview's S3 URI.
+func (s *previewService) GeneratePreview(ctx context.Context, docID uuid.UUID, s3URI string, docBytes []byte) (string, error) {
+	bucket, key, ok := db_util.SplitS3URI(s3URI)
+	if !ok {
+		return "", fmt.Errorf("invalid S3 URI: %s", s3URI)
+	}
+
+	image, err := s.renderer.RenderFirstPage(ctx, docBytes, strings.ToLower(path.Ext(key)))
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to render preview", "docID", docID, "s3File", s3URI, "error", err)
+		return "", err
+	}
+
+	previewKey := previewKeyFor(key)
+	if err := s.s3Client.UploadPublic(ctx, bucket, previewKey, image, previewContentType); err != nil {
+		s.log.ErrorContext(ctx, "Failed to upload preview", "docID", docID, "key", previewKey, "error", err)
+		return "", fmt.Errorf("failed to upload preview: %w", err)
+	}
+
+	previewURI := fmt.Sprintf("s3://%s/%s", bucket, previewKey)
+	err = s.dbQuerier.SetDocumentPreview(ctx, db.SetDocumentPreviewParams{
+		ID:            docID,
+		S3FilePreview: sql.NullString{String: previewURI, Valid: true},
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to save preview", "docID", docID, "error", err)
+		return "", fmt.Errorf("failed to save preview: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "Generated preview", "docID", docID, "preview", previewURI)
+	return previewURI, nil
+}
+
+// previewKeyFor swaps the document's extension for the preview image's.
+func previewKeyFor(key string) string {
+	return strings.TrimSpace(strings.TrimSuffix(key, path.Ext(key))) + ".png"
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_previewKeyFor(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "pdf", key: "report.pdf", want: "report.png"},
+		{name: "docx in folder", key: "2024/Annual Review.docx", want: "2024/Annual Review.png"},
+		{name: "trailing space before extension", key: "brief .pdf", want: "brief.png"},
+		{name: "no extension", key: "notes", want: "notes.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previewKeyFor(tt.key); got != tt.want {
+				t.Errorf("previewKeyFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commandRenderer_unsupportedType(t *testing.T) {
+	_, err := NewCommandRenderer().RenderFirstPage(context.Background(), []byte("data"), ".txt")
+	if !errors.Is(err, ErrUnsupportedPreviewType) {
+		t.Errorf("RenderFirstPage() error = %v, want %v", err, ErrUnsupportedPreviewType)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ModelPrice is what a model charges in US dollars per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// ModelPrices maps model IDs to their prices.
+type ModelPrices map[string]ModelPrice
+
+// ParseModelPrices reads MODEL_PRICES, a list of model=input/output entries
+// separated by semicolons, as in
+// "anthropic.claude-3-haiku-20240307-v1:0=0.25/1.25;llama3=0/0".
+func ParseModelPrices(raw string) (ModelPrices, error) {
+	prices := ModelPrices{}
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, rates, ok := strings.Cut(entry, "=")
+		input, output, ok2 := strings.Cut(rates, "/")
+		if !ok || !ok2 || strings.TrimSpace(model) == "" {
+			return nil, fmt.Errorf("invalid model price %q: want model=input/output", entry)
+		}
+		in, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil || in < 0 {
+			return nil, fmt.Errorf("invalid input price in %q", entry)
+		}
+		out, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil || out < 0 {
+			return nil, fmt.Errorf("invalid output price in %q", entry)
+		}
+		prices[strings.TrimSpace(model)] = ModelPrice{InputPerMillion: in, OutputPerMillion: out}
+	}
+	return prices, nil
+}
+
+// Cost estimates what the tokens cost, rounded to a millionth of a dollar.
+// Using no tokens is free whatever the model; otherwise ok is false when the
+// model has no price.
+func (p ModelPrices) Cost(model string, inputTokens, outputTokens int) (cost float64, ok bool) {
+	if inputTokens == 0 && outputTokens == 0 {
+		return 0, true
+	}
+	price, ok := p[model]
+	if !ok {
+		return 0, false
+	}
+	cost = float64(inputTokens)/1e6*price.InputPerMillion + float64(outputTokens)/1e6*price.OutputPerMillion
+	return math.Round(cost*1e6) / 1e6, true
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModelPrices(t *testing.T) {
+	prices, err := ParseModelPrices(" anthropic.claude-3-haiku-20240307-v1:0=0.25/1.25; llama3 = 0 / 0 ;")
+	require.NoError(t, err)
+	assert.Equal(t, ModelPrices{
+		"anthropic.claude-3-haiku-20240307-v1:0": {InputPerMillion: 0.25, OutputPerMillion: 1.25},
+		"llama3":                                 {},
+	}, prices)
+
+	for _, raw := range []string{"haiku", "haiku=0.25", "=1/1", "haiku=a/1", "haiku=1/-1"} {
+		_, err := ParseModelPrices(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestModelPrices_Cost(t *testing.T) {
+	prices := ModelPrices{"haiku": {InputPerMillion: 0.25, OutputPerMillion: 1.25}}
+
+	cost, ok := prices.Cost("haiku", 1234, 321)
+	assert.True(t, ok)
+	assert.Equal(t, 0.000710, cost)
+
+	_, ok = prices.Cost("llama3", 10, 10)
+	assert.False(t, ok)
+
+	cost, ok = prices.Cost("", 0, 0)
+	assert.True(t, ok, "the heuristic extractor is free")
+	assert.Zero(t, cost)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// PublisherStore is the subset of db.Queries used by the publisher service.
+type PublisherStore interface {
+	FindPublisherByID(ctx context.Context, id uuid.UUID) (db.Publisher, error)
+	ListPublisherAliasNames(ctx context.Context, publisherID uuid.UUID) ([]string, error)
+	ListPublisherPublications(ctx context.Context, publisherID uuid.UUID) ([]db.ListPublisherPublicationsRow, error)
+}
+
+type publisherService struct {
+	log   logger.Logger
+	store PublisherStore
+}
+
+// NewPublisherService creates the service behind publisher profile pages.
+func NewPublisherService(log logger.Logger, store PublisherStore) PublisherDirectory {
+	serviceLogger := log.With("service", "Publisher")
+	return &publisherService{
+		log:   serviceLogger,
+		store: store,
+	}
+}
+
+// Profile returns a publisher with its aliases and documents.
+func (s *publisherService) Profile(ctx context.Context, id uuid.UUID) (db_types.PublisherProfile, error) {
+	publisher, err := s.store.FindPublisherByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db_types.PublisherProfile{}, fmt.Errorf("%w: %s", repository.ErrTermNotFound, id)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to load publisher", "id", id, "error", err)
+		return db_types.PublisherProfile{}, fmt.Errorf("failed to load publisher: %w", err)
+	}
+	profile := db_types.PublisherProfile{
+		ID:   publisher.ID.String(),
+		Name: publisher.Name,
+	}
+
+	if profile.Aliases, err = s.store.ListPublisherAliasNames(ctx, id); err != nil {
+		s.log.ErrorContext(ctx, "Failed to list publisher aliases", "id", id, "error", err)
+		return db_types.PublisherProfile{}, fmt.Errorf("failed to list publisher aliases: %w", err)
+	}
+	rows, err := s.store.ListPublisherPublications(ctx, id)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list publisher documents", "id", id, "error", err)
+		return db_types.PublisherProfile{}, fmt.Errorf("failed to list publisher documents: %w", err)
+	}
+	for _, row := range rows {
+		doc := db_types.PublisherDocument{
+			ID:      row.ID.String(),
+			Title:   row.Title,
+			Authors: row.AuthorNames,
+			Link:    util.ConvertS3URIToURL(row.S3File),
+		}
+		if row.PublishDate.Valid {
+			doc.PublishDate = row.PublishDate.Time.Format("2006-01-02")
+		}
+		profile.Documents = append(profile.Documents, doc)
+	}
+	return profile, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+var brownID = uuid.MustParse("77777777-7777-7777-7777-777777777777")
+
+// fakePublisherStore holds Brown University only.
+type fakePublisherStore struct{}
+
+func (fakePublisherStore) FindPublisherByID(_ context.Context, id uuid.UUID) (db.Publisher, error) {
+	if id != brownID {
+		return db.Publisher{}, sql.ErrNoRows
+	}
+	return db.Publisher{ID: brownID, Name: "Brown University", Approved: true}, nil
+}
+
+func (fakePublisherStore) ListPublisherAliasNames(context.Context, uuid.UUID) ([]string, error) {
+	return []string{"Brown Univ."}, nil
+}
+
+func (fakePublisherStore) ListPublisherPublications(context.Context, uuid.UUID) ([]db.ListPublisherPublicationsRow, error) {
+	return []db.ListPublisherPublicationsRow{
+		{ID: reportID, Title: "Psychoceramics", PublishDate: sql.NullTime{Time: time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true}, S3File: "s3://bucket/psychoceramics.pdf", AuthorNames: []string{"Josiah Carberry"}},
+		{ID: congoID, Title: "Undated notes", S3File: "s3://bucket/notes.pdf", AuthorNames: []string{}},
+	}, nil
+}
+
+func TestPublisherService_Profile(t *testing.T) {
+	publishers := NewPublisherService(logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError}), fakePublisherStore{})
+
+	profile, err := publishers.Profile(context.Background(), brownID)
+	require.NoError(t, err)
+	assert.Equal(t, "Brown University", profile.Name)
+	assert.Equal(t, []string{"Brown Univ."}, profile.Aliases)
+	require.Len(t, profile.Documents, 2)
+	assert.Equal(t, "2019-04-01", profile.Documents[0].PublishDate)
+	assert.Equal(t, []string{"Josiah Carberry"}, profile.Documents[0].Authors)
+	assert.NotEmpty(t, profile.Documents[0].Link)
+	assert.Empty(t, profile.Documents[1].PublishDate)
+
+	_, err = publishers.Profile(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, repository.ErrTermNotFound)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+// maxReviewQueue caps how many documents the reviewer queue lists at once.
+const maxReviewQueue = 200
+
+var reviewStateLabels = map[string]string{
+	repository.ReviewAIExtracted: "AI extracted",
+	repository.ReviewInReview:    "In review",
+	repository.ReviewApproved:    "Approved",
+}
+
+// ReviewStateLabel names a review state for display.
+func ReviewStateLabel(state string) string {
+	if label, ok := reviewStateLabels[state]; ok {
+		return label
+	}
+	return state
+}
+
+// ReviewStore is the subset of db.Queries used by the review service.
+type ReviewStore interface {
+	ListDocumentFieldSources(ctx context.Context, docID uuid.UUID) ([]db.ListDocumentFieldSourcesRow, error)
+	ListDocumentsForReview(ctx context.Context, maxDocuments int32) ([]db.ListDocumentsForReviewRow, error)
+}
+
+type reviewService struct {
+	log   logger.Logger
+	store ReviewStore
+}
+
+// NewReviewService creates the service behind the reviewer queue and the review
+// state shown on the metadata page. Documents change state when their metadata
+// is saved, in repository.DocumentRepository.
+func NewReviewService(log logger.Logger, store ReviewStore) ReviewQueue {
+	serviceLogger := log.With("service", "Review")
+	return &reviewService{
+		log:   serviceLogger,
+		store: store,
+	}
+}
+
+// Queue lists the documents that are not yet approved, oldest first.
+func (s *reviewService) Queue(ctx context.Context) (db_types.ReviewQueue, error) {
+	rows, err := s.store.ListDocumentsForReview(ctx, maxReviewQueue)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list documents for review", "error", err)
+		return db_types.ReviewQueue{}, fmt.Errorf("failed to list documents for review: %w", err)
+	}
+
+	queue := db_types.ReviewQueue{Items: make([]db_types.ReviewItem, len(rows))}
+	for i, row := range rows {
+		queue.Total = row.Total
+		queue.Items[i] = db_types.ReviewItem{
+			ID:          row.ID.String(),
+			Title:       row.Title,
+			FileName:    row.FileName,
+			State:       row.ReviewState,
+			StateLabel:  ReviewStateLabel(row.ReviewState),
+			ModelFields: row.LlmFields,
+		}
+		if row.CreatedAt.Valid {
+			queue.Items[i].CreatedAt = row.CreatedAt.Time.Format("2006-01-02 15:04")
+		}
+	}
+	return queue, nil
+}
+
+// Review returns the document's review state and the fields a model wrote.
+func (s *reviewService) Review(ctx context.Context, doc db.FindDocumentByIDRow) (db_types.DocumentReview, error) {
+	review := db_types.DocumentReview{
+		State:      doc.ReviewState,
+		Approved:   doc.ReviewState == repository.ReviewApproved,
+		StateLabel: ReviewStateLabel(doc.ReviewState),
+		ApprovedBy: doc.ApprovedByName.String,
+	}
+	if doc.ApprovedAt.Valid {
+		review.ApprovedAt = doc.ApprovedAt.Time.Format("2006-01-02 15:04")
+	}
+
+	sources, err := s.store.ListDocumentFieldSources(ctx, doc.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list field sources", "docID", doc.ID, "error", err)
+		return review, fmt.Errorf("failed to list field sources: %w", err)
+	}
+	for _, source := range sources {
+		if source.Source == repository.SourceLLM {
+			review.ModelFields = append(review.ModelFields, source.Field)
+		}
+	}
+	return review, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeReviewStore struct {
+	maxDocuments int32
+	sources      []db.ListDocumentFieldSourcesRow
+}
+
+func (f *fakeReviewStore) ListDocumentFieldSources(context.Context, uuid.UUID) ([]db.ListDocumentFieldSourcesRow, error) {
+	return f.sources, nil
+}
+
+func (f *fakeReviewStore) ListDocumentsForReview(_ context.Context, maxDocuments int32) ([]db.ListDocumentsForReviewRow, error) {
+	f.maxDocuments = maxDocuments
+	return []db.ListDocumentsForReviewRow{
+		{
+			ID:          uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			Title:       "Field Report",
+			FileName:    "field-report.pdf",
+			ReviewState: repository.ReviewAIExtracted,
+			CreatedAt:   sql.NullTime{Time: time.Date(2025, 3, 10, 15, 4, 5, 0, time.UTC), Valid: true},
+			LlmFields:   []string{"abstract", "title"},
+			Total:       2,
+		},
+		{
+			ID:          uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			Title:       "Annual Report",
+			FileName:    "annual.pdf",
+			ReviewState: repository.ReviewInReview,
+			Total:       2,
+		},
+	}, nil
+}
+
+func TestReviewService_Queue(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	store := &fakeReviewStore{}
+
+	queue, err := NewReviewService(log, store).Queue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(maxReviewQueue), store.maxDocuments)
+	assert.Equal(t, int64(2), queue.Total)
+	assert.Equal(t, db_types.ReviewItem{
+		ID:          "11111111-1111-1111-1111-111111111111",
+		Title:       "Field Report",
+		FileName:    "field-report.pdf",
+		State:       repository.ReviewAIExtracted,
+		StateLabel:  "AI extracted",
+		CreatedAt:   "2025-03-10 15:04",
+		ModelFields: []string{"abstract", "title"},
+	}, queue.Items[0])
+	assert.Equal(t, "In review", queue.Items[1].StateLabel)
+	assert.Empty(t, queue.Items[1].CreatedAt)
+}
+
+func TestReviewService_Review(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	store := &fakeReviewStore{sources: []db.ListDocumentFieldSourcesRow{
+		{Field: "abstract", Source: repository.SourceLLM},
+		{Field: "title", Source: repository.SourceHuman},
+		{Field: "keywords", Source: repository.SourceLLM},
+	}}
+	service := NewReviewService(log, store)
+
+	review, err := service.Review(context.Background(), db.FindDocumentByIDRow{ReviewState: repository.ReviewInReview})
+	require.NoError(t, err)
+	assert.False(t, review.Approved)
+	assert.Equal(t, "In review", review.StateLabel)
+	assert.Equal(t, []string{"abstract", "keywords"}, review.ModelFields)
+
+	review, err = service.Review(context.Background(), db.FindDocumentByIDRow{
+		ReviewState:    repository.ReviewApproved,
+		ApprovedByName: sql.NullString{String: "reviewer", Valid: true},
+		ApprovedAt:     sql.NullTime{Time: time.Date(2025, 3, 11, 9, 30, 0, 0, time.UTC), Valid: true},
+	})
+	require.NoError(t, err)
+	assert.True(t, review.Approved)
+	assert.Equal(t, "reviewer", review.ApprovedBy)
+	assert.Equal(t, "2025-03-11 09:30", review.ApprovedAt)
+}
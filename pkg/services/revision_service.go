@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+type revisionService struct {
+	log       logger.Logger
+	dbQuerier *db.Queries
+	documents *repository.DocumentRepository
+}
+
+// NewRevisionService creates the metadata revision service. Revisions are
+// recorded by documents whenever metadata is saved.
+func NewRevisionService(log logger.Logger, dbQuerier *db.Queries, documents *repository.DocumentRepository) DocumentRevisions {
+	serviceLogger := log.With("service", "Revision")
+	return &revisionService{
+		log:       serviceLogger,
+		dbQuerier: dbQuerier,
+		documents: documents,
+	}
+}
+
+// ListRevisions returns the document's revisions, newest first.
+func (s *revisionService) ListRevisions(ctx context.Context, docID uuid.UUID) ([]db_types.RevisionSummary, error) {
+	rows, err := s.dbQuerier.ListDocumentRevisions(ctx, docID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list revisions", "docID", docID, "error", err)
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	revisions := make([]db_types.RevisionSummary, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, db_types.RevisionSummary{
+			Revision:      row.Revision,
+			RestoredFrom:  row.RestoredFrom.Int32,
+			CreatedByName: row.CreatedByName,
+			CreatedAt:     row.CreatedAt.Format("2006-01-02 15:04"),
+		})
+	}
+	return revisions, nil
+}
+
+// CompareRevisions returns the fields that differ between two revisions.
+func (s *revisionService) CompareRevisions(ctx context.Context, docID uuid.UUID, from, to int32) ([]db_types.AuditChange, error) {
+	before, err := s.getRevision(ctx, docID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.getRevision(ctx, docID, to)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(RevisionSnapshot(before), RevisionSnapshot(after)).entries(), nil
+}
+
+// RestoreRevision sets the document's metadata back to an earlier revision and
+// records the result as a new revision, which it returns. Either all of it
+// happens or none of it does.
+func (s *revisionService) RestoreRevision(ctx context.Context, docID uuid.UUID, revision int32, actor Actor) (int32, error) {
+	rev, err := s.getRevision(ctx, docID, revision)
+	if err != nil {
+		return 0, err
+	}
+
+	restored, err := s.documents.SaveMetadata(ctx, repository.MetadataUpdate{
+		DocID:           docID,
+		Title:           rev.Title,
+		Abstract:        rev.Abstract,
+		PublishDate:     rev.PublishDate,
+		Language:        rev.Language,
+		EnglishTitle:    rev.EnglishTitle,
+		EnglishAbstract: rev.EnglishAbstract,
+		Terms: repository.Terms{
+			Authors:    repository.ByName(rev.Authors),
+			Keywords:   repository.ByName(rev.Keywords),
+			Categories: repository.ByName(rev.Categories),
+			Regions:    repository.ByName(rev.Regions),
+			Publisher:  repository.PublisherByName(rev.Source.String),
+		},
+	}, repository.RevisionInfo{
+		CreatedBy:     actor.ID,
+		CreatedByName: actor.Username,
+		RestoredFrom:  revision,
+	})
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to restore revision", "docID", docID, "revision", revision, "error", err)
+		return 0, fmt.Errorf("failed to restore revision: %w", err)
+	}
+
+	s.log.InfoContext(ctx, "Revision restored", "docID", docID, "revision", revision, "newRevision", restored)
+	return restored, nil
+}
+
+func (s *revisionService) getRevision(ctx context.Context, docID uuid.UUID, revision int32) (db.DocumentRevision, error) {
+	rev, err := s.dbQuerier.GetDocumentRevision(ctx, db.GetDocumentRevisionParams{DocID: docID, Revision: revision})
+	if errors.Is(err, sql.ErrNoRows) {
+		return rev, fmt.Errorf("%w: %d", ErrRevisionNotFound, revision)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to get revision", "docID", docID, "revision", revision, "error", err)
+		return rev, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return rev, nil
+}
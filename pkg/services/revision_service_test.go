@@ -0,0 +1,34 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+)
+
+func TestRevisionSnapshot_MatchesDocumentSnapshot(t *testing.T) {
+	published := sql.NullTime{Time: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	doc := db.FindDocumentByIDRow{
+		Title:        "Report",
+		Abstract:     sql.NullString{String: "About", Valid: true},
+		PublishDate:  published,
+		AuthorNames:  []string{"Zed", "Amy"},
+		KeywordNames: []string{"peace"},
+	}
+	rev := db.DocumentRevision{
+		Title:       "Report",
+		Abstract:    sql.NullString{String: "About", Valid: true},
+		PublishDate: published,
+		Authors:     []string{"Amy", "Zed"},
+		Keywords:    []string{"peace"},
+	}
+
+	assert.Empty(t, Diff(DocumentSnapshot(doc), RevisionSnapshot(rev)))
+
+	rev.Title = "Old report"
+	assert.Equal(t, Changes{"title": {Before: "Report", After: "Old report"}}, Diff(DocumentSnapshot(doc), RevisionSnapshot(rev)))
+}
@@ -0,0 +1,52 @@
+package services
+
+import "slices"
+
+type Role string
+
+type Permission string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleEditor   Role = "editor"
+	RoleReviewer Role = "reviewer"
+	RoleAdmin    Role = "admin"
+
+	PermUpload           Permission = "upload"
+	PermEditMetadata     Permission = "edit_metadata"
+	PermMarkDelete       Permission = "mark_delete"
+	PermManageTaxonomy   Permission = "manage_taxonomy"
+	PermManageUsers      Permission = "manage_users"
+	PermManageExtraction Permission = "manage_extraction"
+	PermReviewMetadata   Permission = "review_metadata"
+)
+
+// Roles lists every role, from least to most access. The same values are
+// enforced by the users_role_check constraint.
+var Roles = []Role{RoleViewer, RoleEditor, RoleReviewer, RoleAdmin}
+
+// rolePermissions is the single place that decides what each role may do.
+// Viewers can sign in and browse the document lists but change nothing.
+var rolePermissions = map[Role][]Permission{
+	RoleViewer:   {},
+	RoleEditor:   {PermUpload, PermEditMetadata},
+	RoleReviewer: {PermUpload, PermEditMetadata, PermMarkDelete, PermManageTaxonomy, PermReviewMetadata},
+	RoleAdmin:    {PermUpload, PermEditMetadata, PermMarkDelete, PermManageTaxonomy, PermManageUsers, PermManageExtraction, PermReviewMetadata},
+}
+
+// ParseRole returns the role with the given name.
+func ParseRole(name string) (Role, bool) {
+	role := Role(name)
+	_, ok := rolePermissions[role]
+	return role, ok
+}
+
+// Can reports whether the role has the permission. Unknown roles have none.
+func (r Role) Can(perm Permission) bool {
+	return slices.Contains(rolePermissions[r], perm)
+}
+
+// Permissions returns the role's permissions.
+func (r Role) Permissions() []Permission {
+	return slices.Clone(rolePermissions[r])
+}
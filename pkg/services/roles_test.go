@@ -0,0 +1,85 @@
+package services
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+func TestRole_Can(t *testing.T) {
+	tests := []struct {
+		role Role
+		perm Permission
+		want bool
+	}{
+		{RoleViewer, PermUpload, false},
+		{RoleEditor, PermUpload, true},
+		{RoleEditor, PermEditMetadata, true},
+		{RoleEditor, PermMarkDelete, false},
+		{RoleReviewer, PermMarkDelete, true},
+		{RoleReviewer, PermManageTaxonomy, true},
+		{RoleReviewer, PermManageUsers, false},
+		{RoleAdmin, PermManageUsers, true},
+		{RoleReviewer, PermManageExtraction, false},
+		{RoleAdmin, PermManageExtraction, true},
+		{RoleEditor, PermReviewMetadata, false},
+		{RoleReviewer, PermReviewMetadata, true},
+		{Role("owner"), PermUpload, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.perm), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.Can(tt.perm))
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, role := range Roles {
+		got, ok := ParseRole(string(role))
+		assert.True(t, ok)
+		assert.Equal(t, role, got)
+	}
+	_, ok := ParseRole("master")
+	assert.False(t, ok)
+}
+
+func TestRequirePermission(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	sm, err := NewGorillaSessionManager(sessions.NewCookieStore([]byte("test-key")), "test", log, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		user *db.User
+		want int
+	}{
+		{"no user", nil, http.StatusForbidden},
+		{"viewer", &db.User{Role: string(RoleViewer)}, http.StatusForbidden},
+		{"editor", &db.User{Role: string(RoleEditor)}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/upload", nil), rec)
+			if tt.user != nil {
+				c.Set(contextKeyUser, tt.user)
+			}
+
+			handler := sm.RequirePermission(PermUpload)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			require.NoError(t, handler(c))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
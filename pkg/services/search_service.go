@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/DSSD-Madison/gmu/pkg/awskendra"
 	"github.com/DSSD-Madison/gmu/pkg/db/generated"
 	db_util "github.com/DSSD-Madison/gmu/pkg/db/util"
 	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
 )
 
+// regionFacet is the filter and facet key of document regions.
+const regionFacet = "Region"
+
 type SearchService struct {
 	log          logger.Logger
 	kendraClient awskendra.Client
@@ -29,7 +35,8 @@ func NewSearchService(log logger.Logger, kendra awskendra.Client, dbQuerier *db.
 func (s *SearchService) SearchDocuments(ctx context.Context, query string, filters url.Values, pageNum int) (awskendra.KendraResults, error) {
 	s.log.DebugContext(ctx, "Starting document search", "query", query, "page", pageNum)
 
-	kendraFilterMap := convertURLValuesToKendraFilters(filters)
+	regions := s.regionTree(ctx)
+	kendraFilterMap := convertURLValuesToKendraFilters(filters, regions)
 	s.log.DebugContext(ctx, "Converted filters for Kendra", "filter_map", kendraFilterMap)
 
 	results, err := s.kendraClient.MakeQuery(ctx, query, kendraFilterMap, pageNum)
@@ -38,6 +45,7 @@ func (s *SearchService) SearchDocuments(ctx context.Context, query string, filte
 		return awskendra.KendraResults{}, fmt.Errorf("failed to retrieve search results: %w", err)
 	}
 	s.log.DebugContext(ctx, "Received results from Kendra", "count", results.Count)
+	results.Filters = nestRegionFacet(results.Filters, regions)
 
 	if len(results.Results) > 0 {
 		s.log.DebugContext(ctx, "Attempting to enrich results from database")
@@ -56,8 +64,26 @@ func (s *SearchService) SearchDocuments(ctx context.Context, query string, filte
 	return results, nil
 }
 
-// convertURLValuesToKendraFilters is a helper to transform filter format
-func convertURLValuesToKendraFilters(values url.Values) map[string][]string {
+// regionTree loads the region hierarchy. On failure it logs and returns nil,
+// and searches filter on the selected regions alone.
+func (s *SearchService) regionTree(ctx context.Context) *taxonomy.RegionTree {
+	rows, err := s.dbQuerier.ListRegionHierarchy(ctx)
+	if err != nil {
+		s.log.WarnContext(ctx, "Failed to load region hierarchy", "error", err)
+		return nil
+	}
+	regions := make([]taxonomy.Region, len(rows))
+	for i, row := range rows {
+		regions[i] = taxonomy.Region{ID: row.ID, Name: row.Name, ParentID: row.ParentID.UUID}
+	}
+	return taxonomy.NewRegionTree(regions)
+}
+
+// convertURLValuesToKendraFilters is a helper to transform filter format. A
+// selected region also selects every region within it, so that "Africa"
+// matches documents tagged only "Kenya". Those regions are named as the
+// database spells them, so they are title-cased as Kendra holds them.
+func convertURLValuesToKendraFilters(values url.Values, regions *taxonomy.RegionTree) map[string][]string {
 	if values == nil {
 		return nil
 	}
@@ -66,6 +92,12 @@ func convertURLValuesToKendraFilters(values url.Values) map[string][]string {
 		if len(v) > 0 {
 			valsCopy := make([]string, len(v))
 			copy(valsCopy, v)
+			if k == regionFacet {
+				valsCopy = regions.Expand(valsCopy)
+				for i := len(v); i < len(valsCopy); i++ {
+					valsCopy[i] = taxonomy.TitleCase(valsCopy[i])
+				}
+			}
 			kendraFilters[k] = valsCopy
 		}
 	}
@@ -74,3 +106,78 @@ func convertURLValuesToKendraFilters(values url.Values) map[string][]string {
 	}
 	return kendraFilters
 }
+
+// nestRegionFacet orders the region facet's options as a tree, each under the
+// region it belongs to. Regions that have no count of their own but contain
+// listed regions are added with a count of 0, so they can still be selected.
+func nestRegionFacet(filters []awskendra.FilterCategory, regions *taxonomy.RegionTree) []awskendra.FilterCategory {
+	if regions == nil {
+		return filters
+	}
+	for i, filter := range filters {
+		if filter.Category != regionFacet {
+			continue
+		}
+
+		options := make(map[string]*awskendra.FilterOption)
+		var order []string // first appearance, so the largest counts lead
+		add := func(option awskendra.FilterOption) {
+			key := strings.ToLower(option.Label)
+			if _, ok := options[key]; !ok {
+				options[key] = &option
+				order = append(order, key)
+			}
+		}
+		for _, option := range filter.Options {
+			add(option)
+		}
+		for _, option := range filter.Options {
+			for _, ancestor := range regions.Ancestors(option.Label) {
+				add(awskendra.FilterOption{Label: ancestor})
+			}
+		}
+
+		children := make(map[string][]string)
+		var roots []string
+		for _, key := range order {
+			parent, ok := regions.Parent(options[key].Label)
+			if ok {
+				options[key].Parent = parent
+				children[strings.ToLower(parent)] = append(children[strings.ToLower(parent)], key)
+			} else {
+				roots = append(roots, key)
+			}
+		}
+
+		// Ancestors were added after every listed option, so a parent would
+		// otherwise trail its children; rank each by its best descendant.
+		rank := make(map[string]int)
+		var rankOf func(key string) int
+		rankOf = func(key string) int {
+			if r, ok := rank[key]; ok {
+				return r
+			}
+			rank[key] = slices.Index(order, key)
+			for _, child := range children[key] {
+				rank[key] = min(rank[key], rankOf(child))
+			}
+			return rank[key]
+		}
+		byRank := func(a, b string) int { return rankOf(a) - rankOf(b) }
+
+		nested := make([]awskendra.FilterOption, 0, len(order))
+		var walk func(keys []string, depth int)
+		walk = func(keys []string, depth int) {
+			slices.SortStableFunc(keys, byRank)
+			for _, key := range keys {
+				option := *options[key]
+				option.Depth = depth
+				nested = append(nested, option)
+				walk(children[key], depth+1)
+			}
+		}
+		walk(roots, 0)
+		filters[i].Options = nested
+	}
+	return filters
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+func newSearchRegionTree() *taxonomy.RegionTree {
+	africa, eastAfrica, kenya, uganda, egypt, asia := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	europe, balkans, bosnia := uuid.New(), uuid.New(), uuid.New()
+	return taxonomy.NewRegionTree([]taxonomy.Region{
+		{ID: africa, Name: "Africa"},
+		{ID: eastAfrica, Name: "East Africa", ParentID: africa},
+		{ID: kenya, Name: "Kenya", ParentID: eastAfrica},
+		{ID: uganda, Name: "Uganda", ParentID: eastAfrica},
+		{ID: egypt, Name: "Egypt", ParentID: africa},
+		{ID: asia, Name: "Asia"},
+		{ID: europe, Name: "Europe"},
+		{ID: balkans, Name: "Western Balkans", ParentID: europe},
+		{ID: bosnia, Name: "Bosnia and Herzegovina", ParentID: balkans},
+	})
+}
+
+func TestConvertURLValuesToKendraFilters(t *testing.T) {
+	regions := newSearchRegionTree()
+
+	filters := convertURLValuesToKendraFilters(url.Values{
+		"Region":   {"africa", "Asia"},
+		"Keywords": {"Peacebuilding"},
+		"Authors":  {},
+	}, regions)
+	assert.Equal(t, map[string][]string{
+		"Region":   {"africa", "Asia", "East Africa", "Kenya", "Uganda", "Egypt"},
+		"Keywords": {"Peacebuilding"},
+	}, filters)
+
+	filters = convertURLValuesToKendraFilters(url.Values{"Region": {"Europe"}}, regions)
+	assert.Equal(t, map[string][]string{
+		"Region": {"Europe", "Western Balkans", "Bosnia And Herzegovina"},
+	}, filters, "regions within the selection are named as Kendra holds them")
+
+	filters = convertURLValuesToKendraFilters(url.Values{"Region": {"Kenya"}}, nil)
+	assert.Equal(t, map[string][]string{"Region": {"Kenya"}}, filters, "without a hierarchy the selection is used as is")
+
+	assert.Nil(t, convertURLValuesToKendraFilters(nil, regions))
+}
+
+func TestNestRegionFacet(t *testing.T) {
+	filters := []awskendra.FilterCategory{
+		{Category: "Keywords", Options: []awskendra.FilterOption{{Label: "Peacebuilding", Count: 4}}},
+		{Category: "Region", Options: []awskendra.FilterOption{
+			{Label: "Asia", Count: 9},
+			{Label: "Kenya", Count: 5, Selected: true},
+			{Label: "Egypt", Count: 3},
+			{Label: "Uganda", Count: 2},
+			{Label: "Atlantis", Count: 1},
+		}},
+	}
+
+	nested := nestRegionFacet(filters, newSearchRegionTree())
+
+	assert.Equal(t, []awskendra.FilterOption{{Label: "Peacebuilding", Count: 4}}, nested[0].Options)
+	assert.Equal(t, []awskendra.FilterOption{
+		{Label: "Asia", Count: 9},
+		{Label: "Africa"},
+		{Label: "East Africa", Parent: "Africa", Depth: 1},
+		{Label: "Kenya", Count: 5, Selected: true, Parent: "East Africa", Depth: 2},
+		{Label: "Uganda", Count: 2, Parent: "East Africa", Depth: 2},
+		{Label: "Egypt", Count: 3, Parent: "Africa", Depth: 1},
+		{Label: "Atlantis", Count: 1},
+	}, nested[1].Options)
+}
+
+func TestNestRegionFacetWithoutHierarchy(t *testing.T) {
+	options := []awskendra.FilterOption{{Label: "Kenya", Count: 5}, {Label: "Egypt", Count: 3}}
+	filters := []awskendra.FilterCategory{{Category: "Region", Options: options}}
+
+	assert.Equal(t, options, nestRegionFacet(filters, nil)[0].Options)
+}
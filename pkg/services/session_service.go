@@ -18,7 +18,9 @@ import (
 const (
 	sessionKeyAuthenticated = "authenticated"
 	sessionKeyUserID        = "user_id"
-	sessionKeyIsMaster      = "is_master"
+	sessionKeyRole          = "role"
+
+	contextKeyUser = "user"
 )
 
 type GorillaSessionManager struct {
@@ -61,7 +63,7 @@ func (sm *GorillaSessionManager) Create(c echo.Context, user db.User) error {
 
 	session.Values[sessionKeyAuthenticated] = true
 	session.Values[sessionKeyUserID] = user.ID.String()
-	session.Values[sessionKeyIsMaster] = user.IsMaster
+	session.Values[sessionKeyRole] = user.Role
 
 	err := session.Save(c.Request(), c.Response())
 	if err != nil {
@@ -80,7 +82,7 @@ func (sm *GorillaSessionManager) Destroy(c echo.Context) error {
 
 	session.Values[sessionKeyAuthenticated] = false
 	delete(session.Values, sessionKeyUserID)
-	delete(session.Values, sessionKeyIsMaster)
+	delete(session.Values, sessionKeyRole)
 	session.Options.MaxAge = -1
 
 	err := session.Save(c.Request(), c.Response())
@@ -91,6 +93,10 @@ func (sm *GorillaSessionManager) Destroy(c echo.Context) error {
 }
 
 func (sm *GorillaSessionManager) GetUserID(c echo.Context) (string, bool) {
+	if identity, ok := apiKeyIdentityFromContext(c); ok {
+		return identity.UserID.String(), true
+	}
+
 	session, err := sm.getSession(c.Request())
 	if err != nil || session == nil {
 		return "", false
@@ -109,6 +115,10 @@ func (sm *GorillaSessionManager) GetUserID(c echo.Context) (string, bool) {
 }
 
 func (sm *GorillaSessionManager) IsAuthenticated(c echo.Context) bool {
+	if _, ok := apiKeyIdentityFromContext(c); ok {
+		return true
+	}
+
 	session, err := sm.getSession(c.Request())
 	if err != nil || session == nil {
 		return false
@@ -117,18 +127,68 @@ func (sm *GorillaSessionManager) IsAuthenticated(c echo.Context) bool {
 	return ok && authenticated
 }
 
+// IsMaster reports whether the user has the admin role.
 func (sm *GorillaSessionManager) IsMaster(c echo.Context) bool {
+	// A key acts as an admin only when its owner is one and it has the admin scope.
+	if identity, ok := apiKeyIdentityFromContext(c); ok {
+		return identity.Role == RoleAdmin && identity.HasScope(ScopeAdmin)
+	}
+
+	role, ok := sm.Role(c)
+	return ok && role == RoleAdmin
+}
+
+// Role returns the signed-in user's role. Behind RequireAuth it is read from the
+// database, so role changes apply without signing in again.
+func (sm *GorillaSessionManager) Role(c echo.Context) (Role, bool) {
+	if identity, ok := apiKeyIdentityFromContext(c); ok {
+		return identity.Role, true
+	}
+	if user, ok := c.Get(contextKeyUser).(*db.User); ok {
+		return Role(user.Role), true
+	}
+
 	if !sm.IsAuthenticated(c) {
-		return false
+		return "", false
 	}
 
 	session, err := sm.getSession(c.Request())
 	if err != nil || session == nil {
-		return false
+		return "", false
+	}
+
+	role, ok := session.Values[sessionKeyRole].(string)
+	return Role(role), ok
+}
+
+// Actor returns the signed-in user for the audit log. It is only set behind RequireAuth.
+func (sm *GorillaSessionManager) Actor(c echo.Context) (Actor, bool) {
+	if identity, ok := apiKeyIdentityFromContext(c); ok {
+		return Actor{ID: identity.UserID, Username: identity.Username}, true
+	}
+	if user, ok := c.Get(contextKeyUser).(*db.User); ok {
+		return Actor{ID: user.ID, Username: user.Username}, true
 	}
+	return Actor{}, false
+}
 
-	isMaster, ok := session.Values[sessionKeyIsMaster].(bool)
-	return ok && isMaster
+func (sm *GorillaSessionManager) HasPermission(c echo.Context, perm Permission) bool {
+	role, ok := sm.Role(c)
+	return ok && role.Can(perm)
+}
+
+// RequirePermission rejects users whose role lacks perm. It must come after RequireAuth.
+func (sm *GorillaSessionManager) RequirePermission(perm Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !sm.HasPermission(c, perm) {
+				role, _ := sm.Role(c)
+				sm.log.WarnContext(c.Request().Context(), "requirePermission: access denied", "permission", perm, "role", role, "path", c.Path())
+				return c.String(http.StatusForbidden, "Access denied")
+			}
+			return next(c)
+		}
+	}
 }
 
 func (sm *GorillaSessionManager) redirectToLogin(c echo.Context) error {
@@ -168,7 +228,7 @@ func (sm *GorillaSessionManager) fetchUserByID(ctx context.Context, userID strin
 		ID:           row.ID,
 		Username:     row.Username,
 		PasswordHash: row.PasswordHash,
-		IsMaster:     row.IsMaster,
+		Role:         row.Role,
 		CreatedAt:    row.CreatedAt,
 	}
 
@@ -177,6 +237,11 @@ func (sm *GorillaSessionManager) fetchUserByID(ctx context.Context, userID strin
 
 func (sm *GorillaSessionManager) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		// Requests already authenticated by APIKeyService.RequireScope carry no session.
+		if _, ok := apiKeyIdentityFromContext(c); ok {
+			return next(c)
+		}
+
 		session, _ := sm.getSession(c.Request())
 		auth, ok := session.Values["authenticated"].(bool)
 		sm.log.Info("requireAuth called", "auth", auth, "ok", ok)
@@ -205,6 +270,7 @@ func (sm *GorillaSessionManager) RequireAuth(next echo.HandlerFunc) echo.Handler
 			return sm.forceLogoutAndRedirect(c)
 		}
 
+		c.Set(contextKeyUser, user)
 		return next(c)
 	}
 }
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+const (
+	DefaultSpendDays = 30
+	MaxSpendDays     = 366
+)
+
+// SpendStore is the subset of db.Queries used by the spend report.
+type SpendStore interface {
+	SpendByDay(ctx context.Context, since time.Time) ([]db.SpendByDayRow, error)
+	SpendByUser(ctx context.Context, since time.Time) ([]db.SpendByUserRow, error)
+	SpendByModel(ctx context.Context, since time.Time) ([]db.SpendByModelRow, error)
+}
+
+type spendService struct {
+	log   logger.Logger
+	store SpendStore
+	now   func() time.Time
+}
+
+// NewSpendService creates the service that totals the tokens and cost of the
+// recorded metadata extractions.
+func NewSpendService(log logger.Logger, store SpendStore) SpendReporter {
+	serviceLogger := log.With("service", "Spend")
+	return &spendService{
+		log:   serviceLogger,
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Report totals the extractions of the last days days, including today, by
+// day, by the user who uploaded the document and by model. days is clamped to
+// between 1 and MaxSpendDays.
+func (s *spendService) Report(ctx context.Context, days int) (db_types.SpendReport, error) {
+	days = min(max(days, 1), MaxSpendDays)
+	now := s.now()
+	since := time.Date(now.Year(), now.Month(), now.Day()-days+1, 0, 0, 0, 0, now.Location())
+	report := db_types.SpendReport{
+		Days:  days,
+		Since: since.Format("2006-01-02"),
+	}
+
+	byDay, err := s.store.SpendByDay(ctx, since)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to total spend by day", "error", err)
+		return report, fmt.Errorf("failed to total spend by day: %w", err)
+	}
+	byUser, err := s.store.SpendByUser(ctx, since)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to total spend by user", "error", err)
+		return report, fmt.Errorf("failed to total spend by user: %w", err)
+	}
+	byModel, err := s.store.SpendByModel(ctx, since)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to total spend by model", "error", err)
+		return report, fmt.Errorf("failed to total spend by model: %w", err)
+	}
+
+	var totalCost float64
+	report.Total.Label = "Total"
+	report.ByDay = make([]db_types.SpendRow, len(byDay))
+	for i, r := range byDay {
+		report.ByDay[i] = spendRow(r.Day.Format("2006-01-02"), r.Extractions, r.Failed, r.InputTokens, r.OutputTokens, r.Cost, r.Unpriced)
+		report.Total.Extractions += r.Extractions
+		report.Total.Failed += r.Failed
+		report.Total.InputTokens += r.InputTokens
+		report.Total.OutputTokens += r.OutputTokens
+		report.Total.Unpriced += r.Unpriced
+		totalCost += r.Cost
+	}
+	report.Total.Cost = formatCost(totalCost)
+
+	report.ByUser = make([]db_types.SpendRow, len(byUser))
+	for i, r := range byUser {
+		label := r.CreatedByName
+		if label == "" {
+			label = "Unknown"
+		}
+		report.ByUser[i] = spendRow(label, r.Extractions, r.Failed, r.InputTokens, r.OutputTokens, r.Cost, r.Unpriced)
+	}
+
+	report.ByModel = make([]db_types.SpendRow, len(byModel))
+	for i, r := range byModel {
+		label := r.Extractor
+		if r.Model != "" {
+			label = fmt.Sprintf("%s (%s)", r.Extractor, r.Model)
+		}
+		report.ByModel[i] = spendRow(label, r.Extractions, r.Failed, r.InputTokens, r.OutputTokens, r.Cost, r.Unpriced)
+		report.ByModel[i].AvgLatency = (time.Duration(r.AvgLatencyMs) * time.Millisecond).String()
+	}
+	return report, nil
+}
+
+func spendRow(label string, extractions, failed, inputTokens, outputTokens int64, cost float64, unpriced int64) db_types.SpendRow {
+	return db_types.SpendRow{
+		Label:        label,
+		Extractions:  extractions,
+		Failed:       failed,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         formatCost(cost),
+		Unpriced:     unpriced,
+	}
+}
+
+// formatCost formats US dollars to a hundredth of a cent, since a single
+// extraction usually costs less than a cent.
+func formatCost(cost float64) string {
+	return fmt.Sprintf("$%.4f", cost)
+}
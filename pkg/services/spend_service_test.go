@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeSpendStore struct {
+	since time.Time
+}
+
+func (f *fakeSpendStore) SpendByDay(_ context.Context, since time.Time) ([]db.SpendByDayRow, error) {
+	f.since = since
+	return []db.SpendByDayRow{
+		{Day: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Extractions: 3, Failed: 1, InputTokens: 9000, OutputTokens: 600, Cost: 0.003},
+		{Day: time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), Extractions: 2, InputTokens: 4000, OutputTokens: 300, Cost: 0.00137, Unpriced: 1},
+	}, nil
+}
+
+func (f *fakeSpendStore) SpendByUser(context.Context, time.Time) ([]db.SpendByUserRow, error) {
+	return []db.SpendByUserRow{
+		{CreatedByName: "editor", Extractions: 4, Failed: 1, InputTokens: 12000, OutputTokens: 800, Cost: 0.00437},
+		{Extractions: 1, InputTokens: 1000, OutputTokens: 100, Unpriced: 1},
+	}, nil
+}
+
+func (f *fakeSpendStore) SpendByModel(context.Context, time.Time) ([]db.SpendByModelRow, error) {
+	return []db.SpendByModelRow{
+		{Extractor: "bedrock", Model: "haiku", Extractions: 4, InputTokens: 12000, OutputTokens: 800, Cost: 0.00437, AvgLatencyMs: 2350},
+		{Extractor: "heuristic", Extractions: 1},
+	}, nil
+}
+
+func TestSpendService_Report(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	store := &fakeSpendStore{}
+	service := NewSpendService(log, store).(*spendService)
+	service.now = func() time.Time { return time.Date(2025, 3, 10, 15, 4, 5, 0, time.UTC) }
+
+	report, err := service.Report(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), store.since)
+	assert.Equal(t, 7, report.Days)
+	assert.Equal(t, "2025-03-04", report.Since)
+	assert.Equal(t, db_types.SpendRow{Label: "Total", Extractions: 5, Failed: 1, InputTokens: 13000, OutputTokens: 900, Cost: "$0.0044", Unpriced: 1}, report.Total)
+	assert.Equal(t, []string{"2025-03-10", "2025-03-09"}, []string{report.ByDay[0].Label, report.ByDay[1].Label})
+	assert.Equal(t, "$0.0030", report.ByDay[0].Cost)
+	assert.Equal(t, []string{"editor", "Unknown"}, []string{report.ByUser[0].Label, report.ByUser[1].Label})
+	assert.Equal(t, "bedrock (haiku)", report.ByModel[0].Label)
+	assert.Equal(t, "2.35s", report.ByModel[0].AvgLatency)
+	assert.Equal(t, "heuristic", report.ByModel[1].Label)
+
+	report, err = service.Report(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Days, "at least today is reported")
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), store.since)
+
+	report, err = service.Report(context.Background(), 10000)
+	require.NoError(t, err)
+	assert.Equal(t, MaxSpendDays, report.Days)
+}
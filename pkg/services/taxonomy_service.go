@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+const (
+	// maxTermSuggestions caps the suggestions for an autocomplete query.
+	maxTermSuggestions = 10
+	// maxListedTerms caps the terms listed on a taxonomy's admin page.
+	maxListedTerms = 200
+)
+
+var ErrNoMatchingTerm = errors.New("no matching term")
+
+// taxonomyLabels name the taxonomies for display.
+var taxonomyLabels = map[string]string{
+	taxonomy.Authors:    "Author",
+	taxonomy.Categories: "Category",
+	taxonomy.Keywords:   "Keyword",
+	taxonomy.Publishers: "Publisher",
+	taxonomy.Regions:    "Region",
+}
+
+// taxonomyPlurals name the taxonomies' admin pages.
+var taxonomyPlurals = map[string]string{
+	taxonomy.Authors:    "Authors",
+	taxonomy.Categories: "Categories",
+	taxonomy.Keywords:   "Keywords",
+	taxonomy.Publishers: "Publishers",
+	taxonomy.Regions:    "Regions",
+}
+
+// TaxonomyStore is the subset of db.Queries used by the taxonomy service.
+type TaxonomyStore interface {
+	util.TermFinder
+	ListPendingTerms(ctx context.Context) ([]db.ListPendingTermsRow, error)
+
+	ListAuthorsForAdmin(ctx context.Context, arg db.ListAuthorsForAdminParams) ([]db.ListAuthorsForAdminRow, error)
+	ListKeywordsForAdmin(ctx context.Context, arg db.ListKeywordsForAdminParams) ([]db.ListKeywordsForAdminRow, error)
+	ListRegionsForAdmin(ctx context.Context, arg db.ListRegionsForAdminParams) ([]db.ListRegionsForAdminRow, error)
+	ListCategoriesForAdmin(ctx context.Context, arg db.ListCategoriesForAdminParams) ([]db.ListCategoriesForAdminRow, error)
+	ListPublishersForAdmin(ctx context.Context, arg db.ListPublishersForAdminParams) ([]db.ListPublishersForAdminRow, error)
+
+	FindAuthorByID(ctx context.Context, id uuid.UUID) (db.Author, error)
+	FindKeywordByID(ctx context.Context, id uuid.UUID) (db.Keyword, error)
+	FindRegionByID(ctx context.Context, id uuid.UUID) (db.Region, error)
+	FindCategoryByID(ctx context.Context, id uuid.UUID) (db.Category, error)
+	FindPublisherByID(ctx context.Context, id uuid.UUID) (db.Publisher, error)
+
+	ListAuthorDocuments(ctx context.Context, authorID uuid.UUID) ([]db.ListAuthorDocumentsRow, error)
+	ListKeywordDocuments(ctx context.Context, keywordID uuid.UUID) ([]db.ListKeywordDocumentsRow, error)
+	ListRegionDocuments(ctx context.Context, regionID uuid.UUID) ([]db.ListRegionDocumentsRow, error)
+	ListCategoryDocuments(ctx context.Context, categoryID uuid.UUID) ([]db.ListCategoryDocumentsRow, error)
+	ListPublisherDocuments(ctx context.Context, publisherID uuid.UUID) ([]db.ListPublisherDocumentsRow, error)
+
+	ListRegionHierarchy(ctx context.Context) ([]db.ListRegionHierarchyRow, error)
+	SetRegionParent(ctx context.Context, arg db.SetRegionParentParams) (int64, error)
+}
+
+// TermWriter changes terms in a transaction. It is satisfied by
+// *repository.TermRepository.
+type TermWriter interface {
+	Approve(ctx context.Context, kind string, id uuid.UUID) error
+	AddAlias(ctx context.Context, kind string, id uuid.UUID, alias string) error
+	Merge(ctx context.Context, kind string, from, to uuid.UUID) error
+	Rename(ctx context.Context, kind string, id uuid.UUID, name string) error
+	Split(ctx context.Context, kind string, from uuid.UUID, name string, docIDs []uuid.UUID) (uuid.UUID, error)
+	Delete(ctx context.Context, kind string, id uuid.UUID) error
+}
+
+type taxonomyService struct {
+	log    logger.Logger
+	store  TaxonomyStore
+	writer TermWriter
+}
+
+// NewTaxonomyService creates the service that matches names to authors,
+// keywords, regions, categories and publishers for autocomplete, lets admins approve the
+// terms created for names that matched nothing, and renames, merges, splits
+// and deletes terms.
+func NewTaxonomyService(log logger.Logger, store TaxonomyStore, writer TermWriter) TermManager {
+	serviceLogger := log.With("service", "Taxonomy")
+	return &taxonomyService{
+		log:    serviceLogger,
+		store:  store,
+		writer: writer,
+	}
+}
+
+// TaxonomyLabel names a taxonomy for display.
+func TaxonomyLabel(kind string) string {
+	if label, ok := taxonomyLabels[kind]; ok {
+		return label
+	}
+	return kind
+}
+
+// TaxonomyPlural names a taxonomy's terms as a whole, as in "Regions".
+func TaxonomyPlural(kind string) string {
+	if label, ok := taxonomyPlurals[kind]; ok {
+		return label
+	}
+	return kind
+}
+
+// Suggest lists the terms an autocomplete query may mean, as taxonomy.Suggest
+// ranks them, by their canonical names.
+func (s *taxonomyService) Suggest(ctx context.Context, kind, query string) ([]taxonomy.Term, error) {
+	terms, err := util.ListTerms(ctx, s.store, kind)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list terms", "taxonomy", kind, "error", err)
+		return nil, fmt.Errorf("failed to list terms: %w", err)
+	}
+	return taxonomy.Suggest(query, terms, maxTermSuggestions), nil
+}
+
+// Pending lists the terms awaiting approval.
+func (s *taxonomyService) Pending(ctx context.Context) ([]db_types.PendingTerm, error) {
+	rows, err := s.store.ListPendingTerms(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list pending terms", "error", err)
+		return nil, fmt.Errorf("failed to list pending terms: %w", err)
+	}
+
+	existing := make(map[string][]taxonomy.Term)
+	terms := make([]db_types.PendingTerm, len(rows))
+	for i, row := range rows {
+		terms[i] = db_types.PendingTerm{
+			Taxonomy:  row.Taxonomy,
+			Label:     TaxonomyLabel(row.Taxonomy),
+			ID:        row.ID.String(),
+			Name:      row.Name,
+			Documents: row.Documents,
+		}
+
+		// Uploads only reuse exact matches, so a close spelling is suggested here
+		if _, ok := existing[row.Taxonomy]; !ok {
+			list, err := util.ListTerms(ctx, s.store, row.Taxonomy)
+			if err != nil {
+				s.log.WarnContext(ctx, "Failed to list terms for suggestions", "taxonomy", row.Taxonomy, "error", err)
+			}
+			existing[row.Taxonomy] = list
+		}
+		others := slices.DeleteFunc(slices.Clone(existing[row.Taxonomy]), func(term taxonomy.Term) bool { return term.ID == row.ID })
+		if term, ok := taxonomy.Closest(row.Name, others); ok {
+			terms[i].Suggested = term.Name
+		}
+	}
+	return terms, nil
+}
+
+// Approve keeps a pending term as it is.
+func (s *taxonomyService) Approve(ctx context.Context, kind string, id uuid.UUID) error {
+	if err := s.writer.Approve(ctx, kind, id); err != nil {
+		return s.termError(ctx, "Failed to approve term", kind, err)
+	}
+	s.log.InfoContext(ctx, "Term approved", "taxonomy", kind, "id", id)
+	return nil
+}
+
+// MapTo merges a term into the existing term that name refers to, leaving its
+// name as an alias so later uploads use the existing term. It returns the
+// existing term's name.
+func (s *taxonomyService) MapTo(ctx context.Context, kind string, id uuid.UUID, name string) (string, error) {
+	target, err := s.match(ctx, kind, name, id)
+	if err != nil {
+		return "", err
+	}
+	if err := s.writer.Merge(ctx, kind, id, target.ID); err != nil {
+		return "", s.termError(ctx, "Failed to merge term", kind, err)
+	}
+	s.log.InfoContext(ctx, "Term mapped", "taxonomy", kind, "id", id, "to", target.Name)
+	return target.Name, nil
+}
+
+// AddAlias records alias as another name for the term name refers to, and
+// returns that term's name.
+func (s *taxonomyService) AddAlias(ctx context.Context, kind, name, alias string) (string, error) {
+	target, err := s.match(ctx, kind, name, uuid.Nil)
+	if err != nil {
+		return "", err
+	}
+	if err := s.writer.AddAlias(ctx, kind, target.ID, alias); err != nil {
+		return "", s.termError(ctx, "Failed to add alias", kind, err)
+	}
+	s.log.InfoContext(ctx, "Alias added", "taxonomy", kind, "alias", alias, "term", target.Name)
+	return target.Name, nil
+}
+
+// List returns the terms of a taxonomy whose names contain query, or all of
+// them for an empty query, with their aliases and document counts.
+func (s *taxonomyService) List(ctx context.Context, kind, query string) (db_types.TermList, error) {
+	query = strings.TrimSpace(query)
+	list := db_types.TermList{Taxonomy: kind, Label: TaxonomyPlural(kind), Query: query}
+	var err error
+	switch kind {
+	case taxonomy.Authors:
+		var rows []db.ListAuthorsForAdminRow
+		rows, err = s.store.ListAuthorsForAdmin(ctx, db.ListAuthorsForAdminParams{Query: query, MaxTerms: maxListedTerms})
+		for _, row := range rows {
+			list.Total = row.Total
+			list.Terms = append(list.Terms, db_types.TermUsage{ID: row.ID.String(), Name: row.Name, Approved: row.Approved, Documents: row.Documents, Aliases: row.Aliases})
+		}
+	case taxonomy.Keywords:
+		var rows []db.ListKeywordsForAdminRow
+		rows, err = s.store.ListKeywordsForAdmin(ctx, db.ListKeywordsForAdminParams{Query: query, MaxTerms: maxListedTerms})
+		for _, row := range rows {
+			list.Total = row.Total
+			list.Terms = append(list.Terms, db_types.TermUsage{ID: row.ID.String(), Name: row.Name, Approved: row.Approved, Documents: row.Documents, Aliases: row.Aliases})
+		}
+	case taxonomy.Regions:
+		var rows []db.ListRegionsForAdminRow
+		rows, err = s.store.ListRegionsForAdmin(ctx, db.ListRegionsForAdminParams{Query: query, MaxTerms: maxListedTerms})
+		for _, row := range rows {
+			list.Total = row.Total
+			list.Terms = append(list.Terms, db_types.TermUsage{ID: row.ID.String(), Name: row.Name, Approved: row.Approved, Documents: row.Documents, Aliases: row.Aliases})
+		}
+	case taxonomy.Categories:
+		var rows []db.ListCategoriesForAdminRow
+		rows, err = s.store.ListCategoriesForAdmin(ctx, db.ListCategoriesForAdminParams{Query: query, MaxTerms: maxListedTerms})
+		for _, row := range rows {
+			list.Total = row.Total
+			list.Terms = append(list.Terms, db_types.TermUsage{ID: row.ID.String(), Name: row.Name, Approved: row.Approved, Documents: row.Documents, Aliases: row.Aliases})
+		}
+	case taxonomy.Publishers:
+		var rows []db.ListPublishersForAdminRow
+		rows, err = s.store.ListPublishersForAdmin(ctx, db.ListPublishersForAdminParams{Query: query, MaxTerms: maxListedTerms})
+		for _, row := range rows {
+			list.Total = row.Total
+			list.Terms = append(list.Terms, db_types.TermUsage{ID: row.ID.String(), Name: row.Name, Approved: row.Approved, Documents: row.Documents, Aliases: row.Aliases})
+		}
+	default:
+		return db_types.TermList{}, fmt.Errorf("%w: %q", util.ErrUnknownTaxonomy, kind)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list terms", "taxonomy", kind, "error", err)
+		return db_types.TermList{}, fmt.Errorf("failed to list terms: %w", err)
+	}
+	return list, nil
+}
+
+// Term returns a term with the documents that use it.
+func (s *taxonomyService) Term(ctx context.Context, kind string, id uuid.UUID) (db_types.TermDetail, error) {
+	detail := db_types.TermDetail{Taxonomy: kind, Label: TaxonomyLabel(kind), ID: id.String()}
+	var err error
+	switch kind {
+	case taxonomy.Authors:
+		var term db.Author
+		var rows []db.ListAuthorDocumentsRow
+		if term, err = s.store.FindAuthorByID(ctx, id); err == nil {
+			detail.Name = term.Name
+			detail.ORCID = term.Orcid.String
+			detail.Affiliation = term.Affiliation.String
+			rows, err = s.store.ListAuthorDocuments(ctx, id)
+		}
+		for _, row := range rows {
+			detail.Documents = append(detail.Documents, db_types.TermDocument{ID: row.ID.String(), Title: row.Title})
+		}
+	case taxonomy.Keywords:
+		var term db.Keyword
+		var rows []db.ListKeywordDocumentsRow
+		if term, err = s.store.FindKeywordByID(ctx, id); err == nil {
+			detail.Name = term.Name
+			rows, err = s.store.ListKeywordDocuments(ctx, id)
+		}
+		for _, row := range rows {
+			detail.Documents = append(detail.Documents, db_types.TermDocument{ID: row.ID.String(), Title: row.Title})
+		}
+	case taxonomy.Regions:
+		var term db.Region
+		var rows []db.ListRegionDocumentsRow
+		if term, err = s.store.FindRegionByID(ctx, id); err == nil {
+			detail.Name = term.Name
+			detail.ISOCode = term.IsoCode.String
+			detail.Parent, err = s.regionName(ctx, term.ParentID)
+		}
+		if err == nil {
+			rows, err = s.store.ListRegionDocuments(ctx, id)
+		}
+		for _, row := range rows {
+			detail.Documents = append(detail.Documents, db_types.TermDocument{ID: row.ID.String(), Title: row.Title})
+		}
+	case taxonomy.Categories:
+		var term db.Category
+		var rows []db.ListCategoryDocumentsRow
+		if term, err = s.store.FindCategoryByID(ctx, id); err == nil {
+			detail.Name = term.Name
+			rows, err = s.store.ListCategoryDocuments(ctx, id)
+		}
+		for _, row := range rows {
+			detail.Documents = append(detail.Documents, db_types.TermDocument{ID: row.ID.String(), Title: row.Title})
+		}
+	case taxonomy.Publishers:
+		var term db.Publisher
+		var rows []db.ListPublisherDocumentsRow
+		if term, err = s.store.FindPublisherByID(ctx, id); err == nil {
+			detail.Name = term.Name
+			rows, err = s.store.ListPublisherDocuments(ctx, id)
+		}
+		for _, row := range rows {
+			detail.Documents = append(detail.Documents, db_types.TermDocument{ID: row.ID.String(), Title: row.Title})
+		}
+	default:
+		return db_types.TermDetail{}, fmt.Errorf("%w: %q", util.ErrUnknownTaxonomy, kind)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return db_types.TermDetail{}, fmt.Errorf("%w: %s", repository.ErrTermNotFound, id)
+	}
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to load term", "taxonomy", kind, "id", id, "error", err)
+		return db_types.TermDetail{}, fmt.Errorf("failed to load term: %w", err)
+	}
+	return detail, nil
+}
+
+// Rename gives a term a new name, keeping the old one as an alias. It refuses
+// a name that another term or alias already matches, since the two should be
+// merged instead.
+func (s *taxonomyService) Rename(ctx context.Context, kind string, id uuid.UUID, name string) error {
+	name, err := termName(name)
+	if err != nil {
+		return err
+	}
+	if other, ok, err := s.sameName(ctx, kind, name, id); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("%w: %s is already %s; merge into it instead", repository.ErrTermExists, name, other.Name)
+	}
+
+	if err := s.writer.Rename(ctx, kind, id, name); err != nil {
+		return s.termError(ctx, "Failed to rename term", kind, err)
+	}
+	s.log.InfoContext(ctx, "Term renamed", "taxonomy", kind, "id", id, "name", name)
+	return nil
+}
+
+// Split moves some of a term's documents to the term named name, which is
+// created if no term or alias matches it. It returns the name the documents
+// now use.
+func (s *taxonomyService) Split(ctx context.Context, kind string, id uuid.UUID, name string, docIDs []uuid.UUID) (string, error) {
+	name, err := termName(name)
+	if err != nil {
+		return "", err
+	}
+	if len(docIDs) == 0 {
+		return "", fmt.Errorf("%w: select the documents to move", ErrInvalidTerm)
+	}
+	if other, ok, err := s.sameName(ctx, kind, name, uuid.Nil); err != nil {
+		return "", err
+	} else if ok {
+		if other.ID == id {
+			return "", fmt.Errorf("%w: %s is this term", ErrInvalidTerm, name)
+		}
+		name = other.Name
+	}
+
+	if _, err := s.writer.Split(ctx, kind, id, name, docIDs); err != nil {
+		return "", s.termError(ctx, "Failed to split term", kind, err)
+	}
+	s.log.InfoContext(ctx, "Term split", "taxonomy", kind, "id", id, "to", name, "documents", len(docIDs))
+	return name, nil
+}
+
+// Delete removes a term that no document uses.
+func (s *taxonomyService) Delete(ctx context.Context, kind string, id uuid.UUID) error {
+	if err := s.writer.Delete(ctx, kind, id); err != nil {
+		return s.termError(ctx, "Failed to delete term", kind, err)
+	}
+	s.log.InfoContext(ctx, "Term deleted", "taxonomy", kind, "id", id)
+	return nil
+}
+
+// SetRegionParent places a region within the region named parent, or at the
+// top level when parent is blank. It returns the parent's name. A region
+// cannot be placed within itself or any region within it.
+func (s *taxonomyService) SetRegionParent(ctx context.Context, id uuid.UUID, parent string) (string, error) {
+	parentID := uuid.NullUUID{}
+	if strings.TrimSpace(parent) != "" {
+		term, err := s.match(ctx, taxonomy.Regions, parent, uuid.Nil)
+		if err != nil {
+			return "", err
+		}
+		rows, err := s.store.ListRegionHierarchy(ctx)
+		if err != nil {
+			return "", s.termError(ctx, "Failed to load region hierarchy", taxonomy.Regions, err)
+		}
+		regions := make([]taxonomy.Region, len(rows))
+		for i, row := range rows {
+			regions[i] = taxonomy.Region{ID: row.ID, Name: row.Name, ParentID: row.ParentID.UUID}
+		}
+		if taxonomy.NewRegionTree(regions).IsWithin(term.ID, id) {
+			return "", fmt.Errorf("%w: %s is within this region", ErrInvalidTerm, term.Name)
+		}
+		parentID = uuid.NullUUID{UUID: term.ID, Valid: true}
+		parent = term.Name
+	} else {
+		parent = ""
+	}
+
+	n, err := s.store.SetRegionParent(ctx, db.SetRegionParentParams{ParentID: parentID, ID: id})
+	if err != nil {
+		return "", s.termError(ctx, "Failed to set region parent", taxonomy.Regions, err)
+	}
+	if n == 0 {
+		return "", fmt.Errorf("%w: %s", repository.ErrTermNotFound, id)
+	}
+	s.log.InfoContext(ctx, "Region parent set", "id", id, "parent", parent)
+	return parent, nil
+}
+
+// regionName returns the name of the region id, or "" when id is null.
+func (s *taxonomyService) regionName(ctx context.Context, id uuid.NullUUID) (string, error) {
+	if !id.Valid {
+		return "", nil
+	}
+	region, err := s.store.FindRegionByID(ctx, id.UUID)
+	if err != nil {
+		return "", err
+	}
+	return region.Name, nil
+}
+
+// termName tidies the spacing of a name an admin typed and checks its length.
+func termName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || len(name) > maxTermLength {
+		return "", fmt.Errorf("%w: a name must have 1 to %d characters", ErrInvalidTerm, maxTermLength)
+	}
+	return name, nil
+}
+
+// sameName finds the term, other than exclude, whose name or an alias folds to
+// the same form as name.
+func (s *taxonomyService) sameName(ctx context.Context, kind, name string, exclude uuid.UUID) (taxonomy.Term, bool, error) {
+	terms, err := util.ListTerms(ctx, s.store, kind)
+	if err != nil {
+		return taxonomy.Term{}, false, s.termError(ctx, "Failed to list terms", kind, err)
+	}
+	key := taxonomy.Fold(name)
+	for _, term := range terms {
+		if term.ID != exclude && taxonomy.Fold(term.Label()) == key {
+			return term, true, nil
+		}
+	}
+	return taxonomy.Term{}, false, nil
+}
+
+// match finds the term name refers to, other than the term exclude.
+func (s *taxonomyService) match(ctx context.Context, kind, name string, exclude uuid.UUID) (taxonomy.Term, error) {
+	terms, err := util.ListTerms(ctx, s.store, kind)
+	if err != nil {
+		return taxonomy.Term{}, s.termError(ctx, "Failed to list terms", kind, err)
+	}
+	terms = slices.DeleteFunc(terms, func(term taxonomy.Term) bool { return term.ID == exclude })
+	term, ok := taxonomy.Match(name, terms)
+	if !ok {
+		return taxonomy.Term{}, fmt.Errorf("%w: %s", ErrNoMatchingTerm, name)
+	}
+	return term, nil
+}
+
+func (s *taxonomyService) termError(ctx context.Context, msg, kind string, err error) error {
+	if errors.Is(err, util.ErrUnknownTaxonomy) {
+		return err
+	}
+	if errors.Is(err, repository.ErrTermNotFound) || errors.Is(err, repository.ErrTermExists) || errors.Is(err, repository.ErrTermInUse) {
+		return err
+	}
+	s.log.ErrorContext(ctx, msg, "taxonomy", kind, "error", err)
+	return fmt.Errorf("failed to update %s: %w", kind, err)
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	"github.com/DSSD-Madison/gmu/pkg/db/repository"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+var (
+	oceaniaID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	oceanaID  = uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	congoID   = uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	reportID  = uuid.MustParse("44444444-4444-4444-4444-444444444444")
+)
+
+// fakeTaxonomyStore holds regions only.
+type fakeTaxonomyStore struct {
+	regions []db.ListRegionTermsRow
+	parents map[uuid.UUID]uuid.UUID
+}
+
+func newFakeTaxonomyStore() *fakeTaxonomyStore {
+	return &fakeTaxonomyStore{regions: []db.ListRegionTermsRow{
+		{ID: oceaniaID, Name: "Oceania"},
+		{ID: oceanaID, Name: "Oceana"},
+		{ID: congoID, Name: "Democratic Republic of the Congo"},
+		{ID: congoID, Name: "Democratic Republic of the Congo", Alias: "DRC"},
+	}, parents: make(map[uuid.UUID]uuid.UUID)}
+}
+
+func (f *fakeTaxonomyStore) FindAuthorByName(context.Context, string) (db.Author, error) {
+	return db.Author{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListAuthorTerms(context.Context) ([]db.ListAuthorTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) FindKeywordByName(context.Context, string) (db.Keyword, error) {
+	return db.Keyword{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListKeywordTerms(context.Context) ([]db.ListKeywordTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) FindRegionByName(_ context.Context, name string) (db.Region, error) {
+	for _, row := range f.regions {
+		if row.Alias == "" && strings.EqualFold(row.Name, name) {
+			return db.Region{ID: row.ID, Name: row.Name}, nil
+		}
+	}
+	return db.Region{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListRegionTerms(context.Context) ([]db.ListRegionTermsRow, error) {
+	return f.regions, nil
+}
+
+func (f *fakeTaxonomyStore) FindCategoryByName(context.Context, string) (db.Category, error) {
+	return db.Category{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListCategoryTerms(context.Context) ([]db.ListCategoryTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) FindPublisherByName(context.Context, string) (db.Publisher, error) {
+	return db.Publisher{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListPublisherTerms(context.Context) ([]db.ListPublisherTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListAuthorsForAdmin(context.Context, db.ListAuthorsForAdminParams) ([]db.ListAuthorsForAdminRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListKeywordsForAdmin(context.Context, db.ListKeywordsForAdminParams) ([]db.ListKeywordsForAdminRow, error) {
+	return nil, nil
+}
+
+// ListRegionsForAdmin lists the regions whose names contain arg.Query, each
+// with one document per letter of its name.
+func (f *fakeTaxonomyStore) ListRegionsForAdmin(_ context.Context, arg db.ListRegionsForAdminParams) ([]db.ListRegionsForAdminRow, error) {
+	var rows []db.ListRegionsForAdminRow
+	for _, term := range f.regions {
+		if term.Alias != "" || !strings.Contains(strings.ToLower(term.Name), strings.ToLower(arg.Query)) {
+			continue
+		}
+		row := db.ListRegionsForAdminRow{ID: term.ID, Name: term.Name, Approved: true, Documents: int64(len(term.Name)), Aliases: []string{}}
+		for _, alias := range f.regions {
+			if alias.ID == term.ID && alias.Alias != "" {
+				row.Aliases = append(row.Aliases, alias.Alias)
+			}
+		}
+		rows = append(rows, row)
+	}
+	for i := range rows {
+		rows[i].Total = int64(len(rows))
+	}
+	return rows, nil
+}
+
+func (f *fakeTaxonomyStore) ListCategoriesForAdmin(context.Context, db.ListCategoriesForAdminParams) ([]db.ListCategoriesForAdminRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListPublishersForAdmin(context.Context, db.ListPublishersForAdminParams) ([]db.ListPublishersForAdminRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) FindAuthorByID(context.Context, uuid.UUID) (db.Author, error) {
+	return db.Author{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) FindKeywordByID(context.Context, uuid.UUID) (db.Keyword, error) {
+	return db.Keyword{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) FindRegionByID(_ context.Context, id uuid.UUID) (db.Region, error) {
+	for _, row := range f.regions {
+		if row.ID == id {
+			parent, ok := f.parents[id]
+			return db.Region{ID: row.ID, Name: row.Name, Approved: true, ParentID: uuid.NullUUID{UUID: parent, Valid: ok}}, nil
+		}
+	}
+	return db.Region{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) FindCategoryByID(context.Context, uuid.UUID) (db.Category, error) {
+	return db.Category{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) FindPublisherByID(context.Context, uuid.UUID) (db.Publisher, error) {
+	return db.Publisher{}, sql.ErrNoRows
+}
+
+func (f *fakeTaxonomyStore) ListAuthorDocuments(context.Context, uuid.UUID) ([]db.ListAuthorDocumentsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListKeywordDocuments(context.Context, uuid.UUID) ([]db.ListKeywordDocumentsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListRegionDocuments(context.Context, uuid.UUID) ([]db.ListRegionDocumentsRow, error) {
+	return []db.ListRegionDocumentsRow{{ID: reportID, Title: "Pacific Report"}}, nil
+}
+
+func (f *fakeTaxonomyStore) ListCategoryDocuments(context.Context, uuid.UUID) ([]db.ListCategoryDocumentsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListPublisherDocuments(context.Context, uuid.UUID) ([]db.ListPublisherDocumentsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeTaxonomyStore) ListPendingTerms(context.Context) ([]db.ListPendingTermsRow, error) {
+	return []db.ListPendingTermsRow{{Taxonomy: taxonomy.Regions, ID: oceanaID, Name: "Oceana", Documents: 3}}, nil
+}
+
+func (f *fakeTaxonomyStore) ListRegionHierarchy(context.Context) ([]db.ListRegionHierarchyRow, error) {
+	var rows []db.ListRegionHierarchyRow
+	for _, row := range f.regions {
+		if row.Alias == "" {
+			parent, ok := f.parents[row.ID]
+			rows = append(rows, db.ListRegionHierarchyRow{ID: row.ID, Name: row.Name, ParentID: uuid.NullUUID{UUID: parent, Valid: ok}})
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeTaxonomyStore) SetRegionParent(_ context.Context, arg db.SetRegionParentParams) (int64, error) {
+	for _, row := range f.regions {
+		if row.ID == arg.ID {
+			if arg.ParentID.Valid {
+				f.parents[arg.ID] = arg.ParentID.UUID
+			} else {
+				delete(f.parents, arg.ID)
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+type fakeTermWriter struct {
+	approved []uuid.UUID
+	aliases  map[string]uuid.UUID
+	merged   [][2]uuid.UUID
+	renamed  map[uuid.UUID]string
+	split    map[string][]uuid.UUID // new name -> documents moved to it
+	deleted  []uuid.UUID
+}
+
+func (f *fakeTermWriter) Approve(_ context.Context, _ string, id uuid.UUID) error {
+	f.approved = append(f.approved, id)
+	return nil
+}
+
+func (f *fakeTermWriter) AddAlias(_ context.Context, _ string, id uuid.UUID, alias string) error {
+	if f.aliases == nil {
+		f.aliases = make(map[string]uuid.UUID)
+	}
+	f.aliases[alias] = id
+	return nil
+}
+
+func (f *fakeTermWriter) Merge(_ context.Context, _ string, from, to uuid.UUID) error {
+	f.merged = append(f.merged, [2]uuid.UUID{from, to})
+	return nil
+}
+
+func (f *fakeTermWriter) Rename(_ context.Context, _ string, id uuid.UUID, name string) error {
+	if f.renamed == nil {
+		f.renamed = make(map[uuid.UUID]string)
+	}
+	f.renamed[id] = name
+	return nil
+}
+
+func (f *fakeTermWriter) Split(_ context.Context, _ string, _ uuid.UUID, name string, docIDs []uuid.UUID) (uuid.UUID, error) {
+	if f.split == nil {
+		f.split = make(map[string][]uuid.UUID)
+	}
+	f.split[name] = docIDs
+	return uuid.New(), nil
+}
+
+// Delete refuses to delete Oceania, which stands for a term that documents use.
+func (f *fakeTermWriter) Delete(_ context.Context, _ string, id uuid.UUID) error {
+	if id == oceaniaID {
+		return fmt.Errorf("%w: Oceania", repository.ErrTermInUse)
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func newTaxonomyFixture() (TermManager, *fakeTermWriter) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	writer := &fakeTermWriter{}
+	return NewTaxonomyService(log, newFakeTaxonomyStore(), writer), writer
+}
+
+func TestTaxonomyService_Suggest(t *testing.T) {
+	service, _ := newTaxonomyFixture()
+
+	terms, err := service.Suggest(context.Background(), taxonomy.Regions, "drc")
+	require.NoError(t, err)
+	require.Len(t, terms, 1)
+	assert.Equal(t, "Democratic Republic of the Congo", terms[0].Name)
+
+	terms, err = service.Suggest(context.Background(), taxonomy.Regions, "oce")
+	require.NoError(t, err)
+	assert.Len(t, terms, 2)
+
+	_, err = service.Suggest(context.Background(), "planets", "mars")
+	assert.Error(t, err)
+}
+
+func TestTaxonomyService_Pending(t *testing.T) {
+	service, _ := newTaxonomyFixture()
+
+	pending, err := service.Pending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []db_types.PendingTerm{{
+		Taxonomy:  taxonomy.Regions,
+		Label:     "Region",
+		ID:        oceanaID.String(),
+		Name:      "Oceana",
+		Documents: 3,
+		Suggested: "Oceania",
+	}}, pending)
+}
+
+func TestTaxonomyService_MapTo(t *testing.T) {
+	service, writer := newTaxonomyFixture()
+
+	name, err := service.MapTo(context.Background(), taxonomy.Regions, oceanaID, "oceana")
+	require.NoError(t, err)
+	assert.Equal(t, "Oceania", name, "a term is never mapped to itself")
+	assert.Equal(t, [][2]uuid.UUID{{oceanaID, oceaniaID}}, writer.merged)
+
+	_, err = service.MapTo(context.Background(), taxonomy.Regions, oceanaID, "Atlantis")
+	assert.ErrorIs(t, err, ErrNoMatchingTerm)
+	assert.Len(t, writer.merged, 1)
+}
+
+func TestTaxonomyService_AddAlias(t *testing.T) {
+	service, writer := newTaxonomyFixture()
+
+	name, err := service.AddAlias(context.Background(), taxonomy.Regions, "drc", "Congo-Kinshasa")
+	require.NoError(t, err)
+	assert.Equal(t, "Democratic Republic of the Congo", name)
+	assert.Equal(t, map[string]uuid.UUID{"Congo-Kinshasa": congoID}, writer.aliases)
+
+	_, err = service.AddAlias(context.Background(), taxonomy.Regions, "Atlantis", "Lost City")
+	assert.ErrorIs(t, err, ErrNoMatchingTerm)
+}
+
+func TestTaxonomyService_List(t *testing.T) {
+	service, _ := newTaxonomyFixture()
+
+	list, err := service.List(context.Background(), taxonomy.Regions, " congo ")
+	require.NoError(t, err)
+	assert.Equal(t, db_types.TermList{
+		Taxonomy: taxonomy.Regions,
+		Label:    "Regions",
+		Query:    "congo",
+		Total:    1,
+		Terms: []db_types.TermUsage{{
+			ID:        congoID.String(),
+			Name:      "Democratic Republic of the Congo",
+			Approved:  true,
+			Documents: 32,
+			Aliases:   []string{"DRC"},
+		}},
+	}, list)
+
+	list, err = service.List(context.Background(), taxonomy.Regions, "")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, list.Total)
+
+	_, err = service.List(context.Background(), "planets", "")
+	assert.ErrorIs(t, err, util.ErrUnknownTaxonomy)
+}
+
+func TestTaxonomyService_Term(t *testing.T) {
+	service, _ := newTaxonomyFixture()
+
+	detail, err := service.Term(context.Background(), taxonomy.Regions, oceaniaID)
+	require.NoError(t, err)
+	assert.Equal(t, "Oceania", detail.Name)
+	assert.Equal(t, []db_types.TermDocument{{ID: reportID.String(), Title: "Pacific Report"}}, detail.Documents)
+
+	_, err = service.Term(context.Background(), taxonomy.Regions, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrTermNotFound)
+}
+
+func TestTaxonomyService_Rename(t *testing.T) {
+	service, writer := newTaxonomyFixture()
+
+	require.NoError(t, service.Rename(context.Background(), taxonomy.Regions, oceaniaID, "  Oceania   and Pacific "))
+	assert.Equal(t, map[uuid.UUID]string{oceaniaID: "Oceania and Pacific"}, writer.renamed)
+
+	require.NoError(t, service.Rename(context.Background(), taxonomy.Regions, congoID, "D.R.C."), "a term may take its own alias")
+
+	err := service.Rename(context.Background(), taxonomy.Regions, oceanaID, "OCEANIA")
+	assert.ErrorIs(t, err, repository.ErrTermExists)
+	err = service.Rename(context.Background(), taxonomy.Regions, oceanaID, "DRC")
+	assert.ErrorIs(t, err, repository.ErrTermExists)
+	assert.ErrorIs(t, service.Rename(context.Background(), taxonomy.Regions, oceanaID, " "), ErrInvalidTerm)
+	assert.Len(t, writer.renamed, 2)
+}
+
+func TestTaxonomyService_Split(t *testing.T) {
+	service, writer := newTaxonomyFixture()
+
+	name, err := service.Split(context.Background(), taxonomy.Regions, oceaniaID, "Melanesia", []uuid.UUID{reportID})
+	require.NoError(t, err)
+	assert.Equal(t, "Melanesia", name)
+
+	name, err = service.Split(context.Background(), taxonomy.Regions, oceaniaID, "drc", []uuid.UUID{reportID})
+	require.NoError(t, err)
+	assert.Equal(t, "Democratic Republic of the Congo", name, "an existing term is used by its canonical name")
+	assert.Equal(t, map[string][]uuid.UUID{"Melanesia": {reportID}, "Democratic Republic of the Congo": {reportID}}, writer.split)
+
+	_, err = service.Split(context.Background(), taxonomy.Regions, oceaniaID, "Oceania", []uuid.UUID{reportID})
+	assert.ErrorIs(t, err, ErrInvalidTerm)
+	_, err = service.Split(context.Background(), taxonomy.Regions, oceaniaID, "Polynesia", nil)
+	assert.ErrorIs(t, err, ErrInvalidTerm)
+}
+
+func TestTaxonomyService_Delete(t *testing.T) {
+	service, writer := newTaxonomyFixture()
+
+	require.NoError(t, service.Delete(context.Background(), taxonomy.Regions, oceanaID))
+	assert.ErrorIs(t, service.Delete(context.Background(), taxonomy.Regions, oceaniaID), repository.ErrTermInUse)
+	assert.Equal(t, []uuid.UUID{oceanaID}, writer.deleted)
+}
+
+func TestTaxonomyService_SetRegionParent(t *testing.T) {
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	store := newFakeTaxonomyStore()
+	service := NewTaxonomyService(log, store, &fakeTermWriter{})
+
+	parent, err := service.SetRegionParent(context.Background(), congoID, "oceania")
+	require.NoError(t, err)
+	assert.Equal(t, "Oceania", parent)
+	assert.Equal(t, map[uuid.UUID]uuid.UUID{congoID: oceaniaID}, store.parents)
+
+	detail, err := service.Term(context.Background(), taxonomy.Regions, congoID)
+	require.NoError(t, err)
+	assert.Equal(t, "Oceania", detail.Parent)
+
+	_, err = service.SetRegionParent(context.Background(), oceaniaID, "DRC")
+	assert.ErrorIs(t, err, ErrInvalidTerm, "a region cannot be placed within one of its own")
+	_, err = service.SetRegionParent(context.Background(), oceaniaID, "Oceania")
+	assert.ErrorIs(t, err, ErrInvalidTerm)
+	_, err = service.SetRegionParent(context.Background(), oceaniaID, "Atlantis")
+	assert.ErrorIs(t, err, ErrNoMatchingTerm)
+	_, err = service.SetRegionParent(context.Background(), uuid.New(), "Oceania")
+	assert.ErrorIs(t, err, repository.ErrTermNotFound)
+
+	parent, err = service.SetRegionParent(context.Background(), congoID, " ")
+	require.NoError(t, err)
+	assert.Empty(t, parent)
+	assert.Empty(t, store.parents)
+}
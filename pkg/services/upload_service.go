@@ -0,0 +1,588 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/DSSD-Madison/gmu/pkg/awskendra"
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/db/util"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+	"github.com/DSSD-Madison/gmu/pkg/taxonomy"
+)
+
+// Outcomes of accepting a file, as stored in ingest_batch_files.
+const (
+	UploadOutcomeQueued    = "queued"
+	UploadOutcomeDuplicate = "duplicate"
+	UploadOutcomeFailed    = "failed"
+)
+
+var (
+	ErrUploadTooLarge      = errors.New("upload is too large")
+	ErrUploadBatchNotFound = errors.New("upload batch not found")
+)
+
+// archiveContentTypes are the files taken from a ZIP archive, by extension.
+var archiveContentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+// UploadLimits caps the number of files and the total bytes in one upload,
+// counting the files inside ZIP archives rather than the archives themselves.
+type UploadLimits struct {
+	MaxFiles int
+	MaxBytes int64
+}
+
+// Check returns ErrUploadTooLarge if files or size exceed the limits.
+func (l UploadLimits) Check(files int, size int64) error {
+	if files > l.MaxFiles {
+		return fmt.Errorf("%w: at most %d files can be uploaded at once", ErrUploadTooLarge, l.MaxFiles)
+	}
+	if size > l.MaxBytes {
+		return fmt.Errorf("%w: at most %d MB can be uploaded at once", ErrUploadTooLarge, l.MaxBytes>>20)
+	}
+	return nil
+}
+
+// UploadFile is one uploaded file. Problem says why it cannot be processed,
+// such as an unsupported file inside an archive. Stored and Metadata are set
+// for files imported from a manifest, as for IngestUpload.
+type UploadFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Problem     string
+	Stored      bool
+	Metadata    awskendra.ExtractedMetadata
+}
+
+// IsArchive reports whether the file is a ZIP archive to unpack.
+func IsArchive(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".zip")
+}
+
+// UploadResult is the outcome of accepting one file. JobID is the queued job, or
+// the unfinished job the file duplicates. DocID is the document it duplicates,
+// and ExistingName that document's file name when the contents matched.
+type UploadResult struct {
+	FileName     string
+	Outcome      string
+	Message      string
+	JobID        uuid.UUID
+	DocID        uuid.UUID
+	ExistingName string
+}
+
+// UploadStore is the subset of db.Queries used by the upload service.
+type UploadStore interface {
+	util.TermFinder
+	FindDocumentByS3Path(ctx context.Context, s3File string) (db.Document, error)
+	FindUnfinishedIngestJobByS3File(ctx context.Context, s3File string) (uuid.UUID, error)
+	FindUnfinishedIngestJobByContentHash(ctx context.Context, contentHash string) (uuid.UUID, error)
+	InsertIngestBatchFile(ctx context.Context, arg db.InsertIngestBatchFileParams) error
+	ListIngestBatchFiles(ctx context.Context, batchID uuid.UUID) ([]db.ListIngestBatchFilesRow, error)
+}
+
+// FileDownloader reads files from S3. It is satisfied by *FilemanagerService.
+type FileDownloader interface {
+	DownloadFile(ctx context.Context, key string, bucket string) ([]byte, error)
+}
+
+type uploadService struct {
+	log        logger.Logger
+	store      UploadStore
+	files      FileDownloader
+	duplicates DuplicateFinder
+	ingester   Ingester
+	limits     UploadLimits
+}
+
+// NewUploadService creates the service that checks uploaded files for duplicates
+// and hands the rest to the ingester, one file at a time, as a batch or as
+// listed in a manifest.
+func NewUploadService(log logger.Logger, store UploadStore, files FileDownloader, duplicates DuplicateFinder, ingester Ingester, limits UploadLimits) Uploader {
+	serviceLogger := log.With("service", "Upload")
+	return &uploadService{
+		log:        serviceLogger,
+		store:      store,
+		files:      files,
+		duplicates: duplicates,
+		ingester:   ingester,
+		limits:     limits,
+	}
+}
+
+func (s *uploadService) Limits() UploadLimits {
+	return s.limits
+}
+
+// Submit queues the file as an ingest job unless it duplicates a document or
+// an unfinished job, by name or by contents.
+func (s *uploadService) Submit(ctx context.Context, file UploadFile, actor Actor) UploadResult {
+	result, contentHash := s.check(ctx, file)
+	if result.Outcome != "" {
+		return result
+	}
+
+	jobID, err := s.ingester.Submit(ctx, IngestUpload{
+		FileName:    file.Name,
+		ContentType: file.ContentType,
+		ContentHash: contentHash,
+		Data:        file.Data,
+		Stored:      file.Stored,
+		Metadata:    file.Metadata,
+	}, actor)
+	if err != nil {
+		result.Outcome = UploadOutcomeFailed
+		result.Message = "Could not accept the upload"
+		return result
+	}
+
+	result.Outcome = UploadOutcomeQueued
+	result.JobID = jobID
+	return result
+}
+
+// check returns the file's content hash and a result whose Outcome is empty if
+// the file can be queued, or says why not.
+func (s *uploadService) check(ctx context.Context, file UploadFile) (UploadResult, string) {
+	result := UploadResult{FileName: file.Name}
+	if file.Problem != "" {
+		result.Outcome = UploadOutcomeFailed
+		result.Message = file.Problem
+		return result, ""
+	}
+
+	s3Path := UploadedS3Path(file.Name)
+	if existing, err := s.store.FindDocumentByS3Path(ctx, s3Path); err == nil {
+		result.Outcome = UploadOutcomeDuplicate
+		result.Message = "A file with this name has already been uploaded"
+		result.DocID = existing.ID
+		return result, ""
+	}
+	if jobID, err := s.store.FindUnfinishedIngestJobByS3File(ctx, s3Path); err == nil {
+		result.Outcome = UploadOutcomeDuplicate
+		result.Message = "An upload of a file with this name has not finished"
+		result.JobID = jobID
+		return result, ""
+	}
+
+	// Check for the same file under another name before spending an extraction call
+	contentHash := ContentHash(file.Data)
+	existing, found, err := s.duplicates.FindByContentHash(ctx, contentHash)
+	if err != nil {
+		result.Outcome = UploadOutcomeFailed
+		result.Message = "Error checking for duplicates"
+		return result, ""
+	}
+	if found {
+		result.Outcome = UploadOutcomeDuplicate
+		result.Message = fmt.Sprintf("Already uploaded as %q", existing.FileName)
+		result.DocID = existing.ID
+		result.ExistingName = existing.FileName
+		return result, ""
+	}
+	jobID, err := s.store.FindUnfinishedIngestJobByContentHash(ctx, contentHash)
+	if err == nil {
+		result.Outcome = UploadOutcomeDuplicate
+		result.Message = "An upload of the same file has not finished"
+		result.JobID = jobID
+		return result, ""
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		s.log.ErrorContext(ctx, "Failed to look up unfinished jobs by content hash", "error", err)
+		result.Outcome = UploadOutcomeFailed
+		result.Message = "Error checking for duplicates"
+		return result, ""
+	}
+	return result, contentHash
+}
+
+// SubmitBatch unpacks any ZIP archives, submits every file and records each
+// outcome under a new batch, whose ID it returns. It returns ErrUploadTooLarge
+// without submitting anything if the unpacked files exceed the limits.
+func (s *uploadService) SubmitBatch(ctx context.Context, files []UploadFile, actor Actor) (uuid.UUID, error) {
+	expanded, err := ExpandArchives(files, s.limits)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return s.submitBatch(ctx, expanded, actor)
+}
+
+func (s *uploadService) submitBatch(ctx context.Context, files []UploadFile, actor Actor) (uuid.UUID, error) {
+	batchID := uuid.New()
+	for i, file := range files {
+		result := s.Submit(ctx, file, actor)
+		err := s.store.InsertIngestBatchFile(ctx, db.InsertIngestBatchFileParams{
+			BatchID:       batchID,
+			Position:      int32(i + 1),
+			FileName:      file.Name,
+			Outcome:       result.Outcome,
+			Message:       sql.NullString{String: result.Message, Valid: result.Message != ""},
+			JobID:         uuid.NullUUID{UUID: result.JobID, Valid: result.JobID != uuid.Nil},
+			DocID:         uuid.NullUUID{UUID: result.DocID, Valid: result.DocID != uuid.Nil},
+			CreatedBy:     uuid.NullUUID{UUID: actor.ID, Valid: actor.ID != uuid.Nil},
+			CreatedByName: actorName(actor),
+		})
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to record batch file", "batchID", batchID, "fileName", file.Name, "error", err)
+			return uuid.Nil, fmt.Errorf("failed to record batch file: %w", err)
+		}
+	}
+
+	s.log.InfoContext(ctx, "Upload batch submitted", "batchID", batchID, "files", len(files))
+	return batchID, nil
+}
+
+// PreviewManifest reports what SubmitManifest would do with the same manifest
+// and files, without queuing anything or adding taxonomy terms.
+func (s *uploadService) PreviewManifest(ctx context.Context, manifest UploadFile, files []UploadFile) (db_types.ManifestPreview, error) {
+	items, err := s.planManifest(ctx, manifest, files)
+	if err != nil {
+		return db_types.ManifestPreview{}, err
+	}
+
+	var preview db_types.ManifestPreview
+	seen := map[string]bool{}
+	for _, item := range items {
+		metadata := item.file.Metadata
+		row := db_types.ManifestPreviewRow{
+			Line:        item.line,
+			FileName:    item.file.Name,
+			Origin:      item.origin,
+			Title:       metadata.Title,
+			PublishDate: metadata.PublishDate,
+			Authors:     metadata.AuthorName,
+		}
+
+		result, _ := s.check(ctx, item.file)
+		switch result.Outcome {
+		case "":
+			row.Outcome = db_types.ManifestOutcomeCreate
+			row.Extracted = MissingMetadata(metadata)
+			preview.Creates++
+			names, err := s.newTermNames(ctx, metadata, seen)
+			if err != nil {
+				s.log.ErrorContext(ctx, "Failed to look up manifest terms", "error", err)
+				return db_types.ManifestPreview{}, fmt.Errorf("failed to look up terms: %w", err)
+			}
+			preview.NewTerms = append(preview.NewTerms, names...)
+		case UploadOutcomeDuplicate:
+			row.Outcome = db_types.ManifestOutcomeDuplicate
+			row.Message = result.Message
+			preview.Duplicates++
+		default:
+			row.Outcome = db_types.ManifestOutcomeFailed
+			row.Message = result.Message
+			preview.Failed++
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+	return preview, nil
+}
+
+// SubmitManifest submits every file the manifest lists with the metadata it
+// provides, recording each outcome under a new batch as SubmitBatch does.
+// Uploaded files the manifest does not list are recorded as failed.
+func (s *uploadService) SubmitManifest(ctx context.Context, manifest UploadFile, files []UploadFile, actor Actor) (uuid.UUID, error) {
+	items, err := s.planManifest(ctx, manifest, files)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	batch := make([]UploadFile, len(items))
+	for i, item := range items {
+		batch[i] = item.file
+	}
+	return s.submitBatch(ctx, batch, actor)
+}
+
+// manifestItem is a manifest row with the file it refers to. Line is 0 for an
+// uploaded file that no row lists.
+type manifestItem struct {
+	line   int
+	origin string
+	file   UploadFile
+}
+
+// planManifest matches every manifest row to an uploaded file of that name, or
+// else to the file with that key in the upload bucket, which it downloads. It
+// returns ErrInvalidManifest if the manifest cannot be read and
+// ErrUploadTooLarge once the files exceed the limits.
+func (s *uploadService) planManifest(ctx context.Context, manifest UploadFile, files []UploadFile) ([]manifestItem, error) {
+	rows, err := ParseManifest(manifest.Name, manifest.Data)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := ExpandArchives(files, s.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	byName := make(map[string]int, len(uploaded))
+	for i, file := range uploaded {
+		byName[file.Name] = i
+		size += int64(len(file.Data))
+	}
+	used := make([]bool, len(uploaded))
+	listed := map[string]bool{}
+
+	items := make([]manifestItem, 0, len(rows)+len(uploaded))
+	for _, row := range rows {
+		item := manifestItem{line: row.Line}
+		i, isUpload := byName[row.File]
+		switch {
+		case row.Problem != "":
+			item.file = UploadFile{Name: row.File, Problem: row.Problem}
+		case listed[row.File]:
+			item.file = UploadFile{Name: row.File, Problem: "Listed more than once in the manifest"}
+		case isUpload:
+			item.origin = db_types.ManifestOriginUpload
+			item.file = uploaded[i]
+		default:
+			item.origin = db_types.ManifestOriginBucket
+			item.file = s.download(ctx, row.File)
+			size += int64(len(item.file.Data))
+		}
+		if isUpload {
+			used[i] = true
+		}
+		listed[row.File] = true
+		item.file.Metadata = row.Metadata
+
+		items = append(items, item)
+		if err := s.limits.Check(len(items), size); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, file := range uploaded {
+		if !used[i] {
+			items = append(items, manifestItem{
+				origin: db_types.ManifestOriginUpload,
+				file:   UploadFile{Name: file.Name, Problem: "Not listed in the manifest"},
+			})
+		}
+	}
+	return items, s.limits.Check(len(items), size)
+}
+
+// download reads a file the manifest lists by its key in the upload bucket, or
+// by its full S3 path there. Files it cannot use are returned with a Problem.
+func (s *uploadService) download(ctx context.Context, name string) UploadFile {
+	bucketPrefix := UploadedS3Path("")
+	key := strings.TrimPrefix(name, bucketPrefix)
+	if strings.HasPrefix(key, "s3://") {
+		return UploadFile{Name: name, Problem: fmt.Sprintf("Only files in %s can be imported", bucketPrefix)}
+	}
+	contentType, ok := archiveContentTypes[strings.ToLower(path.Ext(key))]
+	if !ok {
+		return UploadFile{Name: name, Problem: "Only PDF and DOCX files can be imported"}
+	}
+
+	data, err := s.files.DownloadFile(ctx, key, uploadBucket)
+	if err != nil {
+		s.log.WarnContext(ctx, "Failed to download manifest file", "key", key, "error", err)
+		return UploadFile{Name: name, Problem: fmt.Sprintf("Neither uploaded nor found in %s", bucketPrefix)}
+	}
+	return UploadFile{Name: key, ContentType: contentType, Data: data, Stored: true}
+}
+
+// newTermNames returns the provided names that match no author, keyword, region
+// or category, as util.FindTerm matches them, skipping names already in seen.
+func (s *uploadService) newTermNames(ctx context.Context, metadata awskendra.ExtractedMetadata, seen map[string]bool) ([]string, error) {
+	taxonomies := []struct {
+		label string
+		kind  string
+		names []string
+	}{
+		{"Author", taxonomy.Authors, metadata.AuthorName},
+		{"Keyword", taxonomy.Keywords, metadata.KeywordName},
+		{"Region", taxonomy.Regions, metadata.RegionName},
+		{"Category", taxonomy.Categories, metadata.CategoryName},
+	}
+
+	var names []string
+	for _, t := range taxonomies {
+		for _, name := range t.names {
+			key := t.label + ":" + taxonomy.Fold(name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			_, err := util.FindTerm(ctx, s.store, t.kind, name)
+			if errors.Is(err, sql.ErrNoRows) {
+				names = append(names, fmt.Sprintf("%s: %s", t.label, name))
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return names, nil
+}
+
+// Batch returns the batch's files with the current state of their jobs.
+func (s *uploadService) Batch(ctx context.Context, id uuid.UUID) (db_types.UploadBatch, error) {
+	rows, err := s.store.ListIngestBatchFiles(ctx, id)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Failed to list batch files", "batchID", id, "error", err)
+		return db_types.UploadBatch{}, fmt.Errorf("failed to list batch files: %w", err)
+	}
+	if len(rows) == 0 {
+		return db_types.UploadBatch{}, ErrUploadBatchNotFound
+	}
+
+	batch := db_types.UploadBatch{
+		ID:            id.String(),
+		CreatedByName: rows[0].CreatedByName,
+		CreatedAt:     rows[0].CreatedAt.Format("2006-01-02 15:04"),
+	}
+	for _, row := range rows {
+		file := batchFileView(row)
+		switch file.Outcome {
+		case db_types.BatchOutcomeCreated:
+			batch.Created++
+		case db_types.BatchOutcomeDuplicate:
+			batch.Duplicates++
+		case db_types.BatchOutcomeFailed:
+			batch.Failed++
+		default:
+			batch.Processing++
+		}
+		batch.Files = append(batch.Files, file)
+	}
+	return batch, nil
+}
+
+// batchFileView turns a queued file into created, failed or processing
+// according to its job.
+func batchFileView(row db.ListIngestBatchFilesRow) db_types.UploadBatchFile {
+	file := db_types.UploadBatchFile{
+		FileName: row.FileName,
+		Message:  row.Message.String,
+	}
+	if row.JobID.Valid {
+		file.JobID = row.JobID.UUID.String()
+	}
+	if row.DocID.Valid {
+		file.DocID = row.DocID.UUID.String()
+	}
+
+	switch row.Outcome {
+	case UploadOutcomeDuplicate:
+		file.Outcome = db_types.BatchOutcomeDuplicate
+	case UploadOutcomeFailed:
+		file.Outcome = db_types.BatchOutcomeFailed
+	default:
+		switch {
+		case !row.JobStatus.Valid:
+			file.Outcome = db_types.BatchOutcomeFailed
+			file.Message = "The upload job no longer exists"
+		case row.JobStatus.String == IngestStatusDone:
+			file.Outcome = db_types.BatchOutcomeCreated
+			file.DocID = row.JobDocID.UUID.String()
+		case row.JobStatus.String == IngestStatusFailed:
+			file.Outcome = db_types.BatchOutcomeFailed
+			file.Message = row.JobError.String
+		default:
+			file.Outcome = db_types.BatchOutcomeProcessing
+		}
+	}
+	return file
+}
+
+// ExpandArchives replaces every ZIP archive with the PDF and DOCX files inside
+// it. Other files in an archive are kept with a Problem so they are reported.
+// It returns ErrUploadTooLarge once the files exceed the limits.
+func ExpandArchives(files []UploadFile, limits UploadLimits) ([]UploadFile, error) {
+	e := &expander{limits: limits}
+	for _, file := range files {
+		if !IsArchive(file.Name) {
+			if err := e.add(file); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := e.unzip(file); err != nil {
+			return nil, err
+		}
+	}
+	return e.files, nil
+}
+
+type expander struct {
+	limits UploadLimits
+	size   int64
+	files  []UploadFile
+}
+
+func (e *expander) add(file UploadFile) error {
+	e.files = append(e.files, file)
+	e.size += int64(len(file.Data))
+	return e.limits.Check(len(e.files), e.size)
+}
+
+func (e *expander) unzip(archive UploadFile) error {
+	r, err := zip.NewReader(bytes.NewReader(archive.Data), int64(len(archive.Data)))
+	if err != nil {
+		return e.add(UploadFile{Name: archive.Name, Problem: "Not a valid ZIP archive"})
+	}
+
+	for _, entry := range r.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		contentType, ok := archiveContentTypes[ext]
+		switch {
+		case ext == ".zip":
+			err = e.add(UploadFile{Name: name, Problem: "Archives inside archives are not supported"})
+		case !ok:
+			err = e.add(UploadFile{Name: name, Problem: "Only PDF and DOCX files are taken from archives"})
+		default:
+			err = e.addEntry(entry, name, contentType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addEntry reads an archive entry, stopping as soon as it would exceed the byte
+// limit so that a small archive cannot unpack into an unbounded amount of data.
+func (e *expander) addEntry(entry *zip.File, name string, contentType string) error {
+	remaining := e.limits.MaxBytes - e.size
+	if entry.UncompressedSize64 > uint64(max(remaining, 0)) {
+		return e.limits.Check(len(e.files)+1, e.limits.MaxBytes+1)
+	}
+
+	rc, err := entry.Open()
+	if err != nil {
+		return e.add(UploadFile{Name: name, Problem: "Could not be read from the archive"})
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, remaining+1))
+	if err != nil {
+		return e.add(UploadFile{Name: name, Problem: "Could not be read from the archive"})
+	}
+	return e.add(UploadFile{Name: name, ContentType: contentType, Data: data})
+}
@@ -0,0 +1,550 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/logger"
+)
+
+type fakeUploadStore struct {
+	docsByPath  map[string]uuid.UUID
+	jobsByPath  map[string]uuid.UUID
+	jobsByHash  map[string]uuid.UUID
+	authors     map[string]bool
+	batchFiles  []db.InsertIngestBatchFileParams
+	listedRows  []db.ListIngestBatchFilesRow
+	hashLookErr error
+}
+
+func newFakeUploadStore() *fakeUploadStore {
+	return &fakeUploadStore{
+		docsByPath: map[string]uuid.UUID{},
+		jobsByPath: map[string]uuid.UUID{},
+		jobsByHash: map[string]uuid.UUID{},
+		authors:    map[string]bool{},
+	}
+}
+
+func (f *fakeUploadStore) FindAuthorByName(_ context.Context, name string) (db.Author, error) {
+	if !f.authors[strings.ToLower(name)] {
+		return db.Author{}, sql.ErrNoRows
+	}
+	return db.Author{ID: uuid.New(), Name: name}, nil
+}
+
+func (f *fakeUploadStore) ListAuthorTerms(context.Context) ([]db.ListAuthorTermsRow, error) {
+	var rows []db.ListAuthorTermsRow
+	for name := range f.authors {
+		rows = append(rows, db.ListAuthorTermsRow{ID: uuid.New(), Name: name})
+	}
+	return rows, nil
+}
+
+func (f *fakeUploadStore) FindKeywordByName(context.Context, string) (db.Keyword, error) {
+	return db.Keyword{}, sql.ErrNoRows
+}
+
+func (f *fakeUploadStore) ListKeywordTerms(context.Context) ([]db.ListKeywordTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeUploadStore) FindRegionByName(context.Context, string) (db.Region, error) {
+	return db.Region{}, sql.ErrNoRows
+}
+
+func (f *fakeUploadStore) ListRegionTerms(context.Context) ([]db.ListRegionTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeUploadStore) FindCategoryByName(context.Context, string) (db.Category, error) {
+	return db.Category{}, sql.ErrNoRows
+}
+
+func (f *fakeUploadStore) ListCategoryTerms(context.Context) ([]db.ListCategoryTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeUploadStore) FindPublisherByName(context.Context, string) (db.Publisher, error) {
+	return db.Publisher{}, sql.ErrNoRows
+}
+
+func (f *fakeUploadStore) ListPublisherTerms(context.Context) ([]db.ListPublisherTermsRow, error) {
+	return nil, nil
+}
+
+func (f *fakeUploadStore) FindDocumentByS3Path(_ context.Context, s3File string) (db.Document, error) {
+	id, ok := f.docsByPath[s3File]
+	if !ok {
+		return db.Document{}, sql.ErrNoRows
+	}
+	return db.Document{ID: id}, nil
+}
+
+func (f *fakeUploadStore) FindUnfinishedIngestJobByS3File(_ context.Context, s3File string) (uuid.UUID, error) {
+	id, ok := f.jobsByPath[s3File]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return id, nil
+}
+
+func (f *fakeUploadStore) FindUnfinishedIngestJobByContentHash(_ context.Context, contentHash string) (uuid.UUID, error) {
+	if f.hashLookErr != nil {
+		return uuid.Nil, f.hashLookErr
+	}
+	id, ok := f.jobsByHash[contentHash]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return id, nil
+}
+
+func (f *fakeUploadStore) InsertIngestBatchFile(_ context.Context, arg db.InsertIngestBatchFileParams) error {
+	f.batchFiles = append(f.batchFiles, arg)
+	return nil
+}
+
+func (f *fakeUploadStore) ListIngestBatchFiles(context.Context, uuid.UUID) ([]db.ListIngestBatchFilesRow, error) {
+	return f.listedRows, nil
+}
+
+type fakeBucket struct{ files map[string][]byte }
+
+func (f *fakeBucket) DownloadFile(_ context.Context, key string, _ string) ([]byte, error) {
+	data, ok := f.files[key]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return data, nil
+}
+
+type fakeDuplicateFinder struct {
+	DuplicateFinder
+	byHash map[string]db.FindDocumentByContentHashRow
+}
+
+func (f *fakeDuplicateFinder) FindByContentHash(_ context.Context, hash string) (db.FindDocumentByContentHashRow, bool, error) {
+	doc, ok := f.byHash[hash]
+	return doc, ok, nil
+}
+
+type fakeIngester struct {
+	Ingester
+	submitted []IngestUpload
+}
+
+func (f *fakeIngester) Submit(_ context.Context, upload IngestUpload, _ Actor) (uuid.UUID, error) {
+	f.submitted = append(f.submitted, upload)
+	return uuid.New(), nil
+}
+
+type uploadFixture struct {
+	store      *fakeUploadStore
+	bucket     *fakeBucket
+	duplicates *fakeDuplicateFinder
+	ingester   *fakeIngester
+	service    *uploadService
+}
+
+func newUploadFixture(limits UploadLimits) *uploadFixture {
+	f := &uploadFixture{
+		store:      newFakeUploadStore(),
+		bucket:     &fakeBucket{files: map[string][]byte{}},
+		duplicates: &fakeDuplicateFinder{byHash: map[string]db.FindDocumentByContentHashRow{}},
+		ingester:   &fakeIngester{},
+	}
+	log := logger.New(&logger.HandlerOptions{Mode: "prod", Level: slog.LevelError})
+	f.service = NewUploadService(log, f.store, f.bucket, f.duplicates, f.ingester, limits).(*uploadService)
+	return f
+}
+
+type zipEntry struct {
+	name string
+	data []byte
+}
+
+func makeZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		fw, err := w.Create(entry.name)
+		require.NoError(t, err)
+		_, err = fw.Write(entry.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestUploadLimits_Check(t *testing.T) {
+	limits := UploadLimits{MaxFiles: 2, MaxBytes: 10 << 20}
+
+	tests := []struct {
+		name    string
+		files   int
+		size    int64
+		wantErr bool
+	}{
+		{name: "within limits", files: 2, size: 10 << 20},
+		{name: "too many files", files: 3, size: 1, wantErr: true},
+		{name: "too many bytes", files: 1, size: 10<<20 + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Check(tt.files, tt.size)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUploadTooLarge)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExpandArchives(t *testing.T) {
+	archive := makeZip(t,
+		zipEntry{name: "reports/a.pdf", data: []byte("pdf a")},
+		zipEntry{name: "reports/b.DOCX", data: []byte("docx b")},
+		zipEntry{name: "notes.txt", data: []byte("text")},
+		zipEntry{name: "inner.zip", data: []byte("zip")},
+		zipEntry{name: "__MACOSX/reports/._a.pdf", data: []byte("resource fork")},
+		zipEntry{name: "reports/.DS_Store", data: []byte("finder")},
+	)
+	files := []UploadFile{
+		{Name: "single.pdf", ContentType: "application/pdf", Data: []byte("single")},
+		{Name: "bundle.zip", Data: archive},
+	}
+
+	expanded, err := ExpandArchives(files, UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	require.Len(t, expanded, 5)
+
+	assert.Equal(t, "single.pdf", expanded[0].Name)
+	assert.Equal(t, UploadFile{Name: "a.pdf", ContentType: "application/pdf", Data: []byte("pdf a")}, expanded[1])
+	assert.Equal(t, "b.DOCX", expanded[2].Name)
+	assert.Equal(t, archiveContentTypes[".docx"], expanded[2].ContentType)
+	assert.Equal(t, "notes.txt", expanded[3].Name)
+	assert.NotEmpty(t, expanded[3].Problem)
+	assert.Equal(t, "inner.zip", expanded[4].Name)
+	assert.NotEmpty(t, expanded[4].Problem)
+}
+
+func TestExpandArchives_InvalidArchive(t *testing.T) {
+	expanded, err := ExpandArchives([]UploadFile{{Name: "broken.zip", Data: []byte("not a zip")}}, UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	require.Len(t, expanded, 1)
+	assert.NotEmpty(t, expanded[0].Problem)
+}
+
+func TestExpandArchives_Limits(t *testing.T) {
+	archive := makeZip(t,
+		zipEntry{name: "a.pdf", data: bytes.Repeat([]byte("a"), 600)},
+		zipEntry{name: "b.pdf", data: bytes.Repeat([]byte("b"), 600)},
+	)
+
+	tests := []struct {
+		name   string
+		limits UploadLimits
+	}{
+		{name: "too many files", limits: UploadLimits{MaxFiles: 1, MaxBytes: 1 << 20}},
+		{name: "too many bytes", limits: UploadLimits{MaxFiles: 10, MaxBytes: 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExpandArchives([]UploadFile{{Name: "bundle.zip", Data: archive}}, tt.limits)
+			assert.ErrorIs(t, err, ErrUploadTooLarge)
+		})
+	}
+}
+
+func TestUploadService_Submit(t *testing.T) {
+	data := []byte("%PDF-1.7 report")
+	existingDoc := uuid.New()
+	pendingJob := uuid.New()
+
+	tests := []struct {
+		name        string
+		file        UploadFile
+		setup       func(f *uploadFixture)
+		wantOutcome string
+		wantDoc     uuid.UUID
+		wantJob     uuid.UUID
+	}{
+		{
+			name:        "queued",
+			file:        UploadFile{Name: "report.pdf", Data: data},
+			wantOutcome: UploadOutcomeQueued,
+		},
+		{
+			name:        "file with a problem",
+			file:        UploadFile{Name: "notes.txt", Problem: "Only PDF and DOCX files are taken from archives"},
+			wantOutcome: UploadOutcomeFailed,
+		},
+		{
+			name: "document with the same name",
+			file: UploadFile{Name: "report.pdf", Data: data},
+			setup: func(f *uploadFixture) {
+				f.store.docsByPath[UploadedS3Path("report.pdf")] = existingDoc
+			},
+			wantOutcome: UploadOutcomeDuplicate,
+			wantDoc:     existingDoc,
+		},
+		{
+			name: "unfinished job with the same name",
+			file: UploadFile{Name: "report.pdf", Data: data},
+			setup: func(f *uploadFixture) {
+				f.store.jobsByPath[UploadedS3Path("report.pdf")] = pendingJob
+			},
+			wantOutcome: UploadOutcomeDuplicate,
+			wantJob:     pendingJob,
+		},
+		{
+			name: "document with the same contents",
+			file: UploadFile{Name: "renamed.pdf", Data: data},
+			setup: func(f *uploadFixture) {
+				f.duplicates.byHash[ContentHash(data)] = db.FindDocumentByContentHashRow{ID: existingDoc, FileName: "report.pdf"}
+			},
+			wantOutcome: UploadOutcomeDuplicate,
+			wantDoc:     existingDoc,
+		},
+		{
+			name: "unfinished job with the same contents",
+			file: UploadFile{Name: "renamed.pdf", Data: data},
+			setup: func(f *uploadFixture) {
+				f.store.jobsByHash[ContentHash(data)] = pendingJob
+			},
+			wantOutcome: UploadOutcomeDuplicate,
+			wantJob:     pendingJob,
+		},
+		{
+			name: "job lookup error",
+			file: UploadFile{Name: "report.pdf", Data: data},
+			setup: func(f *uploadFixture) {
+				f.store.hashLookErr = errors.New("connection reset")
+			},
+			wantOutcome: UploadOutcomeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+
+			result := f.service.Submit(context.Background(), tt.file, Actor{})
+
+			assert.Equal(t, tt.wantOutcome, result.Outcome)
+			assert.Equal(t, tt.wantDoc, result.DocID)
+			if tt.wantOutcome == UploadOutcomeQueued {
+				require.Len(t, f.ingester.submitted, 1)
+				assert.Equal(t, ContentHash(data), f.ingester.submitted[0].ContentHash)
+				assert.NotEqual(t, uuid.Nil, result.JobID)
+			} else {
+				assert.Empty(t, f.ingester.submitted)
+				assert.Equal(t, tt.wantJob, result.JobID)
+			}
+		})
+	}
+}
+
+func TestUploadService_SubmitBatch(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	existingDoc := uuid.New()
+	f.store.docsByPath[UploadedS3Path("old.pdf")] = existingDoc
+	archive := makeZip(t,
+		zipEntry{name: "new.pdf", data: []byte("new")},
+		zipEntry{name: "old.pdf", data: []byte("old")},
+		zipEntry{name: "notes.txt", data: []byte("text")},
+	)
+
+	batchID, err := f.service.SubmitBatch(context.Background(), []UploadFile{{Name: "bundle.zip", Data: archive}}, Actor{Username: "alice"})
+	require.NoError(t, err)
+	require.Len(t, f.store.batchFiles, 3)
+
+	for i, file := range f.store.batchFiles {
+		assert.Equal(t, batchID, file.BatchID)
+		assert.Equal(t, int32(i+1), file.Position)
+		assert.Equal(t, "alice", file.CreatedByName)
+	}
+	assert.Equal(t, UploadOutcomeQueued, f.store.batchFiles[0].Outcome)
+	assert.True(t, f.store.batchFiles[0].JobID.Valid)
+	assert.Equal(t, UploadOutcomeDuplicate, f.store.batchFiles[1].Outcome)
+	assert.Equal(t, uuid.NullUUID{UUID: existingDoc, Valid: true}, f.store.batchFiles[1].DocID)
+	assert.Equal(t, UploadOutcomeFailed, f.store.batchFiles[2].Outcome)
+	assert.Len(t, f.ingester.submitted, 1)
+}
+
+func TestUploadService_SubmitBatchTooLarge(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 1, MaxBytes: 1 << 20})
+	files := []UploadFile{{Name: "a.pdf", Data: []byte("a")}, {Name: "b.pdf", Data: []byte("b")}}
+
+	_, err := f.service.SubmitBatch(context.Background(), files, Actor{})
+
+	assert.ErrorIs(t, err, ErrUploadTooLarge)
+	assert.Empty(t, f.ingester.submitted)
+	assert.Empty(t, f.store.batchFiles)
+}
+
+func TestUploadService_Batch(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+
+	_, err := f.service.Batch(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, ErrUploadBatchNotFound)
+
+	jobDoc := uuid.New()
+	f.store.listedRows = []db.ListIngestBatchFilesRow{
+		{FileName: "a.pdf", Outcome: UploadOutcomeQueued, JobID: uuid.NullUUID{UUID: uuid.New(), Valid: true}, JobStatus: sql.NullString{String: IngestStatusDone, Valid: true}, JobDocID: uuid.NullUUID{UUID: jobDoc, Valid: true}},
+		{FileName: "b.pdf", Outcome: UploadOutcomeQueued, JobID: uuid.NullUUID{UUID: uuid.New(), Valid: true}, JobStatus: sql.NullString{String: IngestStatusRunning, Valid: true}},
+		{FileName: "c.pdf", Outcome: UploadOutcomeDuplicate, DocID: uuid.NullUUID{UUID: uuid.New(), Valid: true}},
+		{FileName: "d.txt", Outcome: UploadOutcomeFailed, Message: sql.NullString{String: "Only PDF and DOCX files are taken from archives", Valid: true}},
+	}
+
+	batch, err := f.service.Batch(context.Background(), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 1, batch.Created)
+	assert.Equal(t, 1, batch.Processing)
+	assert.Equal(t, 1, batch.Duplicates)
+	assert.Equal(t, 1, batch.Failed)
+	assert.Equal(t, jobDoc.String(), batch.Files[0].DocID)
+}
+
+func TestBatchFileView(t *testing.T) {
+	jobID := uuid.New()
+
+	tests := []struct {
+		name        string
+		row         db.ListIngestBatchFilesRow
+		wantOutcome string
+		wantMessage string
+	}{
+		{
+			name:        "job failed",
+			row:         db.ListIngestBatchFilesRow{Outcome: UploadOutcomeQueued, JobID: uuid.NullUUID{UUID: jobID, Valid: true}, JobStatus: sql.NullString{String: IngestStatusFailed, Valid: true}, JobError: sql.NullString{String: "Bedrock timed out", Valid: true}},
+			wantOutcome: db_types.BatchOutcomeFailed,
+			wantMessage: "Bedrock timed out",
+		},
+		{
+			name:        "job pending",
+			row:         db.ListIngestBatchFilesRow{Outcome: UploadOutcomeQueued, JobID: uuid.NullUUID{UUID: jobID, Valid: true}, JobStatus: sql.NullString{String: IngestStatusPending, Valid: true}},
+			wantOutcome: db_types.BatchOutcomeProcessing,
+		},
+		{
+			name:        "job removed",
+			row:         db.ListIngestBatchFilesRow{Outcome: UploadOutcomeQueued},
+			wantOutcome: db_types.BatchOutcomeFailed,
+			wantMessage: "The upload job no longer exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := batchFileView(tt.row)
+			assert.Equal(t, tt.wantOutcome, file.Outcome)
+			assert.Equal(t, tt.wantMessage, file.Message)
+		})
+	}
+}
+
+func TestUploadService_PreviewManifest(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	f.store.authors["amy"] = true
+	f.store.authors["jane doe"] = true
+	f.bucket.files["2019/annual.pdf"] = []byte("annual report")
+	existingDoc := uuid.New()
+	f.store.docsByPath[UploadedS3Path("old.pdf")] = existingDoc
+
+	manifest := UploadFile{Name: "manifest.csv", Data: []byte(`file,title,authors,publish_date
+report.pdf,Field Report,Amy; Zed; Jane Do,2020-01-31
+s3://manually-uploaded-bep/2019/annual.pdf,,Zed,
+old.pdf,Old,,
+missing.pdf,Missing,,
+report.pdf,Again,,
+`)}
+	files := []UploadFile{
+		{Name: "report.pdf", Data: []byte("field report")},
+		{Name: "old.pdf", Data: []byte("old")},
+		{Name: "extra.pdf", Data: []byte("extra")},
+	}
+
+	preview, err := f.service.PreviewManifest(context.Background(), manifest, files)
+	require.NoError(t, err)
+	require.Len(t, preview.Rows, 6)
+	assert.Equal(t, 2, preview.Creates)
+	assert.Equal(t, 1, preview.Duplicates)
+	assert.Equal(t, 3, preview.Failed)
+	assert.Equal(t, []string{"Author: Zed", "Author: Jane Do"}, preview.NewTerms, "a close spelling is a new term")
+	assert.Empty(t, f.ingester.submitted, "a preview queues nothing")
+
+	report := preview.Rows[0]
+	assert.Equal(t, 2, report.Line)
+	assert.Equal(t, db_types.ManifestOriginUpload, report.Origin)
+	assert.Equal(t, db_types.ManifestOutcomeCreate, report.Outcome)
+	assert.Equal(t, "Field Report", report.Title)
+	assert.Equal(t, []string{"abstract", "source", "keywords", "regions", "categories"}, report.Extracted)
+
+	annual := preview.Rows[1]
+	assert.Equal(t, "2019/annual.pdf", annual.FileName)
+	assert.Equal(t, db_types.ManifestOriginBucket, annual.Origin)
+	assert.Equal(t, db_types.ManifestOutcomeCreate, annual.Outcome)
+
+	assert.Equal(t, db_types.ManifestOutcomeDuplicate, preview.Rows[2].Outcome)
+	assert.Equal(t, db_types.ManifestOutcomeFailed, preview.Rows[3].Outcome)
+	assert.Equal(t, "Listed more than once in the manifest", preview.Rows[4].Message)
+	assert.Equal(t, "extra.pdf", preview.Rows[5].FileName)
+	assert.Zero(t, preview.Rows[5].Line)
+	assert.Equal(t, "Not listed in the manifest", preview.Rows[5].Message)
+}
+
+func TestUploadService_SubmitManifest(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	f.bucket.files["annual.pdf"] = []byte("annual report")
+	manifest := UploadFile{Name: "manifest.json", Data: []byte(`[
+		{"file": "report.pdf", "title": "Field Report", "authors": ["Amy", "Zed"]},
+		{"file": "annual.pdf", "source": "UNDP"}
+	]`)}
+	files := []UploadFile{{Name: "report.pdf", ContentType: "application/pdf", Data: []byte("field report")}}
+
+	_, err := f.service.SubmitManifest(context.Background(), manifest, files, Actor{Username: "alice"})
+	require.NoError(t, err)
+	require.Len(t, f.store.batchFiles, 2)
+	require.Len(t, f.ingester.submitted, 2)
+
+	report := f.ingester.submitted[0]
+	assert.Equal(t, "report.pdf", report.FileName)
+	assert.False(t, report.Stored)
+	assert.Equal(t, "Field Report", report.Metadata.Title)
+	assert.Equal(t, []string{"Amy", "Zed"}, report.Metadata.AuthorName)
+
+	annual := f.ingester.submitted[1]
+	assert.True(t, annual.Stored)
+	assert.Equal(t, "application/pdf", annual.ContentType)
+	assert.Equal(t, "UNDP", annual.Metadata.Source)
+}
+
+func TestUploadService_SubmitManifestInvalid(t *testing.T) {
+	f := newUploadFixture(UploadLimits{MaxFiles: 10, MaxBytes: 1 << 20})
+	manifest := UploadFile{Name: "manifest.csv", Data: []byte("file,titel\nreport.pdf,Report\n")}
+
+	_, err := f.service.SubmitManifest(context.Background(), manifest, nil, Actor{})
+
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+	assert.Empty(t, f.store.batchFiles)
+}
@@ -0,0 +1,224 @@
+package taxonomy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The taxonomies, named as their tables are.
+const (
+	Authors    = "authors"
+	Categories = "categories"
+	Keywords   = "keywords"
+	Publishers = "publishers"
+	Regions    = "regions"
+)
+
+// MinSimilarity is how close a name's folded spelling must be to a term's for
+// Closest to suggest the term, from 0 to 1.
+const MinSimilarity = 0.85
+
+// minFuzzyLength is the shortest folded name Closest compares by similarity.
+// Shorter names, such as acronyms, must match exactly.
+const minFuzzyLength = 5
+
+// Term is a name that refers to a canonical author, keyword, publisher, region
+// or category: the term's own name or one of its aliases.
+type Term struct {
+	ID    uuid.UUID
+	Name  string
+	Alias string
+}
+
+// Label is the name the term is known by, or the alias that matched.
+func (t Term) Label() string {
+	if t.Alias != "" {
+		return t.Alias
+	}
+	return t.Name
+}
+
+// Fold reduces a name to the form names are compared in: lower case, without
+// diacritics, apostrophes or full stops, with other punctuation turned into
+// spaces and runs of spaces collapsed. "Côte d'Ivoire" and "cote divoire" fold
+// to the same key, as do "E.U." and "EU".
+func Fold(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '.', r == '\'', r == '’':
+			continue
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// TitleCase upper-cases the first letter of every word and lower-cases the
+// rest. Terms are title-cased when they are sent to Kendra, whose filters are
+// case-sensitive, so "Bosnia and Herzegovina" is held as "Bosnia And
+// Herzegovina".
+func TitleCase(s string) string {
+	runes := []rune(s)
+	startOfWord := true
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			if startOfWord {
+				runes[i] = unicode.ToUpper(r)
+			} else {
+				runes[i] = unicode.ToLower(r)
+			}
+			startOfWord = false
+		} else {
+			startOfWord = true
+		}
+	}
+	return string(runes)
+}
+
+// Similarity compares two folded names by edit distance, from 0 for nothing in
+// common to 1 for identical.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// Exact finds the term whose name or alias folds to the same key as name.
+// Only an exact match may stand in for a name without a person checking it.
+func Exact(name string, terms []Term) (Term, bool) {
+	key := Fold(name)
+	if key == "" {
+		return Term{}, false
+	}
+	for _, term := range terms {
+		if Fold(term.Label()) == key {
+			return term, true
+		}
+	}
+	return Term{}, false
+}
+
+// Closest finds the term whose name or alias is spelled most like name, at
+// MinSimilarity or above. Names such as "Mary Jones" and "Mark Jones" are that
+// close, so a close term is only a suggestion for a person to confirm.
+func Closest(name string, terms []Term) (Term, bool) {
+	key := Fold(name)
+	if len([]rune(key)) < minFuzzyLength {
+		return Term{}, false
+	}
+
+	var best Term
+	bestScore := 0.0
+	for _, term := range terms {
+		candidate := Fold(term.Label())
+		if len([]rune(candidate)) < minFuzzyLength {
+			continue
+		}
+		if score := Similarity(key, candidate); score >= MinSimilarity && score > bestScore {
+			best, bestScore = term, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// Match finds the term an admin means by name: the Exact match, otherwise the
+// Closest.
+func Match(name string, terms []Term) (Term, bool) {
+	if term, ok := Exact(name, terms); ok {
+		return term, true
+	}
+	return Closest(name, terms)
+}
+
+// Suggest lists up to limit distinct terms for an autocomplete query: terms
+// with a name or alias starting with the query, then those with a word
+// starting with it, then close spellings, each group in the order given.
+func Suggest(query string, terms []Term, limit int) []Term {
+	key := Fold(query)
+	if key == "" {
+		return nil
+	}
+
+	const (
+		prefix = iota
+		wordPrefix
+		similar
+		none
+	)
+	rank := func(candidate string) int {
+		switch {
+		case strings.HasPrefix(candidate, key):
+			return prefix
+		case strings.Contains(candidate, " "+key):
+			return wordPrefix
+		case len([]rune(key)) >= minFuzzyLength && Similarity(key, candidate) >= MinSimilarity:
+			return similar
+		default:
+			return none
+		}
+	}
+
+	best := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	byID := make(map[uuid.UUID]Term)
+	for _, term := range terms {
+		r := rank(Fold(term.Label()))
+		if r == none {
+			continue
+		}
+		previous, seen := best[term.ID]
+		if !seen {
+			ids = append(ids, term.ID)
+		}
+		if !seen || r < previous {
+			best[term.ID] = r
+			byID[term.ID] = term
+		}
+	}
+
+	slices.SortStableFunc(ids, func(a, b uuid.UUID) int { return best[a] - best[b] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	suggestions := make([]Term, len(ids))
+	for i, id := range ids {
+		suggestions[i] = byID[id]
+	}
+	return suggestions
+}
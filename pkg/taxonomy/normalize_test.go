@@ -0,0 +1,137 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Oceania", "oceania"},
+		{"  Côte d'Ivoire ", "cote divoire"},
+		{"São Tomé & Príncipe", "sao tome principe"},
+		{"Democratic Republic Of Congo (DRC)", "democratic republic of congo drc"},
+		{"Müller-Lüdenscheidt, J.", "muller ludenscheidt j"},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Fold(tt.name), tt.name)
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	assert.Equal(t, "Bosnia And Herzegovina", TitleCase("Bosnia and Herzegovina"))
+	assert.Equal(t, "Côte D'Ivoire", TitleCase("CÔTE D'IVOIRE"))
+	assert.Equal(t, "Guinea-Bissau", TitleCase("guinea-bissau"))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 1.0, Similarity("oceania", "oceania"))
+	assert.InDelta(t, 0.857, Similarity("oceana", "oceania"), 0.001)
+	assert.Less(t, Similarity("kenya", "kuwait"), MinSimilarity)
+}
+
+func TestMatch(t *testing.T) {
+	oceania := uuid.New()
+	congo := uuid.New()
+	eu := uuid.New()
+	terms := []Term{
+		{ID: oceania, Name: "Oceania"},
+		{ID: congo, Name: "Democratic Republic of the Congo"},
+		{ID: congo, Name: "Democratic Republic of the Congo", Alias: "DRC"},
+		{ID: eu, Name: "EU"},
+	}
+
+	tests := []struct {
+		name   string
+		wantID uuid.UUID
+		wantOK bool
+	}{
+		{"oceania", oceania, true},
+		{"Oceana", oceania, true},
+		{"drc", congo, true},
+		{"Democratic Republic of Congo", congo, true},
+		{"E.U.", eu, true},
+		{"US", uuid.Nil, false},
+		{"Kenya", uuid.Nil, false},
+		{"", uuid.Nil, false},
+	}
+	for _, tt := range tests {
+		term, ok := Match(tt.name, terms)
+		assert.Equal(t, tt.wantOK, ok, tt.name)
+		assert.Equal(t, tt.wantID, term.ID, tt.name)
+	}
+}
+
+func TestExact(t *testing.T) {
+	mark := uuid.New()
+	joan := uuid.New()
+	meditation := uuid.New()
+	oceania := uuid.New()
+	terms := []Term{
+		{ID: mark, Name: "Mark Jones"},
+		{ID: joan, Name: "Joan Smith"},
+		{ID: meditation, Name: "meditation"},
+		{ID: oceania, Name: "Oceania"},
+		{ID: oceania, Name: "Oceania", Alias: "Australasia"},
+	}
+
+	tests := []struct {
+		name   string
+		wantID uuid.UUID
+		wantOK bool
+	}{
+		{"mark  jones", mark, true},
+		{"australasia", oceania, true},
+		{"Mary Jones", uuid.Nil, false},
+		{"John Smith", uuid.Nil, false},
+		{"mediation", uuid.Nil, false},
+		{"Oceana", uuid.Nil, false},
+		{"", uuid.Nil, false},
+	}
+	for _, tt := range tests {
+		term, ok := Exact(tt.name, terms)
+		assert.Equal(t, tt.wantOK, ok, tt.name)
+		assert.Equal(t, tt.wantID, term.ID, tt.name)
+	}
+
+	// The near misses are still offered to a person as suggestions
+	for name, want := range map[string]uuid.UUID{"Mary Jones": mark, "John Smith": joan, "mediation": meditation} {
+		term, ok := Closest(name, terms)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, term.ID, name)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	africa := uuid.New()
+	southAfrica := uuid.New()
+	ivory := uuid.New()
+	terms := []Term{
+		{ID: southAfrica, Name: "South Africa"},
+		{ID: africa, Name: "Africa"},
+		{ID: ivory, Name: "Côte d'Ivoire"},
+		{ID: ivory, Name: "Côte d'Ivoire", Alias: "Ivory Coast"},
+	}
+
+	got := Suggest("afr", terms, 10)
+	assert.Equal(t, []uuid.UUID{africa, southAfrica}, []uuid.UUID{got[0].ID, got[1].ID})
+
+	got = Suggest("ivory", terms, 10)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "Ivory Coast", got[0].Label())
+	assert.Equal(t, "Côte d'Ivoire", got[0].Name)
+
+	got = Suggest("Afriica", terms, 10)
+	assert.Len(t, got, 1)
+	assert.Equal(t, africa, got[0].ID)
+
+	assert.Len(t, Suggest("a", terms, 1), 1)
+	assert.Empty(t, Suggest(" ", terms, 10))
+}
@@ -0,0 +1,137 @@
+package taxonomy
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Region is a region with the region it belongs to, if any: a country with its
+// continent, for example.
+type Region struct {
+	ID       uuid.UUID
+	Name     string
+	ParentID uuid.UUID // uuid.Nil for a top-level region
+}
+
+// RegionTree answers questions about the region hierarchy by name. Names are
+// compared ignoring case, as search filters compare them.
+type RegionTree struct {
+	ids      map[string]uuid.UUID // lower-cased name -> ID
+	names    map[uuid.UUID]string
+	parents  map[uuid.UUID]uuid.UUID
+	children map[uuid.UUID][]uuid.UUID
+}
+
+// NewRegionTree builds the tree from every region. Parents that are not among
+// regions are ignored.
+func NewRegionTree(regions []Region) *RegionTree {
+	t := &RegionTree{
+		ids:      make(map[string]uuid.UUID, len(regions)),
+		names:    make(map[uuid.UUID]string, len(regions)),
+		parents:  make(map[uuid.UUID]uuid.UUID),
+		children: make(map[uuid.UUID][]uuid.UUID),
+	}
+	for _, r := range regions {
+		t.ids[strings.ToLower(r.Name)] = r.ID
+		t.names[r.ID] = r.Name
+	}
+	for _, r := range regions {
+		if _, ok := t.names[r.ParentID]; ok && r.ParentID != r.ID {
+			t.parents[r.ID] = r.ParentID
+			t.children[r.ParentID] = append(t.children[r.ParentID], r.ID)
+		}
+	}
+	return t
+}
+
+// Parent returns the name of the region name belongs to.
+func (t *RegionTree) Parent(name string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	parent, ok := t.parents[t.ids[strings.ToLower(name)]]
+	if !ok {
+		return "", false
+	}
+	return t.names[parent], true
+}
+
+// Ancestors returns the regions name belongs to, nearest first.
+func (t *RegionTree) Ancestors(name string) []string {
+	if t == nil {
+		return nil
+	}
+	var ancestors []string
+	start := t.ids[strings.ToLower(name)]
+	seen := map[uuid.UUID]bool{start: true}
+	id, ok := t.parents[start]
+	for ok && !seen[id] {
+		seen[id] = true
+		ancestors = append(ancestors, t.names[id])
+		id, ok = t.parents[id]
+	}
+	return ancestors
+}
+
+// Descendants returns every region below name, parents before their children.
+func (t *RegionTree) Descendants(name string) []string {
+	if t == nil {
+		return nil
+	}
+	id, ok := t.ids[strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+	var names []string
+	seen := map[uuid.UUID]bool{id: true}
+	var walk func(uuid.UUID)
+	walk = func(id uuid.UUID) {
+		for _, child := range t.children[id] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			names = append(names, t.names[child])
+			walk(child)
+		}
+	}
+	walk(id)
+	return names
+}
+
+// Expand returns names followed by all of their descendants, each once.
+func (t *RegionTree) Expand(names []string) []string {
+	out := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			out = append(out, name)
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+	for _, name := range names {
+		for _, d := range t.Descendants(name) {
+			add(d)
+		}
+	}
+	return out
+}
+
+// IsWithin reports whether the region id is ancestor or one of its descendants.
+func (t *RegionTree) IsWithin(id, ancestor uuid.UUID) bool {
+	if t == nil {
+		return id == ancestor
+	}
+	seen := make(map[uuid.UUID]bool)
+	for ok := true; ok && !seen[id]; id, ok = t.parents[id] {
+		if id == ancestor {
+			return true
+		}
+		seen[id] = true
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	africaID  = uuid.New()
+	eastID    = uuid.New()
+	kenyaID   = uuid.New()
+	nigeriaID = uuid.New()
+	asiaID    = uuid.New()
+)
+
+func regionTree() *RegionTree {
+	return NewRegionTree([]Region{
+		{ID: africaID, Name: "Africa"},
+		{ID: eastID, Name: "East Africa", ParentID: africaID},
+		{ID: kenyaID, Name: "Kenya", ParentID: eastID},
+		{ID: nigeriaID, Name: "Nigeria", ParentID: africaID},
+		{ID: asiaID, Name: "Asia", ParentID: uuid.New()}, // parent not listed
+	})
+}
+
+func TestRegionTree_Parent(t *testing.T) {
+	tree := regionTree()
+
+	parent, ok := tree.Parent("kenya")
+	assert.True(t, ok)
+	assert.Equal(t, "East Africa", parent)
+
+	_, ok = tree.Parent("Africa")
+	assert.False(t, ok)
+	_, ok = tree.Parent("Asia")
+	assert.False(t, ok, "an unknown parent is ignored")
+	_, ok = tree.Parent("Atlantis")
+	assert.False(t, ok)
+}
+
+func TestRegionTree_Ancestors(t *testing.T) {
+	tree := regionTree()
+
+	assert.Equal(t, []string{"East Africa", "Africa"}, tree.Ancestors("Kenya"))
+	assert.Empty(t, tree.Ancestors("Africa"))
+	assert.Empty(t, tree.Ancestors("Atlantis"))
+}
+
+func TestRegionTree_Descendants(t *testing.T) {
+	tree := regionTree()
+
+	assert.ElementsMatch(t, []string{"East Africa", "Kenya", "Nigeria"}, tree.Descendants("AFRICA"))
+	assert.Equal(t, []string{"Kenya"}, tree.Descendants("East Africa"))
+	assert.Empty(t, tree.Descendants("Kenya"))
+	assert.Empty(t, tree.Descendants("Atlantis"))
+}
+
+func TestRegionTree_Expand(t *testing.T) {
+	tree := regionTree()
+
+	expanded := tree.Expand([]string{"East Africa", "Africa", "Atlantis"})
+	assert.Equal(t, []string{"East Africa", "Africa", "Atlantis"}, expanded[:3], "the selected names come first")
+	assert.ElementsMatch(t, []string{"East Africa", "Africa", "Atlantis", "Kenya", "Nigeria"}, expanded)
+
+	var none *RegionTree
+	assert.Equal(t, []string{"Kenya"}, none.Expand([]string{"Kenya"}))
+}
+
+func TestRegionTree_IsWithin(t *testing.T) {
+	tree := regionTree()
+
+	assert.True(t, tree.IsWithin(kenyaID, africaID))
+	assert.True(t, tree.IsWithin(africaID, africaID))
+	assert.False(t, tree.IsWithin(africaID, kenyaID))
+	assert.False(t, tree.IsWithin(asiaID, africaID))
+}
+
+func TestRegionTree_Cycle(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	tree := NewRegionTree([]Region{{ID: a, Name: "A", ParentID: b}, {ID: b, Name: "B", ParentID: a}})
+
+	assert.Equal(t, []string{"B"}, tree.Ancestors("A"))
+	assert.Equal(t, []string{"B"}, tree.Descendants("A"))
+	assert.False(t, tree.IsWithin(a, uuid.New()))
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+// Keys are managed from a browser session only; a key cannot mint other keys.
+func RegisterAPIKeyRoutes(e *echo.Echo, apiKeysHandler *handlers.APIKeysHandler, sessionManager services.SessionManager) {
+	e.GET("/admin/api-keys", apiKeysHandler.APIKeysPage, sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+	e.POST("/admin/api-keys", apiKeysHandler.CreateKey, sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+	e.POST("/admin/api-keys/revoke", apiKeysHandler.RevokeKey, sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+// APIPrefix is the base path of the versioned JSON API.
+const APIPrefix = "/api/v1"
+
+// The API is open to anonymous callers; a bearer key, when sent, must be valid
+// and have the route's scope, and its requests count against the key's rate limit.
+func RegisterAPIRoutes(e *echo.Echo, apiHandler *handlers.APIHandler, apiKeys services.APIKeyAuthenticator) {
+	api := e.Group(APIPrefix)
+	api.GET("/search", apiHandler.Search, apiKeys.RequireScope(services.ScopeSearch))
+	api.GET("/documents/:id", apiHandler.Document, apiKeys.RequireScope(services.ScopeRead))
+	api.GET("/facets/:facet", apiHandler.Facet, apiKeys.RequireScope(services.ScopeRead))
+	api.GET("/openapi.json", apiHandler.OpenAPI)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAuthorRoutes(e *echo.Echo, authorHandler *handlers.AuthorHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/authors/:id", authorHandler.ProfilePage)
+	e.POST("/admin/terms/authors/:id/details", authorHandler.UpdateDetails, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+}
@@ -6,10 +6,11 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterDatabaseRoutes(e *echo.Echo, databaseHandler *handlers.DatabaseHandler, sessionManager services.SessionManager) {
+func RegisterDatabaseRoutes(e *echo.Echo, databaseHandler *handlers.DatabaseHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
 	// --- Database Search Routes ---
-	e.GET("/authors", databaseHandler.DatabaseSearchAuthors, sessionManager.RequireAuth)
-	e.GET("/keywords", databaseHandler.DatabaseSearchKeywords, sessionManager.RequireAuth)
-	e.GET("/regions", databaseHandler.DatabaseSearchRegions, sessionManager.RequireAuth)
-	e.GET("/categories", databaseHandler.DatabaseSearchCategories, sessionManager.RequireAuth)
+	e.GET("/authors", databaseHandler.DatabaseSearchAuthors, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
+	e.GET("/keywords", databaseHandler.DatabaseSearchKeywords, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
+	e.GET("/regions", databaseHandler.DatabaseSearchRegions, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
+	e.GET("/categories", databaseHandler.DatabaseSearchCategories, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
+	e.GET("/publishers", databaseHandler.DatabaseSearchPublishers, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterDuplicatesRoutes(e *echo.Echo, duplicatesHandler *handlers.DuplicatesHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/duplicates", duplicatesHandler.DuplicatesPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermMarkDelete))
+	e.POST("/admin/duplicates/keep", duplicatesHandler.KeepDocument, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermMarkDelete))
+	e.POST("/admin/duplicates/delete", duplicatesHandler.MarkDuplicate, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermMarkDelete))
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterExtractionRoutes(e *echo.Echo, extractionHandler *handlers.ExtractionHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/extraction", extractionHandler.SettingsPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+
+	// Prompt versions
+	e.GET("/admin/extraction/prompts/:version", extractionHandler.PromptVersion, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+	e.POST("/admin/extraction/prompts", extractionHandler.SavePrompt, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+	e.POST("/admin/extraction/prompts/:version/restore", extractionHandler.RestorePrompt, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+
+	// Vocabularies
+	e.POST("/admin/extraction/vocabulary/:vocabulary", extractionHandler.AddTerm, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+	e.POST("/admin/extraction/vocabulary/:vocabulary/remove", extractionHandler.RemoveTerm, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterIngestRoutes(e *echo.Echo, ingestHandler *handlers.IngestHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	// Recent upload jobs
+	e.GET("/upload/jobs", ingestHandler.JobsPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+
+	// Status page for one upload job, and the fragment it polls
+	e.GET("/upload/jobs/:jobId", ingestHandler.JobPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+	e.GET("/upload/jobs/:jobId/status", ingestHandler.JobStatus, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+
+	// Queue a failed upload job again
+	e.POST("/upload/jobs/:jobId/retry", ingestHandler.RetryJob, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+
+	// Summary of a multi-file upload, and the fragment it polls
+	e.GET("/upload/batches/:batchId", ingestHandler.BatchPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+	e.GET("/upload/batches/:batchId/status", ingestHandler.BatchStatus, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+}
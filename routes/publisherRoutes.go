@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterPublisherRoutes(e *echo.Echo, publisherHandler *handlers.PublisherHandler) {
+	e.GET("/publishers/:id", publisherHandler.ProfilePage)
+}
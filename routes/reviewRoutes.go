@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterReviewRoutes(e *echo.Echo, reviewHandler *handlers.ReviewHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/review", reviewHandler.QueuePage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermReviewMetadata))
+}
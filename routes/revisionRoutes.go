@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterRevisionRoutes(e *echo.Echo, revisionsHandler *handlers.RevisionsHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	// Revisions tab of the metadata page
+	e.GET("/edit-metadata/:fileId/revisions", revisionsHandler.Revisions, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
+
+	e.GET("/edit-metadata/:fileId/revisions/compare", revisionsHandler.CompareRevisions, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
+
+	e.POST("/edit-metadata/:fileId/revisions/:revision/restore", revisionsHandler.RestoreRevision, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
+}
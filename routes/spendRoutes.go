@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterSpendRoutes(e *echo.Echo, spendHandler *handlers.SpendHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/spend", spendHandler.SpendPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageExtraction))
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/DSSD-Madison/gmu/pkg/handlers"
+	"github.com/DSSD-Madison/gmu/pkg/services"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterTaxonomyRoutes(e *echo.Echo, taxonomyHandler *handlers.TaxonomyHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/terms", taxonomyHandler.TermsPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.GET("/admin/terms/:taxonomy", taxonomyHandler.TaxonomyPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.GET("/admin/terms/:taxonomy/:id", taxonomyHandler.TermPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/aliases", taxonomyHandler.AddAlias, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/approve", taxonomyHandler.Approve, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/map", taxonomyHandler.MapTerm, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/rename", taxonomyHandler.Rename, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/merge", taxonomyHandler.Merge, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/split", taxonomyHandler.Split, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/:taxonomy/:id/delete", taxonomyHandler.Delete, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+	e.POST("/admin/terms/regions/:id/parent", taxonomyHandler.SetRegionParent, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageTaxonomy))
+}
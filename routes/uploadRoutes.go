@@ -6,22 +6,33 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterUploadRoutes(e *echo.Echo, uploadHandler *handlers.UploadHandler, sessionManager services.SessionManager) {
+func RegisterUploadRoutes(e *echo.Echo, uploadHandler *handlers.UploadHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
 	// Page to display the upload form
-	e.GET("/upload", uploadHandler.PDFUploadPage, sessionManager.RequireAuth)
+	e.GET("/upload", uploadHandler.PDFUploadPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
 
 	// Action endpoint to handle the actual file upload POST request
-	e.POST("/upload", uploadHandler.HandlePDFUpload, sessionManager.RequireAuth) // <<< CORRECTED HANDLER
+	e.POST("/upload", uploadHandler.HandlePDFUpload, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload)) // <<< CORRECTED HANDLER
+
+	// Import files listed in a CSV or JSON manifest, with a dry-run preview
+	e.GET("/upload/manifest", uploadHandler.ManifestPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+	e.POST("/upload/manifest/preview", uploadHandler.PreviewManifest, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
+	e.POST("/upload/manifest", uploadHandler.ImportManifest, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermUpload))
 
 	// Page to display the metadata edit form, identified by fileId
-	e.GET("/edit-metadata/:fileId", uploadHandler.PDFMetadataEditPage, sessionManager.RequireAuth) // <<< ADDED ROUTE
+	e.GET("/edit-metadata/:fileId", uploadHandler.PDFMetadataEditPage, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata)) // <<< ADDED ROUTE
+
+	// History tab of the metadata page
+	e.GET("/edit-metadata/:fileId/history", uploadHandler.DocumentHistory, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
+
+	// Suggests documents to link as translations of the one being edited
+	e.GET("/edit-metadata/:fileId/translations", uploadHandler.SuggestTranslations, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
 
 	// Action endpoint to handle the saving of edited metadata
-	e.POST("/save-metadata", uploadHandler.HandleMetadataSave, sessionManager.RequireAuth)
+	e.POST("/save-metadata", uploadHandler.HandleMetadataSave, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermEditMetadata))
 
-	e.POST("/toggle-delete", uploadHandler.ToggleDelete, sessionManager.RequireAuth)
+	e.POST("/toggle-delete", uploadHandler.ToggleDelete, apiKeys.RequireScope(services.ScopeUpload), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermMarkDelete))
 
-	e.GET("/latest", uploadHandler.LatestDocumentsPage, sessionManager.RequireAuth)
+	e.GET("/latest", uploadHandler.LatestDocumentsPage, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
 
-	e.POST("/documents-search", uploadHandler.SearchDocumentsPage, sessionManager.RequireAuth)
+	e.POST("/documents-search", uploadHandler.SearchDocumentsPage, apiKeys.RequireScope(services.ScopeRead), sessionManager.RequireAuth)
 }
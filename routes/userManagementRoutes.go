@@ -6,8 +6,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterUserManagementRoutes(e *echo.Echo, userManagementHandler *handlers.UserManagementHandler, sessionManager services.SessionManager) {
-	e.GET("/admin/users", userManagementHandler.ManageUsersPage, sessionManager.RequireAuth)
-	e.POST("/admin/users", userManagementHandler.CreateNewUser, sessionManager.RequireAuth)
-	e.POST("/admin/users/delete", userManagementHandler.DeleteUser, sessionManager.RequireAuth)
+func RegisterUserManagementRoutes(e *echo.Echo, userManagementHandler *handlers.UserManagementHandler, sessionManager services.SessionManager, apiKeys services.APIKeyAuthenticator) {
+	e.GET("/admin/users", userManagementHandler.ManageUsersPage, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+	e.POST("/admin/users", userManagementHandler.CreateNewUser, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+	e.POST("/admin/users/role", userManagementHandler.UpdateRole, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
+	e.POST("/admin/users/delete", userManagementHandler.DeleteUser, apiKeys.RequireScope(services.ScopeAdmin), sessionManager.RequireAuth, sessionManager.RequirePermission(services.PermManageUsers))
 }
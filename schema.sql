@@ -24,17 +24,168 @@ SET row_security = off;
 -- *not* creating schema, since initdb creates it
 
 
+--
+-- Name: document_revisions_immutable(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.document_revisions_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    RAISE EXCEPTION 'document revisions cannot be modified';
+END;
+$$;
+
+
+--
+-- Name: document_search_doc_trigger(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.document_search_doc_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    PERFORM refresh_document_search(NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: document_search_join_trigger(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.document_search_join_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.doc_id IS NOT NULL THEN
+        PERFORM refresh_document_search(OLD.doc_id);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.doc_id IS NOT NULL THEN
+        PERFORM refresh_document_search(NEW.doc_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+
+--
+-- Name: document_search_term_trigger(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.document_search_term_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $_$
+BEGIN
+    EXECUTE format(
+        'SELECT refresh_document_search(doc_id) FROM %I WHERE %I = $1 AND doc_id IS NOT NULL',
+        TG_ARGV[0], TG_ARGV[1]
+    ) USING NEW.id;
+    RETURN NULL;
+END;
+$_$;
+
+
+--
+-- Name: refresh_document_search(uuid); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.refresh_document_search(p_doc_id uuid) RETURNS void
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    INSERT INTO document_search (doc_id, search_vector)
+    SELECT
+        d.id,
+        setweight(to_tsvector('english', COALESCE(d.title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(d.english_title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(a.name, ' ')
+            FROM doc_authors da JOIN authors a ON a.id = da.author_id
+            WHERE da.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(k.name, ' ')
+            FROM doc_keywords dk JOIN keywords k ON k.id = dk.keyword_id
+            WHERE dk.doc_id = d.id), '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(d.abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(d.english_abstract, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(r.name, ' ')
+            FROM doc_regions dr JOIN regions r ON r.id = dr.region_id
+            WHERE dr.doc_id = d.id), '')), 'D') ||
+        setweight(to_tsvector('english', COALESCE((
+            SELECT string_agg(c.name, ' ')
+            FROM doc_categories dc JOIN categories c ON c.id = dc.category_id
+            WHERE dc.doc_id = d.id), '')), 'D')
+    FROM documents d
+    WHERE d.id = p_doc_id
+    ON CONFLICT (doc_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+END;
+$$;
+
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: api_keys; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.api_keys (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    prefix character varying(16) NOT NULL,
+    key_hash character varying(64) NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    rate_limit integer DEFAULT 60 NOT NULL,
+    expires_at timestamp without time zone,
+    last_used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: audit_events; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.audit_events (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    actor_id uuid,
+    actor_name text NOT NULL,
+    action character varying(50) NOT NULL,
+    doc_id uuid,
+    target text,
+    diff jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: author_aliases; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.author_aliases (
+    alias_key character varying(255) NOT NULL,
+    alias character varying(255) NOT NULL,
+    author_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
 --
 -- Name: authors; Type: TABLE; Schema: public; Owner: -
 --
 
 CREATE TABLE public.authors (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
-    name character varying(255) NOT NULL
+    name character varying(255) NOT NULL,
+    approved boolean DEFAULT true NOT NULL,
+    orcid character varying(19),
+    affiliation character varying(255),
+    CONSTRAINT authors_orcid_check CHECK (((orcid)::text ~ '^[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{3}[0-9X]$'::text))
 );
 
 
@@ -44,7 +195,20 @@ CREATE TABLE public.authors (
 
 CREATE TABLE public.categories (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
-    name character varying(255) NOT NULL
+    name character varying(255) NOT NULL,
+    approved boolean DEFAULT true NOT NULL
+);
+
+
+--
+-- Name: category_aliases; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.category_aliases (
+    alias_key character varying(255) NOT NULL,
+    alias character varying(255) NOT NULL,
+    category_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
 
@@ -92,6 +256,83 @@ CREATE TABLE public.doc_regions (
 );
 
 
+--
+-- Name: document_field_sources; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.document_field_sources (
+    doc_id uuid NOT NULL,
+    field character varying(32) NOT NULL,
+    source character varying(10) NOT NULL,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT document_field_sources_source_check CHECK (((source)::text = ANY ((ARRAY['llm'::character varying, 'human'::character varying])::text[])))
+);
+
+
+--
+-- Name: document_pages; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.document_pages (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    doc_id uuid NOT NULL,
+    page_number integer NOT NULL,
+    content text NOT NULL,
+    search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english'::regconfig, content)) STORED
+);
+
+
+--
+-- Name: document_revisions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.document_revisions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    doc_id uuid NOT NULL,
+    revision integer NOT NULL,
+    title text NOT NULL,
+    abstract text,
+    publish_date date,
+    source character varying(255),
+    authors text[] DEFAULT '{}'::text[] NOT NULL,
+    keywords text[] DEFAULT '{}'::text[] NOT NULL,
+    regions text[] DEFAULT '{}'::text[] NOT NULL,
+    categories text[] DEFAULT '{}'::text[] NOT NULL,
+    restored_from integer,
+    created_by uuid,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    language character varying(8),
+    english_title text,
+    english_abstract text
+);
+
+
+--
+-- Name: document_search; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.document_search (
+    doc_id uuid NOT NULL,
+    search_vector tsvector NOT NULL
+);
+
+
+--
+-- Name: document_sync_status; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.document_sync_status (
+    doc_id uuid NOT NULL,
+    operation character varying(20) NOT NULL,
+    status character varying(20) NOT NULL,
+    error text,
+    attempts integer DEFAULT 1 NOT NULL,
+    last_attempt_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_success_at timestamp without time zone
+);
+
+
 --
 -- Name: documents; Type: TABLE; Schema: public; Owner: -
 --
@@ -102,7 +343,6 @@ CREATE TABLE public.documents (
     title text NOT NULL,
     abstract text,
     publish_date date,
-    source character varying(255),
     to_index boolean DEFAULT true,
     s3_file character varying(1024) NOT NULL,
     s3_file_preview character varying(1024),
@@ -110,7 +350,33 @@ CREATE TABLE public.documents (
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     deleted_at timestamp without time zone,
     to_delete boolean DEFAULT false NOT NULL,
-    to_generate_preview boolean DEFAULT true
+    to_generate_preview boolean DEFAULT true,
+    content_hash character varying(64),
+    review_state character varying(20) DEFAULT 'approved'::character varying NOT NULL,
+    approved_by uuid,
+    approved_by_name character varying(255),
+    approved_at timestamp without time zone,
+    publisher_id uuid,
+    language character varying(8),
+    english_title text,
+    english_abstract text,
+    translation_group uuid,
+    CONSTRAINT documents_review_state_check CHECK (((review_state)::text = ANY ((ARRAY['ai_extracted'::character varying, 'in_review'::character varying, 'approved'::character varying])::text[])))
+);
+
+
+--
+-- Name: extraction_prompts; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.extraction_prompts (
+    version integer NOT NULL,
+    template text NOT NULL,
+    note text DEFAULT ''::text NOT NULL,
+    restored_from integer,
+    created_by uuid,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
 
@@ -132,13 +398,131 @@ CREATE TABLE public.flyway_schema_history (
 );
 
 
+--
+-- Name: ingest_batch_files; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.ingest_batch_files (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    batch_id uuid NOT NULL,
+    "position" integer NOT NULL,
+    file_name character varying(1024) NOT NULL,
+    outcome character varying(16) NOT NULL,
+    message text,
+    job_id uuid,
+    doc_id uuid,
+    created_by uuid,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT ingest_batch_files_outcome_check CHECK (((outcome)::text = ANY ((ARRAY['queued'::character varying, 'duplicate'::character varying, 'failed'::character varying])::text[])))
+);
+
+
+--
+-- Name: ingest_jobs; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.ingest_jobs (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    doc_id uuid NOT NULL,
+    file_name character varying(255) NOT NULL,
+    s3_file character varying(1024) NOT NULL,
+    content_type character varying(255) NOT NULL,
+    content_hash character varying(64) NOT NULL,
+    file_data bytea,
+    stage character varying(32) DEFAULT 'received'::character varying NOT NULL,
+    status character varying(16) DEFAULT 'pending'::character varying NOT NULL,
+    pages text[],
+    metadata jsonb DEFAULT '{}'::jsonb NOT NULL,
+    error text,
+    attempts integer DEFAULT 0 NOT NULL,
+    created_by uuid,
+    created_by_name text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    provided_metadata jsonb DEFAULT '{}'::jsonb NOT NULL,
+    CONSTRAINT ingest_jobs_stage_check CHECK (((stage)::text = ANY ((ARRAY['received'::character varying, 'stored'::character varying, 'text_extracted'::character varying, 'metadata_extracted'::character varying, 'saved'::character varying])::text[]))),
+    CONSTRAINT ingest_jobs_status_check CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'running'::character varying, 'failed'::character varying, 'done'::character varying])::text[])))
+);
+
+
+--
+-- Name: keyword_aliases; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.keyword_aliases (
+    alias_key character varying(255) NOT NULL,
+    alias character varying(255) NOT NULL,
+    keyword_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
 --
 -- Name: keywords; Type: TABLE; Schema: public; Owner: -
 --
 
 CREATE TABLE public.keywords (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
-    name character varying(255) NOT NULL
+    name character varying(255) NOT NULL,
+    approved boolean DEFAULT true NOT NULL
+);
+
+
+--
+-- Name: metadata_extractions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.metadata_extractions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    job_id uuid,
+    doc_id uuid NOT NULL,
+    extractor character varying(32) NOT NULL,
+    model character varying(255) DEFAULT ''::character varying NOT NULL,
+    prompt_version character varying(64) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    input_tokens integer DEFAULT 0 NOT NULL,
+    output_tokens integer DEFAULT 0 NOT NULL,
+    cost double precision,
+    latency_ms integer DEFAULT 0 NOT NULL,
+    created_by uuid,
+    created_by_name character varying(255) DEFAULT ''::character varying NOT NULL,
+    error text
+);
+
+
+--
+-- Name: publisher_aliases; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.publisher_aliases (
+    alias_key character varying(255) NOT NULL,
+    alias character varying(255) NOT NULL,
+    publisher_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+--
+-- Name: publishers; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.publishers (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(255) NOT NULL,
+    approved boolean DEFAULT true NOT NULL
+);
+
+
+--
+-- Name: region_aliases; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.region_aliases (
+    alias_key character varying(255) NOT NULL,
+    alias character varying(255) NOT NULL,
+    region_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
 
 
@@ -148,7 +532,11 @@ CREATE TABLE public.keywords (
 
 CREATE TABLE public.regions (
     id uuid DEFAULT gen_random_uuid() NOT NULL,
-    name character varying(255) NOT NULL
+    name character varying(255) NOT NULL,
+    approved boolean DEFAULT true NOT NULL,
+    parent_id uuid,
+    iso_code character varying(2),
+    CONSTRAINT regions_parent_check CHECK ((parent_id <> id))
 );
 
 
@@ -159,12 +547,72 @@ CREATE TABLE public.regions (
 CREATE TABLE public.users (
     username text NOT NULL,
     password_hash text NOT NULL,
-    is_master boolean DEFAULT false NOT NULL,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
-    id uuid DEFAULT gen_random_uuid() NOT NULL
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    role character varying(20) DEFAULT 'editor'::character varying NOT NULL,
+    CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['viewer'::character varying, 'editor'::character varying, 'reviewer'::character varying, 'admin'::character varying])::text[])))
+);
+
+
+--
+-- Name: vocabulary_categories; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.vocabulary_categories (
+    category_id uuid NOT NULL
+);
+
+
+--
+-- Name: vocabulary_keywords; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.vocabulary_keywords (
+    keyword_id uuid NOT NULL
+);
+
+
+--
+-- Name: vocabulary_regions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.vocabulary_regions (
+    region_id uuid NOT NULL
 );
 
 
+--
+-- Name: api_keys api_keys_key_hash_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.api_keys
+    ADD CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash);
+
+
+--
+-- Name: api_keys api_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.api_keys
+    ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: audit_events audit_events_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.audit_events
+    ADD CONSTRAINT audit_events_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: author_aliases author_aliases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.author_aliases
+    ADD CONSTRAINT author_aliases_pkey PRIMARY KEY (alias_key);
+
+
 --
 -- Name: authors authors_name_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -173,6 +621,14 @@ ALTER TABLE ONLY public.authors
     ADD CONSTRAINT authors_name_key UNIQUE (name);
 
 
+--
+-- Name: authors authors_orcid_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.authors
+    ADD CONSTRAINT authors_orcid_key UNIQUE (orcid);
+
+
 --
 -- Name: authors authors_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -197,6 +653,14 @@ ALTER TABLE ONLY public.categories
     ADD CONSTRAINT categories_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: category_aliases category_aliases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.category_aliases
+    ADD CONSTRAINT category_aliases_pkey PRIMARY KEY (alias_key);
+
+
 --
 -- Name: doc_authors doc_authors_doc_id_author_id_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -262,126 +726,329 @@ ALTER TABLE ONLY public.doc_regions
 
 
 --
--- Name: documents documents_file_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_field_sources document_field_sources_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.documents
-    ADD CONSTRAINT documents_file_name_key UNIQUE (file_name);
+ALTER TABLE ONLY public.document_field_sources
+    ADD CONSTRAINT document_field_sources_pkey PRIMARY KEY (doc_id, field);
 
 
 --
--- Name: documents documents_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_pages document_pages_doc_id_page_number_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.documents
-    ADD CONSTRAINT documents_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.document_pages
+    ADD CONSTRAINT document_pages_doc_id_page_number_key UNIQUE (doc_id, page_number);
 
 
 --
--- Name: documents documents_s3_file_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_pages document_pages_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.documents
-    ADD CONSTRAINT documents_s3_file_key UNIQUE (s3_file);
+ALTER TABLE ONLY public.document_pages
+    ADD CONSTRAINT document_pages_pkey PRIMARY KEY (id);
 
 
 --
--- Name: documents documents_s3_file_preview_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_revisions document_revisions_doc_id_revision_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.documents
-    ADD CONSTRAINT documents_s3_file_preview_key UNIQUE (s3_file_preview);
+ALTER TABLE ONLY public.document_revisions
+    ADD CONSTRAINT document_revisions_doc_id_revision_key UNIQUE (doc_id, revision);
 
 
 --
--- Name: flyway_schema_history flyway_schema_history_pk; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_revisions document_revisions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.flyway_schema_history
-    ADD CONSTRAINT flyway_schema_history_pk PRIMARY KEY (installed_rank);
+ALTER TABLE ONLY public.document_revisions
+    ADD CONSTRAINT document_revisions_pkey PRIMARY KEY (id);
 
 
 --
--- Name: keywords keywords_keyword_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_search document_search_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.keywords
-    ADD CONSTRAINT keywords_keyword_key UNIQUE (name);
+ALTER TABLE ONLY public.document_search
+    ADD CONSTRAINT document_search_pkey PRIMARY KEY (doc_id);
 
 
 --
--- Name: keywords keywords_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: document_sync_status document_sync_status_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.keywords
-    ADD CONSTRAINT keywords_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.document_sync_status
+    ADD CONSTRAINT document_sync_status_pkey PRIMARY KEY (doc_id);
 
 
 --
--- Name: regions regions_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: documents documents_file_name_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.regions
-    ADD CONSTRAINT regions_name_key UNIQUE (name);
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_file_name_key UNIQUE (file_name);
 
 
 --
--- Name: regions regions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: documents documents_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.regions
-    ADD CONSTRAINT regions_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_pkey PRIMARY KEY (id);
 
 
 --
--- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: documents documents_s3_file_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.users
-    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_s3_file_key UNIQUE (s3_file);
 
 
 --
--- Name: users users_username_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: documents documents_s3_file_preview_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.users
-    ADD CONSTRAINT users_username_key UNIQUE (username);
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_s3_file_preview_key UNIQUE (s3_file_preview);
 
 
 --
--- Name: flyway_schema_history_s_idx; Type: INDEX; Schema: public; Owner: -
+-- Name: extraction_prompts extraction_prompts_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX flyway_schema_history_s_idx ON public.flyway_schema_history USING btree (success);
+ALTER TABLE ONLY public.extraction_prompts
+    ADD CONSTRAINT extraction_prompts_pkey PRIMARY KEY (version);
 
 
 --
--- Name: idx_categories_name; Type: INDEX; Schema: public; Owner: -
+-- Name: flyway_schema_history flyway_schema_history_pk; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_categories_name ON public.categories USING btree (name);
+ALTER TABLE ONLY public.flyway_schema_history
+    ADD CONSTRAINT flyway_schema_history_pk PRIMARY KEY (installed_rank);
 
 
 --
--- Name: idx_doc_authors_author_id; Type: INDEX; Schema: public; Owner: -
+-- Name: ingest_batch_files ingest_batch_files_batch_id_position_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_doc_authors_author_id ON public.doc_authors USING btree (author_id);
+ALTER TABLE ONLY public.ingest_batch_files
+    ADD CONSTRAINT ingest_batch_files_batch_id_position_key UNIQUE (batch_id, "position");
 
 
 --
--- Name: idx_doc_authors_doc_id; Type: INDEX; Schema: public; Owner: -
+-- Name: ingest_batch_files ingest_batch_files_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_doc_authors_doc_id ON public.doc_authors USING btree (doc_id);
+ALTER TABLE ONLY public.ingest_batch_files
+    ADD CONSTRAINT ingest_batch_files_pkey PRIMARY KEY (id);
 
 
 --
--- Name: idx_doc_categories_category_id; Type: INDEX; Schema: public; Owner: -
+-- Name: ingest_jobs ingest_jobs_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_doc_categories_category_id ON public.doc_categories USING btree (category_id);
+ALTER TABLE ONLY public.ingest_jobs
+    ADD CONSTRAINT ingest_jobs_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: keyword_aliases keyword_aliases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.keyword_aliases
+    ADD CONSTRAINT keyword_aliases_pkey PRIMARY KEY (alias_key);
+
+
+--
+-- Name: keywords keywords_keyword_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.keywords
+    ADD CONSTRAINT keywords_keyword_key UNIQUE (name);
+
+
+--
+-- Name: keywords keywords_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.keywords
+    ADD CONSTRAINT keywords_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: metadata_extractions metadata_extractions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.metadata_extractions
+    ADD CONSTRAINT metadata_extractions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: publisher_aliases publisher_aliases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.publisher_aliases
+    ADD CONSTRAINT publisher_aliases_pkey PRIMARY KEY (alias_key);
+
+
+--
+-- Name: publishers publishers_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.publishers
+    ADD CONSTRAINT publishers_name_key UNIQUE (name);
+
+
+--
+-- Name: publishers publishers_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.publishers
+    ADD CONSTRAINT publishers_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: region_aliases region_aliases_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.region_aliases
+    ADD CONSTRAINT region_aliases_pkey PRIMARY KEY (alias_key);
+
+
+--
+-- Name: regions regions_iso_code_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.regions
+    ADD CONSTRAINT regions_iso_code_key UNIQUE (iso_code);
+
+
+--
+-- Name: regions regions_name_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.regions
+    ADD CONSTRAINT regions_name_key UNIQUE (name);
+
+
+--
+-- Name: regions regions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.regions
+    ADD CONSTRAINT regions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: users users_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: users users_username_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT users_username_key UNIQUE (username);
+
+
+--
+-- Name: vocabulary_categories vocabulary_categories_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_categories
+    ADD CONSTRAINT vocabulary_categories_pkey PRIMARY KEY (category_id);
+
+
+--
+-- Name: vocabulary_keywords vocabulary_keywords_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_keywords
+    ADD CONSTRAINT vocabulary_keywords_pkey PRIMARY KEY (keyword_id);
+
+
+--
+-- Name: vocabulary_regions vocabulary_regions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_regions
+    ADD CONSTRAINT vocabulary_regions_pkey PRIMARY KEY (region_id);
+
+
+--
+-- Name: flyway_schema_history_s_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX flyway_schema_history_s_idx ON public.flyway_schema_history USING btree (success);
+
+
+--
+-- Name: idx_api_keys_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_api_keys_user_id ON public.api_keys USING btree (user_id);
+
+
+--
+-- Name: idx_audit_events_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_audit_events_created_at ON public.audit_events USING btree (created_at DESC);
+
+
+--
+-- Name: idx_audit_events_doc_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_audit_events_doc_id ON public.audit_events USING btree (doc_id, created_at DESC);
+
+
+--
+-- Name: idx_author_aliases_author_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_author_aliases_author_id ON public.author_aliases USING btree (author_id);
+
+
+--
+-- Name: idx_categories_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_categories_name ON public.categories USING btree (name);
+
+
+--
+-- Name: idx_category_aliases_category_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_category_aliases_category_id ON public.category_aliases USING btree (category_id);
+
+
+--
+-- Name: idx_doc_authors_author_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_doc_authors_author_id ON public.doc_authors USING btree (author_id);
+
+
+--
+-- Name: idx_doc_authors_doc_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_doc_authors_doc_id ON public.doc_authors USING btree (doc_id);
+
+
+--
+-- Name: idx_doc_categories_category_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_doc_categories_category_id ON public.doc_categories USING btree (category_id);
 
 
 --
@@ -419,6 +1086,41 @@ CREATE INDEX idx_doc_regions_doc_id ON public.doc_regions USING btree (doc_id);
 CREATE INDEX idx_doc_regions_region_id ON public.doc_regions USING btree (region_id);
 
 
+--
+-- Name: idx_document_pages_doc_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_document_pages_doc_id ON public.document_pages USING btree (doc_id);
+
+
+--
+-- Name: idx_document_pages_search_vector; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_document_pages_search_vector ON public.document_pages USING gin (search_vector);
+
+
+--
+-- Name: idx_document_search_vector; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_document_search_vector ON public.document_search USING gin (search_vector);
+
+
+--
+-- Name: idx_document_sync_status_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_document_sync_status_status ON public.document_sync_status USING btree (status);
+
+
+--
+-- Name: idx_documents_content_hash; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_documents_content_hash ON public.documents USING btree (content_hash);
+
+
 --
 -- Name: idx_documents_created_at; Type: INDEX; Schema: public; Owner: -
 --
@@ -433,6 +1135,13 @@ CREATE INDEX idx_documents_created_at ON public.documents USING btree (created_a
 CREATE INDEX idx_documents_file_name ON public.documents USING btree (file_name);
 
 
+--
+-- Name: idx_documents_language; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_documents_language ON public.documents USING btree (language);
+
+
 --
 -- Name: idx_documents_publish_date; Type: INDEX; Schema: public; Owner: -
 --
@@ -440,6 +1149,20 @@ CREATE INDEX idx_documents_file_name ON public.documents USING btree (file_name)
 CREATE INDEX idx_documents_publish_date ON public.documents USING btree (publish_date);
 
 
+--
+-- Name: idx_documents_publisher_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_documents_publisher_id ON public.documents USING btree (publisher_id);
+
+
+--
+-- Name: idx_documents_review_state; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_documents_review_state ON public.documents USING btree (created_at) WHERE ((review_state)::text <> 'approved'::text);
+
+
 --
 -- Name: idx_documents_title; Type: INDEX; Schema: public; Owner: -
 --
@@ -447,6 +1170,62 @@ CREATE INDEX idx_documents_publish_date ON public.documents USING btree (publish
 CREATE INDEX idx_documents_title ON public.documents USING btree (title);
 
 
+--
+-- Name: idx_documents_translation_group; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_documents_translation_group ON public.documents USING btree (translation_group) WHERE (translation_group IS NOT NULL);
+
+
+--
+-- Name: idx_ingest_jobs_content_hash; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_ingest_jobs_content_hash ON public.ingest_jobs USING btree (content_hash) WHERE ((status)::text <> 'done'::text);
+
+
+--
+-- Name: idx_ingest_jobs_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_ingest_jobs_status ON public.ingest_jobs USING btree (status) WHERE ((status)::text <> 'done'::text);
+
+
+--
+-- Name: idx_keyword_aliases_keyword_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_keyword_aliases_keyword_id ON public.keyword_aliases USING btree (keyword_id);
+
+
+--
+-- Name: idx_metadata_extractions_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_metadata_extractions_created_at ON public.metadata_extractions USING btree (created_at);
+
+
+--
+-- Name: idx_metadata_extractions_job_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_metadata_extractions_job_id ON public.metadata_extractions USING btree (job_id);
+
+
+--
+-- Name: idx_publisher_aliases_publisher_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_publisher_aliases_publisher_id ON public.publisher_aliases USING btree (publisher_id);
+
+
+--
+-- Name: idx_region_aliases_region_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_region_aliases_region_id ON public.region_aliases USING btree (region_id);
+
+
 --
 -- Name: idx_regions_name; Type: INDEX; Schema: public; Owner: -
 --
@@ -454,6 +1233,115 @@ CREATE INDEX idx_documents_title ON public.documents USING btree (title);
 CREATE INDEX idx_regions_name ON public.regions USING btree (name);
 
 
+--
+-- Name: idx_regions_parent_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_regions_parent_id ON public.regions USING btree (parent_id);
+
+
+--
+-- Name: authors authors_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER authors_search_refresh AFTER UPDATE OF name ON public.authors FOR EACH ROW EXECUTE FUNCTION public.document_search_term_trigger('doc_authors', 'author_id');
+
+
+--
+-- Name: categories categories_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER categories_search_refresh AFTER UPDATE OF name ON public.categories FOR EACH ROW EXECUTE FUNCTION public.document_search_term_trigger('doc_categories', 'category_id');
+
+
+--
+-- Name: doc_authors doc_authors_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER doc_authors_search_refresh AFTER INSERT OR DELETE OR UPDATE ON public.doc_authors FOR EACH ROW EXECUTE FUNCTION public.document_search_join_trigger();
+
+
+--
+-- Name: doc_categories doc_categories_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER doc_categories_search_refresh AFTER INSERT OR DELETE OR UPDATE ON public.doc_categories FOR EACH ROW EXECUTE FUNCTION public.document_search_join_trigger();
+
+
+--
+-- Name: doc_keywords doc_keywords_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER doc_keywords_search_refresh AFTER INSERT OR DELETE OR UPDATE ON public.doc_keywords FOR EACH ROW EXECUTE FUNCTION public.document_search_join_trigger();
+
+
+--
+-- Name: doc_regions doc_regions_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER doc_regions_search_refresh AFTER INSERT OR DELETE OR UPDATE ON public.doc_regions FOR EACH ROW EXECUTE FUNCTION public.document_search_join_trigger();
+
+
+--
+-- Name: document_revisions document_revisions_no_update; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER document_revisions_no_update BEFORE UPDATE ON public.document_revisions FOR EACH ROW EXECUTE FUNCTION public.document_revisions_immutable();
+
+
+--
+-- Name: documents documents_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER documents_search_refresh AFTER INSERT OR UPDATE OF title, abstract, english_title, english_abstract ON public.documents FOR EACH ROW EXECUTE FUNCTION public.document_search_doc_trigger();
+
+
+--
+-- Name: keywords keywords_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER keywords_search_refresh AFTER UPDATE OF name ON public.keywords FOR EACH ROW EXECUTE FUNCTION public.document_search_term_trigger('doc_keywords', 'keyword_id');
+
+
+--
+-- Name: regions regions_search_refresh; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER regions_search_refresh AFTER UPDATE OF name ON public.regions FOR EACH ROW EXECUTE FUNCTION public.document_search_term_trigger('doc_regions', 'region_id');
+
+
+--
+-- Name: api_keys api_keys_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.api_keys
+    ADD CONSTRAINT api_keys_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: audit_events audit_events_actor_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.audit_events
+    ADD CONSTRAINT audit_events_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: author_aliases author_aliases_author_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.author_aliases
+    ADD CONSTRAINT author_aliases_author_id_fkey FOREIGN KEY (author_id) REFERENCES public.authors(id) ON DELETE CASCADE;
+
+
+--
+-- Name: category_aliases category_aliases_category_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.category_aliases
+    ADD CONSTRAINT category_aliases_category_id_fkey FOREIGN KEY (category_id) REFERENCES public.categories(id) ON DELETE CASCADE;
+
+
 --
 -- Name: doc_authors doc_authors_author_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -518,6 +1406,182 @@ ALTER TABLE ONLY public.doc_regions
     ADD CONSTRAINT doc_regions_region_id_fkey FOREIGN KEY (region_id) REFERENCES public.regions(id) ON DELETE CASCADE;
 
 
+--
+-- Name: document_field_sources document_field_sources_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_field_sources
+    ADD CONSTRAINT document_field_sources_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE CASCADE;
+
+
+--
+-- Name: document_pages document_pages_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_pages
+    ADD CONSTRAINT document_pages_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE CASCADE;
+
+
+--
+-- Name: document_revisions document_revisions_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_revisions
+    ADD CONSTRAINT document_revisions_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: document_revisions document_revisions_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_revisions
+    ADD CONSTRAINT document_revisions_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE CASCADE;
+
+
+--
+-- Name: document_search document_search_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_search
+    ADD CONSTRAINT document_search_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE CASCADE;
+
+
+--
+-- Name: document_sync_status document_sync_status_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.document_sync_status
+    ADD CONSTRAINT document_sync_status_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE CASCADE;
+
+
+--
+-- Name: documents documents_approved_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_approved_by_fkey FOREIGN KEY (approved_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: documents documents_publisher_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.documents
+    ADD CONSTRAINT documents_publisher_id_fkey FOREIGN KEY (publisher_id) REFERENCES public.publishers(id) ON DELETE SET NULL;
+
+
+--
+-- Name: extraction_prompts extraction_prompts_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.extraction_prompts
+    ADD CONSTRAINT extraction_prompts_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: ingest_batch_files ingest_batch_files_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.ingest_batch_files
+    ADD CONSTRAINT ingest_batch_files_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: ingest_batch_files ingest_batch_files_doc_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.ingest_batch_files
+    ADD CONSTRAINT ingest_batch_files_doc_id_fkey FOREIGN KEY (doc_id) REFERENCES public.documents(id) ON DELETE SET NULL;
+
+
+--
+-- Name: ingest_batch_files ingest_batch_files_job_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.ingest_batch_files
+    ADD CONSTRAINT ingest_batch_files_job_id_fkey FOREIGN KEY (job_id) REFERENCES public.ingest_jobs(id) ON DELETE SET NULL;
+
+
+--
+-- Name: ingest_jobs ingest_jobs_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.ingest_jobs
+    ADD CONSTRAINT ingest_jobs_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: keyword_aliases keyword_aliases_keyword_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.keyword_aliases
+    ADD CONSTRAINT keyword_aliases_keyword_id_fkey FOREIGN KEY (keyword_id) REFERENCES public.keywords(id) ON DELETE CASCADE;
+
+
+--
+-- Name: metadata_extractions metadata_extractions_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.metadata_extractions
+    ADD CONSTRAINT metadata_extractions_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: metadata_extractions metadata_extractions_job_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.metadata_extractions
+    ADD CONSTRAINT metadata_extractions_job_id_fkey FOREIGN KEY (job_id) REFERENCES public.ingest_jobs(id) ON DELETE SET NULL;
+
+
+--
+-- Name: publisher_aliases publisher_aliases_publisher_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.publisher_aliases
+    ADD CONSTRAINT publisher_aliases_publisher_id_fkey FOREIGN KEY (publisher_id) REFERENCES public.publishers(id) ON DELETE CASCADE;
+
+
+--
+-- Name: region_aliases region_aliases_region_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.region_aliases
+    ADD CONSTRAINT region_aliases_region_id_fkey FOREIGN KEY (region_id) REFERENCES public.regions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: regions regions_parent_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.regions
+    ADD CONSTRAINT regions_parent_id_fkey FOREIGN KEY (parent_id) REFERENCES public.regions(id) ON DELETE SET NULL;
+
+
+--
+-- Name: vocabulary_categories vocabulary_categories_category_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_categories
+    ADD CONSTRAINT vocabulary_categories_category_id_fkey FOREIGN KEY (category_id) REFERENCES public.categories(id) ON DELETE CASCADE;
+
+
+--
+-- Name: vocabulary_keywords vocabulary_keywords_keyword_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_keywords
+    ADD CONSTRAINT vocabulary_keywords_keyword_id_fkey FOREIGN KEY (keyword_id) REFERENCES public.keywords(id) ON DELETE CASCADE;
+
+
+--
+-- Name: vocabulary_regions vocabulary_regions_region_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.vocabulary_regions
+    ADD CONSTRAINT vocabulary_regions_region_id_fkey FOREIGN KEY (region_id) REFERENCES public.regions(id) ON DELETE CASCADE;
+
+
 --
 -- PostgreSQL database dump complete
 --
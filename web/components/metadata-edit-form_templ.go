@@ -9,7 +9,12 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import (
+	"slices"
+	"strings"
+
 	db "github.com/DSSD-Madison/gmu/pkg/db/generated"
+	db_types "github.com/DSSD-Madison/gmu/pkg/db/types"
+	"github.com/DSSD-Madison/gmu/pkg/language"
 )
 
 func PDFMetadataEditForm(
@@ -18,11 +23,15 @@ func PDFMetadataEditForm(
 	title string,
 	abstract string,
 	publishDate string,
-	source string,
+	documentLanguage string,
+	englishTitle string,
+	englishAbstract string,
+	selectedPublisher []Pair,
 	selectedRegions []Pair,
 	selectedKeywords []Pair,
 	selectedAuthors []Pair,
 	selectedCategories []Pair,
+	translations []Pair,
 	csrf string,
 	allRegions []db.Region,
 	allKeywords []db.Keyword,
@@ -32,6 +41,9 @@ func PDFMetadataEditForm(
 	isMaster bool,
 	s3Link string,
 	toDelete bool,
+	review db_types.DocumentReview,
+	canApprove bool,
+	authorORCIDs []db_types.AuthorORCID,
 ) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -81,7 +93,7 @@ func PDFMetadataEditForm(
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(originalFilename)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 40, Col: 23}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 52, Col: 23}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -94,91 +106,202 @@ func PDFMetadataEditForm(
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(fileId)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 44, Col: 22}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 56, Col: 22}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</span></p><form hx-post=\"/save-metadata\" method=\"post\" hx-target=\"#flash-messages\" hx-swap=\"innerHTML\" hx-credentials=\"include\" hx-on=\"\n                htmx:beforeRequest: document.getElementById(&#39;flash-messages&#39;).classList.add(&#39;invisible&#39;);\n                htmx:afterSwap:   document.getElementById(&#39;flash-messages&#39;).classList.remove(&#39;invisible&#39;);\n              \"><input type=\"hidden\" name=\"fileId\" value=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</span></p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = ReviewBanner(review).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"flex mb-6 border-b border-gray-300 dark:border-gray-600\"><button type=\"button\" id=\"metadata-tab\" onclick=\"showMetadataTab(&#39;metadata&#39;)\" class=\"px-4 py-2 -mb-px font-medium text-blue-600 border-b-2 border-blue-600 dark:text-blue-400\">Metadata</button> <button type=\"button\" id=\"history-tab\" onclick=\"showMetadataTab(&#39;history&#39;)\" hx-get=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var6 string
-			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(fileId)
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs("/edit-metadata/" + fileId + "/history")
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 53, Col: 53}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 66, Col: 53}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\"> <input type=\"hidden\" name=\"_csrf\" value=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\" hx-target=\"#history-panel\" hx-trigger=\"click\" class=\"px-4 py-2 -mb-px font-medium text-gray-500 border-b-2 border-transparent dark:text-gray-400\">History</button> <button type=\"button\" id=\"revisions-tab\" onclick=\"showMetadataTab(&#39;revisions&#39;)\" hx-get=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var7 string
-			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(csrf)
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs("/edit-metadata/" + fileId + "/revisions")
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 54, Col: 50}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 71, Col: 55}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"><div class=\"mb-4\"><label for=\"title\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Title</label> <input type=\"text\" id=\"title\" name=\"title\" value=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\" hx-target=\"#revisions-panel\" hx-trigger=\"click\" class=\"px-4 py-2 -mb-px font-medium text-gray-500 border-b-2 border-transparent dark:text-gray-400\">Revisions</button></div><div id=\"history-panel\" class=\"hidden\"></div><div id=\"revisions-panel\" class=\"hidden\"></div><form id=\"metadata-panel\" hx-post=\"/save-metadata\" method=\"post\" hx-target=\"#flash-messages\" hx-swap=\"innerHTML\" hx-credentials=\"include\" hx-on=\"\n                htmx:beforeRequest: document.getElementById(&#39;flash-messages&#39;).classList.add(&#39;invisible&#39;);\n                htmx:afterSwap:   document.getElementById(&#39;flash-messages&#39;).classList.remove(&#39;invisible&#39;);\n              \"><input type=\"hidden\" name=\"fileId\" value=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var8 string
-			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(title)
+			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(fileId)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 58, Col: 61}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 84, Col: 53}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div><div class=\"mb-4\"><label for=\"abstract\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Abstract</label> <textarea id=\"abstract\" name=\"abstract\" rows=\"4\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\"> <input type=\"hidden\" name=\"_csrf\" value=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var9 string
-			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(abstract)
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(csrf)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 65, Col: 229}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 85, Col: 50}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</textarea></div><div class=\"grid grid-cols-1 gap-4 mb-4 md:grid-cols-2\"><div><label for=\"publish_date\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Publish Date</label> <input type=\"date\" id=\"publish_date\" name=\"publish_date\" value=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\"><div class=\"mb-4\"><label for=\"title\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Title @ModelFieldBadge(review, \"title\")</label> <input type=\"text\" id=\"title\" name=\"title\" value=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var10 string
-			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(publishDate)
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(title)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 71, Col: 82}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 89, Col: 61}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div><div><label for=\"source\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Source</label> <input type=\"text\" id=\"source\" name=\"source\" value=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div><div class=\"mb-4\"><label for=\"abstract\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Abstract @ModelFieldBadge(review, \"abstract\")</label> <textarea id=\"abstract\" name=\"abstract\" rows=\"4\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var11 string
-			templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(source)
+			templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(abstract)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 76, Col: 65}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 96, Col: 229}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"><p class=\"mt-1 text-xs text-gray-500 dark:text-gray-400\">Internal reference (e.g., bucket name)</p></div></div><hr class=\"my-6 border-gray-300 dark:border-gray-600\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</textarea></div><div class=\"mb-4\"><label for=\"language\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Language</label> <select id=\"language\" name=\"language\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"><option value=\"\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if documentLanguage == "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, " selected")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, ">Unknown</option> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, l := range language.Supported {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<option value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var12 string
+				templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(l.Code)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 105, Col: 29}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if documentLanguage == l.Code {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, " selected")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, ">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var13 string
+				templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(l.Name)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 105, Col: 81}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</option>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</select></div><div class=\"mb-4\"><label for=\"english_title\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">English Title @ModelFieldBadge(review, \"english_title\")</label> <input type=\"text\" id=\"english_title\" name=\"english_title\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var14 string
+			templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(englishTitle)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 112, Col: 84}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" placeholder=\"Leave empty for documents in English\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div><div class=\"mb-4\"><label for=\"english_abstract\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">English Abstract @ModelFieldBadge(review, \"english_abstract\")</label> <textarea id=\"english_abstract\" name=\"english_abstract\" rows=\"4\" placeholder=\"Leave empty for documents in English\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var15 string
+			templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(englishAbstract)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 119, Col: 236}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</textarea></div><div class=\"grid grid-cols-1 gap-4 mb-4 md:grid-cols-2\"><div><label for=\"publish_date\" class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Publish Date @ModelFieldBadge(review, \"publish_date\")</label> <input type=\"date\" id=\"publish_date\" name=\"publish_date\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var16 string
+			templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(publishDate)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 125, Col: 82}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "\" class=\"w-full px-3 py-2 leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div><div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = TagInputJS("publishers", "Publisher", "publisher_names", "/publishers", selectedPublisher).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "</div></div><hr class=\"my-6 border-gray-300 dark:border-gray-600\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -198,10 +321,28 @@ func PDFMetadataEditForm(
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<div class=\"flex items-center justify-start mt-8 space-x-4\"><button type=\"submit\" class=\"px-4 py-2 font-bold text-white bg-blue-500 rounded hover:bg-blue-700 focus:outline-none focus:shadow-outline\">Save Metadata</button> ")
+			templ_7745c5c3_Err = AuthorORCIDInputs(authorORCIDs).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<hr class=\"my-6 border-gray-300 dark:border-gray-600\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = TagInputJS("translations", "Translations", "translation_ids", "/edit-metadata/"+fileId+"/translations", translations).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "<div class=\"flex items-center justify-start mt-8 space-x-4\"><button type=\"submit\" class=\"px-4 py-2 font-bold text-white bg-blue-500 rounded hover:bg-blue-700 focus:outline-none focus:shadow-outline\">Save Metadata</button> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if canApprove && !review.Approved {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<button type=\"submit\" name=\"approve\" value=\"true\" class=\"px-4 py-2 font-bold text-white bg-green-600 rounded hover:bg-green-700 focus:outline-none focus:shadow-outline\">Save and Approve</button> ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
 			if toDelete {
 				templ_7745c5c3_Err = ToggleDeleteButton(fileId, false, "Undo Delete").Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
@@ -213,7 +354,7 @@ func PDFMetadataEditForm(
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</div><div class=\"pt-2\" id=\"flash-messages\"></div></form></div><script>\n\t\t\tfunction addTag(idPrefix, fieldName, uuid, displayName) {\n\t\t\t\tconst tagValue = uuid.trim();\n\t\t\t\tconst tagLabel = displayName.trim();\n\t\t\t\tif (!tagValue || !tagLabel) return;\n\n\t\t\t\tconst container = document.getElementById(`${idPrefix}-tags-display`)?.closest('.tag-input-container');\n\t\t\t\tif (!container) return;\n\n\t\t\t\tconst tagsDisplay = container.querySelector(`#${idPrefix}-tags-display`);\n\t\t\t\tconst hiddenInputsContainer = container.querySelector(`#${idPrefix}-hidden-inputs`);\n\t\t\t\tconst searchInput = container.querySelector(`#${idPrefix}-search-input`);\n\t\t\t\tconst suggestionsContainer = container.querySelector(`#${idPrefix}-suggestions`);\n\n\t\t\t\tconst existingInput = hiddenInputsContainer.querySelector(`input[name=\"${fieldName}\"][value=\"${CSS.escape(tagValue)}\"]`);\n\t\t\t\tif (existingInput) return;\n\n\t\t\t\tconst hiddenInput = document.createElement('input');\n\t\t\t\thiddenInput.type = 'hidden';\n\t\t\t\thiddenInput.name = fieldName;\n\t\t\t\thiddenInput.value = tagValue;\n\t\t\t\thiddenInput.setAttribute('data-tag-value', tagValue);\n\t\t\t\thiddenInputsContainer.appendChild(hiddenInput);\n\n\t\t\t\tconst tagSpan = document.createElement('span');\n\t\t\t\ttagSpan.setAttribute('data-tag-value', tagValue);\n\t\t\t\ttagSpan.setAttribute('data-id-prefix', idPrefix);\n\t\t\t\ttagSpan.setAttribute('data-field-name', fieldName);\n\t\t\t\ttagSpan.className = 'tag-item bg-blue-100 text-blue-800 text-xs font-medium me-2 px-2.5 py-0.5 rounded dark:bg-blue-900 dark:text-blue-300 inline-flex items-center';\n\t\t\t\ttagSpan.textContent = tagLabel + ' ';\n\n\t\t\t\tconst removeButton = document.createElement('button');\n\t\t\t\tremoveButton.type = 'button';\n\t\t\t\tremoveButton.className = 'ml-1 text-blue-600 hover:text-blue-400 focus:outline-none';\n\t\t\t\tremoveButton.innerHTML = '×';\n\t\t\t\tremoveButton.setAttribute('aria-label', `Remove ${tagLabel}`);\n\t\t\t\tremoveButton.onclick = function () { removeTag(this); };\n\t\t\t\ttagSpan.appendChild(removeButton);\n\n\t\t\t\tconst placeholder = tagsDisplay.querySelector('.tag-placeholder');\n\t\t\t\tif (placeholder) placeholder.remove();\n\t\t\t\ttagsDisplay.appendChild(tagSpan);\n\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tsuggestionsContainer.innerHTML = '';\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction removeTag(buttonElement) {\n\t\t\t\tconst tagSpan = buttonElement.closest('.tag-item');\n\t\t\t\tif (!tagSpan) return;\n\n\t\t\t\tconst tagValue = tagSpan.getAttribute('data-tag-value');\n\t\t\t\tconst idPrefix = tagSpan.getAttribute('data-id-prefix');\n\t\t\t\tconst fieldName = tagSpan.getAttribute('data-field-name');\n\n\t\t\t\tconst container = tagSpan.closest('.tag-input-container');\n\t\t\t\tif (!container || !tagValue || !idPrefix || !fieldName) return;\n\n\t\t\t\tconst hiddenInputsContainer = container.querySelector(`#${idPrefix}-hidden-inputs`);\n\t\t\t\tconst tagsDisplay = container.querySelector(`#${idPrefix}-tags-display`);\n\n\t\t\t\tconst hiddenInput = hiddenInputsContainer?.querySelector(`input[name=\"${fieldName}\"][data-tag-value=\"${CSS.escape(tagValue)}\"]`);\n\t\t\t\tif (hiddenInput) hiddenInput.remove();\n\n\t\t\t\ttagSpan.remove();\n\n\t\t\t\tif (tagsDisplay && !tagsDisplay.querySelector('.tag-item')) {\n\t\t\t\t\tconst placeholder = document.createElement('span');\n\t\t\t\t\tplaceholder.className = 'tag-placeholder text-xs text-gray-400 italic p-1';\n\t\t\t\t\tlet labelText = 'items';\n\t\t\t\t\tconst labelElement = container.querySelector(`label[for='${idPrefix}-search-input']`);\n\t\t\t\t\tif (labelElement) {\n\t\t\t\t\t\tlabelText = labelElement.textContent.replace(/\\s+Names$/i, '').toLowerCase();\n\t\t\t\t\t}\n\t\t\t\t\tplaceholder.textContent = `No ${labelText} added yet.`;\n\t\t\t\t\ttagsDisplay.appendChild(placeholder);\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tdocument.addEventListener('click', function(event) {\n\t\t\t\tconst allTagContainers = document.querySelectorAll('.tag-input-container');\n\t\t\t\tallTagContainers.forEach(container => {\n\t\t\t\t\tconst suggestionsDivId = container.querySelector('input[type=text]').id.replace('-search-input', '-suggestions');\n\t\t\t\t\tconst suggestionsDiv = container.querySelector(`#${suggestionsDivId}`);\n\t\t\t\t\tif (suggestionsDiv && !container.contains(event.target)) {\n\t\t\t\t\t\tsuggestionsDiv.innerHTML = '';\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t});\n\n\t\t    function getTagCount(idPrefix) {\n              return document.querySelectorAll(\n                `#${idPrefix}-hidden-inputs input`\n              ).length;\n            }\n\n            document.body.addEventListener('htmx:configRequest', function(evt) {\n              var el = evt.target;\n              var idPrefix = el.getAttribute('data-id-prefix');\n              if (!idPrefix) return;\n              evt.detail.parameters.tagCount = getTagCount(idPrefix);\n            });\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "</div><div class=\"pt-2\" id=\"flash-messages\"></div></form></div><script>\n\t\t\tfunction showMetadataTab(tab) {\n\t\t\t\tconst active = [\"text-blue-600\", \"border-blue-600\", \"dark:text-blue-400\"];\n\t\t\t\tconst inactive = [\"text-gray-500\", \"border-transparent\", \"dark:text-gray-400\"];\n\t\t\t\tfor (const name of [\"metadata\", \"history\", \"revisions\"]) {\n\t\t\t\t\tconst selected = name === tab;\n\t\t\t\t\tdocument.getElementById(`${name}-panel`).classList.toggle(\"hidden\", !selected);\n\t\t\t\t\tdocument.getElementById(`${name}-tab`).classList.remove(...(selected ? inactive : active));\n\t\t\t\t\tdocument.getElementById(`${name}-tab`).classList.add(...(selected ? active : inactive));\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction addTag(idPrefix, fieldName, uuid, displayName) {\n\t\t\t\tconst tagValue = uuid.trim();\n\t\t\t\tconst tagLabel = displayName.trim();\n\t\t\t\tif (!tagValue || !tagLabel) return;\n\n\t\t\t\tconst container = document.getElementById(`${idPrefix}-tags-display`)?.closest('.tag-input-container');\n\t\t\t\tif (!container) return;\n\n\t\t\t\tconst tagsDisplay = container.querySelector(`#${idPrefix}-tags-display`);\n\t\t\t\tconst hiddenInputsContainer = container.querySelector(`#${idPrefix}-hidden-inputs`);\n\t\t\t\tconst searchInput = container.querySelector(`#${idPrefix}-search-input`);\n\t\t\t\tconst suggestionsContainer = container.querySelector(`#${idPrefix}-suggestions`);\n\n\t\t\t\tconst existingInput = hiddenInputsContainer.querySelector(`input[name=\"${fieldName}\"][value=\"${CSS.escape(tagValue)}\"]`);\n\t\t\t\tif (existingInput) return;\n\n\t\t\t\tconst hiddenInput = document.createElement('input');\n\t\t\t\thiddenInput.type = 'hidden';\n\t\t\t\thiddenInput.name = fieldName;\n\t\t\t\thiddenInput.value = tagValue;\n\t\t\t\thiddenInput.setAttribute('data-tag-value', tagValue);\n\t\t\t\thiddenInputsContainer.appendChild(hiddenInput);\n\n\t\t\t\tconst tagSpan = document.createElement('span');\n\t\t\t\ttagSpan.setAttribute('data-tag-value', tagValue);\n\t\t\t\ttagSpan.setAttribute('data-id-prefix', idPrefix);\n\t\t\t\ttagSpan.setAttribute('data-field-name', fieldName);\n\t\t\t\ttagSpan.className = 'tag-item bg-blue-100 text-blue-800 text-xs font-medium me-2 px-2.5 py-0.5 rounded dark:bg-blue-900 dark:text-blue-300 inline-flex items-center';\n\t\t\t\ttagSpan.textContent = tagLabel + ' ';\n\n\t\t\t\tconst removeButton = document.createElement('button');\n\t\t\t\tremoveButton.type = 'button';\n\t\t\t\tremoveButton.className = 'ml-1 text-blue-600 hover:text-blue-400 focus:outline-none';\n\t\t\t\tremoveButton.innerHTML = '×';\n\t\t\t\tremoveButton.setAttribute('aria-label', `Remove ${tagLabel}`);\n\t\t\t\tremoveButton.onclick = function () { removeTag(this); };\n\t\t\t\ttagSpan.appendChild(removeButton);\n\n\t\t\t\tconst placeholder = tagsDisplay.querySelector('.tag-placeholder');\n\t\t\t\tif (placeholder) placeholder.remove();\n\t\t\t\ttagsDisplay.appendChild(tagSpan);\n\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tsuggestionsContainer.innerHTML = '';\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction removeTag(buttonElement) {\n\t\t\t\tconst tagSpan = buttonElement.closest('.tag-item');\n\t\t\t\tif (!tagSpan) return;\n\n\t\t\t\tconst tagValue = tagSpan.getAttribute('data-tag-value');\n\t\t\t\tconst idPrefix = tagSpan.getAttribute('data-id-prefix');\n\t\t\t\tconst fieldName = tagSpan.getAttribute('data-field-name');\n\n\t\t\t\tconst container = tagSpan.closest('.tag-input-container');\n\t\t\t\tif (!container || !tagValue || !idPrefix || !fieldName) return;\n\n\t\t\t\tconst hiddenInputsContainer = container.querySelector(`#${idPrefix}-hidden-inputs`);\n\t\t\t\tconst tagsDisplay = container.querySelector(`#${idPrefix}-tags-display`);\n\n\t\t\t\tconst hiddenInput = hiddenInputsContainer?.querySelector(`input[name=\"${fieldName}\"][data-tag-value=\"${CSS.escape(tagValue)}\"]`);\n\t\t\t\tif (hiddenInput) hiddenInput.remove();\n\n\t\t\t\ttagSpan.remove();\n\n\t\t\t\tif (tagsDisplay && !tagsDisplay.querySelector('.tag-item')) {\n\t\t\t\t\tconst placeholder = document.createElement('span');\n\t\t\t\t\tplaceholder.className = 'tag-placeholder text-xs text-gray-400 italic p-1';\n\t\t\t\t\tlet labelText = 'items';\n\t\t\t\t\tconst labelElement = container.querySelector(`label[for='${idPrefix}-search-input']`);\n\t\t\t\t\tif (labelElement) {\n\t\t\t\t\t\tlabelText = labelElement.textContent.replace(/\\s+Names$/i, '').toLowerCase();\n\t\t\t\t\t}\n\t\t\t\t\tplaceholder.textContent = `No ${labelText} added yet.`;\n\t\t\t\t\ttagsDisplay.appendChild(placeholder);\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tdocument.addEventListener('click', function(event) {\n\t\t\t\tconst allTagContainers = document.querySelectorAll('.tag-input-container');\n\t\t\t\tallTagContainers.forEach(container => {\n\t\t\t\t\tconst suggestionsDivId = container.querySelector('input[type=text]').id.replace('-search-input', '-suggestions');\n\t\t\t\t\tconst suggestionsDiv = container.querySelector(`#${suggestionsDivId}`);\n\t\t\t\t\tif (suggestionsDiv && !container.contains(event.target)) {\n\t\t\t\t\t\tsuggestionsDiv.innerHTML = '';\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t});\n\n\t\t    function getTagCount(idPrefix) {\n              return document.querySelectorAll(\n                `#${idPrefix}-hidden-inputs input`\n              ).length;\n            }\n\n            document.body.addEventListener('htmx:configRequest', function(evt) {\n              var el = evt.target;\n              var idPrefix = el.getAttribute('data-id-prefix');\n              if (!idPrefix) return;\n              evt.detail.parameters.tagCount = getTagCount(idPrefix);\n            });\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -227,4 +368,272 @@ func PDFMetadataEditForm(
 	})
 }
 
+// AuthorORCIDInputs lets editors attach ORCID iDs to the document's saved
+// authors. Clearing one removes it from the author.
+func AuthorORCIDInputs(authors []db_types.AuthorORCID) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var17 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var17 == nil {
+			templ_7745c5c3_Var17 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if len(authors) > 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<div class=\"mb-4\"><p class=\"block mb-2 text-sm font-bold text-gray-700 dark:text-gray-200\">Author ORCID iDs</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, author := range authors {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "<div class=\"flex items-center gap-2 mb-2\"><input type=\"hidden\" name=\"orcid_author_id\" value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var18 string
+				templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(author.ID)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 302, Col: 66}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\"> <input type=\"hidden\" name=\"orcid_author_name\" value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var19 string
+				templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(author.Name)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 303, Col: 70}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\"> <label for=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var20 string
+				templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs("orcid-" + author.ID)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 304, Col: 38}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "\" class=\"w-1/2 text-sm text-gray-700 truncate dark:text-gray-200\"><a href=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var21 templ.SafeURL = templ.URL("/authors/" + author.ID)
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(string(templ_7745c5c3_Var21)))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "\" target=\"_blank\" class=\"hover:underline\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var22 string
+				templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(author.Name)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 305, Col: 106}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "</a></label> <input type=\"text\" id=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var23 string
+				templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs("orcid-" + author.ID)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 307, Col: 49}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "\" name=\"orcid\" value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var24 string
+				templ_7745c5c3_Var24, templ_7745c5c3_Err = templ.JoinStringErrs(author.ORCID)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 307, Col: 85}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var24))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "\" placeholder=\"0000-0000-0000-0000\" class=\"w-1/2 px-3 py-1 text-sm leading-tight text-gray-700 bg-white border border-gray-300 rounded shadow appearance-none dark:border-gray-600 dark:text-gray-200 dark:bg-gray-700 focus:outline-none focus:shadow-outline\"></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<p class=\"text-xs text-gray-500 dark:text-gray-400\">Authors added above get an ORCID field once the metadata is saved.</p></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
+// ReviewBanner shows whether a person has checked the document's metadata and
+// which fields a model wrote.
+func ReviewBanner(review db_types.DocumentReview) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var25 == nil {
+			templ_7745c5c3_Var25 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if review.Approved {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "<p class=\"p-3 mb-4 text-sm text-green-800 bg-green-100 rounded\">Approved ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if review.ApprovedBy != "" {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "by ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var26 string
+				templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(review.ApprovedBy)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 323, Col: 26}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, " on ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var27 string
+				templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(review.ApprovedAt)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 323, Col: 51}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else if review.State != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "<div class=\"p-3 mb-4 text-sm text-yellow-800 bg-yellow-100 rounded\"><p class=\"font-semibold\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var28 string
+			templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(review.StateLabel)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 328, Col: 47}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, ": this metadata has not been approved by a reviewer.</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if len(review.ModelFields) > 0 {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "<p>Written by the model: ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var29 string
+				templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(strings.Join(review.ModelFields, ", "))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/metadata-edit-form.templ`, Line: 330, Col: 69}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, ".</p>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "</div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
+// ModelFieldBadge marks a field whose value a model wrote.
+func ModelFieldBadge(review db_types.DocumentReview, field string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var30 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var30 == nil {
+			templ_7745c5c3_Var30 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if slices.Contains(review.ModelFields, field) {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "<span class=\"ml-1 px-1.5 py-0.5 text-xs font-normal text-purple-800 bg-purple-100 rounded\" title=\"Written by the model\">AI</span>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
@@ -54,7 +54,7 @@ func PDFUpload(csrf string, isAuthorized bool, isMaster bool) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"><div id=\"file-status-display\" class=\"flex flex-col items-center justify-center w-full h-32 px-4 mb-4 text-center transition bg-white border-2 border-gray-300 border-dashed rounded-md cursor-pointer dark:bg-gray-800 dark:border-gray-600 hover:bg-gray-50 dark:hover:bg-gray-700\"><span id=\"upload-status-text\" class=\"text-gray-600 dark:text-gray-300\">Click here or drag & drop a PDF/DOCX</span></div><input type=\"file\" id=\"pdf-upload-input\" name=\"pdf\" accept=\".pdf,.docx,application/pdf,application/vnd.openxmlformats-officedocument.wordprocessingml.document\" class=\"hidden\" required> <button id=\"upload-button\" type=\"submit\" class=\"w-full px-4 py-2 font-bold text-white bg-blue-500 rounded cursor-pointer hover:bg-blue-700 focus:outline-none focus:shadow-outline disabled:opacity-50 disabled:cursor-not-allowed\"><span class=\"button-text\">Upload Selected File</span> <span id=\"upload-indicator\" class=\"htmx-indicator\"><svg class=\"inline w-4 h-4 ml-2 text-white animate-spin\" xmlns=\"http://www.w3.org/2000/svg\" fill=\"none\" viewBox=\"0 0 24 24\"><circle class=\"opacity-25\" cx=\"12\" cy=\"12\" r=\"10\" stroke=\"currentColor\" stroke-width=\"4\"></circle> <path class=\"opacity-75\" fill=\"currentColor\" d=\"M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z\"></path></svg></span></button><div id=\"upload-response\" class=\"h-6 mt-4 text-sm text-center text-gray-700 dark:text-gray-300\"></div></form><div id=\"page-drag-overlay\" class=\"fixed inset-0 z-40 flex items-center justify-center hidden transition-opacity duration-200 bg-blue-500 bg-opacity-75 pointer-events-none dark:bg-blue-400 dark:bg-opacity-80\"><span class=\"text-3xl font-bold text-white dark:text-gray-900\">Drop PDF or DOCX Here</span></div></div><script>\n\t\t\t(function() {\n\t\t\t\tconst fileStatusDisplay = document.getElementById('file-status-display');\n\t\t\t\tconst input = document.getElementById('pdf-upload-input');\n\t\t\t\tconst statusSpan = document.getElementById('upload-status-text');\n\t\t\t\tconst overlay = document.getElementById('page-drag-overlay');\n\t\t\t\tconst form = document.getElementById('pdf-upload-form');\n\t\t\t\tconst uploadButton = document.getElementById('upload-button');\n\t\t\t\tconst buttonText = uploadButton.querySelector('.button-text');\n\n\t\t\t\tif (!fileStatusDisplay || !input || !statusSpan || !overlay || !form || !uploadButton || !buttonText) {\n\t\t\t\t\tconsole.error(\"Upload component elements not found.\");\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tconst defaultStatusText = 'Click here or drag & drop a PDF/DOCX';\n\t\t\t\tconst originalButtonText = buttonText.textContent;\n\t\t\t\tconst allowedMimeTypes = [\n\t\t\t\t\t\"application/pdf\",\n\t\t\t\t\t\"application/vnd.openxmlformats-officedocument.wordprocessingml.document\"\n\t\t\t\t];\n\n\t\t\t\tconst showOverlay = () => {\n\t\t\t\t\toverlay.classList.remove('hidden');\n\t\t\t\t\toverlay.classList.add('opacity-100');\n\t\t\t\t};\n\n\t\t\t\tconst hideOverlay = () => {\n\t\t\t\t\toverlay.classList.remove('opacity-100');\n\t\t\t\t\toverlay.classList.add('opacity-0');\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\toverlay.classList.add('hidden');\n\t\t\t\t\t\toverlay.classList.remove('opacity-0');\n\t\t\t\t\t}, 200);\n\t\t\t\t};\n\n\t\t\t\tconst handleFileSelection = () => {\n\t\t\t\t\tconst files = input.files;\n\t\t\t\t\tif (files && files.length > 0) {\n\t\t\t\t\t\tstatusSpan.textContent = `Selected: ${files[0].name}`;\n\t\t\t\t\t\tstatusSpan.classList.add('text-green-700', 'dark:text-green-400');\n\t\t\t\t\t\tstatusSpan.classList.remove('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\tuploadButton.disabled = false;\n\t\t\t\t\t} else {\n\t\t\t\t\t\tstatusSpan.textContent = defaultStatusText;\n\t\t\t\t\t\tstatusSpan.classList.remove('text-green-700', 'dark:text-green-400');\n\t\t\t\t\t\tstatusSpan.classList.add('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\tinput.value = '';\n\t\t\t\t\t\tuploadButton.disabled = true;\n\t\t\t\t\t}\n\t\t\t\t};\n\n\t\t\t\twindow.addEventListener('dragover', (e) => { e.preventDefault(); showOverlay(); }, false);\n\t\t\t\twindow.addEventListener('dragenter', (e) => { e.preventDefault(); showOverlay(); }, false);\n\t\t\t\twindow.addEventListener('dragleave', (e) => {\n\t\t\t\t\tif (!e.relatedTarget || !document.documentElement.contains(e.relatedTarget)) {\n\t\t\t\t\t\thideOverlay();\n\t\t\t\t\t}\n\t\t\t\t}, false);\n\n\t\t\t\twindow.addEventListener('drop', (e) => {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\thideOverlay();\n\t\t\t\t\tif (e.dataTransfer.files && e.dataTransfer.files.length > 0) {\n\t\t\t\t\t\tconst droppedFile = e.dataTransfer.files[0];\n\t\t\t\t\t\tif (allowedMimeTypes.includes(droppedFile.type) || droppedFile.name.endsWith('.pdf') || droppedFile.name.endsWith('.docx')) {\n\t\t\t\t\t\t\tinput.files = e.dataTransfer.files;\n\t\t\t\t\t\t\thandleFileSelection();\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\tstatusSpan.textContent = 'Please drop a PDF or DOCX file.';\n\t\t\t\t\t\t\tstatusSpan.classList.add('text-red-700', 'dark:text-red-400');\n\t\t\t\t\t\t\tstatusSpan.classList.remove('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\t\tinput.value = '';\n\t\t\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t\t\tif (!input.files || input.files.length === 0) {\n\t\t\t\t\t\t\t\t\thandleFileSelection();\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t}, 3000);\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}, false);\n\n\t\t\t\tfileStatusDisplay.addEventListener('click', () => {\n\t\t\t\t\tinput.click();\n\t\t\t\t});\n\n\t\t\t\tinput.addEventListener('change', handleFileSelection, false);\n\n\t\t\t\tform.addEventListener('htmx:beforeRequest', function(evt) {\n\t\t\t\t\tuploadButton.disabled = true;\n\t\t\t\t\tbuttonText.textContent = 'Uploading...';\n\t\t\t\t\tdocument.getElementById('upload-response').textContent = '';\n\t\t\t\t});\n\n\t\t\t\tform.addEventListener('htmx:afterRequest', function(evt) {\n\t\t\t\t\tuploadButton.disabled = false;\n\t\t\t\t\tbuttonText.textContent = originalButtonText;\n\t\t\t\t\tif(evt.detail.successful) {\n\t\t\t\t\t\tconsole.error(\"Upload successful:\", evt.detail);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tconsole.error(\"Upload failed:\", evt.detail);\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\thandleFileSelection();\n\n\t\t\t})();\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\"><div id=\"file-status-display\" class=\"flex flex-col items-center justify-center w-full h-32 px-4 mb-4 text-center transition bg-white border-2 border-gray-300 border-dashed rounded-md cursor-pointer dark:bg-gray-800 dark:border-gray-600 hover:bg-gray-50 dark:hover:bg-gray-700\"><span id=\"upload-status-text\" class=\"text-gray-600 dark:text-gray-300\">Click here or drag & drop PDF/DOCX files or a ZIP archive</span></div><input type=\"file\" id=\"pdf-upload-input\" name=\"pdf\" accept=\".pdf,.docx,.zip,application/pdf,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/zip\" class=\"hidden\" multiple required> <button id=\"upload-button\" type=\"submit\" class=\"w-full px-4 py-2 font-bold text-white bg-blue-500 rounded cursor-pointer hover:bg-blue-700 focus:outline-none focus:shadow-outline disabled:opacity-50 disabled:cursor-not-allowed\"><span class=\"button-text\">Upload Selected Files</span> <span id=\"upload-indicator\" class=\"htmx-indicator\"><svg class=\"inline w-4 h-4 ml-2 text-white animate-spin\" xmlns=\"http://www.w3.org/2000/svg\" fill=\"none\" viewBox=\"0 0 24 24\"><circle class=\"opacity-25\" cx=\"12\" cy=\"12\" r=\"10\" stroke=\"currentColor\" stroke-width=\"4\"></circle> <path class=\"opacity-75\" fill=\"currentColor\" d=\"M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z\"></path></svg></span></button><div id=\"upload-response\" class=\"h-6 mt-4 text-sm text-center text-gray-700 dark:text-gray-300\"></div><p class=\"mt-2 text-sm text-center\"><a href=\"/upload/jobs\" class=\"text-blue-600 hover:underline dark:text-blue-400\">View recent uploads</a> <span class=\"text-gray-400\">·</span> <a href=\"/upload/manifest\" class=\"text-blue-600 hover:underline dark:text-blue-400\">Import with a manifest</a></p></form><div id=\"page-drag-overlay\" class=\"fixed inset-0 z-40 flex items-center justify-center hidden transition-opacity duration-200 bg-blue-500 bg-opacity-75 pointer-events-none dark:bg-blue-400 dark:bg-opacity-80\"><span class=\"text-3xl font-bold text-white dark:text-gray-900\">Drop PDF, DOCX or ZIP Files Here</span></div></div><script>\n\t\t\t(function() {\n\t\t\t\tconst fileStatusDisplay = document.getElementById('file-status-display');\n\t\t\t\tconst input = document.getElementById('pdf-upload-input');\n\t\t\t\tconst statusSpan = document.getElementById('upload-status-text');\n\t\t\t\tconst overlay = document.getElementById('page-drag-overlay');\n\t\t\t\tconst form = document.getElementById('pdf-upload-form');\n\t\t\t\tconst uploadButton = document.getElementById('upload-button');\n\t\t\t\tconst buttonText = uploadButton.querySelector('.button-text');\n\n\t\t\t\tif (!fileStatusDisplay || !input || !statusSpan || !overlay || !form || !uploadButton || !buttonText) {\n\t\t\t\t\tconsole.error(\"Upload component elements not found.\");\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tconst defaultStatusText = 'Click here or drag & drop PDF/DOCX files or a ZIP archive';\n\t\t\t\tconst originalButtonText = buttonText.textContent;\n\t\t\t\tconst allowedMimeTypes = [\n\t\t\t\t\t\"application/pdf\",\n\t\t\t\t\t\"application/vnd.openxmlformats-officedocument.wordprocessingml.document\",\n\t\t\t\t\t\"application/zip\",\n\t\t\t\t\t\"application/x-zip-compressed\"\n\t\t\t\t];\n\t\t\t\tconst allowedExtensions = ['.pdf', '.docx', '.zip'];\n\t\t\t\tconst isAllowed = (file) => allowedMimeTypes.includes(file.type) ||\n\t\t\t\t\tallowedExtensions.some((ext) => file.name.toLowerCase().endsWith(ext));\n\n\t\t\t\tconst showOverlay = () => {\n\t\t\t\t\toverlay.classList.remove('hidden');\n\t\t\t\t\toverlay.classList.add('opacity-100');\n\t\t\t\t};\n\n\t\t\t\tconst hideOverlay = () => {\n\t\t\t\t\toverlay.classList.remove('opacity-100');\n\t\t\t\t\toverlay.classList.add('opacity-0');\n\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\toverlay.classList.add('hidden');\n\t\t\t\t\t\toverlay.classList.remove('opacity-0');\n\t\t\t\t\t}, 200);\n\t\t\t\t};\n\n\t\t\t\tconst handleFileSelection = () => {\n\t\t\t\t\tconst files = input.files;\n\t\t\t\t\tif (files && files.length > 0) {\n\t\t\t\t\t\tstatusSpan.textContent = files.length === 1 ? `Selected: ${files[0].name}` : `Selected: ${files.length} files`;\n\t\t\t\t\t\tstatusSpan.classList.add('text-green-700', 'dark:text-green-400');\n\t\t\t\t\t\tstatusSpan.classList.remove('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\tuploadButton.disabled = false;\n\t\t\t\t\t} else {\n\t\t\t\t\t\tstatusSpan.textContent = defaultStatusText;\n\t\t\t\t\t\tstatusSpan.classList.remove('text-green-700', 'dark:text-green-400');\n\t\t\t\t\t\tstatusSpan.classList.add('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\tinput.value = '';\n\t\t\t\t\t\tuploadButton.disabled = true;\n\t\t\t\t\t}\n\t\t\t\t};\n\n\t\t\t\twindow.addEventListener('dragover', (e) => { e.preventDefault(); showOverlay(); }, false);\n\t\t\t\twindow.addEventListener('dragenter', (e) => { e.preventDefault(); showOverlay(); }, false);\n\t\t\t\twindow.addEventListener('dragleave', (e) => {\n\t\t\t\t\tif (!e.relatedTarget || !document.documentElement.contains(e.relatedTarget)) {\n\t\t\t\t\t\thideOverlay();\n\t\t\t\t\t}\n\t\t\t\t}, false);\n\n\t\t\t\twindow.addEventListener('drop', (e) => {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\thideOverlay();\n\t\t\t\t\tif (e.dataTransfer.files && e.dataTransfer.files.length > 0) {\n\t\t\t\t\t\tif (Array.from(e.dataTransfer.files).every(isAllowed)) {\n\t\t\t\t\t\t\tinput.files = e.dataTransfer.files;\n\t\t\t\t\t\t\thandleFileSelection();\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\tstatusSpan.textContent = 'Please drop only PDF, DOCX or ZIP files.';\n\t\t\t\t\t\t\tstatusSpan.classList.add('text-red-700', 'dark:text-red-400');\n\t\t\t\t\t\t\tstatusSpan.classList.remove('text-gray-600', 'dark:text-gray-300');\n\t\t\t\t\t\t\tinput.value = '';\n\t\t\t\t\t\t\tsetTimeout(() => {\n\t\t\t\t\t\t\t\tif (!input.files || input.files.length === 0) {\n\t\t\t\t\t\t\t\t\thandleFileSelection();\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t}, 3000);\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}, false);\n\n\t\t\t\tfileStatusDisplay.addEventListener('click', () => {\n\t\t\t\t\tinput.click();\n\t\t\t\t});\n\n\t\t\t\tinput.addEventListener('change', handleFileSelection, false);\n\n\t\t\t\tform.addEventListener('htmx:beforeRequest', function(evt) {\n\t\t\t\t\tuploadButton.disabled = true;\n\t\t\t\t\tbuttonText.textContent = 'Uploading...';\n\t\t\t\t\tdocument.getElementById('upload-response').textContent = '';\n\t\t\t\t});\n\n\t\t\t\tform.addEventListener('htmx:afterRequest', function(evt) {\n\t\t\t\t\tuploadButton.disabled = false;\n\t\t\t\t\tbuttonText.textContent = originalButtonText;\n\t\t\t\t\tif(evt.detail.successful) {\n\t\t\t\t\t\tconsole.error(\"Upload successful:\", evt.detail);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tconsole.error(\"Upload failed:\", evt.detail);\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\thandleFileSelection();\n\n\t\t\t})();\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}